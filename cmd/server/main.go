@@ -2,43 +2,68 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
 	"wh-ma/internal/bootstrap"
 )
 
 func main() {
-	// 1) Context + signal
+	// 1) Context + signal: một ctx cho cả process; signal đầu tiên bắt đầu dừng có thứ tự,
+	//    signal thứ hai (ctx đã hủy, stop() trả lại hành vi mặc định) thoát ngay
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
 
-	// 2) Load config (đã có trong bootstrap)
+	// 2) Load config: configs/config.yaml (hoặc CONFIG_FILE) + env, sai thì dừng và liệt kê lỗi
 	cfg := bootstrap.LoadConfig()
+	storage := flag.String("storage", cfg.Storage, "postgres | memory (memory: dữ liệu demo, không cần Postgres)")
+	flag.Parse()
+	cfg.Storage = *storage
+	os.Setenv("STORAGE", *storage) // flag thắng file/env, kể cả khi reload cấu hình
 
-	// 3) Init OpenTelemetry (CẤY Ở ĐÂY)
-	// Hàm này nằm ở internal/bootstrap/otel.go (anh thêm theo mẫu trước đó)
-	tr := bootstrap.InitTracing(ctx, "wh-ma-api")
-	defer func() {
-		shCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		_ = tr.Shutdown(shCtx)
-	}()
+	// Logger gốc (LOG_LEVEL/LOG_FORMAT, che secret, sampling); log.Printf cũng đi qua handler này
+	lg := bootstrap.NewLogging(cfg.Log, os.Stdout)
+	slog.SetDefault(lg.Logger)
+
+	// 3) Init OpenTelemetry (internal/bootstrap/otel.go): TRACING_EXPORTER / TRACING_SAMPLE_RATIO,
+	//    exporter lỗi hay collector chết không chặn server khởi động
+	tr := bootstrap.InitTracing(ctx, "wh-ma-api", cfg.Tracing, lg.Logger)
+	mt := bootstrap.InitMetrics(ctx, "wh-ma-api", cfg.MetricsExporter, lg.Logger)
 
-	// 4) DB pool (nếu đã gắn tracer trong NewPGXPool thì mọi query sẽ có span)
-	pool, err := bootstrap.NewPGXPool(ctx, cfg.DatabaseURL)
+	// 4) Storage: DB pool (nếu đã gắn tracer trong NewPGXPool thì mọi query sẽ có span)
+	//    hoặc --storage=memory cho frontend/CI không có Postgres
+	st, err := bootstrap.OpenStorage(ctx, cfg)
 	if err != nil {
-		log.Fatalf("db connect: %v", err)
+		log.Fatalf("storage: %v", err)
 	}
 
-	// 5) Router
-	r := bootstrap.BuildRouter(cfg, pool, nil)
+	// 5) Router + scheduler job nền
+	app, err := bootstrap.Build(cfg, st, lg)
+	if err != nil {
+		log.Fatalf("build: %v", err)
+	}
 
-	// 7) Run HTTP (graceful)
-	if err := bootstrap.RunHTTP(r, cfg.Port); err != nil {
-		log.Fatalf("http: %v", err)
+	// 6) Lifecycle: khởi động theo thứ tự dưới, dừng theo thứ tự ngược lại
+	//    (readiness down -> HTTP drain -> config watcher -> scheduler drain -> export drain -> đóng pool -> flush telemetry)
+	lc := bootstrap.NewLifecycle(bootstrap.LifecycleOptions{ShutdownDelay: cfg.ShutdownDelay, Logger: lg.Logger})
+	app.Health.Register(lc.HealthCheck())
+	lc.Add(
+		bootstrap.TelemetryComponent(tr, mt),
+		bootstrap.StorageComponent(st),
+		app.ExportsComponent(),   // job export nền chạy tiếp sau request
+		app.SchedulerComponent(), // chỉ replica leader chạy job
+		app.ConfigWatcherComponent(),
+		bootstrap.HTTPComponent(app.Router, cfg.Port, cfg.HTTPDrain, lg.Logger),
+	)
+	if err := lc.Run(ctx); err != nil {
+		log.Fatalf("%v", err)
 	}
 }
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"wh-ma/internal/adapter/inbound/http/response"
+)
+
+func (a *app) runAlerts(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("alerts: expected list|resolve")
+	}
+	c, err := a.api(ctx)
+	if err != nil {
+		return err
+	}
+	switch args[0] {
+	case "list":
+		return a.alertList(ctx, c, args[1:])
+	case "resolve":
+		return a.alertResolve(ctx, c, args[1:])
+	default:
+		return fmt.Errorf("alerts: unknown subcommand %q", args[0])
+	}
+}
+
+// list -device: cảnh báo chưa xử lý của một device
+func (a *app) alertList(ctx context.Context, c *apiClient, args []string) error {
+	fs := flag.NewFlagSet("alerts list", flag.ContinueOnError)
+	device := fs.Int64("device", 0, "id device (bắt buộc)")
+	limit := fs.Int("limit", 50, "số dòng tối đa")
+	offset := fs.Int("offset", 0, "bỏ qua n dòng đầu")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *device <= 0 {
+		return errors.New("-device is required")
+	}
+
+	var page response.Page[response.Alert]
+	if err := c.call(ctx, http.MethodGet, devicePath(*device)+"/alerts", pageQuery(*limit, *offset), nil, nil, &page); err != nil {
+		return err
+	}
+	return a.out.print(page, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tTYPE\tCREATED\tMESSAGE")
+		for _, al := range page.Items {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", al.ID, al.Type, al.CreatedAt, al.Message)
+		}
+	})
+}
+
+func (a *app) alertResolve(ctx context.Context, c *apiClient, args []string) error {
+	ids, err := idArgs(args)
+	if err != nil {
+		return err
+	}
+	var rep bulkReport
+	for _, id := range ids {
+		err := c.call(ctx, http.MethodPost, "/alerts/"+strconv.FormatInt(id, 10)+"/resolve", nil, nil, nil, nil)
+		rep.add(strconv.FormatInt(id, 10), id, err)
+	}
+	return a.out.printBulk(&rep)
+}
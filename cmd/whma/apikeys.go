@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"wh-ma/internal/adapter/inbound/http/request"
+	"wh-ma/internal/adapter/inbound/http/response"
+)
+
+func (a *app) runAPIKey(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("apikey: expected create|list|revoke")
+	}
+	c, err := a.api(ctx)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "create":
+		return a.apiKeyCreate(ctx, c, args[1:])
+	case "list":
+		return a.apiKeyList(ctx, c, args[1:])
+	case "revoke":
+		return a.apiKeyRevoke(ctx, c, args[1:])
+	default:
+		return fmt.Errorf("apikey: unknown subcommand %q", args[0])
+	}
+}
+
+func (a *app) apiKeyCreate(ctx context.Context, c *apiClient, args []string) error {
+	fs := flag.NewFlagSet("apikey create", flag.ContinueOnError)
+	name := fs.String("name", "", "tên key (bắt buộc)")
+	scopes := fs.String("scopes", "", "danh sách scope, phân cách bằng dấu phẩy (vd readings:write)")
+	devices := fs.String("devices", "", "giới hạn device id, phân cách bằng dấu phẩy")
+	sites := fs.String("sites", "", "giới hạn site, phân cách bằng dấu phẩy")
+	expires := fs.Duration("expires", 0, "thời hạn (vd 720h); 0 = không hết hạn")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	in := request.CreateAPIKey{
+		Name:   *name,
+		Scopes: splitList(*scopes),
+		Sites:  splitList(*sites),
+	}
+	for _, s := range splitList(*devices) {
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid device id %q", s)
+		}
+		in.DeviceIDs = append(in.DeviceIDs, id)
+	}
+	if *expires > 0 {
+		t := time.Now().Add(*expires)
+		in.ExpiresAt = &t
+	}
+
+	var issued response.IssuedAPIKey
+	if err := c.call(ctx, http.MethodPost, "/admin/api-keys", nil, in, nil, &issued); err != nil {
+		return err
+	}
+	err := a.out.print(issued, func(w io.Writer) {
+		fmt.Fprintf(w, "id:\t%d\nprefix:\t%s\nkey:\t%s\n", issued.ID, issued.Prefix, issued.Key)
+	})
+	fmt.Fprintln(os.Stderr, "lưu key ngay bây giờ: key sẽ không được hiển thị lại")
+	return err
+}
+
+func (a *app) apiKeyList(ctx context.Context, c *apiClient, args []string) error {
+	fs := flag.NewFlagSet("apikey list", flag.ContinueOnError)
+	limit := fs.Int("limit", 50, "số dòng tối đa")
+	offset := fs.Int("offset", 0, "bỏ qua n dòng đầu")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var page response.Page[response.APIKey]
+	if err := c.call(ctx, http.MethodGet, "/admin/api-keys", pageQuery(*limit, *offset), nil, nil, &page); err != nil {
+		return err
+	}
+	return a.out.print(page, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tNAME\tPREFIX\tSCOPES\tEXPIRES\tLAST USED\tREVOKED")
+		for _, k := range page.Items {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				k.ID, k.Name, k.Prefix, strings.Join(k.Scopes, ","),
+				deref(k.ExpiresAt), deref(k.LastUsedAt), deref(k.RevokedAt))
+		}
+	})
+}
+
+func (a *app) apiKeyRevoke(ctx context.Context, c *apiClient, args []string) error {
+	fs := flag.NewFlagSet("apikey revoke", flag.ContinueOnError)
+	id := fs.Int64("id", 0, "id của key (bắt buộc)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *id <= 0 {
+		return errors.New("-id is required")
+	}
+
+	var k response.APIKey
+	if err := c.call(ctx, http.MethodDelete, "/admin/api-keys/"+strconv.FormatInt(*id, 10), nil, nil, nil, &k); err != nil {
+		return err
+	}
+	return a.out.print(k, func(w io.Writer) {
+		fmt.Fprintf(w, "revoked %s (%s)\n", k.Prefix, k.Name)
+	})
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"wh-ma/internal/adapter/inbound/http/openapi"
+	"wh-ma/internal/adapter/inbound/http/problem"
+	"wh-ma/internal/adapter/outbound/traced"
+	"wh-ma/internal/bootstrap"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/authz"
+)
+
+type connOptions struct {
+	Tenant    string
+	Server    string
+	APIKey    string
+	Token     string
+	Principal domain.Principal // chỉ dùng ở chế độ trực tiếp
+}
+
+// apiClient: gọi /api/v1 qua http.Client; chế độ trực tiếp thay transport bằng router in-process
+type apiClient struct {
+	base  string
+	http  *http.Client
+	auth  func(*http.Request)
+	close func()
+}
+
+func newClient(ctx context.Context, o connOptions) (*apiClient, error) {
+	if o.Server != "" {
+		if o.Tenant != "" {
+			return nil, errors.New("-tenant only applies to direct DB access; with -server the tenant comes from the credential")
+		}
+		c := &apiClient{
+			base:  strings.TrimRight(o.Server, "/") + openapi.V1Prefix,
+			http:  &http.Client{Timeout: 30 * time.Second, Transport: traced.Transport{}}, // traceparent nếu ctx có span
+			auth:  func(*http.Request) {},
+			close: func() {},
+		}
+		switch {
+		case o.APIKey != "":
+			c.auth = func(r *http.Request) { r.Header.Set("X-API-Key", o.APIKey) }
+		case o.Token != "":
+			c.auth = func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+o.Token) }
+		}
+		return c, nil
+	}
+
+	// Trực tiếp: cùng router/handler/usecase với server, không qua mạng, không cần credential
+	cfg := bootstrap.LoadConfig()
+	cfg.AutoMigrate = false // CLI không tự đổi schema; dùng "whma migrate"
+	gin.SetMode(gin.ReleaseMode)
+	st, err := bootstrap.OpenStorage(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	quiet := slog.New(slog.NewTextHandler(io.Discard, nil))
+	r, err := bootstrap.BuildRouter(cfg, st, quiet)
+	if err != nil {
+		st.Close()
+		return nil, err
+	}
+	return &apiClient{
+		base:  "http://whma" + openapi.V1Prefix,
+		http:  &http.Client{Transport: inProcess{h: r, principal: o.Principal}},
+		auth:  func(*http.Request) {},
+		close: st.Close,
+	}, nil
+}
+
+func (c *apiClient) Close() { c.close() }
+
+// inProcess: RoundTripper phục vụ request bằng router trong process.
+// Không gửi header xác thực -> middleware Authenticate giữ nguyên principal đã gắn vào context.
+// Body đi qua pipe: response lớn (export) được đọc dần, không gom cả vào bộ nhớ.
+type inProcess struct {
+	h         http.Handler
+	principal domain.Principal
+}
+
+func (t inProcess) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.WithContext(authz.WithPrincipal(req.Context(), t.principal))
+	w := newPipeResponse(req)
+	go func() {
+		defer w.finish()
+		t.h.ServeHTTP(w, req)
+	}()
+	<-w.ready
+	return w.resp, nil
+}
+
+// pipeResponse: http.ResponseWriter trả *http.Response ngay khi handler gửi header;
+// body đọc từ pipe, trailer (vd X-Export-Error) có sau khi đọc hết body như HTTP thật.
+type pipeResponse struct {
+	header http.Header
+	resp   *http.Response
+	pw     *io.PipeWriter
+	ready  chan struct{}
+	once   sync.Once
+}
+
+func newPipeResponse(req *http.Request) *pipeResponse {
+	pr, pw := io.Pipe()
+	return &pipeResponse{
+		header: http.Header{},
+		resp:   &http.Response{Proto: "HTTP/1.1", ProtoMajor: 1, ProtoMinor: 1, Body: pr, ContentLength: -1, Request: req},
+		pw:     pw,
+		ready:  make(chan struct{}),
+	}
+}
+
+func (w *pipeResponse) Header() http.Header { return w.header }
+
+func (w *pipeResponse) WriteHeader(code int) {
+	w.once.Do(func() {
+		w.resp.StatusCode = code
+		w.resp.Status = fmt.Sprintf("%d %s", code, http.StatusText(code))
+		w.resp.Header = w.header.Clone()
+		w.resp.Header.Del("Trailer")
+		for _, v := range w.header.Values("Trailer") {
+			for _, k := range strings.Split(v, ",") {
+				if w.resp.Trailer == nil {
+					w.resp.Trailer = http.Header{}
+				}
+				w.resp.Trailer[http.CanonicalHeaderKey(strings.TrimSpace(k))] = nil
+			}
+		}
+		close(w.ready)
+	})
+}
+
+func (w *pipeResponse) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.pw.Write(b)
+}
+
+func (w *pipeResponse) Flush() {}
+
+// finish: handler đã xong -> điền trailer rồi đóng pipe (reader thấy EOF sau khi trailer sẵn sàng)
+func (w *pipeResponse) finish() {
+	w.WriteHeader(http.StatusOK)
+	for k := range w.resp.Trailer {
+		w.resp.Trailer[k] = w.header.Values(k)
+	}
+	w.pw.Close()
+}
+
+// call: gửi body (JSON) và decode response vào out (nil = bỏ qua body).
+// Lỗi HTTP trả về *apiError đọc từ problem+json.
+func (c *apiClient) call(ctx context.Context, method, path string, query url.Values, body any, header map[string]string, out any) error {
+	u := c.base + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	var rd io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		rd = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, rd)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	c.auth(req)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return readAPIError(resp)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// importForm: cờ chung của các lệnh import -> query (dry_run) + field multipart (mapping JSON, sheet).
+// mapping dạng "field=Cột,field2=Cột 2".
+func importForm(mapping, sheet string, dryRun bool) (url.Values, map[string]string, error) {
+	fields := map[string]string{"sheet": sheet}
+	if mapping != "" {
+		m := map[string]string{}
+		for _, kv := range splitList(mapping) {
+			field, col, ok := strings.Cut(kv, "=")
+			if !ok {
+				return nil, nil, fmt.Errorf("invalid -map entry %q (want field=Column)", kv)
+			}
+			m[strings.TrimSpace(field)] = strings.TrimSpace(col)
+		}
+		b, _ := json.Marshal(m)
+		fields["mapping"] = string(b)
+	}
+	query := url.Values{}
+	if dryRun {
+		query.Set("dry_run", "true")
+	}
+	return query, fields, nil
+}
+
+// upload: gửi multipart/form-data (file + field text).
+// Endpoint trả báo cáo cả khi từ chối (422 application/json): vẫn decode vào out, trả status để caller quyết.
+func (c *apiClient) upload(ctx context.Context, path string, query url.Values, file string, fields map[string]string, out any) (int, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for k, v := range fields {
+		if v == "" {
+			continue
+		}
+		if err := mw.WriteField(k, v); err != nil {
+			return 0, err
+		}
+	}
+	part, err := mw.CreateFormFile("file", filepath.Base(file))
+	if err != nil {
+		return 0, err
+	}
+	if _, err := io.Copy(part, f); err != nil {
+		return 0, err
+	}
+	if err := mw.Close(); err != nil {
+		return 0, err
+	}
+
+	u := c.base + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, &buf)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.Header.Set("Accept", "application/json")
+	c.auth(req)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	report := resp.StatusCode == http.StatusUnprocessableEntity &&
+		strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json")
+	if resp.StatusCode >= 300 && !report {
+		return resp.StatusCode, readAPIError(resp)
+	}
+	return resp.StatusCode, json.NewDecoder(resp.Body).Decode(out)
+}
+
+// apiError: problem+json từ server
+type apiError struct {
+	problem.Problem
+}
+
+func (e *apiError) Error() string {
+	if len(e.Errors) == 0 {
+		return e.Code + ": " + e.Detail
+	}
+	fields := make([]string, 0, len(e.Errors))
+	for _, f := range e.Errors {
+		fields = append(fields, f.Field+" "+f.Message)
+	}
+	return e.Code + ": " + strings.Join(fields, "; ")
+}
+
+func readAPIError(resp *http.Response) error {
+	var e apiError
+	b, _ := io.ReadAll(resp.Body)
+	if err := json.Unmarshal(b, &e.Problem); err != nil || e.Code == "" {
+		e.Status = resp.StatusCode
+		e.Code = strings.ToLower(strings.ReplaceAll(http.StatusText(resp.StatusCode), " ", "_"))
+		e.Detail = strings.TrimSpace(string(b))
+	}
+	return &e
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"wh-ma/internal/adapter/inbound/http/handler"
+	"wh-ma/internal/adapter/inbound/http/request"
+	"wh-ma/internal/adapter/inbound/http/response"
+)
+
+func (a *app) runDevices(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("devices: expected list|show|create|import|status|plan")
+	}
+	c, err := a.api(ctx)
+	if err != nil {
+		return err
+	}
+	switch args[0] {
+	case "list":
+		return a.deviceList(ctx, c, args[1:])
+	case "show":
+		return a.deviceShow(ctx, c, args[1:])
+	case "create":
+		return a.deviceCreate(ctx, c, args[1:])
+	case "import":
+		return a.deviceImport(ctx, c, args[1:])
+	case "status":
+		return a.deviceStatus(ctx, c, args[1:])
+	case "plan":
+		return a.devicePlan(ctx, c, args[1:])
+	default:
+		return fmt.Errorf("devices: unknown subcommand %q", args[0])
+	}
+}
+
+func (a *app) deviceList(ctx context.Context, c *apiClient, args []string) error {
+	fs := flag.NewFlagSet("devices list", flag.ContinueOnError)
+	limit := fs.Int("limit", 50, "số dòng tối đa")
+	offset := fs.Int("offset", 0, "bỏ qua n dòng đầu")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var page response.Page[response.Device]
+	if err := c.call(ctx, http.MethodGet, "/devices", pageQuery(*limit, *offset), nil, nil, &page); err != nil {
+		return err
+	}
+	return a.out.print(page, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tSERIAL\tNAME\tSTATUS\tPLAN\tLOCATION\tHOURS\tNEXT MAINT")
+		for _, d := range page.Items {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%d\t%s\n",
+				d.ID, d.SerialNumber, d.Name, d.Status, deref(d.PlanID), deref(d.Location),
+				d.TotalHours, deref(d.ExpectedNextMaint))
+		}
+	})
+}
+
+func (a *app) deviceShow(ctx context.Context, c *apiClient, args []string) error {
+	ids, err := idArgs(args)
+	if err != nil {
+		return err
+	}
+	if len(ids) != 1 {
+		return errors.New("devices show: expected exactly one id")
+	}
+	var d response.Device
+	if err := c.call(ctx, http.MethodGet, devicePath(ids[0]), nil, nil, nil, &d); err != nil {
+		return err
+	}
+	return a.out.print(d, func(w io.Writer) { printDevice(w, d) })
+}
+
+func (a *app) deviceCreate(ctx context.Context, c *apiClient, args []string) error {
+	fs := flag.NewFlagSet("devices create", flag.ContinueOnError)
+	serial := fs.String("serial", "", "số serial (bắt buộc, duy nhất trong tenant)")
+	name := fs.String("name", "", "tên máy (bắt buộc)")
+	model := fs.String("model", "", "model")
+	maker := fs.String("manufacturer", "", "hãng sản xuất")
+	year := fs.Int("year", 0, "năm sản xuất (bắt buộc, >= 1970)")
+	commissioned := fs.String("commissioned", "", "ngày đưa vào sử dụng (YYYY-MM-DD)")
+	status := fs.String("status", "active", "trạng thái ban đầu")
+	location := fs.String("location", "", "site/vị trí")
+	plan := fs.Int64("plan", 0, "id kế hoạch bảo dưỡng")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	in := request.CreateDevice{
+		SerialNumber: *serial,
+		Name:         *name,
+		Model:        *model,
+		Manufacturer: *maker,
+		Year:         *year,
+		Status:       *status,
+		Location:     optString(*location),
+	}
+	if *commissioned != "" {
+		t, err := parseTime(*commissioned)
+		if err != nil {
+			return err
+		}
+		in.CommissionDate = &t
+	}
+	if *plan > 0 {
+		in.PlanID = plan
+	}
+
+	var d response.Device
+	if err := c.call(ctx, http.MethodPost, "/devices", nil, in, nil, &d); err != nil {
+		return err
+	}
+	return a.out.print(d, func(w io.Writer) { printDevice(w, d) })
+}
+
+// status <status> <id>...: merge patch từng device, If-Match * (CLI chủ động ghi đè)
+func (a *app) deviceStatus(ctx context.Context, c *apiClient, args []string) error {
+	if len(args) < 2 {
+		return errors.New("devices status: expected <status> <id>... (or - to read ids from stdin)")
+	}
+	ids, err := idArgs(args[1:])
+	if err != nil {
+		return err
+	}
+	body := map[string]string{"status": args[0]}
+	hdr := map[string]string{"Content-Type": handler.MergePatchContentType, "If-Match": "*"}
+
+	var rep bulkReport
+	for _, id := range ids {
+		err := c.call(ctx, http.MethodPatch, devicePath(id), nil, body, hdr, nil)
+		rep.add(strconv.FormatInt(id, 10), id, err)
+	}
+	return a.out.printBulk(&rep)
+}
+
+// plan <plan-id|none> <id>...: gắn/gỡ kế hoạch bảo dưỡng cho nhiều device
+func (a *app) devicePlan(ctx context.Context, c *apiClient, args []string) error {
+	if len(args) < 2 {
+		return errors.New("devices plan: expected <plan-id|none> <id>... (or - to read ids from stdin)")
+	}
+	var in request.UpdatePlan
+	if args[0] != "none" {
+		planID, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil || planID <= 0 {
+			return fmt.Errorf("devices plan: invalid plan id %q", args[0])
+		}
+		in.PlanID = &planID
+	}
+	ids, err := idArgs(args[1:])
+	if err != nil {
+		return err
+	}
+	hdr := map[string]string{"If-Match": "*"}
+
+	var rep bulkReport
+	for _, id := range ids {
+		err := c.call(ctx, http.MethodPatch, devicePath(id)+"/plan", nil, in, hdr, nil)
+		rep.add(strconv.FormatInt(id, 10), id, err)
+	}
+	return a.out.printBulk(&rep)
+}
+
+// deviceImport: gửi nguyên file CSV/XLSX lên endpoint import; server kiểm, tra plan, ghi tất cả-hoặc-không
+func (a *app) deviceImport(ctx context.Context, c *apiClient, args []string) error {
+	fs := flag.NewFlagSet("devices import", flag.ContinueOnError)
+	file := fs.String("file", "", "file .csv hoặc .xlsx, dòng đầu là header (bắt buộc)")
+	mapping := fs.String("map", "", `ánh xạ field=Cột, vd "serial_number=Số serial,plan=Kế hoạch"`)
+	sheet := fs.String("sheet", "", "sheet của file XLSX (mặc định sheet đầu tiên)")
+	dryRun := fs.Bool("dry-run", false, "chỉ kiểm và in báo cáo, không ghi")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return errors.New("devices import: -file is required")
+	}
+	query, fields, err := importForm(*mapping, *sheet, *dryRun)
+	if err != nil {
+		return fmt.Errorf("devices import: %w", err)
+	}
+
+	var rep response.DeviceImportReport
+	if _, err := c.upload(ctx, "/devices/import", query, *file, fields, &rep); err != nil {
+		return err
+	}
+	err = a.out.print(rep, func(w io.Writer) {
+		fmt.Fprintln(w, "LINE\tSERIAL\tRESULT\tID\tERROR")
+		for _, r := range rep.Rows {
+			result, id := "ok", int64(0)
+			if r.Device != nil {
+				result, id = "created", r.Device.ID
+			}
+			msgs := make([]string, 0, len(r.Errors))
+			for _, e := range r.Errors {
+				msgs = append(msgs, strings.TrimSpace(e.Field+" "+e.Message))
+			}
+			if !r.OK {
+				result = "invalid"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", r.Line, dash(r.SerialNumber), result, fmtID(id), dash(strings.Join(msgs, "; ")))
+		}
+	})
+	if err != nil {
+		return err
+	}
+	switch {
+	case rep.Invalid > 0 && rep.DryRun:
+		return fmt.Errorf("%d of %d rows invalid", rep.Invalid, rep.Total)
+	case rep.Invalid > 0:
+		return fmt.Errorf("%d of %d rows invalid; nothing was imported", rep.Invalid, rep.Total)
+	case !rep.DryRun:
+		fmt.Fprintf(os.Stderr, "imported %d devices\n", rep.Created)
+	}
+	return nil
+}
+
+func printDevice(w io.Writer, d response.Device) {
+	fmt.Fprintf(w, "id:\t%d\n", d.ID)
+	fmt.Fprintf(w, "serial:\t%s\n", d.SerialNumber)
+	fmt.Fprintf(w, "name:\t%s\n", d.Name)
+	fmt.Fprintf(w, "status:\t%s\n", d.Status)
+	fmt.Fprintf(w, "plan:\t%s\n", deref(d.PlanID))
+	fmt.Fprintf(w, "location:\t%s\n", deref(d.Location))
+	fmt.Fprintf(w, "model:\t%s\n", deref(d.Model))
+	fmt.Fprintf(w, "manufacturer:\t%s\n", deref(d.Manufacturer))
+	fmt.Fprintf(w, "year:\t%s\n", deref(d.Year))
+	fmt.Fprintf(w, "commissioned:\t%s\n", deref(d.CommissionDate))
+	fmt.Fprintf(w, "total hours:\t%d\n", d.TotalHours)
+	fmt.Fprintf(w, "after overhaul:\t%d\n", d.AfterOverhaul)
+	fmt.Fprintf(w, "avg daily hours:\t%.2f\n", d.AvgDailyHours)
+	fmt.Fprintf(w, "last reading:\t%s\n", deref(d.LastReadingAt))
+	fmt.Fprintf(w, "next maintenance:\t%s\n", deref(d.ExpectedNextMaint))
+	fmt.Fprintf(w, "version:\t%d\n", d.Version)
+}
+
+func devicePath(id int64) string {
+	return "/devices/" + strconv.FormatInt(id, 10)
+}
+
+func pageQuery(limit, offset int) url.Values {
+	return url.Values{"limit": {strconv.Itoa(limit)}, "offset": {strconv.Itoa(offset)}}
+}
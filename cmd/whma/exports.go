@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"wh-ma/internal/adapter/inbound/http/openapi"
+	"wh-ma/internal/adapter/inbound/http/request"
+	"wh-ma/internal/adapter/inbound/http/response"
+)
+
+// export devices|readings|maintenance|alerts: tải dữ liệu thô ra file.
+// Mặc định stream thẳng (GET /exports/:kind); -async tạo job nền, chờ xong rồi tải qua link ký.
+func (a *app) runExport(ctx context.Context, args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return errors.New("export: expected devices|readings|maintenance|alerts")
+	}
+	kind := args[0]
+	fs := flag.NewFlagSet("export "+kind, flag.ContinueOnError)
+	format := fs.String("format", "csv", "csv | xlsx | jsonl")
+	from := fs.String("from", "", "từ ngày (YYYY-MM-DD hoặc RFC3339); bỏ trống = từ đầu")
+	to := fs.String("to", "", "tới hết ngày (YYYY-MM-DD) hoặc trước mốc RFC3339; bỏ trống = bây giờ")
+	device := fs.Int64("device", 0, "chỉ một device (readings, maintenance, alerts)")
+	out := fs.String("out", "", `file đích; "-" = stdout, bỏ trống = tên file server gợi ý`)
+	async := fs.Bool("async", false, "chạy job nền trên server rồi tải về (export lớn)")
+	poll := fs.Duration("poll", 2*time.Second, "chu kỳ hỏi trạng thái job khi -async")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	c, err := a.api(ctx)
+	if err != nil {
+		return err
+	}
+
+	var resp *http.Response
+	var rows string
+	if *async {
+		in := request.CreateExportJob{Kind: kind, Format: *format, From: *from, To: *to}
+		if *device > 0 {
+			in.DeviceID = device
+		}
+		job, err := a.exportJob(ctx, c, in, *poll)
+		if err != nil {
+			return err
+		}
+		rows = strconv.FormatInt(job.Rows, 10)
+		link := strings.TrimSuffix(c.base, openapi.V1Prefix) + *job.DownloadURL
+		resp, err = c.stream(ctx, link, nil, false)
+		if err != nil {
+			return err
+		}
+	} else {
+		query := url.Values{"format": {*format}}
+		setIf(query, "from", *from)
+		setIf(query, "to", *to)
+		if *device > 0 {
+			query.Set("device_id", strconv.FormatInt(*device, 10))
+		}
+		resp, err = c.stream(ctx, c.base+"/exports/"+url.PathEscape(kind), query, true)
+		if err != nil {
+			return err
+		}
+	}
+	defer resp.Body.Close()
+
+	path := *out
+	if path == "" {
+		path = suggestedName(resp, kind+"."+*format)
+	}
+	var w io.Writer = os.Stdout
+	var f *os.File
+	if path != "-" {
+		if f, err = os.Create(path); err != nil {
+			return err
+		}
+		w = f
+	}
+	_, err = io.Copy(w, resp.Body)
+	if err == nil {
+		// export đồng bộ: lỗi sau khi đã gửi status 200 chỉ có trong trailer
+		if msg := resp.Trailer.Get("X-Export-Error"); msg != "" {
+			err = fmt.Errorf("export incomplete: %s", msg)
+		}
+	}
+	if f != nil {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(path) // không để lại file thiếu dòng
+			return err
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if rows == "" {
+		rows = resp.Trailer.Get("X-Export-Rows")
+	}
+	if path != "-" {
+		fmt.Fprintf(os.Stderr, "exported %s rows of %s to %s\n", rows, kind, path)
+	}
+	return nil
+}
+
+// exportJob: POST /exports/jobs rồi hỏi trạng thái tới khi done (job có download_url)
+func (a *app) exportJob(ctx context.Context, c *apiClient, in request.CreateExportJob, poll time.Duration) (*response.ExportJob, error) {
+	var job response.ExportJob
+	if err := c.call(ctx, http.MethodPost, "/exports/jobs", nil, in, nil, &job); err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(os.Stderr, "export job %s queued\n", job.ID)
+	t := time.NewTicker(poll)
+	defer t.Stop()
+	for {
+		switch job.Status {
+		case "done":
+			if job.DownloadURL == nil {
+				return nil, fmt.Errorf("export job %s: finished without a download link", job.ID)
+			}
+			fmt.Fprintf(os.Stderr, "export job %s done, link valid until %s\n", job.ID, job.ExpiresAt)
+			return &job, nil
+		case "failed":
+			return nil, fmt.Errorf("export job %s failed: %s", job.ID, deref(job.Error))
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("export job %s still %s on the server: %w", job.ID, job.Status, ctx.Err())
+		case <-t.C:
+		}
+		if err := c.call(ctx, http.MethodGet, "/exports/jobs/"+job.ID, nil, nil, nil, &job); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// stream: GET trả body để caller đọc dần; không áp timeout tổng của client (export có thể lâu).
+// Link tải của job đã ký sẵn nên không gửi credential (auth = false).
+func (c *apiClient) stream(ctx context.Context, u string, query url.Values, auth bool) (*http.Response, error) {
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if auth {
+		c.auth(req)
+	}
+	hc := *c.http
+	hc.Timeout = 0
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		return nil, readAPIError(resp)
+	}
+	return resp, nil
+}
+
+// suggestedName: tên file từ Content-Disposition (chỉ lấy phần tên, bỏ đường dẫn)
+func suggestedName(resp *http.Response, fallback string) string {
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		if name := filepath.Base(params["filename"]); name != "." && name != "/" && name != "" {
+			return name
+		}
+	}
+	return fallback
+}
+
+func setIf(q url.Values, key, val string) {
+	if val != "" {
+		q.Set(key, val)
+	}
+}
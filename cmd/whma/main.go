@@ -0,0 +1,157 @@
+// whma: CLI quản trị đội máy, dùng chung hợp đồng /api/v1 với server.
+//
+// Mặc định CLI truy cập DB trực tiếp: router của server chạy trong process trên DATABASE_URL
+// (configs/.env), quyền admin với subject "cli:<user>", -tenant chọn tenant (bỏ trống = cấp hệ thống).
+// Với -server, CLI gọi API từ xa bằng -api-key hoặc -token (tenant/quyền theo credential).
+// Hai chế độ đi qua cùng handler, usecase, validate và cho ra cùng JSON.
+//
+//	whma [-tenant acme] [-server url] [-api-key k | -token jwt] [-o table|json] <command> ...
+//	whma devices list | show <id> | create -serial S -name N ... | status <status> <id>... | plan <plan-id|none> <id>...
+//	whma devices import -file site.xlsx [-map "serial_number=Số serial,plan=Kế hoạch"] [-sheet S] [-dry-run]
+//	whma readings list -device 1 | add -device 1 -hours 8 | import -file logbook.xlsx [-map at=Ngày,meter=Đồng hồ] [-dry-run]
+//	whma plans list | show <id> | create -name N -interval 250 | delete <id>...
+//	whma alerts list -device 1 | resolve <id>...
+//	whma export devices|readings|maintenance|alerts [-format csv|xlsx|jsonl] [-from 2024-01-01] [-to 2024-03-31] [-device 1] [-out f] [-async]
+//	whma apikey create -name gw-01 -scopes readings:write -devices 1,2 [-sites HN] [-expires 720h]
+//	whma apikey list [-limit 50] [-offset 0]
+//	whma apikey revoke -id 3
+//	whma migrate up | down [n|-all] | to <version> | force <version> | status
+//	whma [-tenant acme] backup [-site HN] [-out fleet.zip]
+//	whma [-tenant acme] restore -file fleet.zip [-site HN] [-into staging] [-dry-run]
+//
+// Lệnh nhận nhiều id (status, plan, resolve, delete) đọc id từ stdin khi tham số là "-":
+//
+//	whma -o json devices list -limit 500 | jq '.items[] | select(.location=="HN") | .id' | whma devices status repair -
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"os/user"
+	"syscall"
+
+	"wh-ma/internal/domain"
+)
+
+const usage = `usage: whma [global flags] <command> [flags]
+
+global flags:
+  -tenant id        tenant thao tác ở chế độ trực tiếp (RLS); rỗng = cấp hệ thống
+  -server url       gọi API từ xa thay vì truy cập DB (env WHMA_SERVER)
+  -api-key key      API key khi dùng -server (env WHMA_API_KEY)
+  -token jwt        Bearer token khi dùng -server (env WHMA_TOKEN)
+  -o table|json     định dạng output (mặc định table)
+
+commands:
+  devices list|show|create|import|status|plan
+  readings list|add|import
+  plans list|show|create|delete
+  alerts list|resolve
+  export devices|readings|maintenance|alerts
+                              xuất dữ liệu thô (csv|xlsx|jsonl); -async chạy job nền cho export lớn
+  apikey create|list|revoke   quản lý API key cho client máy
+  migrate up|down|to|force|status
+                              migration schema nhúng trong binary (luôn truy cập DB trực tiếp)
+  backup [-site S] [-out f.zip]
+                              snapshot nhất quán plans/devices/readings/maintenance/alerts ra archive
+                              (-tenant giới hạn phạm vi; luôn truy cập DB trực tiếp; user DB cần
+                              là member của role whma_snapshot: GRANT whma_snapshot TO <user>)
+  restore -file f.zip [-site S] [-into tenant] [-dry-run]
+                              ghi archive vào database cùng version schema, giữ nguyên id
+`
+
+// app: trạng thái dùng chung của một lần chạy CLI
+type app struct {
+	conn   connOptions
+	out    printer
+	client *apiClient // mở lười: migrate/help không cần
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fs := flag.NewFlagSet("whma", flag.ExitOnError)
+	fs.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	var a app
+	fs.StringVar(&a.conn.Tenant, "tenant", "", "tenant thao tác (RLS); rỗng = cấp hệ thống")
+	fs.StringVar(&a.conn.Server, "server", os.Getenv("WHMA_SERVER"), "URL API; rỗng = truy cập DB trực tiếp")
+	fs.StringVar(&a.conn.APIKey, "api-key", os.Getenv("WHMA_API_KEY"), "API key khi dùng -server")
+	fs.StringVar(&a.conn.Token, "token", os.Getenv("WHMA_TOKEN"), "Bearer token khi dùng -server")
+	format := fs.String("o", "table", "table | json")
+	_ = fs.Parse(os.Args[1:])
+	args := fs.Args()
+	if len(args) == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *format != "table" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "whma: -o must be table or json\n")
+		os.Exit(2)
+	}
+	a.out = printer{json: *format == "json"}
+
+	// CLI trực tiếp chạy với quyền admin; subject "cli:<user>" để audit biết ai thao tác
+	a.conn.Principal = domain.Principal{
+		Subject:  "cli:" + osUser(),
+		TenantID: a.conn.Tenant,
+		Role:     domain.RoleAdmin,
+	}
+
+	var err error
+	switch args[0] {
+	case "devices", "device":
+		err = a.runDevices(ctx, args[1:])
+	case "readings", "reading":
+		err = a.runReadings(ctx, args[1:])
+	case "plans", "plan":
+		err = a.runPlans(ctx, args[1:])
+	case "alerts", "alert":
+		err = a.runAlerts(ctx, args[1:])
+	case "export":
+		err = a.runExport(ctx, args[1:])
+	case "apikey":
+		err = a.runAPIKey(ctx, args[1:])
+	case "migrate":
+		err = runMigrate(args[1:])
+	case "backup":
+		err = a.runBackup(ctx, args[1:])
+	case "restore":
+		err = a.runRestore(ctx, args[1:])
+	case "help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", args[0], usage)
+		os.Exit(2)
+	}
+	if a.client != nil {
+		a.client.Close()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "whma: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// api: mở kết nối (DB trực tiếp hoặc server) ở lần đầu cần dùng
+func (a *app) api(ctx context.Context) (*apiClient, error) {
+	if a.client == nil {
+		c, err := newClient(ctx, a.conn)
+		if err != nil {
+			return nil, err
+		}
+		a.client = c
+	}
+	return a.client, nil
+}
+
+func osUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return "unknown"
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"wh-ma/internal/adapter/outbound/migration"
+	"wh-ma/internal/bootstrap"
+)
+
+// whma migrate: migration nhúng trong binary, không cần cài CLI migrate riêng
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return errors.New("migrate: expected up|down|status|to|force")
+	}
+
+	cfg := bootstrap.LoadConfig()
+	m, err := migration.New(cfg.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	switch args[0] {
+	case "up":
+		err = m.Up()
+	case "down":
+		err = migrateDown(m, args[1:])
+	case "to":
+		var v uint64
+		if v, err = versionArg("to", args[1:]); err == nil {
+			err = m.To(uint(v))
+		}
+	case "force":
+		var v uint64
+		if v, err = versionArg("force", args[1:]); err == nil {
+			err = m.Force(int(v))
+		}
+	case "status":
+		// chỉ in trạng thái ở cuối
+	default:
+		return fmt.Errorf("migrate: unknown subcommand %q", args[0])
+	}
+	if err != nil {
+		return err
+	}
+	return printMigrateStatus(m)
+}
+
+// down [n] | down -all (mặc định lùi 1 bước cho an toàn)
+func migrateDown(m *migration.Migrator, args []string) error {
+	fs := flag.NewFlagSet("migrate down", flag.ContinueOnError)
+	all := fs.Bool("all", false, "lùi toàn bộ migration (xóa sạch schema)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *all {
+		return m.Down(0)
+	}
+	n := 1
+	if fs.NArg() > 0 {
+		v, err := strconv.Atoi(fs.Arg(0))
+		if err != nil || v < 1 {
+			return fmt.Errorf("migrate down: invalid step count %q", fs.Arg(0))
+		}
+		n = v
+	}
+	return m.Down(n)
+}
+
+func versionArg(cmd string, args []string) (uint64, error) {
+	if len(args) != 1 {
+		return 0, fmt.Errorf("migrate %s: expected exactly one version", cmd)
+	}
+	v, err := strconv.ParseUint(args[0], 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("migrate %s: invalid version %q", cmd, args[0])
+	}
+	return v, nil
+}
+
+func printMigrateStatus(m *migration.Migrator) error {
+	st, err := m.Status()
+	if err != nil {
+		return err
+	}
+	pending := "-"
+	if len(st.Pending) > 0 {
+		vs := make([]string, 0, len(st.Pending))
+		for _, v := range st.Pending {
+			vs = append(vs, strconv.FormatUint(uint64(v), 10))
+		}
+		pending = strings.Join(vs, ",")
+	}
+	fmt.Printf("version:  %d\nexpected: %d\ndirty:    %t\npending:  %s\n",
+		st.Version, st.Expected, st.Dirty, pending)
+	return nil
+}
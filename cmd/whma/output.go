@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// printer: -o json in nguyên response API (dễ pipe sang jq), -o table in bảng cho người đọc
+type printer struct {
+	json bool
+}
+
+func (p printer) print(v any, table func(w io.Writer)) error {
+	if p.json {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	table(tw)
+	return tw.Flush()
+}
+
+// ===== Thao tác hàng loạt: làm hết, báo từng dòng, lỗi thì exit 1 =====
+
+type bulkResult struct {
+	Ref   string `json:"ref"` // id hoặc "line N"
+	OK    bool   `json:"ok"`
+	ID    int64  `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+type bulkReport struct {
+	Total   int          `json:"total"`
+	Failed  int          `json:"failed"`
+	Results []bulkResult `json:"results"`
+}
+
+func (r *bulkReport) add(ref string, id int64, err error) {
+	r.Total++
+	res := bulkResult{Ref: ref, OK: err == nil, ID: id}
+	if err != nil {
+		r.Failed++
+		res.Error = err.Error()
+	}
+	r.Results = append(r.Results, res)
+}
+
+func (p printer) printBulk(r *bulkReport) error {
+	if r.Results == nil {
+		r.Results = []bulkResult{}
+	}
+	err := p.print(r, func(w io.Writer) {
+		fmt.Fprintln(w, "REF\tRESULT\tID\tERROR")
+		for _, x := range r.Results {
+			result := "ok"
+			if !x.OK {
+				result = "failed"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", x.Ref, result, fmtID(x.ID), dash(x.Error))
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if r.Failed > 0 {
+		return fmt.Errorf("%d of %d failed", r.Failed, r.Total)
+	}
+	return nil
+}
+
+// ===== helpers =====
+
+// idArgs: id từ tham số; "-" = đọc từ stdin (cách nhau bởi khoảng trắng/xuống dòng)
+func idArgs(args []string) ([]int64, error) {
+	if len(args) == 1 && args[0] == "-" {
+		args = nil
+		sc := bufio.NewScanner(os.Stdin)
+		sc.Split(bufio.ScanWords)
+		for sc.Scan() {
+			args = append(args, sc.Text())
+		}
+		if err := sc.Err(); err != nil {
+			return nil, err
+		}
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("expected at least one id (or - to read ids from stdin)")
+	}
+	ids := make([]int64, 0, len(args))
+	for _, s := range args {
+		id, err := strconv.ParseInt(strings.Trim(s, `",`), 10, 64)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid id %q", s)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// parseTime: RFC3339 hoặc YYYY-MM-DD (UTC)
+func parseTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q (want RFC3339 or YYYY-MM-DD)", s)
+	}
+	return t, nil
+}
+
+func optString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func deref[T any](p *T) string {
+	if p == nil {
+		return "-"
+	}
+	return dash(fmt.Sprint(*p))
+}
+
+func fmtID(id int64) string {
+	if id == 0 {
+		return "-"
+	}
+	return strconv.FormatInt(id, 10)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"wh-ma/internal/adapter/inbound/http/request"
+	"wh-ma/internal/adapter/inbound/http/response"
+)
+
+func (a *app) runPlans(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("plans: expected list|show|create|delete")
+	}
+	c, err := a.api(ctx)
+	if err != nil {
+		return err
+	}
+	switch args[0] {
+	case "list":
+		return a.planList(ctx, c, args[1:])
+	case "show":
+		return a.planShow(ctx, c, args[1:])
+	case "create":
+		return a.planCreate(ctx, c, args[1:])
+	case "delete":
+		return a.planDelete(ctx, c, args[1:])
+	default:
+		return fmt.Errorf("plans: unknown subcommand %q", args[0])
+	}
+}
+
+func (a *app) planList(ctx context.Context, c *apiClient, args []string) error {
+	fs := flag.NewFlagSet("plans list", flag.ContinueOnError)
+	limit := fs.Int("limit", 50, "số dòng tối đa")
+	offset := fs.Int("offset", 0, "bỏ qua n dòng đầu")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var page response.Page[response.Plan]
+	if err := c.call(ctx, http.MethodGet, "/plans", pageQuery(*limit, *offset), nil, nil, &page); err != nil {
+		return err
+	}
+	return a.out.print(page, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tNAME\tINTERVAL\tSHARED\tDESCRIPTION")
+		for _, p := range page.Items {
+			fmt.Fprintf(w, "%d\t%s\t%dh\t%t\t%s\n", p.ID, p.Name, p.IntervalHours, p.Shared, deref(p.Description))
+		}
+	})
+}
+
+func (a *app) planShow(ctx context.Context, c *apiClient, args []string) error {
+	ids, err := idArgs(args)
+	if err != nil {
+		return err
+	}
+	if len(ids) != 1 {
+		return errors.New("plans show: expected exactly one id")
+	}
+	var p response.Plan
+	if err := c.call(ctx, http.MethodGet, planPath(ids[0]), nil, nil, nil, &p); err != nil {
+		return err
+	}
+	return a.out.print(p, func(w io.Writer) { printPlan(w, p) })
+}
+
+func (a *app) planCreate(ctx context.Context, c *apiClient, args []string) error {
+	fs := flag.NewFlagSet("plans create", flag.ContinueOnError)
+	name := fs.String("name", "", "tên kế hoạch (bắt buộc)")
+	interval := fs.Int("interval", 0, "chu kỳ bảo dưỡng, giờ chạy (bắt buộc)")
+	desc := fs.String("description", "", "mô tả")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	in := request.UpsertPlan{Name: *name, IntervalHours: *interval, Description: optString(*desc)}
+	var p response.Plan
+	if err := c.call(ctx, http.MethodPost, "/plans", nil, in, nil, &p); err != nil {
+		return err
+	}
+	return a.out.print(p, func(w io.Writer) { printPlan(w, p) })
+}
+
+func (a *app) planDelete(ctx context.Context, c *apiClient, args []string) error {
+	ids, err := idArgs(args)
+	if err != nil {
+		return err
+	}
+	var rep bulkReport
+	for _, id := range ids {
+		err := c.call(ctx, http.MethodDelete, planPath(id), nil, nil, nil, nil)
+		rep.add(strconv.FormatInt(id, 10), id, err)
+	}
+	return a.out.printBulk(&rep)
+}
+
+func printPlan(w io.Writer, p response.Plan) {
+	fmt.Fprintf(w, "id:\t%d\nname:\t%s\ninterval:\t%dh\nshared:\t%t\ndescription:\t%s\nversion:\t%d\n",
+		p.ID, p.Name, p.IntervalHours, p.Shared, deref(p.Description), p.Version)
+}
+
+func planPath(id int64) string {
+	return "/plans/" + strconv.FormatInt(id, 10)
+}
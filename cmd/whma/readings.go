@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"wh-ma/internal/adapter/inbound/http/request"
+	"wh-ma/internal/adapter/inbound/http/response"
+)
+
+func (a *app) runReadings(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("readings: expected list|add|import")
+	}
+	c, err := a.api(ctx)
+	if err != nil {
+		return err
+	}
+	switch args[0] {
+	case "list":
+		return a.readingList(ctx, c, args[1:])
+	case "add":
+		return a.readingAdd(ctx, c, args[1:])
+	case "import":
+		return a.readingImport(ctx, c, args[1:])
+	default:
+		return fmt.Errorf("readings: unknown subcommand %q", args[0])
+	}
+}
+
+func (a *app) readingList(ctx context.Context, c *apiClient, args []string) error {
+	fs := flag.NewFlagSet("readings list", flag.ContinueOnError)
+	device := fs.Int64("device", 0, "id device (bắt buộc)")
+	limit := fs.Int("limit", 50, "số dòng tối đa")
+	offset := fs.Int("offset", 0, "bỏ qua n dòng đầu")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *device <= 0 {
+		return errors.New("-device is required")
+	}
+
+	var page response.Page[response.Reading]
+	if err := c.call(ctx, http.MethodGet, devicePath(*device)+"/readings", pageQuery(*limit, *offset), nil, nil, &page); err != nil {
+		return err
+	}
+	return a.out.print(page, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tAT\tHOURS\tLOCATION\tOPERATOR")
+		for _, r := range page.Items {
+			fmt.Fprintf(w, "%d\t%s\t%d\t%s\t%s\n", r.ID, r.At, r.HoursDelta, deref(r.Location), deref(r.OperatorID))
+		}
+	})
+}
+
+func (a *app) readingAdd(ctx context.Context, c *apiClient, args []string) error {
+	fs := flag.NewFlagSet("readings add", flag.ContinueOnError)
+	device := fs.Int64("device", 0, "id device (bắt buộc)")
+	hours := fs.Int("hours", -1, "số giờ chạy thêm (bắt buộc)")
+	at := fs.String("at", "", "thời điểm ghi (RFC3339 hoặc YYYY-MM-DD); bỏ trống = bây giờ")
+	location := fs.String("location", "", "site/vị trí")
+	operator := fs.String("operator", "", "mã người vận hành")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *device <= 0 || *hours < 0 {
+		return errors.New("-device and -hours are required")
+	}
+
+	in, err := newReading(strconv.Itoa(*hours), *at, *location, *operator)
+	if err != nil {
+		return err
+	}
+	var r response.Reading
+	if err := c.call(ctx, http.MethodPost, devicePath(*device)+"/readings", nil, in, nil, &r); err != nil {
+		return err
+	}
+	return a.out.print(r, func(w io.Writer) {
+		fmt.Fprintf(w, "id:\t%d\ndevice:\t%d\nat:\t%s\nhours:\t%d\n", r.ID, r.DeviceID, r.At, r.HoursDelta)
+	})
+}
+
+// import -file logbook.xlsx: nhập lịch sử giờ máy qua POST /readings/import (tất cả-hoặc-không).
+// Mỗi dòng: device_id hoặc serial_number, at, hours_delta hoặc meter; tùy chọn location, operator_id.
+func (a *app) readingImport(ctx context.Context, c *apiClient, args []string) error {
+	fs := flag.NewFlagSet("readings import", flag.ContinueOnError)
+	file := fs.String("file", "", "file .csv hoặc .xlsx, dòng đầu là header (bắt buộc)")
+	mapping := fs.String("map", "", `ánh xạ field=Cột, vd "serial_number=Số máy,at=Ngày,meter=Đồng hồ"`)
+	sheet := fs.String("sheet", "", "sheet của file XLSX (mặc định sheet đầu tiên)")
+	dryRun := fs.Bool("dry-run", false, "chỉ kiểm và in báo cáo, không ghi")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return errors.New("readings import: -file is required")
+	}
+	query, fields, err := importForm(*mapping, *sheet, *dryRun)
+	if err != nil {
+		return fmt.Errorf("readings import: %w", err)
+	}
+
+	var rep response.ReadingsImportReport
+	if _, err := c.upload(ctx, "/readings/import", query, *file, fields, &rep); err != nil {
+		return err
+	}
+	err = a.out.print(rep, func(w io.Writer) {
+		fmt.Fprintln(w, "LINE\tDEVICE\tAT\tDELTA\tRESULT\tERROR")
+		for _, r := range rep.Rows {
+			result := "ok"
+			switch {
+			case !r.OK:
+				result = "invalid"
+			case r.DuplicateOf != nil:
+				result = fmt.Sprintf("duplicate of %d", *r.DuplicateOf)
+			}
+			msgs := make([]string, 0, len(r.Errors))
+			for _, e := range r.Errors {
+				msgs = append(msgs, strings.TrimSpace(e.Field+" "+e.Message))
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%s\t%s\n", r.Line, deref(r.DeviceID), deref(r.At), r.HoursDelta, result, dash(strings.Join(msgs, "; ")))
+		}
+		if len(rep.Devices) > 0 {
+			fmt.Fprintln(w)
+			fmt.Fprintln(w, "DEVICE\tREADINGS\tADDED\tTOTAL\tAOH\tLAST READING\tNEXT MAINT\tDUE")
+			for _, d := range rep.Devices {
+				fmt.Fprintf(w, "%d\t%d\t%d\t%d\t%d\t%s\t%s\t%t\n", d.DeviceID, d.Readings, d.HoursAdded,
+					d.TotalHours, d.AfterOverhaul, d.LastReadingAt, deref(d.ExpectedNextMaint), d.MaintenanceDue)
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+	switch {
+	case rep.Invalid > 0 && rep.DryRun:
+		return fmt.Errorf("%d of %d rows invalid", rep.Invalid, rep.Total)
+	case rep.Invalid > 0:
+		return fmt.Errorf("%d of %d rows invalid; nothing was imported", rep.Invalid, rep.Total)
+	case !rep.DryRun:
+		fmt.Fprintf(os.Stderr, "imported %d readings (%d duplicates skipped)\n", rep.Created, rep.Duplicates)
+	}
+	return nil
+}
+
+func newReading(hours, at, location, operator string) (request.SubmitReading, error) {
+	h, err := strconv.Atoi(hours)
+	if err != nil {
+		return request.SubmitReading{}, fmt.Errorf("invalid hours_delta %q", hours)
+	}
+	in := request.SubmitReading{
+		HoursDelta: h,
+		Location:   optString(location),
+		OperatorID: optString(operator),
+	}
+	if at != "" {
+		t, err := parseTime(at)
+		if err != nil {
+			return request.SubmitReading{}, err
+		}
+		in.At = &t
+	}
+	return in, nil
+}
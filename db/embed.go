@@ -0,0 +1,9 @@
+// Package db: nhúng migration SQL vào binary (server auto-migrate, whma migrate).
+package db
+
+import "embed"
+
+// Migrations: db/migrations/*.sql theo định dạng golang-migrate (NNNNNN_name.up|down.sql)
+//
+//go:embed migrations/*.sql
+var Migrations embed.FS
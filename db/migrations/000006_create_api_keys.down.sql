@@ -0,0 +1,2 @@
+-- 6_down
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,15 @@
+-- 6_up: create api_keys (khóa cho client máy: gateway đồng hồ giờ, script ETL)
+CREATE TABLE IF NOT EXISTS api_keys (
+  id           BIGSERIAL PRIMARY KEY,
+  name         TEXT NOT NULL,
+  prefix       TEXT NOT NULL UNIQUE,      -- phần đầu của key, dùng để tra cứu/hiển thị
+  secret_hash  TEXT NOT NULL,             -- sha256(key) dạng hex, không lưu key gốc
+  scopes       TEXT[] NOT NULL DEFAULT '{}',
+  device_ids   BIGINT[] NOT NULL DEFAULT '{}', -- rỗng = mọi device
+  sites        TEXT[] NOT NULL DEFAULT '{}',   -- rỗng = mọi site
+  expires_at   TIMESTAMPTZ,
+  last_used_at TIMESTAMPTZ,
+  revoked_at   TIMESTAMPTZ,
+  created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  created_by   TEXT
+);
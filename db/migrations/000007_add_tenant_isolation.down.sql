@@ -0,0 +1,48 @@
+-- 7_down
+DROP POLICY IF EXISTS plans_write ON plans;
+DROP POLICY IF EXISTS plans_read ON plans;
+DROP POLICY IF EXISTS tenant_isolation ON maintenance_events;
+DROP POLICY IF EXISTS tenant_isolation ON alerts;
+DROP POLICY IF EXISTS tenant_isolation ON readings;
+DROP POLICY IF EXISTS tenant_isolation ON devices;
+
+ALTER TABLE plans              NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE plans              DISABLE ROW LEVEL SECURITY;
+ALTER TABLE maintenance_events NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE maintenance_events DISABLE ROW LEVEL SECURITY;
+ALTER TABLE alerts             NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE alerts             DISABLE ROW LEVEL SECURITY;
+ALTER TABLE readings           NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE readings           DISABLE ROW LEVEL SECURITY;
+ALTER TABLE devices            NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE devices            DISABLE ROW LEVEL SECURITY;
+
+DROP INDEX IF EXISTS idx_api_keys_tenant;
+DROP INDEX IF EXISTS idx_plans_tenant;
+
+ALTER TABLE maintenance_events DROP CONSTRAINT IF EXISTS fk_maint_device;
+ALTER TABLE maintenance_events
+  ADD CONSTRAINT fk_maint_device FOREIGN KEY (device_id) REFERENCES devices(id)
+  ON UPDATE CASCADE ON DELETE CASCADE;
+ALTER TABLE alerts DROP CONSTRAINT IF EXISTS fk_alerts_device;
+ALTER TABLE alerts
+  ADD CONSTRAINT fk_alerts_device FOREIGN KEY (device_id) REFERENCES devices(id)
+  ON UPDATE CASCADE ON DELETE CASCADE;
+ALTER TABLE readings DROP CONSTRAINT IF EXISTS fk_readings_device;
+ALTER TABLE readings
+  ADD CONSTRAINT fk_readings_device FOREIGN KEY (device_id) REFERENCES devices(id)
+  ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE devices DROP CONSTRAINT IF EXISTS uq_devices_id_tenant;
+ALTER TABLE devices DROP CONSTRAINT IF EXISTS uq_devices_tenant_serial;
+-- lưu ý: thất bại nếu hai tenant đã dùng trùng serial
+ALTER TABLE devices ADD CONSTRAINT devices_serial_number_key UNIQUE (serial_number);
+
+ALTER TABLE api_keys           DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE plans              DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE maintenance_events DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE alerts             DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE readings           DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE devices            DROP COLUMN IF EXISTS tenant_id;
+
+DROP FUNCTION IF EXISTS app_tenant();
@@ -0,0 +1,85 @@
+-- 7_up: multi-tenant (mỗi nhà thầu = 1 tenant), cô lập bằng Row-Level Security
+-- Connection pool set app.tenant_id từ principal mỗi lần acquire; chưa set (hệ thống/CLI) => NULL.
+
+CREATE OR REPLACE FUNCTION app_tenant() RETURNS TEXT
+LANGUAGE sql STABLE AS $$
+  SELECT NULLIF(current_setting('app.tenant_id', true), '')
+$$;
+
+-- 1) Thêm cột, dữ liệu cũ thuộc tenant 'default'
+ALTER TABLE devices            ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE readings           ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE alerts             ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE maintenance_events ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+-- plans: NULL = template dùng chung cho mọi tenant
+ALTER TABLE plans              ADD COLUMN IF NOT EXISTS tenant_id TEXT DEFAULT 'default';
+-- api_keys: NULL = key cấp hệ thống
+ALTER TABLE api_keys           ADD COLUMN IF NOT EXISTS tenant_id TEXT DEFAULT 'default';
+
+-- 2) Từ giờ tenant_id lấy từ connection (thiếu tenant => NOT NULL chặn insert)
+ALTER TABLE devices            ALTER COLUMN tenant_id SET DEFAULT app_tenant();
+ALTER TABLE readings           ALTER COLUMN tenant_id SET DEFAULT app_tenant();
+ALTER TABLE alerts             ALTER COLUMN tenant_id SET DEFAULT app_tenant();
+ALTER TABLE maintenance_events ALTER COLUMN tenant_id SET DEFAULT app_tenant();
+ALTER TABLE plans              ALTER COLUMN tenant_id SET DEFAULT app_tenant();
+ALTER TABLE api_keys           ALTER COLUMN tenant_id SET DEFAULT app_tenant();
+
+-- 3) Serial duy nhất theo tenant thay vì toàn cục
+ALTER TABLE devices DROP CONSTRAINT IF EXISTS devices_serial_number_key;
+ALTER TABLE devices ADD CONSTRAINT uq_devices_tenant_serial UNIQUE (tenant_id, serial_number);
+
+-- 4) Bảng con trỏ về device cùng tenant (FK kép), không thể gắn nhầm device tenant khác
+ALTER TABLE devices ADD CONSTRAINT uq_devices_id_tenant UNIQUE (id, tenant_id);
+
+ALTER TABLE readings DROP CONSTRAINT IF EXISTS fk_readings_device;
+ALTER TABLE readings
+  ADD CONSTRAINT fk_readings_device
+  FOREIGN KEY (device_id, tenant_id) REFERENCES devices(id, tenant_id)
+  ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE alerts DROP CONSTRAINT IF EXISTS fk_alerts_device;
+ALTER TABLE alerts
+  ADD CONSTRAINT fk_alerts_device
+  FOREIGN KEY (device_id, tenant_id) REFERENCES devices(id, tenant_id)
+  ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE maintenance_events DROP CONSTRAINT IF EXISTS fk_maint_device;
+ALTER TABLE maintenance_events
+  ADD CONSTRAINT fk_maint_device
+  FOREIGN KEY (device_id, tenant_id) REFERENCES devices(id, tenant_id)
+  ON UPDATE CASCADE ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_plans_tenant ON plans(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_api_keys_tenant ON api_keys(tenant_id);
+
+-- 5) Row-Level Security (FORCE: áp dụng cả cho owner của bảng)
+ALTER TABLE devices            ENABLE ROW LEVEL SECURITY;
+ALTER TABLE devices            FORCE ROW LEVEL SECURITY;
+ALTER TABLE readings           ENABLE ROW LEVEL SECURITY;
+ALTER TABLE readings           FORCE ROW LEVEL SECURITY;
+ALTER TABLE alerts             ENABLE ROW LEVEL SECURITY;
+ALTER TABLE alerts             FORCE ROW LEVEL SECURITY;
+ALTER TABLE maintenance_events ENABLE ROW LEVEL SECURITY;
+ALTER TABLE maintenance_events FORCE ROW LEVEL SECURITY;
+ALTER TABLE plans              ENABLE ROW LEVEL SECURITY;
+ALTER TABLE plans              FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON devices
+  USING (tenant_id = app_tenant()) WITH CHECK (tenant_id = app_tenant());
+CREATE POLICY tenant_isolation ON readings
+  USING (tenant_id = app_tenant()) WITH CHECK (tenant_id = app_tenant());
+CREATE POLICY tenant_isolation ON alerts
+  USING (tenant_id = app_tenant()) WITH CHECK (tenant_id = app_tenant());
+CREATE POLICY tenant_isolation ON maintenance_events
+  USING (tenant_id = app_tenant()) WITH CHECK (tenant_id = app_tenant());
+
+-- plans: tenant đọc được plan của mình + template dùng chung;
+-- chỉ ghi được plan của chính mình (template do connection không tenant quản lý)
+CREATE POLICY plans_read ON plans FOR SELECT
+  USING (tenant_id IS NULL OR tenant_id = app_tenant());
+CREATE POLICY plans_write ON plans
+  USING (tenant_id IS NOT DISTINCT FROM app_tenant())
+  WITH CHECK (tenant_id IS NOT DISTINCT FROM app_tenant());
+
+-- api_keys: không bật RLS vì xác thực phải tra key theo prefix trước khi biết tenant;
+-- query quản trị tự lọc theo app_tenant().
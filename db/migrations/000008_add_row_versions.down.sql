@@ -0,0 +1,3 @@
+-- 8_down
+ALTER TABLE plans   DROP COLUMN IF EXISTS version;
+ALTER TABLE devices DROP COLUMN IF EXISTS version;
@@ -0,0 +1,3 @@
+-- 8_up: version cho optimistic concurrency (ETag / If-Match)
+ALTER TABLE devices ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE plans   ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
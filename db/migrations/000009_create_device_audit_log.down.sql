@@ -0,0 +1,2 @@
+-- 9_down
+DROP TABLE IF EXISTS device_audit_log;
@@ -0,0 +1,20 @@
+-- 9_up: nhật ký thay đổi thiết bị (ai sửa gì, giá trị trước/sau)
+CREATE TABLE IF NOT EXISTS device_audit_log (
+  id         BIGSERIAL PRIMARY KEY,
+  tenant_id  TEXT NOT NULL DEFAULT app_tenant(),
+  device_id  BIGINT NOT NULL,
+  action     TEXT NOT NULL,              -- vd "profile_patched"
+  actor      TEXT,                       -- principal thực hiện (user hoặc API key)
+  changes    JSONB NOT NULL DEFAULT '{}', -- {"field": {"from": ..., "to": ...}}
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CONSTRAINT fk_device_audit_device
+    FOREIGN KEY (device_id, tenant_id) REFERENCES devices(id, tenant_id)
+    ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_device_audit_device_time ON device_audit_log(device_id, created_at DESC);
+
+ALTER TABLE device_audit_log ENABLE ROW LEVEL SECURITY;
+ALTER TABLE device_audit_log FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON device_audit_log
+  USING (tenant_id = app_tenant()) WITH CHECK (tenant_id = app_tenant());
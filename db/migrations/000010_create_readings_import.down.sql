@@ -0,0 +1,2 @@
+-- 10_down
+DROP TABLE IF EXISTS readings_import;
@@ -0,0 +1,11 @@
+-- 10_up: bảng đệm cho import reading hàng loạt
+-- COPY FROM không chạy trên bảng bật RLS, nên import COPY vào đây rồi INSERT ... SELECT sang readings
+-- (policy + FK kép của readings vẫn kiểm từng dòng). Dòng chỉ tồn tại trong transaction của import:
+-- cùng câu lệnh INSERT xóa chúng, transaction khác không bao giờ thấy.
+CREATE UNLOGGED TABLE IF NOT EXISTS readings_import (
+  device_id   BIGINT NOT NULL,
+  at          TIMESTAMPTZ NOT NULL,
+  hours_delta INTEGER NOT NULL,
+  location    TEXT,
+  operator_id TEXT
+);
@@ -0,0 +1,9 @@
+-- 11_down
+DROP POLICY IF EXISTS snapshot_read ON device_audit_log;
+DROP POLICY IF EXISTS snapshot_read ON plans;
+DROP POLICY IF EXISTS snapshot_read ON maintenance_events;
+DROP POLICY IF EXISTS snapshot_read ON alerts;
+DROP POLICY IF EXISTS snapshot_read ON readings;
+DROP POLICY IF EXISTS snapshot_read ON devices;
+
+DROP FUNCTION IF EXISTS app_snapshot();
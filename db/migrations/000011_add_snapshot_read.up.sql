@@ -0,0 +1,16 @@
+-- 11_up: chế độ đọc snapshot cho backup/restore (whma backup|restore)
+-- Transaction set app.snapshot = 'on' (SET LOCAL) thì SELECT thấy dòng của mọi tenant;
+-- ghi vẫn chỉ qua policy tenant_isolation (restore set app.tenant_id theo từng nhóm dòng).
+-- Server không bao giờ set biến này (bindTenant chỉ set app.tenant_id).
+
+CREATE OR REPLACE FUNCTION app_snapshot() RETURNS BOOLEAN
+LANGUAGE sql STABLE AS $$
+  SELECT COALESCE(current_setting('app.snapshot', true), '') = 'on'
+$$;
+
+CREATE POLICY snapshot_read ON devices            FOR SELECT USING (app_snapshot());
+CREATE POLICY snapshot_read ON readings           FOR SELECT USING (app_snapshot());
+CREATE POLICY snapshot_read ON alerts             FOR SELECT USING (app_snapshot());
+CREATE POLICY snapshot_read ON maintenance_events FOR SELECT USING (app_snapshot());
+CREATE POLICY snapshot_read ON plans              FOR SELECT USING (app_snapshot());
+CREATE POLICY snapshot_read ON device_audit_log   FOR SELECT USING (app_snapshot());
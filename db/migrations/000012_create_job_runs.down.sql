@@ -0,0 +1,3 @@
+-- 12_down
+DROP FUNCTION IF EXISTS app_tenant_ids();
+DROP TABLE IF EXISTS job_runs;
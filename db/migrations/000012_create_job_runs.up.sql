@@ -0,0 +1,29 @@
+-- 12_up: lịch sử chạy job nền (scheduler) + danh sách tenant cho job quét toàn đội máy
+-- job_runs là metadata hệ thống (không có dữ liệu tenant, summary chỉ là số đếm) nên không bật RLS.
+CREATE TABLE IF NOT EXISTS job_runs (
+  id           BIGSERIAL PRIMARY KEY,
+  job          TEXT NOT NULL,              -- vd "forecast_recompute"
+  instance     TEXT NOT NULL,              -- replica đã chạy (hostname-pid)
+  scheduled_at TIMESTAMPTZ NOT NULL,       -- mốc lịch của lần chạy
+  started_at   TIMESTAMPTZ NOT NULL,
+  finished_at  TIMESTAMPTZ NOT NULL,
+  status       TEXT NOT NULL CHECK (status IN ('ok','failed','timeout','canceled')),
+  error        TEXT,
+  summary      TEXT                        -- vd "tenants=3 devices=120 updated=118"
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_runs_job_time ON job_runs(job, started_at DESC);
+CREATE INDEX IF NOT EXISTS idx_job_runs_started ON job_runs(started_at);
+
+-- app_tenant_ids: tenant đang có device (job nền lặp qua từng tenant rồi chạy với app.tenant_id đó).
+-- Bật app.snapshot (migration 000011) chỉ trong lúc đọc rồi trả lại giá trị cũ.
+CREATE OR REPLACE FUNCTION app_tenant_ids() RETURNS SETOF TEXT
+LANGUAGE plpgsql VOLATILE AS $$
+DECLARE
+  prev TEXT := COALESCE(current_setting('app.snapshot', true), '');
+BEGIN
+  PERFORM set_config('app.snapshot', 'on', true);
+  RETURN QUERY SELECT DISTINCT d.tenant_id FROM devices d WHERE d.deleted_at IS NULL ORDER BY 1;
+  PERFORM set_config('app.snapshot', prev, true);
+END
+$$;
@@ -0,0 +1,2 @@
+-- 13_down
+ALTER TABLE readings DROP COLUMN IF EXISTS source;
@@ -0,0 +1,4 @@
+-- 13_up: nguồn của reading (metrics readings theo nguồn)
+--   api    = nhập từng reading qua API/CLI
+--   import = import hàng loạt (CSV/XLSX, InsertReadingsFromImport)
+ALTER TABLE readings ADD COLUMN IF NOT EXISTS source TEXT NOT NULL DEFAULT 'api';
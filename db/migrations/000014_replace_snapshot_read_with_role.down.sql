@@ -0,0 +1,36 @@
+-- 14_down: trả lại policy snapshot_read theo biến app.snapshot (như 000011) và app_tenant_ids
+-- như 000012. Role là đối tượng cấp cluster: chỉ thu quyền trong database này, không DROP ROLE.
+
+DROP FUNCTION IF EXISTS app_tenant_ids();
+CREATE FUNCTION app_tenant_ids() RETURNS SETOF TEXT
+LANGUAGE plpgsql VOLATILE AS $$
+DECLARE
+  prev TEXT := COALESCE(current_setting('app.snapshot', true), '');
+BEGIN
+  PERFORM set_config('app.snapshot', 'on', true);
+  RETURN QUERY SELECT DISTINCT d.tenant_id FROM devices d WHERE d.deleted_at IS NULL ORDER BY 1;
+  PERFORM set_config('app.snapshot', prev, true);
+END
+$$;
+
+DO $$
+BEGIN
+  IF EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'whma_snapshot') THEN
+    REVOKE SELECT ON plans, devices, readings, maintenance_events, alerts, device_audit_log, schema_migrations
+      FROM whma_snapshot;
+    REVOKE USAGE ON SCHEMA public FROM whma_snapshot;
+  END IF;
+END
+$$;
+
+CREATE OR REPLACE FUNCTION app_snapshot() RETURNS BOOLEAN
+LANGUAGE sql STABLE AS $$
+  SELECT COALESCE(current_setting('app.snapshot', true), '') = 'on'
+$$;
+
+CREATE POLICY snapshot_read ON devices            FOR SELECT USING (app_snapshot());
+CREATE POLICY snapshot_read ON readings           FOR SELECT USING (app_snapshot());
+CREATE POLICY snapshot_read ON alerts             FOR SELECT USING (app_snapshot());
+CREATE POLICY snapshot_read ON maintenance_events FOR SELECT USING (app_snapshot());
+CREATE POLICY snapshot_read ON plans              FOR SELECT USING (app_snapshot());
+CREATE POLICY snapshot_read ON device_audit_log   FOR SELECT USING (app_snapshot());
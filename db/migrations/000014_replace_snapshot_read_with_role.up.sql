@@ -0,0 +1,45 @@
+-- 14_up: đọc snapshot cho backup bằng role riêng thay cho biến app.snapshot (migration 000011)
+-- Biến session thì connection nào cũng set được; role BYPASSRLS chỉ member mới SET ROLE được.
+-- 000011 giữ nguyên (có thể đã chạy trên môi trường thật); file này gỡ phần bypass qua biến của nó
+-- (policy snapshot_read, app_snapshot()) và đổi app_tenant_ids() của 000012 sang đọc bằng role.
+-- whma backup: SET LOCAL ROLE whma_snapshot trong transaction READ ONLY -> thấy mọi tenant.
+-- Role không LOGIN, chỉ SELECT; user chạy backup cần là member: GRANT whma_snapshot TO <user>;
+-- (superuser luôn SET ROLE được). Tạo role BYPASSRLS cần superuser: migration chạy bằng user
+-- không đủ quyền thì lỗi (không có role thì backup lẫn job nền quét tenant đều hỏng); DBA tạo
+-- role (CREATE ROLE whma_snapshot NOLOGIN BYPASSRLS; GRANT whma_snapshot TO <user migrate>;)
+-- rồi "whma migrate force 13" (version 14 bị đánh dấu dirty) và chạy lại migration.
+
+DROP POLICY IF EXISTS snapshot_read ON device_audit_log;
+DROP POLICY IF EXISTS snapshot_read ON plans;
+DROP POLICY IF EXISTS snapshot_read ON maintenance_events;
+DROP POLICY IF EXISTS snapshot_read ON alerts;
+DROP POLICY IF EXISTS snapshot_read ON readings;
+DROP POLICY IF EXISTS snapshot_read ON devices;
+
+DROP FUNCTION IF EXISTS app_snapshot();
+
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'whma_snapshot') THEN
+    BEGIN
+      CREATE ROLE whma_snapshot NOLOGIN BYPASSRLS;
+    EXCEPTION WHEN insufficient_privilege THEN
+      RAISE EXCEPTION 'cannot create role whma_snapshot (needs superuser)'
+        USING HINT = 'as a superuser: CREATE ROLE whma_snapshot NOLOGIN BYPASSRLS; GRANT whma_snapshot TO ' || current_user || '; then re-run the migration';
+    END;
+  END IF;
+END
+$$;
+
+GRANT USAGE ON SCHEMA public TO whma_snapshot;
+GRANT SELECT ON plans, devices, readings, maintenance_events, alerts, device_audit_log, schema_migrations
+  TO whma_snapshot;
+
+-- app_tenant_ids (000012) bật app.snapshot để thấy mọi tenant; policy đó không còn nên đọc bằng
+-- quyền của whma_snapshot (SECURITY DEFINER). search_path cố định: caller không chèn được
+-- bảng/hàm trùng tên vào trước public.
+CREATE OR REPLACE FUNCTION app_tenant_ids() RETURNS SETOF TEXT
+LANGUAGE sql STABLE SECURITY DEFINER SET search_path = pg_catalog, public AS $$
+  SELECT DISTINCT d.tenant_id FROM public.devices d WHERE d.deleted_at IS NULL ORDER BY 1
+$$;
+ALTER FUNCTION app_tenant_ids() OWNER TO whma_snapshot;
@@ -2,35 +2,100 @@
 INSERT INTO devices (
   serial_number, name, model, manufacturer, year_of_manufacture,
   commission_date, total_working_hour, after_overhaul_working_hour,
-  status, last_service_at, location, plan_id, created_at, updated_at
+  status, last_service_at, location, plan_id, created_by, created_at, updated_at
 ) VALUES (
-  $1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,NOW(),NOW()
+  $1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,NOW(),NOW()
 ) RETURNING *;
 
 -- name: GetDevice :one
 SELECT * FROM devices WHERE id = $1 LIMIT 1;
 
+-- name: GetDeviceForUpdate :one
+SELECT * FROM devices WHERE id = $1 LIMIT 1 FOR UPDATE;
+
 -- name: ListDevices :many
+-- sites/ids rỗng = không giới hạn (phạm vi của principal, xem domain.DeviceScope)
 SELECT * FROM devices
 WHERE deleted_at IS NULL
+  AND (cardinality(sqlc.arg(sites)::text[]) = 0 OR location = ANY(sqlc.arg(sites)::text[]))
+  AND (cardinality(sqlc.arg(ids)::bigint[]) = 0 OR id = ANY(sqlc.arg(ids)::bigint[]))
 ORDER BY id
-LIMIT $1 OFFSET $2;
+LIMIT sqlc.arg(lim) OFFSET sqlc.arg(off);
 
 -- name: UpdateDeviceBasic :one
 UPDATE devices SET
-  name = $2,
-  status = $3,
-  location = $4,
+  name = sqlc.arg(name),
+  status = sqlc.arg(status),
+  location = sqlc.arg(location),
+  updated_by = sqlc.arg(updated_by),
+  version = version + 1,
   updated_at = NOW()
-WHERE id = $1
+WHERE id = sqlc.arg(id)
+  AND (sqlc.arg(expected_version)::int = 0 OR version = sqlc.arg(expected_version)::int)
 RETURNING *;
 
 -- name: UpdateDevicePlan :one
 UPDATE devices SET
-  plan_id = $2,
+  plan_id = sqlc.arg(plan_id),
+  updated_by = sqlc.arg(updated_by),
+  version = version + 1,
+  updated_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND (sqlc.arg(expected_version)::int = 0 OR version = sqlc.arg(expected_version)::int)
+RETURNING *;
+
+-- name: UpdateDeviceProfile :one
+UPDATE devices SET
+  serial_number = sqlc.arg(serial_number),
+  name = sqlc.arg(name),
+  model = sqlc.arg(model),
+  manufacturer = sqlc.arg(manufacturer),
+  year_of_manufacture = sqlc.arg(year_of_manufacture),
+  commission_date = sqlc.arg(commission_date),
+  status = sqlc.arg(status),
+  location = sqlc.arg(location),
+  updated_by = sqlc.arg(updated_by),
+  version = version + 1,
   updated_at = NOW()
-WHERE id = $1
+WHERE id = sqlc.arg(id)
+  AND (sqlc.arg(expected_version)::int = 0 OR version = sqlc.arg(expected_version)::int)
 RETURNING *;
 
 -- name: SoftDeleteDevice :exec
-UPDATE devices SET deleted_at = NOW() WHERE id = $1;
+UPDATE devices SET deleted_at = NOW(), deleted_by = $2 WHERE id = $1;
+
+-- name: AddDeviceUsage :one
+UPDATE devices SET
+  total_working_hour = COALESCE(total_working_hour, 0) + sqlc.arg(hours_delta)::int,
+  after_overhaul_working_hour = COALESCE(after_overhaul_working_hour, 0) + sqlc.arg(hours_delta)::int,
+  last_service_at = GREATEST(last_service_at, sqlc.arg(at)::timestamptz),
+  avg_daily_hours = sqlc.arg(avg_daily_hours),
+  expected_next_maint = sqlc.arg(expected_next_maint),
+  version = version + 1,
+  updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListTakenSerials :many
+SELECT serial_number FROM devices
+WHERE serial_number = ANY(sqlc.arg(serials)::text[]);
+
+-- name: ListDevicesBySerials :many
+SELECT * FROM devices
+WHERE serial_number = ANY(sqlc.arg(serials)::text[]) AND deleted_at IS NULL
+ORDER BY id;
+
+-- name: ListDevicesAfter :many
+SELECT * FROM devices
+WHERE id > sqlc.arg(after_id) AND deleted_at IS NULL
+ORDER BY id
+LIMIT sqlc.arg(lim);
+
+-- name: UpdateDeviceForecast :exec
+UPDATE devices SET
+  avg_daily_hours = sqlc.arg(avg_daily_hours),
+  expected_next_maint = sqlc.arg(expected_next_maint)
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL;
+
+-- name: ListDeviceTenants :many
+SELECT t::text AS tenant_id FROM app_tenant_ids() AS t;
@@ -0,0 +1,30 @@
+-- name: CountDevicesByStatusSite :many
+SELECT status, COALESCE(location, '')::text AS site, COUNT(*) AS devices
+FROM devices
+WHERE deleted_at IS NULL
+GROUP BY status, site;
+
+-- name: CountOpenAlertsByType :many
+SELECT type, COUNT(*) AS alerts
+FROM alerts
+WHERE NOT resolved
+  AND device_id IN (SELECT id FROM devices WHERE deleted_at IS NULL)
+GROUP BY type;
+
+-- name: CountOverdueDevicesBySite :many
+SELECT COALESCE(d.location, '')::text AS site, COUNT(*) AS devices
+FROM devices d
+LEFT JOIN plans p ON p.id = d.plan_id
+WHERE d.deleted_at IS NULL AND d.status = 'active'
+  AND (d.expected_next_maint <= sqlc.arg(now)::timestamptz
+       OR (p.interval_hours > 0 AND COALESCE(d.after_overhaul_working_hour, 0) >= p.interval_hours))
+GROUP BY site;
+
+-- name: SumReadingsBySource :many
+SELECT source, COUNT(*) AS readings, COALESCE(SUM(hours_delta), 0)::bigint AS hours
+FROM readings
+GROUP BY source;
+
+-- name: SumMaintenanceCost :one
+SELECT COUNT(*) AS events, COALESCE(SUM(cost), 0)::float8 AS cost
+FROM maintenance_events;
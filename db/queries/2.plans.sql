@@ -10,11 +10,13 @@ SELECT * FROM plans ORDER BY id LIMIT $1 OFFSET $2;
 
 -- name: UpdatePlan :one
 UPDATE plans SET
-  name = $2,
-  interval_hours = $3,
-  description = $4,
+  name = sqlc.arg(name),
+  interval_hours = sqlc.arg(interval_hours),
+  description = sqlc.arg(description),
+  version = version + 1,
   updated_at = NOW()
-WHERE id = $1
+WHERE id = sqlc.arg(id)
+  AND (sqlc.arg(expected_version)::int = 0 OR version = sqlc.arg(expected_version)::int)
 RETURNING *;
 
 -- name: DeletePlan :exec
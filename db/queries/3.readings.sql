@@ -15,5 +15,38 @@ WHERE device_id = $1
 ORDER BY at DESC
 LIMIT 1;
 
+-- name: GetFirstReading :one
+SELECT * FROM readings
+WHERE device_id = $1
+ORDER BY at
+LIMIT 1;
+
+-- name: SumReadingHoursSince :one
+SELECT COALESCE(SUM(hours_delta), 0)::bigint AS hours
+FROM readings
+WHERE device_id = sqlc.arg(device_id) AND at > sqlc.arg(since);
+
 -- name: DeleteReading :exec
 DELETE FROM readings WHERE id = $1;
+
+-- name: CopyReadingsImport :copyfrom
+INSERT INTO readings_import (device_id, at, hours_delta, location, operator_id)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: InsertReadingsFromImport :execrows
+WITH staged AS (
+  DELETE FROM readings_import
+  RETURNING device_id, at, hours_delta, location, operator_id
+)
+INSERT INTO readings (device_id, at, hours_delta, location, operator_id, source)
+SELECT device_id, at, hours_delta, location, operator_id, 'import'
+FROM staged
+ORDER BY device_id, at;
+
+-- name: ListReadingsRange :many
+SELECT * FROM readings
+WHERE at >= sqlc.arg(from_at) AND at < sqlc.arg(to_at)
+  AND (sqlc.narg(device_id)::bigint IS NULL OR device_id = sqlc.narg(device_id)::bigint)
+  AND (at, id) > (sqlc.arg(after_at)::timestamptz, sqlc.arg(after_id)::bigint)
+ORDER BY at, id
+LIMIT sqlc.arg(lim);
@@ -3,6 +3,10 @@ INSERT INTO alerts (device_id, type, message)
 VALUES ($1,$2,$3)
 RETURNING *;
 
+-- name: GetAlert :one
+SELECT * FROM alerts
+WHERE id = $1;
+
 -- name: ListOpenAlertsByDevice :many
 SELECT * FROM alerts
 WHERE device_id = $1 AND resolved = FALSE
@@ -15,4 +19,12 @@ UPDATE alerts SET
   resolved_at = NOW(),
   resolved_by = $2
 WHERE id = $1
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: ListAlertsRange :many
+SELECT * FROM alerts
+WHERE created_at >= sqlc.arg(from_at) AND created_at < sqlc.arg(to_at)
+  AND (sqlc.narg(device_id)::bigint IS NULL OR device_id = sqlc.narg(device_id)::bigint)
+  AND (created_at, id) > (sqlc.arg(after_at)::timestamptz, sqlc.arg(after_id)::bigint)
+ORDER BY created_at, id
+LIMIT sqlc.arg(lim);
@@ -1,7 +1,7 @@
 -- name: CreateMaintenanceEvent :one
 INSERT INTO maintenance_events (device_id, at, interval, notes, performed_by, cost)
 VALUES ($1,$2,$3,$4,$5,$6)
-RETURNING id, device_id, at, interval, notes, performed_by, cost, created_at;
+RETURNING *;
 
 -- name: ListMaintenanceByDevice :many
 SELECT * FROM maintenance_events
@@ -10,4 +10,12 @@ ORDER BY at DESC
 LIMIT $2 OFFSET $3;
 
 -- name: DeleteMaintenanceEvent :exec
-DELETE FROM maintenance_events WHERE id = $1;
\ No newline at end of file
+DELETE FROM maintenance_events WHERE id = $1;
+
+-- name: ListMaintenanceRange :many
+SELECT * FROM maintenance_events
+WHERE at >= sqlc.arg(from_at) AND at < sqlc.arg(to_at)
+  AND (sqlc.narg(device_id)::bigint IS NULL OR device_id = sqlc.narg(device_id)::bigint)
+  AND (at, id) > (sqlc.arg(after_at)::timestamptz, sqlc.arg(after_id)::bigint)
+ORDER BY at, id
+LIMIT sqlc.arg(lim);
@@ -0,0 +1,22 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (name, prefix, secret_hash, scopes, device_ids, sites, expires_at, created_by)
+VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+RETURNING *;
+
+-- name: GetAPIKeyByPrefix :one
+SELECT * FROM api_keys WHERE prefix = $1 LIMIT 1;
+
+-- name: ListAPIKeys :many
+SELECT * FROM api_keys
+WHERE tenant_id IS NOT DISTINCT FROM app_tenant()
+ORDER BY id
+LIMIT $1 OFFSET $2;
+
+-- name: RevokeAPIKey :one
+UPDATE api_keys SET revoked_at = NOW()
+WHERE id = $1 AND revoked_at IS NULL AND tenant_id IS NOT DISTINCT FROM app_tenant()
+RETURNING *;
+
+-- name: TouchAPIKeyLastUsed :exec
+UPDATE api_keys SET last_used_at = NOW()
+WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute');
@@ -0,0 +1,10 @@
+-- name: CreateDeviceAudit :one
+INSERT INTO device_audit_log (device_id, action, actor, changes)
+VALUES ($1,$2,$3,$4)
+RETURNING *;
+
+-- name: ListDeviceAudit :many
+SELECT * FROM device_audit_log
+WHERE device_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2 OFFSET $3;
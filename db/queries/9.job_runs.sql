@@ -0,0 +1,17 @@
+-- name: CreateJobRun :one
+INSERT INTO job_runs (job, instance, scheduled_at, started_at, finished_at, status, error, summary)
+VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+RETURNING *;
+
+-- name: ListJobRuns :many
+SELECT * FROM job_runs
+WHERE job = $1
+ORDER BY started_at DESC, id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: LatestJobRuns :many
+SELECT DISTINCT ON (job) * FROM job_runs
+ORDER BY job, started_at DESC, id DESC;
+
+-- name: DeleteJobRunsBefore :execrows
+DELETE FROM job_runs WHERE started_at < $1;
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"wh-ma/internal/adapter/inbound/http/response"
+	inport "wh-ma/internal/adapter/inbound/port"
+	"wh-ma/internal/domain"
+)
+
+type AlertsHandler struct {
+	svc inport.AlertsInbound
+}
+
+func NewAlertsHandler(svc inport.AlertsInbound) *AlertsHandler {
+	return &AlertsHandler{svc: svc}
+}
+
+// GET /devices/:id/alerts (chỉ alert đang mở)
+func (h *AlertsHandler) ListOpenByDevice(c *gin.Context) {
+	done := observe(c, "ListOpenAlerts")
+	var errMsg string
+	var id domain.DeviceID
+	limit, offset := parsePaging(c, 50, 0)
+
+	defer func() {
+		done(
+			slog.String("error", errMsg),
+			slog.Int64("device_id", int64(id)),
+			slog.Int("limit", int(limit)),
+			slog.Int("offset", int(offset)),
+		)
+	}()
+
+	var ok bool
+	id, ok = parseDeviceID(c)
+	if !ok {
+		errMsg = "invalid id"
+		return
+	}
+
+	items, err := h.svc.ListOpenByDevice(c, id, limit, offset)
+	if err != nil {
+		respondErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	c.JSON(http.StatusOK, response.NewPage(items, limit, offset, response.NewAlert))
+}
+
+// POST /alerts/:id/resolve
+func (h *AlertsHandler) Resolve(c *gin.Context) {
+	done := observe(c, "ResolveAlert")
+	var errMsg string
+	var id int64
+
+	defer func() {
+		done(
+			slog.String("error", errMsg),
+			slog.Int64("alert_id", id),
+		)
+	}()
+
+	var ok bool
+	id, ok = parseID(c)
+	if !ok {
+		errMsg = "invalid id"
+		return
+	}
+
+	al, err := h.svc.Resolve(c, id)
+	if err != nil {
+		respondErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	c.JSON(http.StatusOK, response.NewAlert(al))
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"wh-ma/internal/adapter/inbound/http/request"
+	"wh-ma/internal/adapter/inbound/http/response"
+	inport "wh-ma/internal/adapter/inbound/port"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/dto"
+)
+
+type APIKeysHandler struct {
+	svc inport.APIKeysInbound
+}
+
+func NewAPIKeysHandler(svc inport.APIKeysInbound) *APIKeysHandler {
+	return &APIKeysHandler{svc: svc}
+}
+
+// POST /admin/api-keys — key gốc chỉ xuất hiện trong response này
+func (h *APIKeysHandler) Create(c *gin.Context) {
+	done := observe(c, "CreateAPIKey")
+	var errMsg string
+	defer func() {
+		done(slog.String("error", errMsg))
+	}()
+
+	var in request.CreateAPIKey
+	if err := c.ShouldBindJSON(&in); err != nil {
+		respondBindErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	deviceIDs := make([]domain.DeviceID, 0, len(in.DeviceIDs))
+	for _, id := range in.DeviceIDs {
+		deviceIDs = append(deviceIDs, domain.DeviceID(id))
+	}
+	issued, err := h.svc.Create(c, dto.CreateAPIKeyCmd{
+		Name:      in.Name,
+		Scopes:    in.Scopes,
+		DeviceIDs: deviceIDs,
+		Sites:     in.Sites,
+		ExpiresAt: in.ExpiresAt,
+	})
+	if err != nil {
+		respondErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	c.JSON(http.StatusCreated, response.IssuedAPIKey{APIKey: response.NewAPIKey(issued.Key), Key: issued.Secret})
+}
+
+// GET /admin/api-keys
+func (h *APIKeysHandler) List(c *gin.Context) {
+	done := observe(c, "ListAPIKeys")
+	var errMsg string
+	limit, offset := parsePaging(c, 50, 0)
+	defer func() {
+		done(
+			slog.String("error", errMsg),
+			slog.Int("limit", int(limit)),
+			slog.Int("offset", int(offset)),
+		)
+	}()
+
+	keys, err := h.svc.List(c, limit, offset)
+	if err != nil {
+		respondErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	c.JSON(http.StatusOK, response.NewPage(keys, limit, offset, response.NewAPIKey))
+}
+
+// DELETE /admin/api-keys/:id (thu hồi, giữ lại bản ghi để audit)
+func (h *APIKeysHandler) Revoke(c *gin.Context) {
+	done := observe(c, "RevokeAPIKey")
+	var errMsg string
+	var id int64
+
+	defer func() {
+		done(
+			slog.String("error", errMsg),
+			slog.Int64("api_key_id", id),
+		)
+	}()
+
+	var ok bool
+	id, ok = parseID(c)
+	if !ok {
+		errMsg = "invalid id"
+		return
+	}
+
+	k, err := h.svc.Revoke(c, id)
+	if err != nil {
+		respondErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	c.JSON(http.StatusOK, response.NewAPIKey(k))
+}
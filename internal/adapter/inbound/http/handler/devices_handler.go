@@ -1,13 +1,17 @@
 package handler
 
 import (
+	"encoding/json"
 	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"wh-ma/internal/adapter/inbound/http/metrics"
+	"wh-ma/internal/adapter/inbound/http/problem"
 	"wh-ma/internal/adapter/inbound/http/request"
+	"wh-ma/internal/adapter/inbound/http/response"
 	inport "wh-ma/internal/adapter/inbound/port"
 	"wh-ma/internal/domain"
 	"wh-ma/internal/usecase/dto"
@@ -24,16 +28,14 @@ func NewDevicesHandler(svc inport.DevicesInbound) *DevicesHandler {
 // POST /devices
 func (h *DevicesHandler) Create(c *gin.Context) {
 	done := observe(c, "CreateDevice")
-	status := http.StatusCreated
 	var errMsg string
 	defer func() {
-		done(slog.Int("status", status), slog.String("error", errMsg))
+		done(slog.String("error", errMsg))
 	}()
 	var in request.CreateDevice
 	if err := c.ShouldBindJSON(&in); err != nil {
-		status = http.StatusBadRequest
+		respondBindErr(c, err)
 		errMsg = err.Error()
-		c.JSON(status, gin.H{"error": errMsg})
 		return
 	}
 	var planID *domain.PlanID
@@ -54,25 +56,23 @@ func (h *DevicesHandler) Create(c *gin.Context) {
 	}
 	dev, err := h.svc.Create(c, cmd)
 	if err != nil {
-		status = http.StatusBadRequest
+		respondErr(c, err)
 		errMsg = err.Error()
-		c.JSON(status, gin.H{"error": errMsg})
 		return
 	}
 	metrics.DeviceCreatedTotal.Inc()
-	c.JSON(http.StatusCreated, dev)
+	setETag(c, dev.Version)
+	c.JSON(http.StatusCreated, response.NewDevice(dev))
 }
 
 // GET /devices/:id
 func (h *DevicesHandler) Get(c *gin.Context) {
 	done := observe(c, "GetDevice")
-	status := http.StatusOK
 	var errMsg string
 	var id domain.DeviceID
 
 	defer func() {
 		done(
-			slog.Int("status", status),
 			slog.String("error", errMsg),
 			slog.Int64("device_id", int64(id)),
 		)
@@ -81,31 +81,31 @@ func (h *DevicesHandler) Get(c *gin.Context) {
 	var ok bool
 	id, ok = parseDeviceID(c)
 	if !ok {
-		status = http.StatusBadRequest
 		errMsg = "invalid id"
 		return // parseDeviceID đã trả JSON 400 rồi
 	}
 
 	dev, err := h.svc.Get(c, id)
 	if err != nil {
-		status = http.StatusNotFound
+		respondErr(c, err)
 		errMsg = err.Error()
-		c.JSON(status, gin.H{"error": errMsg})
 		return
 	}
-	c.JSON(status, dev)
+	if notModified(c, dev.Version) {
+		return
+	}
+	setETag(c, dev.Version)
+	c.JSON(http.StatusOK, response.NewDevice(dev))
 }
 
 // GET /devices
 func (h *DevicesHandler) List(c *gin.Context) {
 	done := observe(c, "ListDevices")
-	status := http.StatusOK
 	var errMsg string
 	limit, offset := parsePaging(c, 50, 0)
 
 	defer func() {
 		done(
-			slog.Int("status", status),
 			slog.String("error", errMsg),
 			slog.Int("limit", int(limit)),
 			slog.Int("offset", int(offset)),
@@ -114,25 +114,128 @@ func (h *DevicesHandler) List(c *gin.Context) {
 
 	devs, err := h.svc.List(c, limit, offset)
 	if err != nil {
-		status = http.StatusInternalServerError
+		respondErr(c, err)
 		errMsg = err.Error()
-		c.JSON(status, gin.H{"error": errMsg})
 		return
 	}
 	metrics.DeviceListTotal.Inc()
-	c.JSON(status, gin.H{"items": devs, "limit": limit, "offset": offset})
+	c.JSON(http.StatusOK, response.NewPage(devs, limit, offset, response.NewDevice))
 }
 
+// MergePatchContentType: PATCH /devices/:id theo RFC 7396
+const MergePatchContentType = "application/merge-patch+json"
+
 // PATCH /devices/:id
+// - application/merge-patch+json: sửa mọi field hồ sơ (Patch)
+// - application/json: hợp đồng cũ name/status/location (UpdateBasic)
+func (h *DevicesHandler) Update(c *gin.Context) {
+	if c.ContentType() == MergePatchContentType {
+		h.Patch(c)
+		return
+	}
+	h.UpdateBasic(c)
+}
+
+// PATCH /devices/:id (merge patch)
+func (h *DevicesHandler) Patch(c *gin.Context) {
+	done := observe(c, "PatchDevice")
+	var errMsg string
+	var id domain.DeviceID
+
+	defer func() {
+		done(
+			slog.String("error", errMsg),
+			slog.Int64("device_id", int64(id)),
+		)
+	}()
+
+	var ok bool
+	id, ok = parseDeviceID(c)
+	if !ok {
+		errMsg = "invalid id"
+		return
+	}
+	version, ok := ifMatch(c)
+	if !ok {
+		errMsg = "if-match"
+		return
+	}
+
+	var in request.PatchDevice
+	dec := json.NewDecoder(c.Request.Body)
+	dec.DisallowUnknownFields() // field chỉ đọc/không tồn tại -> 400, không lặng lẽ bỏ qua
+	if err := dec.Decode(&in); err != nil {
+		respondBindErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+
+	cmd := dto.PatchDeviceCmd{
+		ID:             id,
+		Version:        version,
+		SerialNumber:   dto.Opt[string](in.SerialNumber),
+		Name:           dto.Opt[string](in.Name),
+		Model:          dto.Opt[string](in.Model),
+		Manufacturer:   dto.Opt[string](in.Manufacturer),
+		Year:           dto.Opt[int](in.Year),
+		CommissionDate: dto.Opt[time.Time](in.CommissionDate),
+		Location:       dto.Opt[string](in.Location),
+	}
+	if in.Status.Set {
+		cmd.Status.Set = true
+		if in.Status.Value != nil {
+			s := domain.DeviceStatus(*in.Status.Value)
+			cmd.Status.Value = &s
+		}
+	}
+	dev, err := h.svc.Patch(c, cmd)
+	if err != nil {
+		respondErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	setETag(c, dev.Version)
+	c.JSON(http.StatusOK, response.NewDevice(dev))
+}
+
+// GET /devices/:id/audit
+func (h *DevicesHandler) ListAudit(c *gin.Context) {
+	done := observe(c, "ListDeviceAudit")
+	var errMsg string
+	var id domain.DeviceID
+	limit, offset := parsePaging(c, 50, 0)
+
+	defer func() {
+		done(
+			slog.String("error", errMsg),
+			slog.Int64("device_id", int64(id)),
+		)
+	}()
+
+	var ok bool
+	id, ok = parseDeviceID(c)
+	if !ok {
+		errMsg = "invalid id"
+		return
+	}
+
+	entries, err := h.svc.ListAudit(c, id, limit, offset)
+	if err != nil {
+		respondErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	c.JSON(http.StatusOK, response.NewPage(entries, limit, offset, response.NewDeviceAuditEntry))
+}
+
+// PATCH /devices/:id (application/json)
 func (h *DevicesHandler) UpdateBasic(c *gin.Context) {
 	done := observe(c, "UpdateDeviceBasic")
-	status := http.StatusOK
 	var errMsg string
 	var id domain.DeviceID
 
 	defer func() {
 		done(
-			slog.Int("status", status),
 			slog.String("error", errMsg),
 			slog.Int64("device_id", int64(id)),
 		)
@@ -141,16 +244,19 @@ func (h *DevicesHandler) UpdateBasic(c *gin.Context) {
 	var ok bool
 	id, ok = parseDeviceID(c)
 	if !ok {
-		status = http.StatusBadRequest
 		errMsg = "invalid id"
 		return
 	}
+	version, ok := ifMatch(c)
+	if !ok {
+		errMsg = "if-match"
+		return
+	}
 
 	var in request.UpdateBasic
 	if err := c.ShouldBindJSON(&in); err != nil {
-		status = http.StatusBadRequest
+		respondBindErr(c, err)
 		errMsg = err.Error()
-		c.JSON(status, gin.H{"error": errMsg})
 		return
 	}
 
@@ -159,28 +265,27 @@ func (h *DevicesHandler) UpdateBasic(c *gin.Context) {
 		Name:     in.Name,
 		Status:   domain.DeviceStatus(in.Status),
 		Location: in.Location,
+		Version:  version,
 	}
 	dev, err := h.svc.UpdateBasic(c, cmd)
 	if err != nil {
-		status = http.StatusBadRequest
+		respondErr(c, err)
 		errMsg = err.Error()
-		c.JSON(status, gin.H{"error": errMsg})
 		return
 	}
-	c.JSON(status, dev)
+	setETag(c, dev.Version)
+	c.JSON(http.StatusOK, response.NewDevice(dev))
 }
 
 // PATCH /devices/:id/plan
 func (h *DevicesHandler) UpdatePlan(c *gin.Context) {
 	done := observe(c, "UpdateDevicePlan")
-	status := http.StatusOK
 	var errMsg string
 	var id domain.DeviceID
 	var planID *domain.PlanID
 
 	defer func() {
 		attrs := []slog.Attr{
-			slog.Int("status", status),
 			slog.String("error", errMsg),
 			slog.Int64("device_id", int64(id)),
 		}
@@ -193,16 +298,19 @@ func (h *DevicesHandler) UpdatePlan(c *gin.Context) {
 	var ok bool
 	id, ok = parseDeviceID(c)
 	if !ok {
-		status = http.StatusBadRequest
 		errMsg = "invalid id"
 		return
 	}
+	version, ok := ifMatch(c)
+	if !ok {
+		errMsg = "if-match"
+		return
+	}
 
 	var in request.UpdatePlan
 	if err := c.ShouldBindJSON(&in); err != nil {
-		status = http.StatusBadRequest
+		respondBindErr(c, err)
 		errMsg = err.Error()
-		c.JSON(status, gin.H{"error": errMsg})
 		return
 	}
 
@@ -211,27 +319,25 @@ func (h *DevicesHandler) UpdatePlan(c *gin.Context) {
 		planID = &v
 	}
 
-	cmd := dto.UpdateDevicePlanCmd{ID: id, PlanID: planID}
+	cmd := dto.UpdateDevicePlanCmd{ID: id, PlanID: planID, Version: version}
 	dev, err := h.svc.UpdatePlan(c, cmd)
 	if err != nil {
-		status = http.StatusBadRequest
+		respondErr(c, err)
 		errMsg = err.Error()
-		c.JSON(status, gin.H{"error": errMsg})
 		return
 	}
-	c.JSON(status, dev)
+	setETag(c, dev.Version)
+	c.JSON(http.StatusOK, response.NewDevice(dev))
 }
 
 // DELETE /devices/:id (soft delete)
 func (h *DevicesHandler) SoftDelete(c *gin.Context) {
 	done := observe(c, "SoftDeleteDevice")
-	status := http.StatusNoContent
 	var errMsg string
 	var id domain.DeviceID
 
 	defer func() {
 		done(
-			slog.Int("status", status),
 			slog.String("error", errMsg),
 			slog.Int64("device_id", int64(id)),
 		)
@@ -240,30 +346,32 @@ func (h *DevicesHandler) SoftDelete(c *gin.Context) {
 	var ok bool
 	id, ok = parseDeviceID(c)
 	if !ok {
-		status = http.StatusBadRequest
 		errMsg = "invalid id"
 		return
 	}
 
 	if err := h.svc.SoftDelete(c, id); err != nil {
-		status = http.StatusBadRequest
+		respondErr(c, err)
 		errMsg = err.Error()
-		c.JSON(status, gin.H{"error": errMsg})
 		return
 	}
-	c.Status(status) // 204
+	c.Status(http.StatusNoContent) // 204
 }
 
 // ===== helpers =====
 func parseDeviceID(c *gin.Context) (domain.DeviceID, bool) {
+	id, ok := parseID(c)
+	return domain.DeviceID(id), ok
+}
+func parseID(c *gin.Context) (int64, bool) {
 	var uri struct {
 		ID int64 `uri:"id" binding:"required,min=1"`
 	}
 	if err := c.ShouldBindUri(&uri); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		problem.Write(c, http.StatusBadRequest, "invalid_id", "id must be a positive integer")
 		return 0, false
 	}
-	return domain.DeviceID(uri.ID), true
+	return uri.ID, true
 }
 func parsePaging(c *gin.Context, defLimit, defOffset int32) (int32, int32) {
 	var in struct {
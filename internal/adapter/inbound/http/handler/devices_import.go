@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"wh-ma/internal/adapter/inbound/http/response"
+	"wh-ma/internal/adapter/inbound/tabular"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/dto"
+)
+
+// deviceImportColumns: field nhận được -> tên cột khác được tự nhận (ngoài chính tên field).
+// "plan" là tên plan (tra ra id), "plan_id" là id.
+var deviceImportColumns = map[string][]string{
+	"serial_number":   {"serial", "serial_no", "sn"},
+	"name":            {"device_name"},
+	"model":           nil,
+	"manufacturer":    {"maker"},
+	"year":            {"manufacture_year"},
+	"commission_date": {"commissioned"},
+	"status":          nil,
+	"location":        {"site"},
+	"plan_id":         nil,
+	"plan":            {"plan_name"},
+}
+
+// POST /devices/import?dry_run=true (multipart: file, mapping, sheet)
+// 200 = dry-run (kể cả có dòng lỗi), 201 = đã ghi toàn bộ, 422 = có dòng lỗi, không ghi gì (body vẫn là báo cáo)
+func (h *DevicesHandler) Import(c *gin.Context) {
+	done := observe(c, "ImportDevices")
+	var errMsg string
+	var rows int
+	defer func() {
+		done(slog.String("error", errMsg), slog.Int("rows", rows))
+	}()
+
+	in, q, err := bindImport(c)
+	if err != nil {
+		respondBindErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	table, cols, err := openImport(in, deviceImportColumns, []string{"serial_number"}, []string{"name"})
+	if err != nil {
+		respondErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	cmd := dto.ImportDevicesCmd{DryRun: q.DryRun, Rows: make([]dto.ImportDeviceRow, 0, len(table.Rows))}
+	for _, r := range table.Rows {
+		cmd.Rows = append(cmd.Rows, deviceImportRow(r, cols))
+	}
+	rows = len(cmd.Rows)
+
+	rep, err := h.svc.Import(c, cmd)
+	if err != nil {
+		respondErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	status := importStatus(rep.DryRun, rep.Committed)
+	if rep.Invalid > 0 {
+		errMsg = fmt.Sprintf("%d invalid rows", rep.Invalid)
+	}
+	c.JSON(status, response.NewDeviceImportReport(rep))
+}
+
+func deviceImportRow(r tabular.Row, cols map[string]int) dto.ImportDeviceRow {
+	cell := func(field string) string { return r.Cell(cols, field) }
+	row := dto.ImportDeviceRow{
+		Line:     r.Line,
+		PlanName: cell("plan"),
+		Device: dto.CreateDeviceCmd{
+			SerialNumber: cell("serial_number"),
+			Name:         cell("name"),
+			Model:        cell("model"),
+			Manufacturer: cell("manufacturer"),
+			Status:       domain.DeviceStatus(cell("status")),
+		},
+	}
+	var v domain.Violations
+	if s := cell("location"); s != "" {
+		row.Device.Location = &s
+	}
+	if s := cell("year"); s != "" {
+		if n, err := strconv.Atoi(s); err == nil {
+			row.Device.Year = n
+		} else {
+			v.Add("year", "must be an integer")
+		}
+	}
+	if s := cell("commission_date"); s != "" {
+		if t, err := tabular.ParseTime(s); err == nil {
+			row.Device.CommissionDate = &t
+		} else {
+			v.Add("commission_date", err.Error())
+		}
+	}
+	if s := cell("plan_id"); s != "" {
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil && n > 0 {
+			id := domain.PlanID(n)
+			row.Device.PlanID = &id
+		} else {
+			v.Add("plan_id", "must be a positive integer")
+		}
+	}
+	row.Errors = v
+	return row
+}
@@ -0,0 +1,18 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"wh-ma/internal/adapter/inbound/http/problem"
+)
+
+// respondErr trả lỗi từ usecase dạng application/problem+json.
+// Status/mã lỗi do problem.FromError quyết định theo loại lỗi domain.
+func respondErr(c *gin.Context, err error) {
+	problem.Error(c, err)
+}
+
+// respondBindErr: lỗi bind/validate body, query, uri
+func respondBindErr(c *gin.Context, err error) {
+	problem.BindError(c, err)
+}
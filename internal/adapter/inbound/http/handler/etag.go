@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"wh-ma/internal/adapter/inbound/http/problem"
+)
+
+// ===== ETag / If-Match (optimistic concurrency) =====
+// ETag = version của bản ghi, dạng strong: "3".
+// Ghi (PATCH/PUT) bắt buộc If-Match: thiếu -> 428, lệch -> 412 (repo trả version_mismatch).
+// If-Match: * -> version 0 = ghi đè không kiểm (client chủ động chấp nhận).
+
+func etag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", etag(version))
+}
+
+// ifMatch đọc version mong đợi từ If-Match; false = đã trả problem, handler dừng.
+func ifMatch(c *gin.Context) (int, bool) {
+	h := strings.TrimSpace(c.GetHeader("If-Match"))
+	switch {
+	case h == "":
+		problem.Write(c, http.StatusPreconditionRequired, "precondition_required",
+			"If-Match header is required; send the ETag from the last GET")
+		return 0, false
+	case h == "*":
+		return 0, true
+	}
+	v, err := strconv.Atoi(strings.Trim(h, `"`))
+	if err != nil || v < 1 || !strings.HasPrefix(h, `"`) {
+		// weak/sai định dạng: không thể khớp strong ETag nào
+		problem.Write(c, http.StatusPreconditionFailed, "version_mismatch",
+			"If-Match must be a strong ETag returned by this API")
+		return 0, false
+	}
+	return v, true
+}
+
+// notModified: If-None-Match khớp version hiện tại -> 304 (so sánh weak, cho phép W/ và danh sách)
+func notModified(c *gin.Context, version int) bool {
+	h := c.GetHeader("If-None-Match")
+	if h == "" {
+		return false
+	}
+	want := etag(version)
+	for _, tag := range strings.Split(h, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == want {
+			setETag(c, version)
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
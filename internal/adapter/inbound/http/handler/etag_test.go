@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func testContext(header, value string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	if value != "" {
+		c.Request.Header.Set(header, value)
+	}
+	return c, w
+}
+
+func TestIfMatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      string
+		wantVersion int
+		wantOK      bool
+		wantStatus  int
+	}{
+		{"missing", "", 0, false, http.StatusPreconditionRequired},
+		{"strong", `"3"`, 3, true, 0},
+		{"padded", `  "12" `, 12, true, 0},
+		{"any", "*", 0, true, 0},
+		{"weak", `W/"3"`, 0, false, http.StatusPreconditionFailed},
+		{"unquoted", "3", 0, false, http.StatusPreconditionFailed},
+		{"zero", `"0"`, 0, false, http.StatusPreconditionFailed},
+		{"garbage", `"abc"`, 0, false, http.StatusPreconditionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := testContext("If-Match", tt.header)
+			v, ok := ifMatch(c)
+			if v != tt.wantVersion || ok != tt.wantOK {
+				t.Fatalf("ifMatch(%q) = %d, %v; want %d, %v", tt.header, v, ok, tt.wantVersion, tt.wantOK)
+			}
+			if !ok && w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if ok && c.Writer.Written() {
+				t.Fatal("ifMatch wrote a response on success")
+			}
+		})
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{"missing", "", false},
+		{"same", `"4"`, true},
+		{"weak same", `W/"4"`, true},
+		{"list", `"2", W/"4"`, true},
+		{"any", "*", true},
+		{"stale", `"3"`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := testContext("If-None-Match", tt.header)
+			if got := notModified(c, 4); got != tt.want {
+				t.Fatalf("notModified(%q) = %v, want %v", tt.header, got, tt.want)
+			}
+			if !tt.want {
+				return
+			}
+			c.Writer.WriteHeaderNow()
+			if w.Code != http.StatusNotModified {
+				t.Fatalf("status = %d, want 304", w.Code)
+			}
+			if got := w.Header().Get("ETag"); got != `"4"` {
+				t.Fatalf("ETag = %q, want \"4\"", got)
+			}
+		})
+	}
+}
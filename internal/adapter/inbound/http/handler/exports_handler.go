@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"wh-ma/internal/adapter/inbound/http/request"
+	"wh-ma/internal/adapter/inbound/http/response"
+	inport "wh-ma/internal/adapter/inbound/port"
+	"wh-ma/internal/adapter/inbound/tabular"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/dto"
+)
+
+// Trailer của export đồng bộ: lỗi giữa chừng không đổi được status 200 đã gửi,
+// client đọc trailer để biết file có đầy đủ không
+const (
+	trailerExportRows  = "X-Export-Rows"
+	trailerExportError = "X-Export-Error"
+)
+
+type ExportsHandler struct {
+	svc inport.ExportsInbound
+}
+
+func NewExportsHandler(svc inport.ExportsInbound) *ExportsHandler {
+	return &ExportsHandler{svc: svc}
+}
+
+// GET /exports/:kind?format=csv|xlsx|jsonl&from=&to=&device_id=
+// Stream thẳng ra response (CSV/JSONL ghi dần theo trang; XLSX ghi khi xong).
+func (h *ExportsHandler) Stream(c *gin.Context) {
+	done := observe(c, "Export")
+	var errMsg string
+	var rows int64
+	defer func() {
+		done(slog.String("error", errMsg), slog.String("kind", c.Param("kind")), slog.Int64("rows", rows))
+	}()
+
+	var q request.ExportQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondBindErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	var device *int64
+	if q.DeviceID > 0 {
+		device = &q.DeviceID
+	}
+	cmd, err := exportCmd(c.Param("kind"), q.Format, q.From, q.To, device)
+	if err != nil {
+		respondErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+
+	hdr := c.Writer.Header()
+	hdr.Set("Content-Type", tabular.ContentType(tabular.Format(cmd.Format)))
+	hdr.Set("Content-Disposition", attachment(fmt.Sprintf("%s-%s.%s", cmd.Kind, time.Now().UTC().Format("20060102-150405"), cmd.Format)))
+	hdr.Set("Trailer", trailerExportRows+", "+trailerExportError)
+
+	rows, err = h.svc.Export(c, cmd, c.Writer)
+	switch {
+	case err != nil && !c.Writer.Written():
+		// lỗi trước byte đầu tiên (quyền, tham số): vẫn trả problem+json
+		hdr.Del("Content-Disposition")
+		hdr.Del("Trailer")
+		respondErr(c, err)
+		errMsg = err.Error()
+	case err != nil:
+		errMsg = err.Error()
+		hdr.Set(trailerExportError, err.Error())
+	default:
+		hdr.Set(trailerExportRows, strconv.FormatInt(rows, 10))
+	}
+}
+
+// POST /exports/jobs -> 202 + Location; tải file qua download_url khi status = done
+func (h *ExportsHandler) CreateJob(c *gin.Context) {
+	done := observe(c, "CreateExportJob")
+	var errMsg string
+	var id string
+	defer func() {
+		done(slog.String("error", errMsg), slog.String("job_id", id))
+	}()
+
+	var in request.CreateExportJob
+	if err := c.ShouldBindJSON(&in); err != nil {
+		respondBindErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	cmd, err := exportCmd(in.Kind, in.Format, in.From, in.To, in.DeviceID)
+	if err != nil {
+		respondErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	view, err := h.svc.StartJob(c, cmd)
+	if err != nil {
+		respondErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	id = view.Job.ID
+	c.Header("Location", exportsPrefix(c)+"/jobs/"+id)
+	c.JSON(http.StatusAccepted, response.NewExportJob(view, ""))
+}
+
+// GET /exports/jobs/:id
+func (h *ExportsHandler) GetJob(c *gin.Context) {
+	done := observe(c, "GetExportJob")
+	var errMsg string
+	defer func() {
+		done(slog.String("error", errMsg), slog.String("job_id", c.Param("id")))
+	}()
+
+	view, err := h.svc.GetJob(c, c.Param("id"))
+	if err != nil {
+		respondErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	var link string
+	if view.DownloadToken != "" {
+		link = exportsPrefix(c) + "/files/" + view.DownloadToken
+	}
+	c.JSON(http.StatusOK, response.NewExportJob(view, link))
+}
+
+// GET /exports/files/:token — không cần credential: token ký HMAC, có hạn
+func (h *ExportsHandler) Download(c *gin.Context) {
+	done := observe(c, "DownloadExport")
+	var errMsg string
+	defer func() {
+		done(slog.String("error", errMsg))
+	}()
+
+	dl, err := h.svc.Download(c, c.Param("token"))
+	if err != nil {
+		respondErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	defer dl.File.Close()
+	c.Header("Content-Type", tabular.ContentType(tabular.Format(dl.Job.Format)))
+	c.Header("Content-Disposition", attachment(dl.FileName))
+	c.Header("Cache-Control", "private, no-store")
+	http.ServeContent(c.Writer, c.Request, dl.FileName, dl.ModTime, dl.File) // hỗ trợ Range/If-Modified-Since
+}
+
+// exportCmd: đổi tham số query/body -> cmd; from/to dạng ngày tính theo UTC, to lấy hết ngày đó
+func exportCmd(kind, format, from, to string, device *int64) (dto.ExportCmd, error) {
+	cmd := dto.ExportCmd{Kind: domain.ExportKind(kind), Format: strings.ToLower(format)}
+	if cmd.Format == "" {
+		cmd.Format = string(tabular.CSV)
+	}
+	var v domain.Violations
+	parse := func(field, s string, end bool) time.Time {
+		if s == "" {
+			return time.Time{}
+		}
+		if d, err := time.Parse(time.DateOnly, s); err == nil {
+			if end {
+				d = d.AddDate(0, 0, 1)
+			}
+			return d
+		}
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			v.Add(field, "must be YYYY-MM-DD or RFC3339")
+		}
+		return t
+	}
+	cmd.Filter.From = parse("from", from, false)
+	cmd.Filter.To = parse("to", to, true)
+	if device != nil {
+		id := domain.DeviceID(*device)
+		cmd.Filter.DeviceID = &id
+	}
+	return cmd, v.Err()
+}
+
+// exportsPrefix: "/api/v1/exports" hoặc "/api/exports" theo group đang phục vụ request
+func exportsPrefix(c *gin.Context) string {
+	p := c.FullPath()
+	return p[:strings.Index(p, "/exports")+len("/exports")]
+}
+
+func attachment(name string) string {
+	return fmt.Sprintf("attachment; filename=%q", name)
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"wh-ma/internal/adapter/inbound/http/request"
+	"wh-ma/internal/adapter/inbound/tabular"
+	"wh-ma/internal/domain"
+)
+
+// MaxImportFileBytes: giới hạn kích thước file import
+const MaxImportFileBytes = 10 << 20
+
+// bindImport: multipart (file, mapping, sheet) + ?dry_run
+func bindImport(c *gin.Context) (request.ImportFile, request.ImportQuery, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, MaxImportFileBytes)
+	var in request.ImportFile
+	var q request.ImportQuery
+	if err := c.ShouldBind(&in); err != nil {
+		return in, q, err
+	}
+	err := c.ShouldBindQuery(&q)
+	return in, q, err
+}
+
+// openImport: đọc file CSV/XLSX và ánh xạ cột theo mapping + tên cột quen thuộc.
+// required: field bắt buộc phải có cột (mỗi phần tử là danh sách field thay thế nhau).
+func openImport(in request.ImportFile, columns map[string][]string, required ...[]string) (*tabular.Table, map[string]int, error) {
+	mapping := map[string]string{}
+	if in.Mapping != "" {
+		if err := json.Unmarshal([]byte(in.Mapping), &mapping); err != nil {
+			return nil, nil, domain.Invalid("mapping", "must be a JSON object {\"field\": \"column\"}")
+		}
+	}
+	format, err := tabular.FormatOf(in.File.Filename, in.File.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, nil, domain.Invalid("file", err.Error())
+	}
+	f, err := in.File.Open()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	table, err := tabular.Read(f, format, in.Sheet)
+	if err != nil {
+		return nil, nil, domain.Invalid("file", err.Error())
+	}
+	cols, err := table.Columns(mapping, columns)
+	if err != nil {
+		return nil, nil, domain.Invalid("mapping", err.Error())
+	}
+	var v domain.Violations
+	for _, alts := range required {
+		found := false
+		for _, field := range alts {
+			if _, ok := cols[field]; ok {
+				found = true
+			}
+		}
+		if !found {
+			v.Add("mapping", "no column for required field "+joinOr(alts))
+		}
+	}
+	if err := v.Err(); err != nil {
+		return nil, nil, err
+	}
+	return table, cols, nil
+}
+
+func joinOr(fields []string) string {
+	out := fields[0]
+	for _, f := range fields[1:] {
+		out += " or " + f
+	}
+	return out
+}
+
+// importStatus: 200 = dry-run (kể cả có dòng lỗi), 201 = đã ghi, 422 = có dòng lỗi, không ghi gì
+func importStatus(dryRun, committed bool) int {
+	switch {
+	case dryRun:
+		return http.StatusOK
+	case committed:
+		return http.StatusCreated
+	default:
+		return http.StatusUnprocessableEntity
+	}
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"wh-ma/internal/adapter/inbound/http/response"
+	inport "wh-ma/internal/adapter/inbound/port"
+)
+
+type JobsHandler struct {
+	svc inport.JobsInbound
+}
+
+func NewJobsHandler(svc inport.JobsInbound) *JobsHandler {
+	return &JobsHandler{svc: svc}
+}
+
+// GET /admin/jobs — job nền đã đăng ký, lịch, lần chạy gần nhất
+func (h *JobsHandler) List(c *gin.Context) {
+	done := observe(c, "ListJobs")
+	var errMsg string
+	defer func() {
+		done(slog.String("error", errMsg))
+	}()
+
+	o, err := h.svc.ListJobs(c)
+	if err != nil {
+		respondErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	c.JSON(http.StatusOK, response.NewJobsOverview(o))
+}
+
+// GET /admin/jobs/:name/runs — lịch sử chạy, mới nhất trước
+func (h *JobsHandler) ListRuns(c *gin.Context) {
+	done := observe(c, "ListJobRuns")
+	var errMsg string
+	name := c.Param("name")
+	limit, offset := parsePaging(c, 50, 0)
+	defer func() {
+		done(
+			slog.String("error", errMsg),
+			slog.String("job", name),
+			slog.Int("limit", int(limit)),
+			slog.Int("offset", int(offset)),
+		)
+	}()
+
+	runs, err := h.svc.ListRuns(c, name, limit, offset)
+	if err != nil {
+		respondErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	c.JSON(http.StatusOK, response.NewPage(runs, limit, offset, response.NewJobRun))
+}
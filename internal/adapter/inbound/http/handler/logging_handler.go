@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"wh-ma/internal/adapter/inbound/http/request"
+	"wh-ma/internal/adapter/inbound/http/response"
+	inport "wh-ma/internal/adapter/inbound/port"
+)
+
+type LoggingHandler struct {
+	svc inport.LoggingInbound
+}
+
+func NewLoggingHandler(svc inport.LoggingInbound) *LoggingHandler {
+	return &LoggingHandler{svc: svc}
+}
+
+// GET /admin/log-level
+func (h *LoggingHandler) Get(c *gin.Context) {
+	done := observe(c, "GetLogLevel")
+	var errMsg string
+	defer func() {
+		done(slog.String("error", errMsg))
+	}()
+
+	level, err := h.svc.GetLogLevel(c)
+	if err != nil {
+		respondErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	c.JSON(http.StatusOK, response.LogLevel{Level: level})
+}
+
+// PUT /admin/log-level — chỉ replica nhận request, mất khi restart
+func (h *LoggingHandler) Set(c *gin.Context) {
+	done := observe(c, "SetLogLevel")
+	var errMsg string
+	var in request.SetLogLevel
+	defer func() {
+		done(slog.String("error", errMsg), slog.String("level", in.Level))
+	}()
+	if err := c.ShouldBindJSON(&in); err != nil {
+		respondBindErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+
+	level, err := h.svc.SetLogLevel(c, in.Level)
+	if err != nil {
+		respondErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	c.JSON(http.StatusOK, response.LogLevel{Level: level})
+}
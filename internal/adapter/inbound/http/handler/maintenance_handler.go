@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"wh-ma/internal/adapter/inbound/http/request"
+	"wh-ma/internal/adapter/inbound/http/response"
+	inport "wh-ma/internal/adapter/inbound/port"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/dto"
+)
+
+type MaintenanceHandler struct {
+	svc inport.MaintenanceInbound
+}
+
+func NewMaintenanceHandler(svc inport.MaintenanceInbound) *MaintenanceHandler {
+	return &MaintenanceHandler{svc: svc}
+}
+
+// POST /devices/:id/maintenance
+func (h *MaintenanceHandler) Log(c *gin.Context) {
+	done := observe(c, "LogMaintenance")
+	var errMsg string
+	var id domain.DeviceID
+
+	defer func() {
+		done(
+			slog.String("error", errMsg),
+			slog.Int64("device_id", int64(id)),
+		)
+	}()
+
+	var ok bool
+	id, ok = parseDeviceID(c)
+	if !ok {
+		errMsg = "invalid id"
+		return
+	}
+
+	var in request.LogMaintenance
+	if err := c.ShouldBindJSON(&in); err != nil {
+		respondBindErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+
+	var at time.Time
+	if in.At != nil {
+		at = *in.At
+	}
+	ev, err := h.svc.Log(c, dto.LogMaintenanceCmd{
+		DeviceID:    id,
+		At:          at,
+		Interval:    in.Interval,
+		Notes:       in.Notes,
+		PerformedBy: in.PerformedBy,
+		Cost:        in.Cost,
+	})
+	if err != nil {
+		respondErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	c.JSON(http.StatusCreated, response.NewMaintenanceEvent(ev))
+}
+
+// GET /devices/:id/maintenance
+func (h *MaintenanceHandler) ListByDevice(c *gin.Context) {
+	done := observe(c, "ListMaintenance")
+	var errMsg string
+	var id domain.DeviceID
+	limit, offset := parsePaging(c, 50, 0)
+
+	defer func() {
+		done(
+			slog.String("error", errMsg),
+			slog.Int64("device_id", int64(id)),
+			slog.Int("limit", int(limit)),
+			slog.Int("offset", int(offset)),
+		)
+	}()
+
+	var ok bool
+	id, ok = parseDeviceID(c)
+	if !ok {
+		errMsg = "invalid id"
+		return
+	}
+
+	items, err := h.svc.ListByDevice(c, id, limit, offset)
+	if err != nil {
+		respondErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	c.JSON(http.StatusOK, response.NewPage(items, limit, offset, response.NewMaintenanceEvent))
+}
@@ -1,51 +1,26 @@
 package handler
 
 import (
-	"context"
 	"log/slog"
-	"time"
 
 	"wh-ma/internal/adapter/inbound/http/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
-// toArgs chuyển []slog.Attr -> []any để truyền vào logger.Info(...any)
-func toArgs(attrs []slog.Attr) []any {
-	out := make([]any, 0, len(attrs))
-	for _, a := range attrs {
-		out = append(out, a)
-	}
-	return out
-}
-
-// Lấy logger từ context (đã được middleware gắn); fallback = slog.Default()
-func getLogger(ctx context.Context) *slog.Logger {
-	return middleware.CtxLogger(ctx, slog.Default())
-}
-
+// observe: thuộc tính của thao tác (op + những gì handler truyền vào done) được gộp vào
+// dòng "request.done" của RequestLogMiddleware — mỗi request đúng một dòng log.
+// status đã có sẵn ở request.done (middleware ghi); error rỗng thì bỏ.
 func observe(c *gin.Context, op string) (done func(attrs ...slog.Attr)) {
-	logger := getLogger(c.Request.Context())
-	start := time.Now()
-
-	route := c.FullPath()
-	if route == "" {
-		route = c.Request.URL.Path
-	}
-
-	logger.Info("start "+op,
-		slog.String("method", c.Request.Method),
-		slog.String("path", route),
-	)
-
 	return func(attrs ...slog.Attr) {
-		base := []slog.Attr{
-			slog.String("op", op),
-			slog.String("method", c.Request.Method),
-			slog.String("path", route),
-			slog.Duration("took", time.Since(start)),
+		out := make([]slog.Attr, 0, len(attrs)+1)
+		out = append(out, slog.String("op", op))
+		for _, a := range attrs {
+			if a.Key == "error" && a.Value.String() == "" {
+				continue
+			}
+			out = append(out, a)
 		}
-		all := append(base, attrs...)
-		logger.Info("done "+op, toArgs(all)...)
+		middleware.AddLogAttrs(c, out...)
 	}
 }
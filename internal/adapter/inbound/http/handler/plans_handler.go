@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"wh-ma/internal/adapter/inbound/http/request"
+	"wh-ma/internal/adapter/inbound/http/response"
+	inport "wh-ma/internal/adapter/inbound/port"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/dto"
+)
+
+type PlansHandler struct {
+	svc inport.PlansInbound
+}
+
+func NewPlansHandler(svc inport.PlansInbound) *PlansHandler {
+	return &PlansHandler{svc: svc}
+}
+
+// POST /plans
+func (h *PlansHandler) Create(c *gin.Context) {
+	done := observe(c, "CreatePlan")
+	var errMsg string
+	defer func() {
+		done(slog.String("error", errMsg))
+	}()
+
+	var in request.UpsertPlan
+	if err := c.ShouldBindJSON(&in); err != nil {
+		respondBindErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	p, err := h.svc.Create(c, dto.CreatePlanCmd{
+		Name:          in.Name,
+		IntervalHours: in.IntervalHours,
+		Description:   in.Description,
+	})
+	if err != nil {
+		respondErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	setETag(c, p.Version)
+	c.JSON(http.StatusCreated, response.NewPlan(p))
+}
+
+// GET /plans/:id
+func (h *PlansHandler) Get(c *gin.Context) {
+	done := observe(c, "GetPlan")
+	var errMsg string
+	var id domain.PlanID
+
+	defer func() {
+		done(
+			slog.String("error", errMsg),
+			slog.Int64("plan_id", int64(id)),
+		)
+	}()
+
+	raw, ok := parseID(c)
+	if !ok {
+		errMsg = "invalid id"
+		return
+	}
+	id = domain.PlanID(raw)
+
+	p, err := h.svc.Get(c, id)
+	if err != nil {
+		respondErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	if notModified(c, p.Version) {
+		return
+	}
+	setETag(c, p.Version)
+	c.JSON(http.StatusOK, response.NewPlan(p))
+}
+
+// GET /plans
+func (h *PlansHandler) List(c *gin.Context) {
+	done := observe(c, "ListPlans")
+	var errMsg string
+	limit, offset := parsePaging(c, 50, 0)
+
+	defer func() {
+		done(
+			slog.String("error", errMsg),
+			slog.Int("limit", int(limit)),
+			slog.Int("offset", int(offset)),
+		)
+	}()
+
+	plans, err := h.svc.List(c, limit, offset)
+	if err != nil {
+		respondErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	c.JSON(http.StatusOK, response.NewPage(plans, limit, offset, response.NewPlan))
+}
+
+// PUT /plans/:id
+func (h *PlansHandler) Update(c *gin.Context) {
+	done := observe(c, "UpdatePlan")
+	var errMsg string
+	var id domain.PlanID
+
+	defer func() {
+		done(
+			slog.String("error", errMsg),
+			slog.Int64("plan_id", int64(id)),
+		)
+	}()
+
+	raw, ok := parseID(c)
+	if !ok {
+		errMsg = "invalid id"
+		return
+	}
+	id = domain.PlanID(raw)
+	version, ok := ifMatch(c)
+	if !ok {
+		errMsg = "if-match"
+		return
+	}
+
+	var in request.UpsertPlan
+	if err := c.ShouldBindJSON(&in); err != nil {
+		respondBindErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	p, err := h.svc.Update(c, dto.UpdatePlanCmd{
+		ID:            id,
+		Name:          in.Name,
+		IntervalHours: in.IntervalHours,
+		Description:   in.Description,
+		Version:       version,
+	})
+	if err != nil {
+		respondErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	setETag(c, p.Version)
+	c.JSON(http.StatusOK, response.NewPlan(p))
+}
+
+// DELETE /plans/:id
+func (h *PlansHandler) Delete(c *gin.Context) {
+	done := observe(c, "DeletePlan")
+	var errMsg string
+	var id domain.PlanID
+
+	defer func() {
+		done(
+			slog.String("error", errMsg),
+			slog.Int64("plan_id", int64(id)),
+		)
+	}()
+
+	raw, ok := parseID(c)
+	if !ok {
+		errMsg = "invalid id"
+		return
+	}
+	id = domain.PlanID(raw)
+
+	if err := h.svc.Delete(c, id); err != nil {
+		respondErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	c.Status(http.StatusNoContent) // 204
+}
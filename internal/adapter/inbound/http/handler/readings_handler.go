@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"wh-ma/internal/adapter/inbound/http/request"
+	"wh-ma/internal/adapter/inbound/http/response"
+	inport "wh-ma/internal/adapter/inbound/port"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/dto"
+)
+
+type ReadingsHandler struct {
+	svc inport.ReadingsInbound
+}
+
+func NewReadingsHandler(svc inport.ReadingsInbound) *ReadingsHandler {
+	return &ReadingsHandler{svc: svc}
+}
+
+// POST /devices/:id/readings
+func (h *ReadingsHandler) Submit(c *gin.Context) {
+	done := observe(c, "SubmitReading")
+	var errMsg string
+	var id domain.DeviceID
+
+	defer func() {
+		done(
+			slog.String("error", errMsg),
+			slog.Int64("device_id", int64(id)),
+		)
+	}()
+
+	var ok bool
+	id, ok = parseDeviceID(c)
+	if !ok {
+		errMsg = "invalid id"
+		return
+	}
+
+	var in request.SubmitReading
+	if err := c.ShouldBindJSON(&in); err != nil {
+		respondBindErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+
+	var at time.Time
+	if in.At != nil {
+		at = *in.At
+	}
+	rd, err := h.svc.Submit(c, dto.SubmitReadingCmd{
+		DeviceID:   id,
+		At:         at,
+		HoursDelta: in.HoursDelta,
+		Location:   in.Location,
+		OperatorID: in.OperatorID,
+	})
+	if err != nil {
+		respondErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	c.JSON(http.StatusCreated, response.NewReading(rd))
+}
+
+// GET /devices/:id/readings
+func (h *ReadingsHandler) ListByDevice(c *gin.Context) {
+	done := observe(c, "ListReadings")
+	var errMsg string
+	var id domain.DeviceID
+	limit, offset := parsePaging(c, 50, 0)
+
+	defer func() {
+		done(
+			slog.String("error", errMsg),
+			slog.Int64("device_id", int64(id)),
+			slog.Int("limit", int(limit)),
+			slog.Int("offset", int(offset)),
+		)
+	}()
+
+	var ok bool
+	id, ok = parseDeviceID(c)
+	if !ok {
+		errMsg = "invalid id"
+		return
+	}
+
+	items, err := h.svc.ListByDevice(c, id, limit, offset)
+	if err != nil {
+		respondErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	c.JSON(http.StatusOK, response.NewPage(items, limit, offset, response.NewReading))
+}
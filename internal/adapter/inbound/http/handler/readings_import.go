@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"wh-ma/internal/adapter/inbound/http/response"
+	"wh-ma/internal/adapter/inbound/tabular"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/dto"
+)
+
+// readingImportColumns: field -> tên cột khác được tự nhận (sổ nhật ký thường ghi "date", "meter"...)
+var readingImportColumns = map[string][]string{
+	"device_id":     nil,
+	"serial_number": {"serial", "serial_no", "sn"},
+	"at":            {"date", "time", "datetime"},
+	"hours_delta":   {"delta", "hours"},
+	"meter":         {"meter_reading", "hour_meter", "total_hours"},
+	"location":      {"site"},
+	"operator_id":   {"operator"},
+}
+
+// POST /readings/import?dry_run=true (multipart: file, mapping, sheet)
+// 200 = dry-run (kể cả có dòng lỗi), 201 = đã ghi toàn bộ, 422 = có dòng lỗi, không ghi gì (body vẫn là báo cáo)
+func (h *ReadingsHandler) Import(c *gin.Context) {
+	done := observe(c, "ImportReadings")
+	var errMsg string
+	var rows int
+	defer func() {
+		done(slog.String("error", errMsg), slog.Int("rows", rows))
+	}()
+
+	in, q, err := bindImport(c)
+	if err != nil {
+		respondBindErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	table, cols, err := openImport(in, readingImportColumns,
+		[]string{"device_id", "serial_number"}, []string{"at"}, []string{"hours_delta", "meter"})
+	if err != nil {
+		respondErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	cmd := dto.ImportReadingsCmd{DryRun: q.DryRun, Rows: make([]dto.ImportReadingRow, 0, len(table.Rows))}
+	for _, r := range table.Rows {
+		cmd.Rows = append(cmd.Rows, readingImportRow(r, cols))
+	}
+	rows = len(cmd.Rows)
+
+	rep, err := h.svc.Import(c, cmd)
+	if err != nil {
+		respondErr(c, err)
+		errMsg = err.Error()
+		return
+	}
+	status := importStatus(rep.DryRun, rep.Committed)
+	if rep.Invalid > 0 {
+		errMsg = fmt.Sprintf("%d invalid rows", rep.Invalid)
+	}
+	c.JSON(status, response.NewReadingsImportReport(rep))
+}
+
+func readingImportRow(r tabular.Row, cols map[string]int) dto.ImportReadingRow {
+	cell := func(field string) string { return r.Cell(cols, field) }
+	row := dto.ImportReadingRow{Line: r.Line, SerialNumber: cell("serial_number")}
+	var v domain.Violations
+	if s := cell("device_id"); s != "" {
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil && n > 0 {
+			row.DeviceID = domain.DeviceID(n)
+		} else {
+			v.Add("device_id", "must be a positive integer")
+		}
+	}
+	if s := cell("at"); s != "" {
+		if t, err := tabular.ParseTime(s); err == nil {
+			row.At = t
+		} else {
+			v.Add("at", err.Error())
+		}
+	}
+	intCell := func(field string) *int {
+		s := cell(field)
+		if s == "" {
+			return nil
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			v.Add(field, "must be an integer")
+			return nil
+		}
+		return &n
+	}
+	row.HoursDelta = intCell("hours_delta")
+	row.Meter = intCell("meter")
+	if s := cell("location"); s != "" {
+		row.Location = &s
+	}
+	if s := cell("operator_id"); s != "" {
+		row.OperatorID = &s
+	}
+	row.Errors = v
+	return row
+}
@@ -0,0 +1,136 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ===== Check dựng sẵn cho các subsystem =====
+
+// DBPing: mất DB thì replica không phục vụ được gì -> critical
+func DBPing(pool *pgxpool.Pool) Check {
+	return Check{
+		Name:     "db",
+		Critical: true,
+		Run: func(ctx context.Context) (any, error) {
+			return nil, pool.Ping(ctx)
+		},
+	}
+}
+
+// PoolSaturation: tỉ lệ kết nối đang bị giữ >= threshold -> degraded (request sẽ xếp hàng chờ kết nối)
+func PoolSaturation(pool *pgxpool.Pool, threshold float64) Check {
+	return Check{
+		Name: "db_pool",
+		Run: func(context.Context) (any, error) {
+			st := pool.Stat()
+			detail := map[string]any{
+				"acquired": st.AcquiredConns(),
+				"idle":     st.IdleConns(),
+				"max":      st.MaxConns(),
+			}
+			if st.MaxConns() > 0 && float64(st.AcquiredConns())/float64(st.MaxConns()) >= threshold {
+				return detail, fmt.Errorf("pool saturated: %d/%d connections acquired", st.AcquiredConns(), st.MaxConns())
+			}
+			return detail, nil
+		},
+	}
+}
+
+// SchemaVersion: schema dirty hoặc cũ hơn binary -> critical. Schema mới hơn binary (đang rolling
+// deploy) vẫn phục vụ được vì migration chỉ thêm. Version ít đổi nên cache lâu.
+func SchemaVersion(pool *pgxpool.Pool, expected uint) Check {
+	return Check{
+		Name:     "schema",
+		Critical: true,
+		TTL:      30 * time.Second,
+		Run: func(ctx context.Context) (any, error) {
+			version, dirty, err := schemaVersion(ctx, pool)
+			if err != nil {
+				return nil, err
+			}
+			detail := map[string]any{"version": version, "expected": expected, "dirty": dirty}
+			switch {
+			case dirty:
+				return detail, errors.New("last migration failed; fix it and run: whma migrate force <version>")
+			case version < expected:
+				return detail, errors.New("database schema is older than this binary; run: whma migrate up")
+			}
+			return detail, nil
+		},
+	}
+}
+
+// Heartbeat: vòng lặp nền (scheduler dispatcher...) phải đánh dấu trong maxAge gần nhất;
+// đứng lại = bị treo, replica nên bị loại khỏi vòng để replica khác nhận việc.
+func Heartbeat(name string, critical bool, last func() time.Time, maxAge time.Duration) Check {
+	return Check{
+		Name:     name,
+		Critical: critical,
+		Run: func(context.Context) (any, error) {
+			t := last()
+			if t.IsZero() {
+				return nil, errors.New("not started")
+			}
+			age := time.Since(t)
+			detail := map[string]any{"last_beat": t.UTC(), "age_ms": age.Milliseconds()}
+			if age > maxAge {
+				return detail, fmt.Errorf("no heartbeat for %s (max %s)", age.Round(time.Second), maxAge)
+			}
+			return detail, nil
+		},
+	}
+}
+
+// TCPDial: endpoint phụ trợ (collector OTLP...) có nhận kết nối không. Mặc định không critical:
+// mất telemetry không phải lý do ngừng phục vụ.
+func TCPDial(name, addr string) Check {
+	return Check{
+		Name: name,
+		TTL:  30 * time.Second,
+		Run: func(ctx context.Context) (any, error) {
+			var d net.Dialer
+			conn, err := d.DialContext(ctx, "tcp", addr)
+			if err != nil {
+				return map[string]any{"addr": addr}, err
+			}
+			_ = conn.Close()
+			return map[string]any{"addr": addr}, nil
+		},
+	}
+}
+
+// Static: subsystem không có gì để kiểm (storage in-memory) nhưng vẫn hiện trong báo cáo
+func Static(name string, detail any) Check {
+	return Check{
+		Name: name,
+		TTL:  time.Hour,
+		Run:  func(context.Context) (any, error) { return detail, nil },
+	}
+}
+
+// schemaVersion: đọc bảng schema_migrations của golang-migrate; chưa có bảng = version 0
+func schemaVersion(ctx context.Context, pool *pgxpool.Pool) (uint, bool, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+	err := pool.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return 0, false, nil
+	case errors.As(err, &pgErr) && pgErr.Code == "42P01": // undefined_table
+		return 0, false, nil
+	case err != nil:
+		return 0, false, err
+	}
+	return uint(version), dirty, nil
+}
@@ -0,0 +1,77 @@
+package health
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHeartbeat(t *testing.T) {
+	tests := []struct {
+		name    string
+		last    time.Time
+		wantErr string // rỗng = up
+	}{
+		{"not started", time.Time{}, "not started"},
+		{"recent", time.Now().Add(-time.Second), ""},
+		{"stale", time.Now().Add(-time.Minute), "no heartbeat for 1m0s (max 10s)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Heartbeat("scheduler", true, func() time.Time { return tt.last }, 10*time.Second)
+			if c.Name != "scheduler" || !c.Critical {
+				t.Fatalf("check = %s (critical %v)", c.Name, c.Critical)
+			}
+			detail, err := c.Run(context.Background())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("err = %v, want up", err)
+				}
+			} else if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("err = %v, want %q", err, tt.wantErr)
+			}
+			if tt.last.IsZero() {
+				return
+			}
+			d, ok := detail.(map[string]any)
+			if !ok || !d["last_beat"].(time.Time).Equal(tt.last.UTC()) {
+				t.Errorf("detail = %v", detail)
+			}
+		})
+	}
+
+	t.Run("non-critical", func(t *testing.T) {
+		if Heartbeat("watcher", false, time.Now, time.Second).Critical {
+			t.Fatal("critical = true, want false")
+		}
+	})
+}
+
+func TestTCPDial(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+
+	c := TCPDial("otlp", addr)
+	if c.Critical {
+		t.Error("TCPDial is critical, want non-critical")
+	}
+	if _, err := c.Run(context.Background()); err != nil {
+		t.Fatalf("listening: err = %v", err)
+	}
+	_ = ln.Close()
+	if _, err := c.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "refused") {
+		t.Fatalf("closed: err = %v, want connection refused", err)
+	}
+}
+
+func TestStatic(t *testing.T) {
+	detail, err := Static("storage", map[string]any{"kind": "memory"}).Run(context.Background())
+	if err != nil || detail.(map[string]any)["kind"] != "memory" {
+		t.Fatalf("Static = %v, %v", detail, err)
+	}
+}
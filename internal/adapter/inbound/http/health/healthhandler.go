@@ -1,23 +1,24 @@
 package health
 
 import (
-	"context"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type Handler struct {
 	startedAt time.Time
-	pool      *pgxpool.Pool
+	registry  *Registry // check do các subsystem đăng ký ở bootstrap
 }
 
-func NewHandler(pool *pgxpool.Pool) *Handler {
+func NewHandler(registry *Registry) *Handler {
+	if registry == nil {
+		registry = NewRegistry()
+	}
 	return &Handler{
 		startedAt: time.Now(),
-		pool:      pool,
+		registry:  registry,
 	}
 }
 
@@ -32,23 +33,54 @@ func (h *Handler) Liveness(c *gin.Context) {
 	})
 }
 
-// GET /readiness -> readiness (ping DB với timeout ngắn)
+type componentJSON struct {
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	Critical  bool      `json:"critical"`
+	LatencyMs float64   `json:"latency_ms"`
+	CheckedAt time.Time `json:"checked_at"`
+	Cached    bool      `json:"cached"` // kết quả lấy từ cache (TTL của check), không chạy lại
+	Error     string    `json:"error,omitempty"`
+	Detail    any       `json:"detail,omitempty"`
+}
+
+// GET /readiness[?verbose=1] -> 503 khi có check critical down (k8s/Caddy bỏ replica khỏi vòng);
+// check không critical down chỉ báo "degraded". verbose: trạng thái + latency từng component.
 func (h *Handler) Readiness(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c, 1*time.Second)
-	defer cancel()
-
-	if err := h.pool.Ping(ctx); err != nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{
-			"ok":      false,
-			"reason":  "db_unreachable",
-			"message": err.Error(),
-		})
-		return
+	start := time.Now()
+	rep := h.registry.Evaluate(c)
+
+	failing := []string{}
+	for _, res := range rep.Components {
+		if res.Status != StatusUp {
+			failing = append(failing, res.Name)
+		}
+	}
+	body := gin.H{
+		"ok":      rep.Ready(),
+		"status":  rep.Status,
+		"failing": failing,
+	}
+	if v := c.Query("verbose"); v != "" && v != "0" && v != "false" {
+		comps := make([]componentJSON, 0, len(rep.Components))
+		for _, res := range rep.Components {
+			comps = append(comps, componentJSON{
+				Name:      res.Name,
+				Status:    res.Status,
+				Critical:  res.Critical,
+				LatencyMs: float64(res.Latency.Microseconds()) / 1000,
+				CheckedAt: res.CheckedAt.UTC(),
+				Cached:    res.CheckedAt.Before(start),
+				Error:     res.Error,
+				Detail:    res.Detail,
+			})
+		}
+		body["components"] = comps
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"ok":      true,
-		"db":      "up",
-		"message": "ready",
-	})
+	code := http.StatusOK
+	if !rep.Ready() {
+		code = http.StatusServiceUnavailable
+	}
+	c.JSON(code, body)
 }
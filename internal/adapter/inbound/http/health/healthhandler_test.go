@@ -0,0 +1,121 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type readinessBody struct {
+	OK         bool            `json:"ok"`
+	Status     string          `json:"status"`
+	Failing    []string        `json:"failing"`
+	Components []componentJSON `json:"components"`
+}
+
+func readiness(t *testing.T, h *Handler, query string) (int, readinessBody) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/readiness"+query, nil)
+	h.Readiness(c)
+	var body readinessBody
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("body %s: %v", w.Body.String(), err)
+	}
+	return w.Code, body
+}
+
+func TestReadinessStatusCode(t *testing.T) {
+	up := func(context.Context) (any, error) { return nil, nil }
+	down := func(context.Context) (any, error) { return nil, errors.New("boom") }
+	tests := []struct {
+		name        string
+		checks      []Check
+		wantCode    int
+		wantStatus  string
+		wantFailing []string
+	}{
+		{"up", []Check{{Name: "db", Critical: true, Run: up}}, http.StatusOK, StatusUp, []string{}},
+		{"non-critical down: degraded, still ready", []Check{
+			{Name: "db", Critical: true, Run: up},
+			{Name: "otlp", Run: down},
+		}, http.StatusOK, StatusDegraded, []string{"otlp"}},
+		{"critical down", []Check{
+			{Name: "db", Critical: true, Run: down},
+			{Name: "otlp", Run: down},
+		}, http.StatusServiceUnavailable, StatusDown, []string{"db", "otlp"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			for _, c := range tt.checks {
+				r.Register(c)
+			}
+			code, body := readiness(t, NewHandler(r), "")
+			if code != tt.wantCode || body.Status != tt.wantStatus || body.OK != (code == http.StatusOK) {
+				t.Fatalf("code = %d, status = %s, ok = %v; want %d, %s", code, body.Status, body.OK, tt.wantCode, tt.wantStatus)
+			}
+			if len(body.Failing) != len(tt.wantFailing) {
+				t.Fatalf("failing = %v, want %v", body.Failing, tt.wantFailing)
+			}
+			for i := range body.Failing {
+				if body.Failing[i] != tt.wantFailing[i] {
+					t.Fatalf("failing = %v, want %v", body.Failing, tt.wantFailing)
+				}
+			}
+			if body.Components != nil {
+				t.Error("components in a non-verbose body")
+			}
+		})
+	}
+}
+
+func TestReadinessVerbose(t *testing.T) {
+	r := NewRegistry()
+	r.Register(Check{Name: "db", Critical: true, Run: func(context.Context) (any, error) {
+		return map[string]any{"version": 14}, nil
+	}})
+	r.Register(Check{Name: "otlp", Run: func(context.Context) (any, error) { return nil, errors.New("refused") }})
+	h := NewHandler(r)
+
+	for _, q := range []string{"?verbose=0", "?verbose=false"} {
+		if _, body := readiness(t, h, q); body.Components != nil {
+			t.Errorf("%s: components = %v, want none", q, body.Components)
+		}
+	}
+
+	_, body := readiness(t, h, "?verbose=1")
+	if len(body.Components) != 2 {
+		t.Fatalf("components = %+v", body.Components)
+	}
+	db, otlp := body.Components[0], body.Components[1]
+	if db.Name != "db" || db.Status != StatusUp || !db.Critical || db.Error != "" || db.CheckedAt.IsZero() {
+		t.Errorf("db = %+v", db)
+	}
+	if d, ok := db.Detail.(map[string]any); !ok || d["version"] != float64(14) {
+		t.Errorf("db detail = %v", db.Detail)
+	}
+	if otlp.Name != "otlp" || otlp.Status != StatusDown || otlp.Critical || otlp.Error != "refused" {
+		t.Errorf("otlp = %+v", otlp)
+	}
+	// probe trước (verbose=0/false) đã chạy check; còn trong TTL nên lần này lấy từ cache
+	if !db.Cached || !otlp.Cached {
+		t.Errorf("cached = %v, %v; want results from the earlier probes", db.Cached, otlp.Cached)
+	}
+
+	fresh := NewHandler(func() *Registry {
+		r := NewRegistry()
+		r.Register(Check{Name: "db", Run: func(context.Context) (any, error) { return nil, nil }})
+		return r
+	}())
+	if _, body := readiness(t, fresh, "?verbose=true"); len(body.Components) != 1 || body.Components[0].Cached {
+		t.Errorf("first probe components = %+v, want one uncached", body.Components)
+	}
+}
@@ -0,0 +1,142 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Trạng thái một component / cả replica
+const (
+	StatusUp       = "up"
+	StatusDown     = "down"
+	StatusDegraded = "degraded" // chỉ check không critical lỗi: vẫn nhận traffic
+)
+
+const (
+	DefaultCheckTimeout = time.Second
+	DefaultCheckTTL     = 2 * time.Second
+)
+
+// Check: một subsystem tự đăng ký. Critical lỗi -> /readiness trả 503 (k8s/Caddy bỏ replica
+// khỏi vòng); không critical chỉ báo degraded. Kết quả được cache TTL để probe dày
+// (nhiều load balancer, mỗi vài giây) không dồn tải xuống DB.
+type Check struct {
+	Name     string
+	Critical bool
+	Timeout  time.Duration // 0 = DefaultCheckTimeout
+	TTL      time.Duration // 0 = DefaultCheckTTL
+	// Run: lỗi = down; detail (có thể nil) hiện trong ?verbose=1
+	Run func(ctx context.Context) (detail any, err error)
+}
+
+type Result struct {
+	Name      string
+	Critical  bool
+	Status    string
+	Error     string
+	Detail    any
+	Latency   time.Duration
+	CheckedAt time.Time
+}
+
+type Report struct {
+	Status     string // StatusUp | StatusDegraded | StatusDown
+	Components []Result
+}
+
+// Ready: không có check critical nào down
+func (r Report) Ready() bool { return r.Status != StatusDown }
+
+type Registry struct {
+	mu     sync.Mutex
+	checks []*cachedCheck
+}
+
+type cachedCheck struct {
+	Check
+	mu   sync.Mutex // một lần chạy tại một thời điểm; probe đến sau chờ rồi dùng kết quả mới
+	last Result
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) Register(c Check) {
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultCheckTimeout
+	}
+	if c.TTL <= 0 {
+		c.TTL = DefaultCheckTTL
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, &cachedCheck{Check: c})
+}
+
+// Evaluate: các check chạy song song, mỗi check tối đa Timeout của nó
+func (r *Registry) Evaluate(ctx context.Context) Report {
+	r.mu.Lock()
+	checks := append([]*cachedCheck(nil), r.checks...)
+	r.mu.Unlock()
+
+	out := Report{Status: StatusUp, Components: make([]Result, len(checks))}
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out.Components[i] = c.result(ctx)
+		}()
+	}
+	wg.Wait()
+
+	for _, res := range out.Components {
+		switch {
+		case res.Status == StatusUp:
+		case res.Critical:
+			out.Status = StatusDown
+		case out.Status == StatusUp:
+			out.Status = StatusDegraded
+		}
+	}
+	return out
+}
+
+func (c *cachedCheck) result(ctx context.Context) Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.last.CheckedAt.IsZero() && time.Since(c.last.CheckedAt) < c.TTL {
+		return c.last
+	}
+
+	cctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.Timeout)
+	defer cancel()
+	start := time.Now()
+	detail, err := run(cctx, c.Run)
+	res := Result{Name: c.Name, Critical: c.Critical, Status: StatusUp, Detail: detail, Latency: time.Since(start), CheckedAt: time.Now()}
+	if err == nil && cctx.Err() != nil {
+		err = cctx.Err()
+	}
+	if err != nil {
+		res.Status = StatusDown
+		res.Error = err.Error()
+		if errors.Is(err, context.DeadlineExceeded) {
+			res.Error = "timed out after " + c.Timeout.String()
+		}
+	}
+	c.last = res
+	return res
+}
+
+// run: check panic = down, không làm sập probe
+func run(ctx context.Context, fn func(ctx context.Context) (any, error)) (detail any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.New("check panicked")
+		}
+	}()
+	return fn(ctx)
+}
@@ -0,0 +1,118 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// counting: check đếm số lần chạy thật (không tính lần lấy từ cache)
+func counting(name string, critical bool, ttl time.Duration, err error) (Check, *atomic.Int32) {
+	var n atomic.Int32
+	return Check{
+		Name:     name,
+		Critical: critical,
+		TTL:      ttl,
+		Run: func(context.Context) (any, error) {
+			n.Add(1)
+			return nil, err
+		},
+	}, &n
+}
+
+func TestEvaluateCachesEachCheckForItsTTL(t *testing.T) {
+	r := NewRegistry()
+	short, shortRuns := counting("short", true, 50*time.Millisecond, nil)
+	long, longRuns := counting("long", true, time.Hour, errors.New("down"))
+	r.Register(short)
+	r.Register(long)
+
+	first := r.Evaluate(context.Background())
+	second := r.Evaluate(context.Background())
+	if shortRuns.Load() != 1 || longRuns.Load() != 1 {
+		t.Fatalf("runs within TTL = %d, %d; want 1, 1", shortRuns.Load(), longRuns.Load())
+	}
+	if !second.Components[0].CheckedAt.Equal(first.Components[0].CheckedAt) {
+		t.Error("cached result has a new CheckedAt")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	r.Evaluate(context.Background())
+	if shortRuns.Load() != 2 || longRuns.Load() != 1 {
+		t.Fatalf("runs after short TTL = %d, %d; want 2, 1", shortRuns.Load(), longRuns.Load())
+	}
+	// lỗi cũng được cache như kết quả tốt
+	if res := second.Components[1]; res.Status != StatusDown || res.Error != "down" {
+		t.Errorf("cached failure = %+v", res)
+	}
+}
+
+func TestEvaluateStatus(t *testing.T) {
+	boom := errors.New("boom")
+	tests := []struct {
+		name      string
+		checks    []Check
+		want      string
+		wantReady bool
+	}{
+		{"no checks", nil, StatusUp, true},
+		{"all up", []Check{
+			{Name: "db", Critical: true, Run: func(context.Context) (any, error) { return nil, nil }},
+			{Name: "otlp", Run: func(context.Context) (any, error) { return nil, nil }},
+		}, StatusUp, true},
+		{"non-critical down", []Check{
+			{Name: "db", Critical: true, Run: func(context.Context) (any, error) { return nil, nil }},
+			{Name: "otlp", Run: func(context.Context) (any, error) { return nil, boom }},
+		}, StatusDegraded, true},
+		{"critical down", []Check{
+			{Name: "db", Critical: true, Run: func(context.Context) (any, error) { return nil, boom }},
+		}, StatusDown, false},
+		{"critical and non-critical down", []Check{
+			{Name: "otlp", Run: func(context.Context) (any, error) { return nil, boom }},
+			{Name: "db", Critical: true, Run: func(context.Context) (any, error) { return nil, boom }},
+		}, StatusDown, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			for _, c := range tt.checks {
+				r.Register(c)
+			}
+			rep := r.Evaluate(context.Background())
+			if rep.Status != tt.want || rep.Ready() != tt.wantReady {
+				t.Fatalf("status = %s, ready = %v; want %s, %v", rep.Status, rep.Ready(), tt.want, tt.wantReady)
+			}
+			if len(rep.Components) != len(tt.checks) {
+				t.Fatalf("components = %d, want %d", len(rep.Components), len(tt.checks))
+			}
+			for i, res := range rep.Components {
+				if res.Name != tt.checks[i].Name || res.Critical != tt.checks[i].Critical {
+					t.Errorf("component %d = %s (critical %v), want registration order", i, res.Name, res.Critical)
+				}
+			}
+		})
+	}
+}
+
+// Check treo hoặc panic chỉ làm component đó down, không làm treo/sập probe
+func TestEvaluateTimeoutAndPanic(t *testing.T) {
+	r := NewRegistry()
+	r.Register(Check{Name: "slow", Timeout: 20 * time.Millisecond, Run: func(ctx context.Context) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}})
+	r.Register(Check{Name: "panics", Run: func(context.Context) (any, error) { panic("bug") }})
+
+	rep := r.Evaluate(context.Background())
+	if got := rep.Components[0]; got.Status != StatusDown || got.Error != "timed out after 20ms" {
+		t.Errorf("slow = %+v", got)
+	}
+	if got := rep.Components[1]; got.Status != StatusDown || got.Error != "check panicked" {
+		t.Errorf("panics = %+v", got)
+	}
+	if rep.Status != StatusDegraded {
+		t.Errorf("status = %s, want %s", rep.Status, StatusDegraded)
+	}
+}
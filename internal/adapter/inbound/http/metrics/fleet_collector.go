@@ -0,0 +1,143 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	inport "wh-ma/internal/adapter/inbound/port"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/dto"
+)
+
+const (
+	// DefaultFleetTTL: số liệu đội máy được dùng lại trong khoảng này (mỗi lần làm mới chạy 5 query/tenant)
+	DefaultFleetTTL = time.Minute
+	// fleetRefreshTimeout: scrape không chờ DB quá lâu; lỗi/timeout thì trả số liệu cũ
+	fleetRefreshTimeout = 10 * time.Second
+)
+
+// FleetCollector: prometheus.Collector cho số liệu nghiệp vụ, query lúc scrape nhưng có cache TTL.
+// Nhiều scrape cùng lúc chỉ làm mới một lần (mutex); làm mới lỗi thì giữ snapshot cũ và
+// đếm fleet_stats_refresh_errors_total, fleet_stats_last_success_timestamp_seconds cho biết độ cũ.
+type FleetCollector struct {
+	src    inport.FleetStatsInbound
+	ttl    time.Duration
+	logger *slog.Logger
+
+	mu        sync.Mutex
+	snapshot  []dto.TenantFleetStats
+	attempt   time.Time // lần làm mới gần nhất (kể cả lỗi) -> lỗi cũng chờ hết TTL mới thử lại
+	success   time.Time
+	lastTook  time.Duration
+	errsTotal float64
+
+	devices, openAlerts, overdue                   *prometheus.Desc
+	readings, readingHours, maintEvents, maintCost *prometheus.Desc
+	lastSuccess, refreshSeconds, refreshErrors     *prometheus.Desc
+}
+
+func NewFleetCollector(src inport.FleetStatsInbound, ttl time.Duration, logger *slog.Logger) *FleetCollector {
+	if ttl <= 0 {
+		ttl = DefaultFleetTTL
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &FleetCollector{
+		src:    src,
+		ttl:    ttl,
+		logger: logger,
+
+		devices: prometheus.NewDesc("fleet_devices",
+			"Devices (not deleted) by status and site.", []string{"tenant", "status", "site"}, nil),
+		openAlerts: prometheus.NewDesc("fleet_open_alerts",
+			"Unresolved alerts by type and severity.", []string{"tenant", "type", "severity"}, nil),
+		overdue: prometheus.NewDesc("fleet_overdue_devices",
+			"Active devices past their expected maintenance date or plan interval, by site.", []string{"tenant", "site"}, nil),
+		readings: prometheus.NewDesc("fleet_readings",
+			"Stored readings by source (api, import).", []string{"tenant", "source"}, nil),
+		readingHours: prometheus.NewDesc("fleet_reading_hours",
+			"Working hours ingested through readings, by source.", []string{"tenant", "source"}, nil),
+		maintEvents: prometheus.NewDesc("fleet_maintenance_events",
+			"Recorded maintenance events.", []string{"tenant"}, nil),
+		maintCost: prometheus.NewDesc("fleet_maintenance_cost",
+			"Sum of maintenance event costs.", []string{"tenant"}, nil),
+
+		lastSuccess: prometheus.NewDesc("fleet_stats_last_success_timestamp_seconds",
+			"Unix time of the last successful fleet stats refresh.", nil, nil),
+		refreshSeconds: prometheus.NewDesc("fleet_stats_refresh_duration_seconds",
+			"Duration of the last fleet stats refresh.", nil, nil),
+		refreshErrors: prometheus.NewDesc("fleet_stats_refresh_errors_total",
+			"Failed fleet stats refreshes (stale values are served).", nil, nil),
+	}
+}
+
+// compile-time check
+var _ prometheus.Collector = (*FleetCollector)(nil)
+
+func (c *FleetCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		c.devices, c.openAlerts, c.overdue, c.readings, c.readingHours, c.maintEvents, c.maintCost,
+		c.lastSuccess, c.refreshSeconds, c.refreshErrors,
+	} {
+		ch <- d
+	}
+}
+
+func (c *FleetCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if time.Since(c.attempt) >= c.ttl {
+		c.refresh()
+	}
+
+	gauge := func(d *prometheus.Desc, v float64, labels ...string) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.GaugeValue, v, labels...)
+	}
+	for _, t := range c.snapshot {
+		st := t.Stats
+		for _, x := range st.Devices {
+			gauge(c.devices, float64(x.Count), t.Tenant, string(x.Status), x.Site)
+		}
+		for _, x := range st.OpenAlerts {
+			gauge(c.openAlerts, float64(x.Count), t.Tenant, x.Type, domain.AlertSeverity(x.Type))
+		}
+		for _, x := range st.Overdue {
+			gauge(c.overdue, float64(x.Count), t.Tenant, x.Site)
+		}
+		for _, x := range st.Readings {
+			gauge(c.readings, float64(x.Readings), t.Tenant, x.Source)
+			gauge(c.readingHours, float64(x.Hours), t.Tenant, x.Source)
+		}
+		gauge(c.maintEvents, float64(st.Maintenance.Events), t.Tenant)
+		gauge(c.maintCost, st.Maintenance.Cost, t.Tenant)
+	}
+
+	if !c.success.IsZero() {
+		gauge(c.lastSuccess, float64(c.success.UnixNano())/1e9)
+	}
+	gauge(c.refreshSeconds, c.lastTook.Seconds())
+	ch <- prometheus.MustNewConstMetric(c.refreshErrors, prometheus.CounterValue, c.errsTotal)
+}
+
+// refresh: gọi khi đang giữ mu
+func (c *FleetCollector) refresh() {
+	start := time.Now()
+	c.attempt = start
+	ctx, cancel := context.WithTimeout(context.Background(), fleetRefreshTimeout)
+	defer cancel()
+
+	stats, err := c.src.Collect(ctx)
+	c.lastTook = time.Since(start)
+	if err != nil {
+		c.errsTotal++
+		c.logger.Warn("fleet stats refresh failed, serving stale values", "error", err, "stale_since", c.success)
+		return
+	}
+	c.snapshot = stats
+	c.success = time.Now()
+}
@@ -5,7 +5,13 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
-// HTTP
+// HTTP: path là route template của Gin (/api/v1/devices/:id), request không khớp route nào
+// gom vào UnmatchedRoute để số series không phụ thuộc URL client gửi lên
+const UnmatchedRoute = "unmatched"
+
+// OtherMethod: nhãn method cho mọi method ngoài chuẩn HTTP (client gửi method tùy ý không sinh series mới)
+const OtherMethod = "OTHER"
+
 var (
 	HTTPRequestsTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
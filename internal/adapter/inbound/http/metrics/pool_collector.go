@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PoolCollector: pgxpool.Stat đọc tại lúc scrape (rẻ, chỉ lấy lock của pool).
+// Các bộ đếm tích lũy từ lúc mở pool nên xuất dạng counter.
+type PoolCollector struct {
+	pool *pgxpool.Pool
+
+	acquired, idle, constructing, total, max                      *prometheus.Desc
+	acquires, acquireSeconds, canceled, empty, created, destroyed *prometheus.Desc
+}
+
+func NewPoolCollector(pool *pgxpool.Pool) *PoolCollector {
+	desc := func(name, help string, labels ...string) *prometheus.Desc {
+		return prometheus.NewDesc("db_pool_"+name, help, labels, nil)
+	}
+	return &PoolCollector{
+		pool: pool,
+
+		acquired:     desc("acquired_conns", "Connections currently checked out of the pool."),
+		idle:         desc("idle_conns", "Idle connections in the pool."),
+		constructing: desc("constructing_conns", "Connections being established."),
+		total:        desc("total_conns", "Total connections (acquired + idle + constructing)."),
+		max:          desc("max_conns", "Configured maximum pool size."),
+
+		acquires:       desc("acquires_total", "Successful connection acquires."),
+		acquireSeconds: desc("acquire_duration_seconds_total", "Total time spent waiting for a connection."),
+		canceled:       desc("canceled_acquires_total", "Acquires canceled by their context."),
+		empty:          desc("empty_acquires_total", "Acquires that had to wait because no idle connection was available."),
+		created:        desc("new_conns_total", "Connections opened."),
+		destroyed:      desc("destroyed_conns_total", "Connections closed by the pool, by reason.", "reason"),
+	}
+}
+
+// compile-time check
+var _ prometheus.Collector = (*PoolCollector)(nil)
+
+func (c *PoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		c.acquired, c.idle, c.constructing, c.total, c.max,
+		c.acquires, c.acquireSeconds, c.canceled, c.empty, c.created, c.destroyed,
+	} {
+		ch <- d
+	}
+}
+
+func (c *PoolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.pool.Stat()
+	gauge := func(d *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.GaugeValue, v)
+	}
+	counter := func(d *prometheus.Desc, v float64, labels ...string) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.CounterValue, v, labels...)
+	}
+	gauge(c.acquired, float64(s.AcquiredConns()))
+	gauge(c.idle, float64(s.IdleConns()))
+	gauge(c.constructing, float64(s.ConstructingConns()))
+	gauge(c.total, float64(s.TotalConns()))
+	gauge(c.max, float64(s.MaxConns()))
+
+	counter(c.acquires, float64(s.AcquireCount()))
+	counter(c.acquireSeconds, s.AcquireDuration().Seconds())
+	counter(c.canceled, float64(s.CanceledAcquireCount()))
+	counter(c.empty, float64(s.EmptyAcquireCount()))
+	counter(c.created, float64(s.NewConnsCount()))
+	counter(c.destroyed, float64(s.MaxLifetimeDestroyCount()), "max_lifetime")
+	counter(c.destroyed, float64(s.MaxIdleDestroyCount()), "max_idle")
+}
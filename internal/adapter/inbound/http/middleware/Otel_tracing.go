@@ -5,6 +5,7 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -18,7 +19,10 @@ func OTelMiddleware(serviceName string) gin.HandlerFunc {
 			route = c.Request.URL.Path
 		}
 
-		ctx, span := tracer.Start(c.Request.Context(), route,
+		// traceparent/tracestate/baggage từ gateway, mobile backend: span server là con của span phía gọi
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		ctx, span := tracer.Start(ctx, route,
+			trace.WithSpanKind(trace.SpanKindServer),
 			trace.WithAttributes(
 				attribute.String("http.method", c.Request.Method),
 				attribute.String("http.route", route),
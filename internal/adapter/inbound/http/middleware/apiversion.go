@@ -0,0 +1,16 @@
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+// APIVersion gắn header API-Version cho mọi response của group.
+// successor != "" => group cũ (alias), báo Deprecation + Link tới prefix có version.
+func APIVersion(version, successor string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("API-Version", version)
+		if successor != "" {
+			c.Header("Deprecation", "true")
+			c.Header("Link", "<"+successor+">; rel=\"successor-version\"")
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"wh-ma/internal/adapter/inbound/http/problem"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/authz"
+)
+
+// Claims của access token (HS256, ký bằng JWT_SECRET)
+type AuthClaims struct {
+	Tenant string   `json:"tenant,omitempty"` // rỗng = người dùng cấp hệ thống
+	Role   string   `json:"role"`
+	Sites  []string `json:"sites,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// KeyAuthenticator: đổi API key -> Principal (usecase API keys triển khai)
+type KeyAuthenticator interface {
+	Authenticate(ctx context.Context, raw string) (domain.Principal, error)
+}
+
+// Authenticate gắn domain.Principal vào request.Context() từ một trong hai nguồn:
+//   - "Authorization: Bearer <jwt>" (người dùng)
+//   - "X-API-Key: <key>" hoặc "Authorization: ApiKey <key>" (client máy)
+//
+// Không có credential thì đi tiếp ẩn danh: usecase sẽ tự trả 401 qua authz.
+// Credential sai/hết hạn/thu hồi -> 401 problem ngay tại đây.
+func Authenticate(secret []byte, keys KeyAuthenticator) gin.HandlerFunc {
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+
+	return func(c *gin.Context) {
+		var (
+			p   domain.Principal
+			err error
+		)
+		h := c.GetHeader("Authorization")
+		key := c.GetHeader("X-API-Key")
+		if k, ok := strings.CutPrefix(h, "ApiKey "); ok {
+			key = k
+		}
+
+		switch {
+		case key != "":
+			if keys == nil {
+				err = errors.New("api key authentication is not configured")
+				break
+			}
+			p, err = keys.Authenticate(c.Request.Context(), key)
+		case h == "":
+			c.Next()
+			return
+		case strings.HasPrefix(h, "Bearer "):
+			p, err = parseToken(parser, secret, strings.TrimPrefix(h, "Bearer "))
+		default:
+			err = errors.New("unsupported authorization scheme")
+		}
+		if err != nil {
+			problem.Write(c, http.StatusUnauthorized, "unauthenticated", err.Error())
+			return
+		}
+
+		ctx := authz.WithPrincipal(c.Request.Context(), p)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+func parseToken(parser *jwt.Parser, secret []byte, raw string) (domain.Principal, error) {
+	if len(secret) == 0 {
+		return domain.Principal{}, errors.New("token authentication is not configured")
+	}
+	var claims AuthClaims
+	_, err := parser.ParseWithClaims(raw, &claims, func(*jwt.Token) (any, error) {
+		return secret, nil
+	})
+	if err != nil {
+		return domain.Principal{}, errors.New("invalid token")
+	}
+	if claims.Subject == "" || claims.Role == "" {
+		return domain.Principal{}, errors.New("token is missing sub/role")
+	}
+	return domain.Principal{
+		Subject:  claims.Subject,
+		TenantID: claims.Tenant,
+		Role:     domain.Role(claims.Role),
+		Sites:    claims.Sites,
+	}, nil
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"sync/atomic"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// CORS: danh sách origin đổi được lúc chạy (reload cấu hình) mà không dựng lại router;
+// request đang xử lý giữ handler cũ, request sau dùng handler mới.
+type CORS struct {
+	h atomic.Pointer[gin.HandlerFunc]
+}
+
+// NewCORS: origins rỗng hoặc {"*"} = mọi origin. Origin phải có dạng scheme://host
+// (cors.New panic nếu sai, bootstrap validate trước).
+func NewCORS(origins []string) *CORS {
+	c := &CORS{}
+	c.SetOrigins(origins)
+	return c
+}
+
+func (c *CORS) SetOrigins(origins []string) {
+	cfg := cors.DefaultConfig()
+	if len(origins) == 0 || (len(origins) == 1 && origins[0] == "*") {
+		cfg.AllowAllOrigins = true
+	} else {
+		cfg.AllowOrigins = origins
+	}
+	cfg.AllowHeaders = []string{"Authorization", "Content-Type", "X-API-Key", "If-Match", "If-None-Match", "traceparent", "tracestate", "baggage"}
+	cfg.ExposeHeaders = []string{"ETag", "Trace-Id", "API-Version", "Deprecation", "Link", "Location", "Content-Disposition", "X-Export-Rows", "X-Export-Error"}
+	cfg.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	h := cors.New(cfg)
+	c.h.Store(&h)
+}
+
+func (c *CORS) Handler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		(*c.h.Load())(ctx)
+	}
+}
@@ -1,15 +1,21 @@
 package middleware
 
 import (
+	"net/http"
 	"strconv"
 	"time"
 
-	"wh-ma/internal/adapter/inbound/http/metrics"
-
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+
+	"wh-ma/internal/adapter/inbound/http/metrics"
 )
 
-func PrometheusHTTP() gin.HandlerFunc {
+// HTTPMetrics: middleware duy nhất đo count/latency/status cho mọi request.
+// Nhãn path chỉ là route template; khi span được sample, trace_id gắn làm exemplar
+// để từ điểm latency trên Grafana nhảy thẳng sang trace (cần OTelMiddleware chạy trước).
+func HTTPMetrics() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
 
@@ -18,12 +24,42 @@ func PrometheusHTTP() gin.HandlerFunc {
 		lat := time.Since(start).Seconds()
 		path := c.FullPath()
 		if path == "" {
-			path = c.Request.URL.Path // khi route chưa đặt param chuẩn
+			path = metrics.UnmatchedRoute // 404/405: không dùng URL thô làm nhãn
 		}
-		method := c.Request.Method
+		method := methodLabel(c.Request.Method)
 		status := strconv.Itoa(c.Writer.Status())
 
-		metrics.HTTPRequestsTotal.WithLabelValues(path, method, status).Inc()
-		metrics.HTTPRequestDuration.WithLabelValues(path, method).Observe(lat)
+		count := metrics.HTTPRequestsTotal.WithLabelValues(path, method, status)
+		latency := metrics.HTTPRequestDuration.WithLabelValues(path, method)
+		if ex := traceExemplar(c); ex != nil {
+			count.(prometheus.ExemplarAdder).AddWithExemplar(1, ex)
+			latency.(prometheus.ExemplarObserver).ObserveWithExemplar(lat, ex)
+			return
+		}
+		count.Inc()
+		latency.Observe(lat)
+	}
+}
+
+// standardMethods: method chuẩn giữ nguyên làm nhãn, còn lại -> metrics.OtherMethod
+var standardMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true, http.MethodPut: true,
+	http.MethodPatch: true, http.MethodDelete: true, http.MethodConnect: true,
+	http.MethodOptions: true, http.MethodTrace: true,
+}
+
+func methodLabel(m string) string {
+	if standardMethods[m] {
+		return m
+	}
+	return metrics.OtherMethod
+}
+
+// traceExemplar: nil khi request không có span được sample (trace bị bỏ thì exemplar trỏ vào hư không)
+func traceExemplar(c *gin.Context) prometheus.Labels {
+	sc := trace.SpanContextFromContext(c.Request.Context())
+	if !sc.IsSampled() {
+		return nil
 	}
+	return prometheus.Labels{"trace_id": sc.TraceID().String()}
 }
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"wh-ma/internal/adapter/inbound/http/metrics"
+)
+
+func TestMethodLabel(t *testing.T) {
+	cases := map[string]string{
+		"GET":           "GET",
+		"PATCH":         "PATCH",
+		"OPTIONS":       "OPTIONS",
+		"get":           metrics.OtherMethod, // method phân biệt hoa thường
+		"PROPFIND":      metrics.OtherMethod,
+		"X-RANDOM-1234": metrics.OtherMethod,
+		"":              metrics.OtherMethod,
+	}
+	for in, want := range cases {
+		if got := methodLabel(in); got != want {
+			t.Errorf("methodLabel(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// Method lạ đếm vào series OTHER, không tạo nhãn theo giá trị client gửi.
+func TestHTTPMetricsOtherMethod(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(HTTPMetrics())
+
+	before := testutil.ToFloat64(metrics.HTTPRequestsTotal.WithLabelValues(metrics.UnmatchedRoute, metrics.OtherMethod, "404"))
+	for _, m := range []string{"FOO", "BAR"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(m, "/nowhere", nil))
+	}
+	after := testutil.ToFloat64(metrics.HTTPRequestsTotal.WithLabelValues(metrics.UnmatchedRoute, metrics.OtherMethod, "404"))
+	if after-before != 2 {
+		t.Fatalf("OTHER count grew by %v, want 2", after-before)
+	}
+	if n := testutil.CollectAndCount(metrics.HTTPRequestsTotal); n != 1 {
+		t.Fatalf("series = %d, want 1 (no series per raw method)", n)
+	}
+}
@@ -3,7 +3,6 @@ package middleware
 import (
 	"context"
 	"log/slog"
-	"os"
 	"strings"
 	"time"
 
@@ -14,14 +13,24 @@ import (
 
 type ctxLoggerKey struct{}
 
-const ginCtxLoggerKey = "logger"
+const (
+	ginCtxLoggerKey   = "logger"
+	ginCtxLogAttrsKey = "log_attrs"
+)
+
+// AddLogAttrs: handler gắn thêm thuộc tính (op, id, lỗi nghiệp vụ...) vào dòng request.done duy nhất
+func AddLogAttrs(c *gin.Context, attrs ...slog.Attr) {
+	prev, _ := c.Get(ginCtxLogAttrsKey)
+	list, _ := prev.([]slog.Attr)
+	c.Set(ginCtxLogAttrsKey, append(list, attrs...))
+}
 
 // Ghi log cho mỗi request; không crash nếu base == nil.
 // Tự đính kèm trace_id/span_id nếu OTel đã init.
 // Đồng thời gắn logger vào cả gin.Context lẫn request.Context().
 func RequestLogMiddleware(base *slog.Logger) gin.HandlerFunc {
 	if base == nil {
-		base = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{}))
+		base = slog.Default()
 	}
 
 	return func(c *gin.Context) {
@@ -60,12 +69,24 @@ func RequestLogMiddleware(base *slog.Logger) gin.HandlerFunc {
 		c.Next()
 
 		latency := time.Since(start)
-		l.Info("request.done",
-			slog.Int("status", c.Writer.Status()),
+		status := c.Writer.Status()
+		attrs := []slog.Attr{
+			slog.Int("status", status),
 			slog.Int("size", c.Writer.Size()),
 			slog.Int64("latency_ms", latency.Milliseconds()),
-			slog.String("err", strings.Join(c.Errors.Errors(), "; ")),
-		)
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("err", strings.Join(c.Errors.Errors(), "; ")))
+		}
+		if v, ok := c.Get(ginCtxLogAttrsKey); ok {
+			extra, _ := v.([]slog.Attr)
+			attrs = append(attrs, extra...)
+		}
+		level := slog.LevelInfo
+		if status >= 500 {
+			level = slog.LevelError
+		}
+		l.LogAttrs(c.Request.Context(), level, "request.done", attrs...)
 	}
 }
 
@@ -76,7 +97,7 @@ func GetLogger(c *gin.Context) *slog.Logger {
 			return l
 		}
 	}
-	return slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{}))
+	return slog.Default()
 }
 
 // CtxLogger trả logger từ context.Context (dùng cho các chỗ chỉ có ctx).
@@ -92,5 +113,5 @@ func CtxLogger(ctx context.Context, fallback *slog.Logger) *slog.Logger {
 	if fallback != nil {
 		return fallback
 	}
-	return slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{}))
+	return slog.Default()
 }
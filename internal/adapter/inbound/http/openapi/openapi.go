@@ -0,0 +1,203 @@
+// Package openapi: tài liệu OpenAPI 3 (openapi.yaml, embed vào binary) + validate request theo spec.
+//
+// openapi.yaml là nguồn sự thật của hợp đồng HTTP:
+//   - GET /openapi.json: spec dạng JSON cho integrator/codegen
+//   - GET /docs: trang tài liệu tương tác
+//   - Validator(): middleware chặn request sai schema trước khi vào handler
+//   - VerifyRoutes(): route gin nào thiếu trong spec => lỗi lúc khởi động (chống drift)
+package openapi
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/gin-gonic/gin"
+
+	"wh-ma/internal/adapter/inbound/http/problem"
+	"wh-ma/internal/domain"
+)
+
+//go:embed openapi.yaml
+var specYAML []byte
+
+// Prefix chính thức của hợp đồng v1; "/api" là alias cũ, map về cùng operation
+const (
+	V1Prefix     = "/api/v1"
+	LegacyPrefix = "/api"
+)
+
+type Spec struct {
+	doc  *openapi3.T
+	json []byte
+}
+
+// Load parse + validate spec đã embed; lỗi ở đây là lỗi build (spec hỏng)
+func Load() (*Spec, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(specYAML)
+	if err != nil {
+		return nil, fmt.Errorf("openapi: parse spec: %w", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("openapi: invalid spec: %w", err)
+	}
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("openapi: marshal spec: %w", err)
+	}
+	return &Spec{doc: doc, json: raw}, nil
+}
+
+// ===== Handlers =====
+
+// ServeJSON: GET /openapi.json
+func (s *Spec) ServeJSON(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", s.json)
+}
+
+// ServeDocs: GET /docs (Redoc render từ /openapi.json)
+func (s *Spec) ServeDocs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(docsPage))
+}
+
+// redocURL: bản Redoc cố định (không dùng "latest": bundle đổi ngầm theo CDN).
+// TODO: gắn integrity="sha384-..." (SRI) tính từ đúng file này, làm lại mỗi lần đổi version:
+//
+//	curl -sL <url> | openssl dgst -sha384 -binary | openssl base64 -A
+const redocURL = "https://cdn.redoc.ly/redoc/v2.1.5/bundles/redoc.standalone.js"
+
+const docsPage = `<!doctype html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>WH-MA API</title>
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+</head>
+<body>
+  <redoc spec-url="/openapi.json"></redoc>
+  <script src="` + redocURL + `" crossorigin="anonymous"></script>
+</body>
+</html>`
+
+// ===== Request validation =====
+
+// Validator: middleware cho group /api/v1 và /api.
+// Route gin (c.FullPath) -> operation trong spec; sai schema => problem 400 kèm danh sách field.
+// Xác thực không kiểm ở đây (middleware.Authenticate + authz lo).
+func (s *Spec) Validator() gin.HandlerFunc {
+	opts := &openapi3filter.Options{
+		MultiError:         true,
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+	}
+	return func(c *gin.Context) {
+		route, ok := s.route(c.Request.Method, c.FullPath())
+		if !ok {
+			c.Next() // route không có trong spec: VerifyRoutes đã chặn lúc khởi động
+			return
+		}
+		params := make(map[string]string, len(c.Params))
+		for _, p := range c.Params {
+			params[p.Key] = p.Value
+		}
+		err := openapi3filter.ValidateRequest(c.Request.Context(), &openapi3filter.RequestValidationInput{
+			Request:    c.Request,
+			PathParams: params,
+			Route:      route,
+			Options:    opts,
+		})
+		if err != nil {
+			problem.Error(c, validationError(err))
+			return
+		}
+		c.Next()
+	}
+}
+
+func (s *Spec) route(method, ginPath string) (*routers.Route, bool) {
+	path := specPath(ginPath)
+	item := s.doc.Paths.Value(path)
+	if item == nil {
+		return nil, false
+	}
+	op := item.GetOperation(method)
+	if op == nil {
+		return nil, false
+	}
+	return &routers.Route{Spec: s.doc, Path: path, PathItem: item, Method: method, Operation: op}, true
+}
+
+// specPath: "/api/devices/:id" -> "/api/v1/devices/{id}"
+func specPath(ginPath string) string {
+	if rest, ok := strings.CutPrefix(ginPath, LegacyPrefix+"/"); ok && !strings.HasPrefix(ginPath, V1Prefix+"/") {
+		ginPath = V1Prefix + "/" + rest
+	}
+	segs := strings.Split(ginPath, "/")
+	for i, seg := range segs {
+		if strings.HasPrefix(seg, ":") || strings.HasPrefix(seg, "*") {
+			segs[i] = "{" + seg[1:] + "}"
+		}
+	}
+	return strings.Join(segs, "/")
+}
+
+// validationError: lỗi openapi3filter -> domain validation error (field theo JSON pointer)
+func validationError(err error) error {
+	var v domain.Violations
+	var collect func(re *openapi3filter.RequestError, err error)
+	collect = func(re *openapi3filter.RequestError, err error) {
+		switch e := err.(type) {
+		case openapi3.MultiError:
+			for _, inner := range e {
+				collect(re, inner)
+			}
+		case *openapi3filter.RequestError:
+			if e.Err == nil {
+				v.Add(fieldOf(e), e.Reason)
+				return
+			}
+			collect(e, e.Err)
+		case *openapi3.SchemaError:
+			field := fieldOf(re)
+			if ptr := e.JSONPointer(); len(ptr) > 0 {
+				field = strings.Join(ptr, ".")
+			}
+			v.Add(field, e.Reason)
+		default:
+			v.Add(fieldOf(re), err.Error())
+		}
+	}
+	collect(nil, err)
+	return v.Err()
+}
+
+// fieldOf: tên tham số (path/query) hoặc "body"
+func fieldOf(re *openapi3filter.RequestError) string {
+	if re != nil && re.Parameter != nil {
+		return re.Parameter.Name
+	}
+	return "body"
+}
+
+// ===== Drift check =====
+
+// VerifyRoutes: mọi route gin phải có operation trong spec (alias /api map về /api/v1)
+func (s *Spec) VerifyRoutes(routes gin.RoutesInfo) error {
+	var missing []string
+	for _, rt := range routes {
+		if _, ok := s.route(rt.Method, rt.Path); !ok {
+			missing = append(missing, rt.Method+" "+rt.Path)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	sort.Strings(missing)
+	return fmt.Errorf("openapi: routes missing from openapi.yaml: %s", strings.Join(missing, ", "))
+}
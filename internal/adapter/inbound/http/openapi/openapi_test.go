@@ -0,0 +1,22 @@
+package openapi
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	if _, err := Load(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// /docs nạp Redoc theo version cố định
+func TestDocsPagePinsRedoc(t *testing.T) {
+	if strings.Contains(docsPage, "/latest/") {
+		t.Fatal("docs page loads Redoc from latest")
+	}
+	if !strings.Contains(docsPage, redocURL) {
+		t.Fatal("docs page does not load redocURL")
+	}
+}
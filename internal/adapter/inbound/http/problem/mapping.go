@@ -0,0 +1,120 @@
+package problem
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	"wh-ma/internal/domain"
+)
+
+// ===== Lớp map lỗi duy nhất: domain error -> status + mã ổn định =====
+
+// kinds: sentinel -> (status, mã mặc định khi lỗi không mang mã riêng)
+var kinds = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{domain.ErrValidation, http.StatusBadRequest, "validation_failed"},
+	{domain.ErrUnauthenticated, http.StatusUnauthorized, "unauthenticated"},
+	{domain.ErrForbidden, http.StatusForbidden, "forbidden"},
+	{domain.ErrNotFound, http.StatusNotFound, "not_found"},
+	{domain.ErrConflict, http.StatusConflict, "conflict"},
+	{domain.ErrPreconditionFailed, http.StatusPreconditionFailed, "precondition_failed"},
+}
+
+// FromError dựng Problem cho err; lỗi không thuộc domain => 500 và không lộ message gốc
+func FromError(err error) Problem {
+	for _, k := range kinds {
+		if !errors.Is(err, k.err) {
+			continue
+		}
+		p := Problem{Status: k.status, Code: k.code, Detail: err.Error()}
+		var de *domain.Error
+		if errors.As(err, &de) {
+			if de.Code != "" {
+				p.Code = de.Code
+			}
+			p.Errors = de.Fields
+		}
+		return p
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return Problem{Status: http.StatusGatewayTimeout, Code: "timeout", Detail: "request timed out"}
+	}
+	return Problem{Status: http.StatusInternalServerError, Code: "internal_error", Detail: "internal server error"}
+}
+
+// Error ghi problem cho err và trả status đã dùng (để handler log)
+func Error(c *gin.Context, err error) int {
+	p := FromError(err)
+	write(c, p)
+	return p.Status
+}
+
+// BindError: lỗi bind/validate request body/query (gin binding)
+func BindError(c *gin.Context, err error) int {
+	p := Problem{Status: http.StatusBadRequest, Code: "invalid_request", Detail: err.Error()}
+
+	var ve validator.ValidationErrors
+	var se *json.SyntaxError
+	var te *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &ve):
+		p.Code = "validation_failed"
+		p.Detail = "request validation failed"
+		for _, fe := range ve {
+			p.Errors = append(p.Errors, domain.FieldError{Field: fe.Field(), Message: ruleMessage(fe)})
+		}
+	case errors.As(err, &te):
+		p.Code = "validation_failed"
+		p.Detail = "request validation failed"
+		p.Errors = []domain.FieldError{{Field: te.Field, Message: "must be " + te.Type.String()}}
+	case errors.As(err, &se):
+		p.Detail = "malformed JSON body"
+	}
+	write(c, p)
+	return p.Status
+}
+
+func ruleMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		return "must be >= " + fe.Param()
+	case "max":
+		return "must be <= " + fe.Param()
+	case "oneof":
+		return "must be one of: " + fe.Param()
+	}
+	return "failed rule " + fe.Tag()
+}
+
+// UseJSONFieldNames: lỗi validate báo theo tên field JSON ("serial_number") thay vì tên struct Go
+func UseJSONFieldNames() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		for _, tag := range []string{"json", "form", "uri"} {
+			name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return f.Name
+	})
+}
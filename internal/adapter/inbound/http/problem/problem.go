@@ -0,0 +1,36 @@
+package problem
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"wh-ma/internal/domain"
+)
+
+const ContentType = "application/problem+json"
+
+// Problem: RFC 7807 (application/problem+json)
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code"` // mã lỗi ổn định cho client
+
+	Errors []domain.FieldError `json:"errors,omitempty"` // chi tiết validate từng field
+}
+
+// Write trả problem và abort chuỗi middleware/handler
+func Write(c *gin.Context, status int, code, detail string) {
+	write(c, Problem{Status: status, Code: code, Detail: detail})
+}
+
+func write(c *gin.Context, p Problem) {
+	p.Type = "about:blank"
+	p.Title = http.StatusText(p.Status)
+	p.Instance = c.Request.URL.Path
+	c.Header("Content-Type", ContentType)
+	c.AbortWithStatusJSON(p.Status, p)
+}
@@ -0,0 +1,12 @@
+package request
+
+import "time"
+
+// POST /admin/api-keys
+type CreateAPIKey struct {
+	Name      string     `json:"name" binding:"required"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1"`
+	DeviceIDs []int64    `json:"device_ids"`
+	Sites     []string   `json:"sites"`
+	ExpiresAt *time.Time `json:"expires_at"` // RFC3339; bỏ trống = không hết hạn
+}
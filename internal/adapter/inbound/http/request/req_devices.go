@@ -1,6 +1,9 @@
 package request
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // POST /devices
 type CreateDevice struct {
@@ -26,3 +29,37 @@ type UpdateBasic struct {
 type UpdatePlan struct {
 	PlanID *int64 `json:"plan_id"`
 }
+
+// PATCH /devices/:id  (Content-Type: application/merge-patch+json, RFC 7396)
+// Key vắng mặt = giữ nguyên; null = xóa giá trị. plan_id đi qua /devices/:id/plan.
+type PatchDevice struct {
+	SerialNumber   Patch[string]    `json:"serial_number"` // cần quyền devices:serial
+	Name           Patch[string]    `json:"name"`
+	Model          Patch[string]    `json:"model"`
+	Manufacturer   Patch[string]    `json:"manufacturer"`
+	Year           Patch[int]       `json:"year"`
+	CommissionDate Patch[time.Time] `json:"commission_date"` // RFC3339
+	Status         Patch[string]    `json:"status"`
+	Location       Patch[string]    `json:"location"`
+}
+
+// Patch: phân biệt key vắng mặt (Set=false) với null (Set=true, Value=nil)
+type Patch[T any] struct {
+	Set   bool
+	Value *T
+}
+
+// UnmarshalJSON chỉ được gọi khi key có mặt, kể cả khi giá trị là null
+func (p *Patch[T]) UnmarshalJSON(b []byte) error {
+	p.Set = true
+	if string(b) == "null" {
+		p.Value = nil
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	p.Value = &v
+	return nil
+}
@@ -0,0 +1,19 @@
+package request
+
+// GET /exports/:kind
+// from/to: YYYY-MM-DD (to tính cả ngày đó) hoặc RFC3339; khoảng [from, to)
+type ExportQuery struct {
+	Format   string `form:"format"` // csv (mặc định) | xlsx | jsonl
+	From     string `form:"from"`
+	To       string `form:"to"`
+	DeviceID int64  `form:"device_id" binding:"min=0"`
+}
+
+// POST /exports/jobs
+type CreateExportJob struct {
+	Kind     string `json:"kind" binding:"required"`
+	Format   string `json:"format"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	DeviceID *int64 `json:"device_id" binding:"omitempty,min=1"`
+}
@@ -0,0 +1,15 @@
+package request
+
+import "mime/multipart"
+
+// POST /devices/import, /readings/import (multipart/form-data)
+type ImportFile struct {
+	File    *multipart.FileHeader `form:"file" binding:"required"`
+	Mapping string                `form:"mapping"` // JSON {"field": "Tên cột trong file"}
+	Sheet   string                `form:"sheet"`   // XLSX; rỗng = sheet đầu tiên
+}
+
+// query của các endpoint import
+type ImportQuery struct {
+	DryRun bool `form:"dry_run"`
+}
@@ -0,0 +1,6 @@
+package request
+
+// PUT /admin/log-level
+type SetLogLevel struct {
+	Level string `json:"level" binding:"required"`
+}
@@ -0,0 +1,12 @@
+package request
+
+import "time"
+
+// POST /devices/:id/maintenance
+type LogMaintenance struct {
+	At          *time.Time `json:"at"`       // RFC3339; bỏ trống = now
+	Interval    *int       `json:"interval"` // 250, 500, 1000...
+	Notes       *string    `json:"notes"`
+	PerformedBy *string    `json:"performed_by"`
+	Cost        *string    `json:"cost"` // decimal string, ví dụ "12345.67"
+}
@@ -0,0 +1,8 @@
+package request
+
+// POST /plans, PUT /plans/:id
+type UpsertPlan struct {
+	Name          string  `json:"name" binding:"required"`
+	IntervalHours int     `json:"interval_hours" binding:"required,min=1"`
+	Description   *string `json:"description"`
+}
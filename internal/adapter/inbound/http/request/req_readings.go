@@ -0,0 +1,11 @@
+package request
+
+import "time"
+
+// POST /devices/:id/readings
+type SubmitReading struct {
+	At         *time.Time `json:"at"` // RFC3339; bỏ trống = now
+	HoursDelta int        `json:"hours_delta" binding:"min=0"`
+	Location   *string    `json:"location"`
+	OperatorID *string    `json:"operator_id"`
+}
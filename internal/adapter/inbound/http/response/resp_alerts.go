@@ -0,0 +1,28 @@
+package response
+
+import "wh-ma/internal/domain"
+
+// GET /devices/:id/alerts, POST /alerts/:id/resolve
+type Alert struct {
+	ID         int64   `json:"id"`
+	DeviceID   int64   `json:"device_id"`
+	Type       string  `json:"type"`
+	Message    string  `json:"message"`
+	Resolved   bool    `json:"resolved"`
+	ResolvedAt *string `json:"resolved_at"`
+	ResolvedBy *string `json:"resolved_by"`
+	CreatedAt  string  `json:"created_at"`
+}
+
+func NewAlert(a *domain.Alert) Alert {
+	return Alert{
+		ID:         a.ID,
+		DeviceID:   int64(a.DeviceID),
+		Type:       a.Type,
+		Message:    a.Message,
+		Resolved:   a.Resolved,
+		ResolvedAt: tsPtr(a.ResolvedAt),
+		ResolvedBy: strPtr(a.ResolvedBy),
+		CreatedAt:  ts(a.CreatedAt),
+	}
+}
@@ -0,0 +1,51 @@
+package response
+
+import "wh-ma/internal/domain"
+
+// GET/DELETE /admin/api-keys... (không bao giờ trả secret hash)
+type APIKey struct {
+	ID         int64    `json:"id"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"`
+	Scopes     []string `json:"scopes"`
+	DeviceIDs  []int64  `json:"device_ids"`
+	Sites      []string `json:"sites"`
+	ExpiresAt  *string  `json:"expires_at"`
+	LastUsedAt *string  `json:"last_used_at"`
+	RevokedAt  *string  `json:"revoked_at"`
+	CreatedAt  string   `json:"created_at"`
+	CreatedBy  *string  `json:"created_by"`
+}
+
+// POST /admin/api-keys: key gốc chỉ xuất hiện đúng một lần ở đây
+type IssuedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+func NewAPIKey(k *domain.APIKey) APIKey {
+	ids := make([]int64, 0, len(k.DeviceIDs))
+	for _, id := range k.DeviceIDs {
+		ids = append(ids, int64(id))
+	}
+	return APIKey{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     nonNil(k.Scopes),
+		DeviceIDs:  ids,
+		Sites:      nonNil(k.Sites),
+		ExpiresAt:  tsPtr(k.ExpiresAt),
+		LastUsedAt: tsPtr(k.LastUsedAt),
+		RevokedAt:  tsPtr(k.RevokedAt),
+		CreatedAt:  ts(k.CreatedAt),
+		CreatedBy:  strPtr(k.CreatedBy),
+	}
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,24 @@
+package response
+
+import "wh-ma/internal/domain"
+
+// GET /devices/:id/audit
+type DeviceAuditEntry struct {
+	ID        int64                         `json:"id"`
+	DeviceID  int64                         `json:"device_id"`
+	Action    string                        `json:"action"`
+	Actor     *string                       `json:"actor"`
+	Changes   map[string]domain.FieldChange `json:"changes"` // {"field": {"from": ..., "to": ...}}
+	CreatedAt string                        `json:"created_at"`
+}
+
+func NewDeviceAuditEntry(e *domain.DeviceAuditEntry) DeviceAuditEntry {
+	return DeviceAuditEntry{
+		ID:        e.ID,
+		DeviceID:  int64(e.DeviceID),
+		Action:    e.Action,
+		Actor:     strPtr(e.Actor),
+		Changes:   e.Changes,
+		CreatedAt: ts(e.CreatedAt),
+	}
+}
@@ -0,0 +1,46 @@
+package response
+
+import (
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/dto"
+)
+
+// POST /devices/import
+type DeviceImportReport struct {
+	DryRun    bool              `json:"dry_run"`
+	Committed bool              `json:"committed"` // false = không ghi dòng nào
+	Total     int               `json:"total"`
+	Valid     int               `json:"valid"`
+	Invalid   int               `json:"invalid"`
+	Created   int               `json:"created"`
+	Rows      []DeviceImportRow `json:"rows"`
+}
+
+type DeviceImportRow struct {
+	Line         int                 `json:"line"` // số dòng trong file (header = 1)
+	SerialNumber string              `json:"serial_number"`
+	OK           bool                `json:"ok"`
+	Errors       []domain.FieldError `json:"errors,omitempty"`
+	Device       *Device             `json:"device,omitempty"` // chỉ khi đã ghi
+}
+
+func NewDeviceImportReport(r *dto.ImportReport) DeviceImportReport {
+	out := DeviceImportReport{
+		DryRun:    r.DryRun,
+		Committed: r.Committed,
+		Total:     r.Total,
+		Valid:     r.Valid,
+		Invalid:   r.Invalid,
+		Created:   r.Created,
+		Rows:      make([]DeviceImportRow, 0, len(r.Rows)),
+	}
+	for _, row := range r.Rows {
+		x := DeviceImportRow{Line: row.Line, SerialNumber: row.SerialNumber, OK: row.OK(), Errors: row.Errors}
+		if row.Device != nil {
+			d := NewDevice(row.Device)
+			x.Device = &d
+		}
+		out.Rows = append(out.Rows, x)
+	}
+	return out
+}
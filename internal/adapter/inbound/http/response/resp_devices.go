@@ -0,0 +1,68 @@
+package response
+
+import "wh-ma/internal/domain"
+
+// GET/POST/PATCH /devices...
+type Device struct {
+	ID           int64   `json:"id"`
+	SerialNumber string  `json:"serial_number"`
+	Name         string  `json:"name"`
+	Status       string  `json:"status"`
+	PlanID       *int64  `json:"plan_id"`
+	Location     *string `json:"location"`
+
+	// profile
+	Model          *string `json:"model"`
+	Manufacturer   *string `json:"manufacturer"`
+	Year           *int    `json:"year"`
+	CommissionDate *string `json:"commission_date"`
+
+	// state
+	TotalHours        int     `json:"total_hours"`
+	AfterOverhaul     int     `json:"after_overhaul_hours"`
+	AvgDailyHours     float64 `json:"avg_daily_hours"`
+	LastReadingAt     *string `json:"last_reading_at"`
+	ExpectedNextMaint *string `json:"expected_next_maint"`
+
+	// audit
+	CreatedAt string  `json:"created_at"`
+	UpdatedAt string  `json:"updated_at"`
+	CreatedBy *string `json:"created_by"`
+	UpdatedBy *string `json:"updated_by"`
+	Version   int     `json:"version"` // = ETag; gửi lại qua If-Match khi sửa
+}
+
+func NewDevice(d *domain.Device) Device {
+	out := Device{
+		ID:           int64(d.ID),
+		SerialNumber: d.SerialNumber,
+		Name:         d.Name,
+		Status:       string(d.Status),
+		Location:     strPtr(d.State.Location),
+
+		Model:          strPtr(d.Profile.Model),
+		Manufacturer:   strPtr(d.Profile.Manufacturer),
+		CommissionDate: tsPtr(&d.Profile.CommissionDate),
+
+		TotalHours:        d.State.TotalHours,
+		AfterOverhaul:     d.State.AfterOverhaul,
+		AvgDailyHours:     d.State.AvgDailyHours,
+		LastReadingAt:     tsPtr(d.State.LastReadingAt),
+		ExpectedNextMaint: tsPtr(d.State.ExpectedNextMaint),
+
+		CreatedAt: ts(d.CreatedAt),
+		UpdatedAt: ts(d.UpdatedAt),
+		CreatedBy: strPtr(d.Audit.CreatedBy),
+		UpdatedBy: strPtr(d.Audit.UpdatedBy),
+		Version:   d.Version,
+	}
+	if d.PlanID != nil {
+		v := int64(*d.PlanID)
+		out.PlanID = &v
+	}
+	if d.Profile.Year != 0 {
+		v := d.Profile.Year
+		out.Year = &v
+	}
+	return out
+}
@@ -0,0 +1,48 @@
+package response
+
+import (
+	"wh-ma/internal/usecase/dto"
+)
+
+// POST /exports/jobs, GET /exports/jobs/:id
+type ExportJob struct {
+	ID          string  `json:"id"`
+	Kind        string  `json:"kind"`
+	Format      string  `json:"format"`
+	Status      string  `json:"status"` // queued | running | done | failed
+	From        *string `json:"from"`
+	To          string  `json:"to"`
+	DeviceID    *int64  `json:"device_id"`
+	Rows        int64   `json:"rows"`
+	Error       *string `json:"error,omitempty"`
+	CreatedBy   string  `json:"created_by"`
+	CreatedAt   string  `json:"created_at"`
+	FinishedAt  *string `json:"finished_at"`
+	ExpiresAt   string  `json:"expires_at"`
+	DownloadURL *string `json:"download_url"` // chỉ khi done; không cần credential, hết hạn cùng expires_at
+}
+
+// NewExportJob: downloadURL rỗng = chưa tải được
+func NewExportJob(v *dto.ExportJobView, downloadURL string) ExportJob {
+	j := v.Job
+	out := ExportJob{
+		ID:          j.ID,
+		Kind:        string(j.Kind),
+		Format:      j.Format,
+		Status:      string(j.Status),
+		From:        tsPtr(&j.Filter.From),
+		To:          ts(j.Filter.To),
+		Rows:        j.Rows,
+		Error:       strPtr(j.Error),
+		CreatedBy:   j.CreatedBy,
+		CreatedAt:   ts(j.CreatedAt),
+		FinishedAt:  tsPtr(j.FinishedAt),
+		ExpiresAt:   ts(j.ExpiresAt),
+		DownloadURL: strPtr(downloadURL),
+	}
+	if j.Filter.DeviceID != nil {
+		id := int64(*j.Filter.DeviceID)
+		out.DeviceID = &id
+	}
+	return out
+}
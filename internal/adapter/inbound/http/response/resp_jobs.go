@@ -0,0 +1,70 @@
+package response
+
+import (
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/dto"
+)
+
+// GET /admin/jobs: trạng thái scheduler trên replica trả lời (chỉ leader chạy job)
+type JobsOverview struct {
+	Instance string `json:"instance"`
+	Leader   bool   `json:"leader"`
+	Jobs     []Job  `json:"jobs"`
+}
+
+type Job struct {
+	Name           string  `json:"name"`
+	Schedule       string  `json:"schedule"`
+	TimeoutSeconds int64   `json:"timeout_seconds"`
+	NextRunAt      *string `json:"next_run_at"` // null khi replica này không phải leader
+	Running        bool    `json:"running"`
+	LastRun        *JobRun `json:"last_run"` // lần chạy mới nhất trên mọi replica
+}
+
+// GET /admin/jobs/:name/runs
+type JobRun struct {
+	ID          int64   `json:"id"`
+	Job         string  `json:"job"`
+	Instance    string  `json:"instance"`
+	ScheduledAt string  `json:"scheduled_at"`
+	StartedAt   string  `json:"started_at"`
+	FinishedAt  string  `json:"finished_at"`
+	DurationMs  int64   `json:"duration_ms"`
+	Status      string  `json:"status"`
+	Error       *string `json:"error"`
+	Summary     *string `json:"summary"`
+}
+
+func NewJobsOverview(o *dto.JobsOverview) JobsOverview {
+	out := JobsOverview{Instance: o.Instance, Leader: o.Leader, Jobs: make([]Job, 0, len(o.Jobs))}
+	for _, j := range o.Jobs {
+		v := Job{
+			Name:           j.Info.Name,
+			Schedule:       j.Info.Schedule,
+			TimeoutSeconds: int64(j.Info.Timeout.Seconds()),
+			NextRunAt:      tsPtr(j.Info.NextRunAt),
+			Running:        j.Info.Running,
+		}
+		if j.LastRun != nil {
+			r := NewJobRun(j.LastRun)
+			v.LastRun = &r
+		}
+		out.Jobs = append(out.Jobs, v)
+	}
+	return out
+}
+
+func NewJobRun(r *domain.JobRun) JobRun {
+	return JobRun{
+		ID:          r.ID,
+		Job:         r.Job,
+		Instance:    r.Instance,
+		ScheduledAt: ts(r.ScheduledAt),
+		StartedAt:   ts(r.StartedAt),
+		FinishedAt:  ts(r.FinishedAt),
+		DurationMs:  r.Duration().Milliseconds(),
+		Status:      string(r.Status),
+		Error:       strPtr(r.Error),
+		Summary:     strPtr(r.Summary),
+	}
+}
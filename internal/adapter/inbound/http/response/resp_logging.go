@@ -0,0 +1,6 @@
+package response
+
+// GET/PUT /admin/log-level
+type LogLevel struct {
+	Level string `json:"level"`
+}
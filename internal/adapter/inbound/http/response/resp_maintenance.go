@@ -0,0 +1,34 @@
+package response
+
+import (
+	"strconv"
+
+	"wh-ma/internal/domain"
+)
+
+// GET/POST /devices/:id/maintenance
+type MaintenanceEvent struct {
+	ID          int64   `json:"id"`
+	DeviceID    int64   `json:"device_id"`
+	At          string  `json:"at"`
+	Interval    *int    `json:"interval"`
+	Notes       *string `json:"notes"`
+	PerformedBy *string `json:"performed_by"`
+	Cost        string  `json:"cost"` // decimal dạng chuỗi, vd "120.50" (giống request)
+}
+
+func NewMaintenanceEvent(m *domain.MaintenanceEvent) MaintenanceEvent {
+	out := MaintenanceEvent{
+		ID:          m.ID,
+		DeviceID:    int64(m.DeviceID),
+		At:          ts(m.At),
+		Notes:       strPtr(m.Notes),
+		PerformedBy: strPtr(m.PerformedBy),
+		Cost:        strconv.FormatFloat(m.Cost, 'f', 2, 64),
+	}
+	if m.Interval != 0 {
+		v := m.Interval
+		out.Interval = &v
+	}
+	return out
+}
@@ -0,0 +1,28 @@
+package response
+
+import "wh-ma/internal/domain"
+
+// GET/POST/PUT /plans...
+type Plan struct {
+	ID            int64   `json:"id"`
+	Name          string  `json:"name"`
+	IntervalHours int     `json:"interval_hours"`
+	Description   *string `json:"description"`
+	Shared        bool    `json:"shared"` // template dùng chung, tenant chỉ đọc
+	CreatedAt     string  `json:"created_at"`
+	UpdatedAt     string  `json:"updated_at"`
+	Version       int     `json:"version"` // = ETag
+}
+
+func NewPlan(p *domain.Plan) Plan {
+	return Plan{
+		ID:            int64(p.ID),
+		Name:          p.Name,
+		IntervalHours: p.IntervalHours,
+		Description:   p.Description,
+		Shared:        p.Shared(),
+		CreatedAt:     ts(p.CreatedAt),
+		UpdatedAt:     ts(p.UpdatedAt),
+		Version:       p.Version,
+	}
+}
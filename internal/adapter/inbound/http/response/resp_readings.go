@@ -0,0 +1,24 @@
+package response
+
+import "wh-ma/internal/domain"
+
+// GET/POST /devices/:id/readings
+type Reading struct {
+	ID         int64   `json:"id"`
+	DeviceID   int64   `json:"device_id"`
+	At         string  `json:"at"`
+	HoursDelta int     `json:"hours_delta"`
+	Location   *string `json:"location"`
+	OperatorID *string `json:"operator_id"`
+}
+
+func NewReading(r *domain.Reading) Reading {
+	return Reading{
+		ID:         r.ID,
+		DeviceID:   int64(r.DeviceID),
+		At:         ts(r.At),
+		HoursDelta: r.HoursDelta,
+		Location:   strPtr(r.Location),
+		OperatorID: strPtr(r.OperatorID),
+	}
+}
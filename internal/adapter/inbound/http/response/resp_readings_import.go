@@ -0,0 +1,81 @@
+package response
+
+import (
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/dto"
+)
+
+// POST /readings/import
+type ReadingsImportReport struct {
+	DryRun     bool                        `json:"dry_run"`
+	Committed  bool                        `json:"committed"` // false = không ghi dòng nào
+	Total      int                         `json:"total"`
+	Valid      int                         `json:"valid"`
+	Invalid    int                         `json:"invalid"`
+	Duplicates int                         `json:"duplicates"`
+	Created    int                         `json:"created"`
+	Rows       []ReadingImportRow          `json:"rows"`
+	Devices    []ReadingImportDeviceResult `json:"devices"` // state sau import (dry-run: dự kiến)
+}
+
+type ReadingImportRow struct {
+	Line        int                 `json:"line"` // số dòng trong file (header = 1)
+	DeviceID    *int64              `json:"device_id"`
+	At          *string             `json:"at"`
+	HoursDelta  int                 `json:"hours_delta"` // sau khi đổi meter -> delta
+	OK          bool                `json:"ok"`
+	DuplicateOf *int                `json:"duplicate_of,omitempty"` // dòng trùng hệt, bỏ qua
+	Errors      []domain.FieldError `json:"errors,omitempty"`
+}
+
+type ReadingImportDeviceResult struct {
+	DeviceID          int64   `json:"device_id"`
+	Readings          int     `json:"readings"`
+	HoursAdded        int     `json:"hours_added"`
+	TotalHours        int     `json:"total_hours"`
+	AfterOverhaul     int     `json:"after_overhaul_hours"`
+	LastReadingAt     string  `json:"last_reading_at"`
+	AvgDailyHours     float64 `json:"avg_daily_hours"`
+	ExpectedNextMaint *string `json:"expected_next_maint"`
+	MaintenanceDue    bool    `json:"maintenance_due"`
+}
+
+func NewReadingsImportReport(r *dto.ReadingsImportReport) ReadingsImportReport {
+	out := ReadingsImportReport{
+		DryRun:     r.DryRun,
+		Committed:  r.Committed,
+		Total:      r.Total,
+		Valid:      r.Valid,
+		Invalid:    r.Invalid,
+		Duplicates: r.Duplicates,
+		Created:    r.Created,
+		Rows:       make([]ReadingImportRow, 0, len(r.Rows)),
+		Devices:    make([]ReadingImportDeviceResult, 0, len(r.Devices)),
+	}
+	for _, row := range r.Rows {
+		x := ReadingImportRow{Line: row.Line, At: tsPtr(&row.At), HoursDelta: row.HoursDelta, OK: row.OK(), Errors: row.Errors}
+		if row.DeviceID != 0 {
+			id := int64(row.DeviceID)
+			x.DeviceID = &id
+		}
+		if row.DuplicateOf > 0 {
+			line := row.DuplicateOf
+			x.DuplicateOf = &line
+		}
+		out.Rows = append(out.Rows, x)
+	}
+	for _, d := range r.Devices {
+		out.Devices = append(out.Devices, ReadingImportDeviceResult{
+			DeviceID:          int64(d.DeviceID),
+			Readings:          d.Readings,
+			HoursAdded:        d.HoursAdded,
+			TotalHours:        d.TotalHours,
+			AfterOverhaul:     d.AfterOverhaul,
+			LastReadingAt:     ts(d.LastReadingAt),
+			AvgDailyHours:     d.AvgDailyHours,
+			ExpectedNextMaint: tsPtr(d.ExpectedNextMaint),
+			MaintenanceDue:    d.MaintenanceDue,
+		})
+	}
+	return out
+}
@@ -0,0 +1,47 @@
+// Package response: hợp đồng JSON trả cho client (v1).
+//
+// Handler không serialize domain trực tiếp: đổi field trong domain không được làm đổi wire format.
+// Quy ước v1: field snake_case, thời gian RFC3339 (UTC), không trả field nội bộ
+// (tenant_id, deleted_*, secret hash...). Thay đổi phá vỡ tương thích => thêm v2, không sửa v1.
+package response
+
+import "time"
+
+// Version của hợp đồng hiện tại (header API-Version, prefix /api/v1)
+const Version = "1"
+
+// Page: envelope cho mọi endpoint dạng danh sách
+type Page[T any] struct {
+	Items  []T   `json:"items"`
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+// NewPage map từng phần tử domain sang response
+func NewPage[D any, T any](items []D, limit, offset int32, conv func(D) T) Page[T] {
+	out := make([]T, 0, len(items))
+	for _, it := range items {
+		out = append(out, conv(it))
+	}
+	return Page[T]{Items: out, Limit: limit, Offset: offset}
+}
+
+// --- helpers thời gian ---
+func ts(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func tsPtr(t *time.Time) *string {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	s := ts(*t)
+	return &s
+}
+
+func strPtr(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,12 @@
+package router
+
+import (
+	"wh-ma/internal/adapter/inbound/http/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func MountAlerts(rg *gin.RouterGroup, h *handler.AlertsHandler) {
+	rg.GET("/devices/:id/alerts", h.ListOpenByDevice)
+	rg.POST("/alerts/:id/resolve", h.Resolve)
+}
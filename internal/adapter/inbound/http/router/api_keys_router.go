@@ -0,0 +1,14 @@
+package router
+
+import (
+	"wh-ma/internal/adapter/inbound/http/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func MountAPIKeys(rg *gin.RouterGroup, h *handler.APIKeysHandler) {
+	g := rg.Group("/admin/api-keys")
+	g.POST("", h.Create)
+	g.GET("", h.List)
+	g.DELETE("/:id", h.Revoke)
+}
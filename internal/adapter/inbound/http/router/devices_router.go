@@ -10,8 +10,10 @@ func MountDevices(rg *gin.RouterGroup, h *handler.DevicesHandler) {
 	g := rg.Group("/devices")
 	g.POST("", h.Create)
 	g.GET("", h.List)
+	g.POST("/import", h.Import) // multipart CSV/XLSX; ?dry_run=true
 	g.GET("/:id", h.Get)
-	g.PATCH("/:id", h.UpdateBasic)
+	g.PATCH("/:id", h.Update) // merge-patch+json hoặc json cũ
+	g.GET("/:id/audit", h.ListAudit)
 	g.PATCH("/:id/plan", h.UpdatePlan)
 	g.DELETE("/:id", h.SoftDelete)
 }
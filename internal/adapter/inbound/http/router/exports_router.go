@@ -0,0 +1,15 @@
+package router
+
+import (
+	"wh-ma/internal/adapter/inbound/http/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func MountExports(rg *gin.RouterGroup, h *handler.ExportsHandler) {
+	g := rg.Group("/exports")
+	g.GET("/:kind", h.Stream) // devices | readings | maintenance | alerts
+	g.POST("/jobs", h.CreateJob)
+	g.GET("/jobs/:id", h.GetJob)
+	g.GET("/files/:token", h.Download) // link ký, không cần credential
+}
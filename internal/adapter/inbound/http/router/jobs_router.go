@@ -0,0 +1,13 @@
+package router
+
+import (
+	"wh-ma/internal/adapter/inbound/http/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func MountJobs(rg *gin.RouterGroup, h *handler.JobsHandler) {
+	g := rg.Group("/admin/jobs")
+	g.GET("", h.List)
+	g.GET("/:name/runs", h.ListRuns)
+}
@@ -0,0 +1,13 @@
+package router
+
+import (
+	"wh-ma/internal/adapter/inbound/http/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func MountLogging(rg *gin.RouterGroup, h *handler.LoggingHandler) {
+	g := rg.Group("/admin/log-level")
+	g.GET("", h.Get)
+	g.PUT("", h.Set)
+}
@@ -0,0 +1,13 @@
+package router
+
+import (
+	"wh-ma/internal/adapter/inbound/http/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func MountMaintenance(rg *gin.RouterGroup, h *handler.MaintenanceHandler) {
+	g := rg.Group("/devices/:id/maintenance")
+	g.POST("", h.Log)
+	g.GET("", h.ListByDevice)
+}
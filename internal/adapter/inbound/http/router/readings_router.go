@@ -0,0 +1,15 @@
+package router
+
+import (
+	"wh-ma/internal/adapter/inbound/http/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func MountReadings(rg *gin.RouterGroup, h *handler.ReadingsHandler) {
+	g := rg.Group("/devices/:id/readings")
+	g.POST("", h.Submit)
+	g.GET("", h.ListByDevice)
+
+	rg.POST("/readings/import", h.Import) // sổ nhật ký CSV/XLSX nhiều device; ?dry_run=true
+}
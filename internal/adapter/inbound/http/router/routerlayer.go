@@ -4,63 +4,79 @@ import (
 	"log/slog"
 	"net/http"
 
-	"github.com/gin-contrib/cors"
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"wh-ma/internal/adapter/inbound/http/health"
 	"wh-ma/internal/adapter/inbound/http/middleware"
+	"wh-ma/internal/adapter/inbound/http/openapi"
+	"wh-ma/internal/adapter/inbound/http/problem"
 )
 
 // Options cho Router.New để cấu hình CORS/mode
 type Options struct {
-	AppEnv      string   // "production" => gin.ReleaseMode
-	AllowOrigin []string // []{"*"} => AllowAllOrigins
+	AppEnv    string           // "production" => gin.ReleaseMode
+	CORS      *middleware.CORS // nil => mọi origin
+	JWTSecret []byte           // HS256 secret để xác thực Bearer token
+	APIKeys   middleware.KeyAuthenticator
+	OpenAPI   *openapi.Spec    // nil => không publish /openapi.json, /docs
+	Health    *health.Registry // check của các subsystem cho /readiness (nil = luôn sẵn sàng)
+}
+
+// metricsHandler: như promhttp.Handler() nhưng cho phép OpenMetrics (định dạng duy nhất mang exemplar)
+func metricsHandler() http.Handler {
+	return promhttp.InstrumentMetricHandler(prometheus.DefaultRegisterer,
+		promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{EnableOpenMetrics: true}))
 }
 
 // New tạo *gin.Engine với middleware & infra endpoints
 // - Recovery, RequestID, Logger
 // - CORS
+// - Authenticate (Bearer JWT / API key -> Principal)
 // - Prometheus middleware + /metrics
 // - /healthz, /readiness
+// - /openapi.json, /docs
 //
 // Domain endpoints (devices, plans, alerts, readings) sẽ được mount từ bootstrap
 // qua các hàm router.Mount* vào group /api.
-func New(p *pgxpool.Pool, baseLogger *slog.Logger, opt Options) *gin.Engine {
+func New(baseLogger *slog.Logger, opt Options) *gin.Engine {
 	if opt.AppEnv == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
+	problem.UseJSONFieldNames()
+
 	r := gin.New()
+	// handler truyền *gin.Context xuống usecase -> cần đọc được principal/span trong request.Context()
+	r.ContextWithFallback = true
 
 	// Middlewares nền tảng
 	r.Use(gin.Recovery())
 	r.Use(requestid.New())
 	r.Use(middleware.OTelMiddleware("wh-ma-api"))
 	r.Use(middleware.RequestLogMiddleware(baseLogger)) // logger có request-id
-	r.Use(middleware.PromMetrics())
-	r.Use(middleware.PrometheusHTTP()) // đo count/latency/status cho mọi request
+	r.Use(middleware.HTTPMetrics())                    // đo count/latency/status cho mọi request (sau OTel: exemplar trace_id)
 
-	// CORS
-	c := cors.DefaultConfig()
-	if len(opt.AllowOrigin) == 1 && opt.AllowOrigin[0] == "*" {
-		c.AllowAllOrigins = true
-	} else if len(opt.AllowOrigin) > 0 {
-		c.AllowOrigins = opt.AllowOrigin
-	} else {
-		c.AllowAllOrigins = true
+	// CORS (danh sách origin reload được, xem middleware.CORS)
+	if opt.CORS == nil {
+		opt.CORS = middleware.NewCORS(nil)
 	}
-	c.AllowHeaders = []string{"Authorization", "Content-Type"}
-	c.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
-	r.Use(cors.New(c))
+	r.Use(opt.CORS.Handler())
+
+	// AuthN: gắn Principal; AuthZ do usecase kiểm tra theo policy
+	r.Use(middleware.Authenticate(opt.JWTSecret, opt.APIKeys))
 
 	// Infra endpoints
-	h := health.NewHandler(p)
-	r.GET("/healthz", h.Liveness)                    // liveness: không ping DB
-	r.GET("/readiness", h.Readiness)                 // readiness: ping DB ngắn
-	r.GET("/metrics", gin.WrapH(promhttp.Handler())) // Prometheus scrape
+	h := health.NewHandler(opt.Health)
+	r.GET("/healthz", h.Liveness)                  // liveness: không ping DB
+	r.GET("/readiness", h.Readiness)               // readiness: check đã đăng ký (?verbose=1 từng component)
+	r.GET("/metrics", gin.WrapH(metricsHandler())) // Prometheus scrape
+	if opt.OpenAPI != nil {
+		r.GET("/openapi.json", opt.OpenAPI.ServeJSON) // OpenAPI 3 spec
+		r.GET("/docs", opt.OpenAPI.ServeDocs)         // tài liệu tương tác
+	}
 
 	// Ping root (optional)
 	r.GET("/", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"ok": true}) })
@@ -0,0 +1,11 @@
+package port
+
+import (
+	"context"
+	"wh-ma/internal/domain"
+)
+
+type AlertsInbound interface {
+	ListOpenByDevice(ctx context.Context, deviceID domain.DeviceID, limit, offset int32) ([]*domain.Alert, error)
+	Resolve(ctx context.Context, id int64) (*domain.Alert, error)
+}
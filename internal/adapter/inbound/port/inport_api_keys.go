@@ -0,0 +1,16 @@
+package port
+
+import (
+	"context"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/dto"
+)
+
+type APIKeysInbound interface {
+	Create(ctx context.Context, in dto.CreateAPIKeyCmd) (*dto.IssuedAPIKey, error)
+	List(ctx context.Context, limit, offset int32) ([]*domain.APIKey, error)
+	Revoke(ctx context.Context, id int64) (*domain.APIKey, error)
+
+	// Authenticate: key gốc -> Principal (middleware gọi cho mỗi request có key)
+	Authenticate(ctx context.Context, raw string) (domain.Principal, error)
+}
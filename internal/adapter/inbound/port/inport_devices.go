@@ -22,4 +22,11 @@ type DevicesInbound interface {
 
 	// 4) SoftDelete
 	SoftDelete(ctx context.Context, id domain.DeviceID) error
+
+	// 6) Patch (JSON Merge Patch toàn bộ hồ sơ) + nhật ký thay đổi
+	Patch(ctx context.Context, in dto.PatchDeviceCmd) (*domain.Device, error)
+	ListAudit(ctx context.Context, id domain.DeviceID, limit, offset int32) ([]*domain.DeviceAuditEntry, error)
+
+	// 7) Import hàng loạt: báo cáo từng dòng, dry-run hoặc ghi tất cả-hoặc-không
+	Import(ctx context.Context, in dto.ImportDevicesCmd) (*dto.ImportReport, error)
 }
@@ -0,0 +1,20 @@
+package port
+
+import (
+	"context"
+	"io"
+
+	"wh-ma/internal/usecase/dto"
+)
+
+type ExportsInbound interface {
+	// Export đồng bộ: stream thẳng ra w theo từng trang, trả số dòng dữ liệu đã ghi
+	Export(ctx context.Context, in dto.ExportCmd, w io.Writer) (int64, error)
+
+	// Export lớn: chạy nền, kết quả tải qua link ký có hạn
+	StartJob(ctx context.Context, in dto.ExportCmd) (*dto.ExportJobView, error)
+	GetJob(ctx context.Context, id string) (*dto.ExportJobView, error)
+
+	// Download: mở file theo token của link tải (không cần principal, tenant nằm trong token)
+	Download(ctx context.Context, token string) (*dto.ExportDownload, error)
+}
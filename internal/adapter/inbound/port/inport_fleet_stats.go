@@ -0,0 +1,12 @@
+package port
+
+import (
+	"context"
+
+	"wh-ma/internal/usecase/dto"
+)
+
+type FleetStatsInbound interface {
+	// Collect: số liệu của mọi tenant (collector Prometheus gọi, không qua authz, không có principal)
+	Collect(ctx context.Context) ([]dto.TenantFleetStats, error)
+}
@@ -0,0 +1,18 @@
+package port
+
+import (
+	"context"
+
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/dto"
+)
+
+type JobsInbound interface {
+	// Trạng thái scheduler + job đã đăng ký (admin)
+	ListJobs(ctx context.Context) (*dto.JobsOverview, error)
+	// Lịch sử chạy của một job, mới nhất trước (admin)
+	ListRuns(ctx context.Context, job string, limit, offset int32) ([]*domain.JobRun, error)
+
+	// RecordRun: scheduler ghi kết quả mỗi lần chạy (không qua authz, không có principal)
+	RecordRun(ctx context.Context, run *domain.JobRun) error
+}
@@ -0,0 +1,9 @@
+package port
+
+import "context"
+
+type LoggingInbound interface {
+	// Log level hiện tại / đổi log level của replica này (admin)
+	GetLogLevel(ctx context.Context) (string, error)
+	SetLogLevel(ctx context.Context, level string) (string, error)
+}
@@ -0,0 +1,12 @@
+package port
+
+import (
+	"context"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/dto"
+)
+
+type MaintenanceInbound interface {
+	Log(ctx context.Context, in dto.LogMaintenanceCmd) (*domain.MaintenanceEvent, error)
+	ListByDevice(ctx context.Context, deviceID domain.DeviceID, limit, offset int32) ([]*domain.MaintenanceEvent, error)
+}
@@ -0,0 +1,15 @@
+package port
+
+import (
+	"context"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/dto"
+)
+
+type PlansInbound interface {
+	Create(ctx context.Context, in dto.CreatePlanCmd) (*domain.Plan, error)
+	Get(ctx context.Context, id domain.PlanID) (*domain.Plan, error)
+	List(ctx context.Context, limit, offset int32) ([]*domain.Plan, error)
+	Update(ctx context.Context, in dto.UpdatePlanCmd) (*domain.Plan, error)
+	Delete(ctx context.Context, id domain.PlanID) error
+}
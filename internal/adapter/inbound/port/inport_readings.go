@@ -0,0 +1,16 @@
+package port
+
+import (
+	"context"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/dto"
+)
+
+type ReadingsInbound interface {
+	// Ghi nhận giờ vận hành + cập nhật state/dự báo của device
+	Submit(ctx context.Context, in dto.SubmitReadingCmd) (*domain.Reading, error)
+	ListByDevice(ctx context.Context, deviceID domain.DeviceID, limit, offset int32) ([]*domain.Reading, error)
+
+	// Import lịch sử (sổ nhật ký): kiểm như Submit, tính lại state/dự báo một lần mỗi device
+	Import(ctx context.Context, in dto.ImportReadingsCmd) (*dto.ReadingsImportReport, error)
+}
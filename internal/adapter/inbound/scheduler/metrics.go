@@ -0,0 +1,56 @@
+package scheduler
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	jobRunsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "job_runs_total",
+			Help: "Background job runs by outcome (ok, failed, timeout, canceled).",
+		},
+		[]string{"job", "status"},
+	)
+
+	jobRunDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "job_run_duration_seconds",
+			Help:    "Duration of background job runs.",
+			Buckets: []float64{0.1, 0.5, 1, 5, 15, 30, 60, 300, 900, 1800, 3600},
+		},
+		[]string{"job"},
+	)
+
+	jobLastSuccess = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "job_last_success_timestamp_seconds",
+			Help: "Unix time of the last successful run of a background job on this instance.",
+		},
+		[]string{"job"},
+	)
+
+	jobsRunning = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "jobs_running",
+			Help: "Background jobs currently running on this instance.",
+		},
+		[]string{"job"},
+	)
+
+	jobRunsSkipped = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "job_runs_skipped_total",
+			Help: "Scheduled runs skipped because the previous run of the job was still going.",
+		},
+		[]string{"job"},
+	)
+
+	schedulerIsLeader = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "scheduler_is_leader",
+			Help: "1 if this instance holds the scheduler leader lock and runs background jobs.",
+		},
+	)
+)
@@ -0,0 +1,405 @@
+// Package scheduler: chạy job nền theo lịch cron song song với HTTP server.
+//
+// Mọi replica đều chạy Scheduler nhưng chỉ replica giữ LeaderLock (PG: advisory lock) mới chạy job;
+// replica khác thử nhận quyền định kỳ và thay thế khi leader chết (connection giữ lock đứt).
+// Mỗi lần chạy có timeout riêng, kết quả (thời lượng, trạng thái, lỗi, summary) được ghi qua
+// JobsInbound.RecordRun và đếm vào Prometheus. Khi server dừng: không khởi động job mới,
+// chờ job đang chạy xong tối đa Drain rồi mới hủy ctx của chúng.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	inport "wh-ma/internal/adapter/inbound/port"
+	outport "wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+)
+
+const (
+	// DefaultLeaderPoll: chu kỳ thử nhận quyền (replica thường) / kiểm còn giữ quyền (leader)
+	DefaultLeaderPoll = 15 * time.Second
+	// DefaultDrain: lúc dừng, chờ job đang chạy tối đa bao lâu trước khi hủy
+	DefaultDrain = 30 * time.Second
+
+	tick           = time.Second      // độ phân giải lịch (cron tính theo phút)
+	recordTimeout  = 5 * time.Second  // ghi job_runs sau khi job xong
+	abandonAfter   = 10 * time.Second // sau khi hủy, chờ job dừng thêm bao lâu rồi bỏ mặc
+	releaseTimeout = 3 * time.Second  // trả lock lúc dừng; quá hạn thì đóng connection giữ lock
+)
+
+// Job: Schedule là biểu thức cron 5 trường ("15 2 * * *"), @hourly/@daily hoặc "@every 15m";
+// giờ theo múi giờ local của process, đổi bằng tiền tố "CRON_TZ=Asia/Ho_Chi_Minh ".
+// Run nhận ctx bị hủy khi quá Timeout, mất quyền leader hoặc hết thời gian drain.
+type Job struct {
+	Name     string
+	Schedule string
+	Timeout  time.Duration
+	Run      func(ctx context.Context) (summary string, err error)
+}
+
+type Options struct {
+	Instance   string        // tên replica trong job_runs; rỗng = hostname-pid
+	LeaderPoll time.Duration // 0 = DefaultLeaderPoll
+	Drain      time.Duration // 0 = DefaultDrain
+	Logger     *slog.Logger  // nil = slog.Default()
+}
+
+type Scheduler struct {
+	jobs []*entry
+	lock outport.LeaderLock
+	opt  Options
+	log  *slog.Logger
+
+	mu       sync.Mutex
+	leader   bool
+	term     context.Context    // ctx của job trong nhiệm kỳ leader hiện tại
+	termStop context.CancelFunc // hủy term: mất quyền hoặc hết thời gian drain
+	wg       sync.WaitGroup     // job đang chạy
+
+	abandonAfter time.Duration // = abandonAfter; test rút ngắn
+
+	heartbeat atomic.Int64 // unix nano của vòng lặp gần nhất (health: dispatcher có bị treo không)
+}
+
+type entry struct {
+	Job
+	sched   cron.Schedule
+	next    time.Time // zero khi không phải leader
+	running bool
+}
+
+// compile-time check: usecase đọc trạng thái job qua JobCatalog
+var _ outport.JobCatalog = (*Scheduler)(nil)
+
+func New(jobs []Job, lock outport.LeaderLock, opt Options) (*Scheduler, error) {
+	if opt.Instance == "" {
+		host, _ := os.Hostname()
+		opt.Instance = fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+	if opt.LeaderPoll <= 0 {
+		opt.LeaderPoll = DefaultLeaderPoll
+	}
+	if opt.Drain <= 0 {
+		opt.Drain = DefaultDrain
+	}
+	if opt.Logger == nil {
+		opt.Logger = slog.Default()
+	}
+	s := &Scheduler{lock: lock, opt: opt, log: opt.Logger.With(slog.String("component", "scheduler")), abandonAfter: abandonAfter}
+	seen := map[string]bool{}
+	for _, j := range jobs {
+		if j.Name == "" || j.Run == nil {
+			return nil, errors.New("scheduler: job needs a name and a run function")
+		}
+		if seen[j.Name] {
+			return nil, fmt.Errorf("scheduler: duplicate job %q", j.Name)
+		}
+		seen[j.Name] = true
+		if j.Timeout <= 0 {
+			return nil, fmt.Errorf("scheduler: job %s: timeout must be positive", j.Name)
+		}
+		sched, err := cron.ParseStandard(j.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("scheduler: job %s: schedule %q: %w", j.Name, j.Schedule, err)
+		}
+		s.jobs = append(s.jobs, &entry{Job: j, sched: sched})
+	}
+	return s, nil
+}
+
+// Status: job đã đăng ký; NextRunAt chỉ có khi replica này là leader
+func (s *Scheduler) Status() domain.SchedulerStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := domain.SchedulerStatus{Instance: s.opt.Instance, Leader: s.leader, Jobs: make([]domain.JobInfo, 0, len(s.jobs))}
+	for _, e := range s.jobs {
+		info := domain.JobInfo{Name: e.Name, Schedule: e.Schedule, Timeout: e.Timeout, Running: e.running}
+		if s.leader && !e.next.IsZero() {
+			next := e.next
+			info.NextRunAt = &next
+		}
+		st.Jobs = append(st.Jobs, info)
+	}
+	return st
+}
+
+// Heartbeat: lần gần nhất vòng lặp chạy (mỗi giây); zero khi chưa Run.
+// Kiểm quyền leader bị treo (DB không trả lời) cũng làm heartbeat đứng lại.
+func (s *Scheduler) Heartbeat() time.Time {
+	n := s.heartbeat.Load()
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n)
+}
+
+// Run: chạy tới khi ctx bị hủy, sau đó drain job đang chạy và trả quyền leader rồi mới return.
+// rec ghi lịch sử từng lần chạy.
+func (s *Scheduler) Run(ctx context.Context, rec inport.JobsInbound) {
+	s.log.Info("scheduler started", slog.String("instance", s.opt.Instance), slog.Int("jobs", len(s.jobs)))
+	t := time.NewTicker(tick)
+	defer t.Stop()
+	var checkAt time.Time
+	for {
+		if ctx.Err() != nil { // đang dừng: không kiểm quyền/khởi động job nữa, lock giữ tới hết drain
+			s.drain()
+			return
+		}
+		now := time.Now()
+		s.heartbeat.Store(now.UnixNano())
+		// tới lịch kiểm quyền, hoặc có job tới hạn: xác nhận còn là leader ngay trước khi chạy
+		if !now.Before(checkAt) || s.due(now) {
+			s.elect(ctx)
+			checkAt = now.Add(s.opt.LeaderPoll)
+		}
+		s.dispatch(now, rec)
+
+		select {
+		case <-ctx.Done():
+		case <-t.C:
+		}
+	}
+}
+
+// ===== leader =====
+
+func (s *Scheduler) elect(ctx context.Context) {
+	s.mu.Lock()
+	leader := s.leader
+	s.mu.Unlock()
+
+	if leader {
+		if !s.lock.Held(ctx) && ctx.Err() == nil {
+			s.stepDown()
+		}
+		return
+	}
+	ok, err := s.lock.TryAcquire(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.log.Warn("leader lock unavailable", slog.String("error", err.Error()))
+		}
+		return
+	}
+	if ok {
+		s.becomeLeader(ctx)
+	}
+}
+
+func (s *Scheduler) becomeLeader(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// job sống qua lúc ctx của server bị hủy (drain), chỉ dừng khi termStop
+	s.term, s.termStop = context.WithCancel(context.WithoutCancel(ctx))
+	s.leader = true
+	now := time.Now()
+	for _, e := range s.jobs {
+		e.next = e.sched.Next(now)
+	}
+	schedulerIsLeader.Set(1)
+	s.log.Info("scheduler became leader", slog.String("instance", s.opt.Instance))
+}
+
+// stepDown: mất lock -> replica khác có thể đã nhận quyền, hủy job đang chạy để không chạy trùng
+func (s *Scheduler) stepDown() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.leader = false
+	s.termStop()
+	for _, e := range s.jobs {
+		e.next = time.Time{}
+	}
+	schedulerIsLeader.Set(0)
+	s.log.Warn("scheduler lost leadership, running jobs canceled", slog.String("instance", s.opt.Instance))
+}
+
+// ===== chạy job =====
+
+func (s *Scheduler) due(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.leader {
+		return false
+	}
+	for _, e := range s.jobs {
+		if !now.Before(e.next) && !e.running {
+			return true
+		}
+	}
+	return false
+}
+
+// dispatch: khởi động job tới hạn; lần trước chưa xong thì bỏ lượt này (không chạy chồng)
+func (s *Scheduler) dispatch(now time.Time, rec inport.JobsInbound) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.leader {
+		return
+	}
+	for _, e := range s.jobs {
+		if now.Before(e.next) {
+			continue
+		}
+		scheduled := e.next
+		e.next = e.sched.Next(now)
+		if e.running {
+			jobRunsSkipped.WithLabelValues(e.Name).Inc()
+			s.log.Warn("job still running, skipped scheduled run", slog.String("job", e.Name))
+			continue
+		}
+		e.running = true
+		s.wg.Add(1)
+		go s.run(s.term, e, scheduled, rec)
+	}
+}
+
+func (s *Scheduler) run(term context.Context, e *entry, scheduled time.Time, rec inport.JobsInbound) {
+	defer s.wg.Done()
+	jobsRunning.WithLabelValues(e.Name).Inc()
+	defer jobsRunning.WithLabelValues(e.Name).Dec()
+
+	// mỗi lần chạy là một trace riêng: span usecase/repository/SQL của job nằm dưới span này
+	tctx, span := otel.Tracer("wh-ma-scheduler").Start(term, "job "+e.Name,
+		trace.WithNewRoot(),
+		trace.WithAttributes(
+			attribute.String("job.name", e.Name),
+			attribute.String("job.instance", s.opt.Instance),
+			attribute.String("job.scheduled_at", scheduled.UTC().Format(time.RFC3339)),
+		),
+	)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(tctx, e.Timeout)
+	started := time.Now()
+	summary, err := call(ctx, e.Run)
+	timedOut := errors.Is(ctx.Err(), context.DeadlineExceeded)
+	cancel()
+
+	run := &domain.JobRun{
+		Job:         e.Name,
+		Instance:    s.opt.Instance,
+		ScheduledAt: scheduled,
+		StartedAt:   started,
+		FinishedAt:  time.Now(),
+		Status:      domain.JobOK,
+		Summary:     summary,
+	}
+	if err != nil {
+		run.Error = err.Error()
+		switch {
+		case term.Err() != nil:
+			run.Status = domain.JobCanceled
+		case timedOut:
+			run.Status = domain.JobTimeout
+		default:
+			run.Status = domain.JobFailed
+		}
+	}
+	s.mu.Lock()
+	e.running = false
+	s.mu.Unlock()
+
+	span.SetAttributes(attribute.String("job.status", string(run.Status)), attribute.String("job.summary", summary))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, run.Error)
+	}
+	jobRunsTotal.WithLabelValues(e.Name, string(run.Status)).Inc()
+	jobRunDuration.WithLabelValues(e.Name).Observe(run.Duration().Seconds())
+	if run.Status == domain.JobOK {
+		jobLastSuccess.WithLabelValues(e.Name).Set(float64(run.FinishedAt.Unix()))
+	}
+	attrs := []any{
+		slog.String("job", e.Name),
+		slog.String("status", string(run.Status)),
+		slog.Duration("duration", run.Duration()),
+		slog.String("summary", summary),
+	}
+	if err != nil {
+		s.log.Warn("job finished", append(attrs, slog.String("error", run.Error))...)
+	} else {
+		s.log.Info("job finished", attrs...)
+	}
+
+	// term có thể đã bị hủy (drain/mất quyền) nhưng kết quả vẫn phải được ghi
+	rctx, rcancel := context.WithTimeout(context.WithoutCancel(tctx), recordTimeout)
+	defer rcancel()
+	if err := rec.RecordRun(rctx, run); err != nil {
+		s.log.Error("record job run", slog.String("job", e.Name), slog.String("error", err.Error()))
+	}
+}
+
+// call: panic trong job thành lỗi của lần chạy, không làm sập server
+func call(ctx context.Context, fn func(ctx context.Context) (string, error)) (summary string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn(ctx)
+}
+
+// ===== dừng =====
+
+// StopTimeout: Run trả về chậm nhất bao lâu sau khi ctx bị hủy (Drain, chờ job dừng sau khi hủy,
+// trả lock); lifecycle dừng storage sau đó nên phải chờ đủ.
+func (s *Scheduler) StopTimeout() time.Duration {
+	return s.opt.Drain + s.abandonAfter + releaseTimeout
+}
+
+// drain: ngừng lập lịch, chờ job đang chạy tối đa Drain, quá thì hủy rồi trả lock.
+// Lock luôn được trả (ctx riêng, hạn ngắn) kể cả khi job bỏ qua việc hủy: connection giữ lock
+// còn mượn thì pool.Close lúc dừng storage bị treo. Job bị bỏ mặc có thể chạy trùng với leader
+// mới trong lúc process thoát.
+func (s *Scheduler) drain() {
+	s.mu.Lock()
+	leader, stop := s.leader, s.termStop
+	s.leader = false
+	for _, e := range s.jobs {
+		e.next = time.Time{}
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(s.opt.Drain):
+		s.log.Warn("drain timeout, canceling running jobs", slog.Duration("drain", s.opt.Drain))
+		stop()
+		select {
+		case <-done:
+		case <-time.After(s.abandonAfter):
+			s.log.Error("jobs ignored cancellation, abandoning them")
+		}
+	}
+	if stop != nil {
+		stop()
+	}
+
+	if leader {
+		ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+		defer cancel()
+		if err := s.lock.Release(ctx); err != nil {
+			s.log.Warn("release leader lock", slog.String("error", err.Error()))
+		}
+	}
+	schedulerIsLeader.Set(0)
+	s.log.Info("scheduler stopped", slog.String("instance", s.opt.Instance))
+}
@@ -0,0 +1,177 @@
+package scheduler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	inport "wh-ma/internal/adapter/inbound/port"
+	"wh-ma/internal/domain"
+)
+
+// fakeLock: luôn nhận quyền; ghi lại Held với ctx đã hủy và lúc Release
+type fakeLock struct {
+	mu             sync.Mutex
+	heldCanceled   bool
+	released       bool
+	releasedBefore bool // Release khi job còn chạy
+	releaseBounded bool // ctx của Release có hạn
+	jobRunning     func() bool
+}
+
+func (l *fakeLock) TryAcquire(ctx context.Context) (bool, error) { return true, nil }
+
+func (l *fakeLock) Held(ctx context.Context) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if ctx.Err() != nil {
+		l.heldCanceled = true
+	}
+	return true
+}
+
+func (l *fakeLock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.released = true
+	l.releasedBefore = l.jobRunning()
+	_, l.releaseBounded = ctx.Deadline()
+	return nil
+}
+
+// recorder: chỉ RecordRun được scheduler gọi
+type recorder struct {
+	inport.JobsInbound
+	mu   sync.Mutex
+	runs []*domain.JobRun
+}
+
+func (r *recorder) RecordRun(ctx context.Context, run *domain.JobRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.runs = append(r.runs, run)
+	return nil
+}
+
+// Dừng khi job đang chạy: không nhả lock (kể cả qua Held với ctx đã hủy) tới khi job xong.
+func TestRunReleasesLockAfterDrain(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		running bool
+	)
+	started, finish := make(chan struct{}), make(chan struct{})
+	lock := &fakeLock{jobRunning: func() bool { mu.Lock(); defer mu.Unlock(); return running }}
+	job := Job{Name: "slow", Schedule: "@every 1s", Timeout: 10 * time.Second, Run: func(ctx context.Context) (string, error) {
+		mu.Lock()
+		running = true
+		mu.Unlock()
+		close(started)
+		<-finish
+		mu.Lock()
+		running = false
+		mu.Unlock()
+		return "ok", ctx.Err()
+	}}
+	s, err := New([]Job{job}, lock, Options{
+		Instance:   "test",
+		LeaderPoll: 10 * time.Millisecond,
+		Drain:      5 * time.Second,
+		Logger:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := &recorder{}
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		s.Run(ctx, rec)
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job never started")
+	}
+	cancel()
+	time.Sleep(1500 * time.Millisecond) // hơn một tick: vòng lặp đã thấy ctx bị hủy
+	lock.mu.Lock()
+	early := lock.released
+	lock.mu.Unlock()
+	if early {
+		t.Fatal("lock released while the job was still draining")
+	}
+
+	close(finish)
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after the job finished")
+	}
+	if !lock.released || lock.releasedBefore {
+		t.Fatalf("released = %v, while job running = %v; want released after the job", lock.released, lock.releasedBefore)
+	}
+	if lock.heldCanceled {
+		t.Error("Held called with a canceled ctx")
+	}
+	if len(rec.runs) != 1 || rec.runs[0].Status != domain.JobOK {
+		t.Errorf("runs = %+v, want one ok run", rec.runs)
+	}
+}
+
+// Job bỏ qua cả việc hủy: vẫn trả lock (ctx có hạn) để connection giữ lock về pool / bị đóng,
+// storage dừng sau đó không bị treo.
+func TestRunReleasesLockWhenJobIgnoresCancel(t *testing.T) {
+	started, finish := make(chan struct{}), make(chan struct{})
+	defer close(finish)
+	var running atomic.Bool
+	lock := &fakeLock{jobRunning: running.Load}
+	job := Job{Name: "stuck", Schedule: "@every 1s", Timeout: time.Hour, Run: func(ctx context.Context) (string, error) {
+		running.Store(true)
+		defer running.Store(false)
+		close(started)
+		<-finish // không nhìn ctx
+		return "", nil
+	}}
+	s, err := New([]Job{job}, lock, Options{
+		Instance:   "test",
+		LeaderPoll: 10 * time.Millisecond,
+		Drain:      50 * time.Millisecond,
+		Logger:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.abandonAfter = 50 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		s.Run(ctx, &recorder{})
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job never started")
+	}
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(s.StopTimeout()):
+		t.Fatal("Run did not return within StopTimeout")
+	}
+	lock.mu.Lock()
+	defer lock.mu.Unlock()
+	if !lock.released || !lock.releasedBefore {
+		t.Fatalf("released = %v, while job running = %v; want released with the job abandoned", lock.released, lock.releasedBefore)
+	}
+	if !lock.releaseBounded {
+		t.Error("Release called without a deadline")
+	}
+}
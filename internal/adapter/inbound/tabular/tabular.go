@@ -0,0 +1,210 @@
+// Package tabular: đọc file bảng (CSV/XLSX) thành header + các dòng chuỗi cho import hàng loạt,
+// và ghi bảng ra CSV/XLSX/JSON Lines cho export (writer.go).
+// Không biết gì về nghiệp vụ: ánh xạ cột -> field và đổi kiểu do handler/usecase lo.
+package tabular
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type Format string
+
+const (
+	CSV  Format = "csv"
+	XLSX Format = "xlsx"
+)
+
+const xlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// FormatOf: định dạng theo đuôi file, không rõ thì theo content type
+func FormatOf(filename, contentType string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return CSV, nil
+	case ".xlsx":
+		return XLSX, nil
+	}
+	switch strings.TrimSpace(strings.Split(contentType, ";")[0]) {
+	case "text/csv":
+		return CSV, nil
+	case xlsxContentType:
+		return XLSX, nil
+	}
+	return "", fmt.Errorf("unsupported file type %q (want .csv or .xlsx)", filename)
+}
+
+// Table: dòng đầu là header; Row.Line là số dòng trong file (header = 1)
+type Table struct {
+	Header []string
+	Rows   []Row
+}
+
+type Row struct {
+	Line  int
+	Cells []string
+}
+
+// Read: đọc toàn bộ file; sheet chỉ dùng cho XLSX (rỗng = sheet đầu tiên).
+// Dòng trống bị bỏ qua; ô được trim khoảng trắng.
+func Read(r io.Reader, f Format, sheet string) (*Table, error) {
+	var records [][]string
+	var lines []int
+	switch f {
+	case CSV:
+		cr := csv.NewReader(r)
+		cr.FieldsPerRecord = -1
+		cr.TrimLeadingSpace = true
+		for {
+			rec, err := cr.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("csv: %w", err)
+			}
+			line, _ := cr.FieldPos(0)
+			records = append(records, rec)
+			lines = append(lines, line)
+		}
+	case XLSX:
+		var err error
+		if records, err = readXLSX(r, sheet); err != nil {
+			return nil, err
+		}
+		for i := range records {
+			lines = append(lines, i+1)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format %q", f)
+	}
+
+	t := &Table{}
+	for i, rec := range records {
+		for j := range rec {
+			rec[j] = strings.TrimSpace(rec[j])
+		}
+		if blank(rec) {
+			continue
+		}
+		if t.Header == nil {
+			t.Header = rec
+			continue
+		}
+		t.Rows = append(t.Rows, Row{Line: lines[i], Cells: rec})
+	}
+	if t.Header == nil {
+		return nil, errors.New("file is empty (no header row)")
+	}
+	return t, nil
+}
+
+// readXLSX: giá trị thô của ô (ngày là số serial Excel, xem ParseTime) để không phụ thuộc định dạng hiển thị
+func readXLSX(r io.Reader, sheet string) ([][]string, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("xlsx: %w", err)
+	}
+	defer f.Close()
+	if sheet == "" {
+		sheet = f.GetSheetName(0)
+	}
+	if idx, err := f.GetSheetIndex(sheet); err != nil || idx < 0 {
+		return nil, fmt.Errorf("xlsx: sheet %q not found", sheet)
+	}
+	rows, err := f.GetRows(sheet, excelize.Options{RawCellValue: true})
+	if err != nil {
+		return nil, fmt.Errorf("xlsx: %w", err)
+	}
+	return rows, nil
+}
+
+func blank(rec []string) bool {
+	for _, c := range rec {
+		if c != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// ===== Ánh xạ cột =====
+
+// Columns: field -> chỉ số cột.
+// mapping (field -> tên cột trong header) ưu tiên; field không có trong mapping thì
+// tìm cột cùng tên hoặc một trong aliases (không phân biệt hoa thường, "_" ~ " ").
+// Lỗi nếu mapping trỏ tới cột không có hoặc nêu field lạ.
+func (t *Table) Columns(mapping map[string]string, aliases map[string][]string) (map[string]int, error) {
+	index := make(map[string]int, len(t.Header))
+	for i, h := range t.Header {
+		if k := normalize(h); k != "" {
+			if _, dup := index[k]; !dup {
+				index[k] = i
+			}
+		}
+	}
+	cols := make(map[string]int, len(aliases))
+	for field, names := range aliases {
+		if col, ok := mapping[field]; ok {
+			i, found := index[normalize(col)]
+			if !found {
+				return nil, fmt.Errorf("column %q for %s not in header", col, field)
+			}
+			cols[field] = i
+			continue
+		}
+		for _, name := range append([]string{field}, names...) {
+			if i, found := index[normalize(name)]; found {
+				cols[field] = i
+				break
+			}
+		}
+	}
+	for field := range mapping {
+		if _, ok := aliases[field]; !ok {
+			return nil, fmt.Errorf("unknown field %q", field)
+		}
+	}
+	return cols, nil
+}
+
+// Cell: ô của field trong dòng ("" nếu không ánh xạ hoặc dòng ngắn hơn header)
+func (r Row) Cell(cols map[string]int, field string) string {
+	i, ok := cols[field]
+	if !ok || i >= len(r.Cells) {
+		return ""
+	}
+	return r.Cells[i]
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(s), " ", "_"))
+}
+
+// ===== Đổi kiểu ô =====
+
+// ParseTime: RFC3339, "YYYY-MM-DD[ HH:MM[:SS]]" (UTC) hoặc số serial ngày của Excel (ô ngày/giờ trong XLSX)
+func ParseTime(s string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, time.DateOnly, "2006-01-02 15:04", time.DateTime} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	if n, err := strconv.ParseFloat(s, 64); err == nil && n > 0 {
+		return excelize.ExcelDateToTime(n, false)
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (want YYYY-MM-DD, YYYY-MM-DD HH:MM or RFC3339)", s)
+}
@@ -0,0 +1,224 @@
+package tabular
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+
+	"wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+)
+
+// JSONL: chỉ dùng để ghi (export), mỗi dòng một object theo header
+const JSONL Format = "jsonl"
+
+// maxXLSXRows: giới hạn của Excel (kể cả header)
+const maxXLSXRows = 1048576
+
+// ContentType / Ext: cho header Content-Type và tên file tải về
+func ContentType(f Format) string {
+	switch f {
+	case CSV:
+		return "text/csv; charset=utf-8"
+	case XLSX:
+		return xlsxContentType
+	case JSONL:
+		return "application/x-ndjson"
+	}
+	return "application/octet-stream"
+}
+
+func Ext(f Format) string { return "." + string(f) }
+
+// Encoder: triển khai port.TableEncoder cho usecase export
+type Encoder struct{}
+
+// compile-time check
+var _ port.TableEncoder = Encoder{}
+
+func (Encoder) Supports(format string) bool {
+	switch Format(format) {
+	case CSV, XLSX, JSONL:
+		return true
+	}
+	return false
+}
+
+func (Encoder) NewWriter(w io.Writer, format string) (port.TableWriter, error) {
+	switch Format(format) {
+	case CSV:
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case JSONL:
+		return &jsonlWriter{w: bufio.NewWriter(w)}, nil
+	case XLSX:
+		return newXLSXWriter(w)
+	}
+	return nil, domain.Invalid("format", fmt.Sprintf("unsupported format %q (want csv, xlsx or jsonl)", format))
+}
+
+// ===== CSV =====
+// Ô là chuỗi; thời gian RFC3339 UTC. Ghi qua bộ đệm của csv.Writer, không giữ cả file.
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) WriteHeader(cols []string) error { return c.w.Write(cols) }
+
+func (c *csvWriter) WriteRow(vals []any) error {
+	rec := make([]string, len(vals))
+	for i, v := range vals {
+		rec[i] = csvCell(v)
+	}
+	return c.w.Write(rec)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func csvCell(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		// chặn công thức khi mở bằng Excel (CSV injection): ô chữ bắt đầu bằng = + - @
+		if x != "" && strings.ContainsRune("=+-@\t\r", rune(x[0])) {
+			return "'" + x
+		}
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case time.Time:
+		return x.UTC().Format(time.RFC3339)
+	case *time.Time:
+		if x == nil {
+			return ""
+		}
+		return x.UTC().Format(time.RFC3339)
+	default:
+		return fmt.Sprint(x)
+	}
+}
+
+// ===== JSON Lines =====
+// Object giữ thứ tự cột như header; ô trống -> null
+type jsonlWriter struct {
+	w    *bufio.Writer
+	keys [][]byte
+}
+
+func (j *jsonlWriter) WriteHeader(cols []string) error {
+	j.keys = make([][]byte, len(cols))
+	for i, c := range cols {
+		b, err := json.Marshal(c)
+		if err != nil {
+			return err
+		}
+		j.keys[i] = b
+	}
+	return nil
+}
+
+func (j *jsonlWriter) WriteRow(vals []any) error {
+	j.w.WriteByte('{')
+	for i, v := range vals {
+		if i > 0 {
+			j.w.WriteByte(',')
+		}
+		j.w.Write(j.keys[i])
+		j.w.WriteByte(':')
+		if t, ok := v.(time.Time); ok {
+			v = t.UTC()
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		j.w.Write(b)
+	}
+	j.w.WriteByte('}')
+	return j.w.WriteByte('\n')
+}
+
+func (j *jsonlWriter) Close() error { return j.w.Flush() }
+
+// ===== XLSX =====
+// StreamWriter của excelize đẩy dòng ra file tạm khi lớn; cả workbook chỉ được ghi ra w lúc Close.
+type xlsxWriter struct {
+	out       io.Writer
+	f         *excelize.File
+	sw        *excelize.StreamWriter
+	row       int
+	dateStyle int
+}
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	f := excelize.NewFile()
+	const sheet = "Sheet1"
+	sw, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	dateFmt := "yyyy-mm-dd hh:mm:ss"
+	style, err := f.NewStyle(&excelize.Style{CustomNumFmt: &dateFmt})
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &xlsxWriter{out: w, f: f, sw: sw, dateStyle: style}, nil
+}
+
+func (x *xlsxWriter) WriteHeader(cols []string) error {
+	vals := make([]any, len(cols))
+	for i, c := range cols {
+		vals[i] = c
+	}
+	return x.setRow(vals)
+}
+
+func (x *xlsxWriter) WriteRow(vals []any) error {
+	cells := make([]any, len(vals))
+	for i, v := range vals {
+		switch t := v.(type) {
+		case time.Time:
+			cells[i] = excelize.Cell{StyleID: x.dateStyle, Value: t.UTC()}
+		case *time.Time:
+			if t != nil {
+				cells[i] = excelize.Cell{StyleID: x.dateStyle, Value: t.UTC()}
+			}
+		default:
+			cells[i] = v
+		}
+	}
+	return x.setRow(cells)
+}
+
+func (x *xlsxWriter) setRow(vals []any) error {
+	if x.row >= maxXLSXRows {
+		return domain.Invalid("format", fmt.Sprintf("xlsx holds at most %d rows; use csv or jsonl", maxXLSXRows-1))
+	}
+	x.row++
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+	return x.sw.SetRow(cell, vals)
+}
+
+func (x *xlsxWriter) Close() error {
+	defer x.f.Close()
+	if err := x.sw.Flush(); err != nil {
+		return err
+	}
+	_, err := x.f.WriteTo(x.out)
+	return err
+}
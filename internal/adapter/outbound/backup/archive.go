@@ -0,0 +1,138 @@
+// Package backup: snapshot logic dữ liệu đội máy ra archive di động và restore ngược lại.
+//
+// Archive là file zip:
+//
+//	manifest.json            định dạng, version schema, thời điểm snapshot, phạm vi, số dòng mỗi bảng
+//	plans.jsonl              mỗi dòng = row_to_json của một dòng trong bảng (giữ nguyên id, tenant_id, version...)
+//	devices.jsonl
+//	readings.jsonl
+//	maintenance_events.jsonl
+//	alerts.jsonl
+//	device_audit_log.jsonl
+//
+// Mọi bảng được đọc trong một transaction REPEATABLE READ (role whma_snapshot, thấy mọi tenant)
+// nên archive nhất quán tại một thời điểm.
+// Restore ghi lại đúng id (khóa ngoại giữ nguyên) vào database cùng version schema, trong một
+// transaction; lọc theo tenant/site được cả lúc backup lẫn lúc restore.
+// API key không nằm trong archive: là credential của từng môi trường, cấp lại sau khi restore.
+package backup
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	// Format / FormatVersion: nhận diện archive; đổi bố cục file thì tăng FormatVersion
+	Format        = "whma-backup"
+	FormatVersion = 1
+
+	manifestFile = "manifest.json"
+)
+
+// Manifest: manifest.json của archive
+type Manifest struct {
+	Format        string      `json:"format"`
+	FormatVersion int         `json:"format_version"`
+	SchemaVersion uint        `json:"schema_version"` // restore cần database ở đúng version này
+	SnapshotAt    string      `json:"snapshot_at"`    // now() của transaction đọc (RFC3339)
+	Scope         Scope       `json:"scope"`
+	Tables        []TableInfo `json:"tables"`
+}
+
+// Scope: phạm vi dữ liệu; rỗng = toàn bộ
+type Scope struct {
+	Tenant string `json:"tenant,omitempty"`
+	Site   string `json:"site,omitempty"` // devices.location; kéo theo reading/bảo dưỡng/cảnh báo/audit của các device đó
+}
+
+type TableInfo struct {
+	Name string `json:"name"`
+	File string `json:"file"`
+	Rows int64  `json:"rows"`
+}
+
+// tables: thứ tự ghi = thứ tự restore (bảng cha trước bảng con)
+var tables = []string{"plans", "devices", "readings", "maintenance_events", "alerts", "device_audit_log"}
+
+// ===== kết nối =====
+
+// Backup: một connection riêng (không qua pool của server, không bindTenant)
+type Backup struct {
+	conn *pgx.Conn
+}
+
+func New(ctx context.Context, databaseURL string) (*Backup, error) {
+	conn, err := pgx.Connect(ctx, databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("db connect: %w", err)
+	}
+	return &Backup{conn: conn}, nil
+}
+
+func (b *Backup) Close() error {
+	return b.conn.Close(context.Background())
+}
+
+// snapshotRole: phần còn lại của transaction chạy bằng role whma_snapshot (BYPASSRLS, chỉ SELECT,
+// migration 000014) nên đọc được mọi tenant; user của DATABASE_URL phải là member của role
+const snapshotRole = "whma_snapshot"
+
+func useSnapshotRole(ctx context.Context, tx pgx.Tx) error {
+	if _, err := tx.Exec(ctx, "SET LOCAL ROLE "+snapshotRole); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && (pgErr.Code == "42501" || pgErr.Code == "22023") {
+			return fmt.Errorf("backup needs role %[1]s: run \"GRANT %[1]s TO <database user>\" as a superuser (%s)", snapshotRole, pgErr.Message)
+		}
+		return err
+	}
+	return nil
+}
+
+// schemaVersion: version golang-migrate của database; dirty thì không backup/restore
+func schemaVersion(ctx context.Context, tx pgx.Tx) (uint, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+	err := tx.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, errors.New("database has no schema yet; run \"whma migrate up\" first")
+	}
+	if err != nil {
+		return 0, fmt.Errorf("read schema version: %w", err)
+	}
+	if dirty {
+		return 0, fmt.Errorf("schema version %d is dirty; fix it with \"whma migrate force\" first", version)
+	}
+	return uint(version), nil
+}
+
+// ===== đọc archive =====
+
+func readManifest(zr *zip.Reader) (*Manifest, error) {
+	f, err := zr.Open(manifestFile)
+	if err != nil {
+		return nil, fmt.Errorf("not a %s archive: %w", Format, err)
+	}
+	defer f.Close()
+	var m Manifest
+	if err := json.NewDecoder(f).Decode(&m); err != nil {
+		return nil, fmt.Errorf("read %s: %w", manifestFile, err)
+	}
+	if m.Format != Format {
+		return nil, fmt.Errorf("not a %s archive (format %q)", Format, m.Format)
+	}
+	if m.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("archive format version %d is not supported (want %d)", m.FormatVersion, FormatVersion)
+	}
+	return &m, nil
+}
+
+func fileOf(table string) string { return table + ".jsonl" }
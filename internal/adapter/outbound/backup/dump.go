@@ -0,0 +1,109 @@
+package backup
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ===== phạm vi theo bảng ($1 = tenant, $2 = site; chuỗi rỗng = không lọc) =====
+
+// deviceScope: device trong phạm vi, alias là tên bảng devices trong câu query
+func deviceScope(alias string) string {
+	return fmt.Sprintf(`($1 = '' OR %[1]s.tenant_id = $1) AND ($2 = '' OR %[1]s.location = $2)`, alias)
+}
+
+// childScope: dòng của device trong phạm vi (FK kép device_id + tenant_id)
+var childScope = `(t.tenant_id, t.device_id) IN (SELECT d.tenant_id, d.id FROM devices d WHERE ` + deviceScope("d") + `)`
+
+// dumpWhere: điều kiện lọc từng bảng (alias t).
+// plans: plan của tenant trong phạm vi + template (tenant_id NULL); lọc site thì chỉ giữ plan device đang dùng,
+// template cũng chỉ giữ plan đang dùng khi lọc tenant.
+var dumpWhere = map[string]string{
+	"plans": `(t.tenant_id IS NULL OR $1 = '' OR t.tenant_id = $1)
+	  AND (($2 = '' AND (t.tenant_id IS NOT NULL OR $1 = ''))
+	       OR t.id IN (SELECT d.plan_id FROM devices d WHERE ` + deviceScope("d") + `))`,
+	"devices":            deviceScope("t"),
+	"readings":           childScope,
+	"maintenance_events": childScope,
+	"alerts":             childScope,
+	"device_audit_log":   childScope,
+}
+
+// Dump: ghi archive zip ra w từ một snapshot REPEATABLE READ (không khóa ghi của server).
+// Dòng được sắp theo tenant rồi id để restore ghi theo lô cùng tenant.
+func (b *Backup) Dump(ctx context.Context, w io.Writer, scope Scope) (*Manifest, error) {
+	tx, err := b.conn.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }() // chỉ đọc: không có gì để commit
+
+	if err := useSnapshotRole(ctx, tx); err != nil {
+		return nil, err
+	}
+	m := &Manifest{Format: Format, FormatVersion: FormatVersion, Scope: scope}
+	if m.SchemaVersion, err = schemaVersion(ctx, tx); err != nil {
+		return nil, err
+	}
+	var at time.Time
+	if err := tx.QueryRow(ctx, "SELECT now()").Scan(&at); err != nil {
+		return nil, err
+	}
+	m.SnapshotAt = at.UTC().Format(time.RFC3339)
+
+	zw := zip.NewWriter(w)
+	for _, t := range tables {
+		f, err := zw.Create(fileOf(t))
+		if err != nil {
+			return nil, err
+		}
+		n, err := dumpTable(ctx, tx, f, t, scope)
+		if err != nil {
+			return nil, fmt.Errorf("backup %s: %w", t, err)
+		}
+		m.Tables = append(m.Tables, TableInfo{Name: t, File: fileOf(t), Rows: n})
+	}
+	f, err := zw.Create(manifestFile)
+	if err != nil {
+		return nil, err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(m); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// dumpTable: mỗi dòng một object JSON (row_to_json giữ mọi cột, kể cả cột thêm ở migration sau)
+func dumpTable(ctx context.Context, tx pgx.Tx, w io.Writer, table string, scope Scope) (int64, error) {
+	// tên bảng lấy từ danh sách cố định, không từ input
+	sql := fmt.Sprintf(`SELECT row_to_json(t)::text FROM %s t WHERE %s ORDER BY t.tenant_id NULLS FIRST, t.id`,
+		table, dumpWhere[table])
+	rows, err := tx.Query(ctx, sql, scope.Tenant, scope.Site)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	var n int64
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return n, err
+		}
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, rows.Err()
+}
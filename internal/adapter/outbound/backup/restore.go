@@ -0,0 +1,298 @@
+package backup
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	// restoreBatch: số dòng mỗi câu INSERT (cùng tenant)
+	restoreBatch = 500
+	// maxLine: dòng JSON dài nhất chấp nhận (audit log có thể chứa giá trị lớn)
+	maxLine = 64 << 20
+)
+
+type RestoreOptions struct {
+	Scope  Scope  // lọc thêm trên dữ liệu trong archive; rỗng = mọi thứ archive có
+	Into   string // ghi vào tenant khác (vd "staging"); cần phạm vi đúng một tenant
+	DryRun bool   // chạy hết rồi rollback: kiểm archive, schema và xung đột id mà không ghi
+}
+
+// Report: số dòng đã ghi theo bảng (template plan đã có sẵn, giống hệt, không tính)
+type Report struct {
+	SchemaVersion uint        `json:"schema_version"`
+	SnapshotAt    string      `json:"snapshot_at"`
+	Tenants       []string    `json:"tenants"`
+	Tables        []TableInfo `json:"tables"`
+	DryRun        bool        `json:"dry_run"`
+}
+
+// rowKey: các cột dùng để lọc dòng và gom lô; phần còn lại của dòng giữ nguyên
+type rowKey struct {
+	ID       int64   `json:"id"`
+	TenantID *string `json:"tenant_id"`
+	DeviceID int64   `json:"device_id"`
+	PlanID   *int64  `json:"plan_id"`
+	Location *string `json:"location"`
+}
+
+// Restore: ghi archive vào database trong một transaction, giữ nguyên id.
+// Database phải ở đúng version schema của archive (migrate to <version>, restore, rồi migrate up).
+// Id đã có trong database đích -> lỗi, không ghi gì; template plan trùng id thì phải giống hệt.
+func (b *Backup) Restore(ctx context.Context, path string, opt RestoreOptions) (*Report, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	m, err := readManifest(&zr.Reader)
+	if err != nil {
+		return nil, err
+	}
+	if opt.Into != "" && opt.Scope.Tenant == "" && m.Scope.Tenant == "" {
+		return nil, errors.New("restoring into another tenant needs a single source tenant: pick one with -tenant")
+	}
+	sel, err := selectDevices(&zr.Reader, opt.Scope)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := b.conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }() // no-op sau Commit
+	// không dùng role snapshot: mọi INSERT vẫn qua policy tenant_isolation (xem insert)
+	version, err := schemaVersion(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if version != m.SchemaVersion {
+		return nil, fmt.Errorf("archive has schema version %d but the database is at %d; "+
+			"run \"whma migrate to %d\", restore, then \"whma migrate up\"", m.SchemaVersion, version, m.SchemaVersion)
+	}
+
+	r := &restorer{tx: tx, sel: sel, opt: opt, tenants: map[string]bool{}, maxID: map[string]int64{}}
+	rep := &Report{SchemaVersion: m.SchemaVersion, SnapshotAt: m.SnapshotAt, DryRun: opt.DryRun}
+	for _, t := range tables {
+		n, err := r.table(ctx, &zr.Reader, t)
+		if err != nil {
+			return nil, err
+		}
+		rep.Tables = append(rep.Tables, TableInfo{Name: t, File: fileOf(t), Rows: n})
+	}
+	// id ghi tay không đi qua sequence -> đẩy sequence lên quá id lớn nhất đã ghi (không bao giờ lùi).
+	// Lấy max từ archive: transaction không đọc được dòng của tenant khác qua RLS.
+	for _, t := range tables {
+		if _, err := tx.Exec(ctx, fmt.Sprintf(bumpSequence, t), r.maxID[t]); err != nil {
+			return nil, fmt.Errorf("restore %s: sequence: %w", t, err)
+		}
+	}
+	for t := range r.tenants {
+		rep.Tenants = append(rep.Tenants, t)
+	}
+	sort.Strings(rep.Tenants)
+
+	if opt.DryRun {
+		return rep, nil
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return rep, nil
+}
+
+const bumpSequence = `SELECT setval(s.seq, GREATEST($1::bigint, COALESCE(pg_sequence_last_value(s.seq), 0), 1))
+  FROM (SELECT pg_get_serial_sequence('%[1]s', 'id')::regclass AS seq) s`
+
+// ===== chọn dòng =====
+
+// selection: device trong phạm vi restore và plan mà chúng dùng
+type selection struct {
+	scope   Scope
+	devices map[int64]bool
+	plans   map[int64]bool
+}
+
+// selectDevices: đọc devices.jsonl trước (plan được ghi trước device nhưng lọc theo device)
+func selectDevices(zr *zip.Reader, scope Scope) (*selection, error) {
+	sel := &selection{scope: scope, devices: map[int64]bool{}, plans: map[int64]bool{}}
+	err := eachRow(zr, "devices", func(k rowKey, _ []byte) error {
+		if (scope.Tenant == "" || deref(k.TenantID) == scope.Tenant) &&
+			(scope.Site == "" || deref(k.Location) == scope.Site) {
+			sel.devices[k.ID] = true
+			if k.PlanID != nil {
+				sel.plans[*k.PlanID] = true
+			}
+		}
+		return nil
+	})
+	return sel, err
+}
+
+// keep: cùng quy tắc phạm vi với dumpWhere
+func (s *selection) keep(table string, k rowKey) bool {
+	switch table {
+	case "plans":
+		template := k.TenantID == nil
+		if !template && s.scope.Tenant != "" && *k.TenantID != s.scope.Tenant {
+			return false
+		}
+		if s.scope.Site == "" && (!template || s.scope.Tenant == "") {
+			return true
+		}
+		return s.plans[k.ID]
+	case "devices":
+		return s.devices[k.ID]
+	default:
+		return s.devices[k.DeviceID]
+	}
+}
+
+// ===== ghi =====
+
+type restorer struct {
+	tx      pgx.Tx
+	sel     *selection
+	opt     RestoreOptions
+	tenants map[string]bool
+	maxID   map[string]int64 // bảng -> id lớn nhất đã ghi
+}
+
+// table: đọc từng dòng, gom lô cùng tenant đích rồi INSERT
+func (r *restorer) table(ctx context.Context, zr *zip.Reader, table string) (int64, error) {
+	var (
+		n      int64
+		rows   [][]byte
+		tenant *string
+	)
+	flush := func() error {
+		if len(rows) == 0 {
+			return nil
+		}
+		k, err := r.insert(ctx, table, tenant, rows)
+		n += k
+		rows = rows[:0]
+		return err
+	}
+	err := eachRow(zr, table, func(k rowKey, line []byte) error {
+		if !r.sel.keep(table, k) {
+			return nil
+		}
+		t := k.TenantID
+		if t != nil && r.opt.Into != "" {
+			t = &r.opt.Into
+		}
+		if len(rows) > 0 && (deref(t) != deref(tenant) || (t == nil) != (tenant == nil) || len(rows) >= restoreBatch) {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		tenant = t
+		rows = append(rows, bytes.Clone(line))
+		r.maxID[table] = max(r.maxID[table], k.ID)
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	return n, err
+}
+
+// insert: dòng JSON -> jsonb_populate_record theo kiểu của bảng (map cột theo tên).
+// app.tenant_id = tenant của lô nên policy tenant_isolation vẫn kiểm từng dòng;
+// template plan (tenant NULL) ghi khi app.tenant_id rỗng, trùng id thì giữ bản có sẵn nếu giống hệt.
+func (r *restorer) insert(ctx context.Context, table string, tenant *string, rows [][]byte) (int64, error) {
+	if tenant != nil {
+		r.tenants[*tenant] = true
+	}
+	if _, err := r.tx.Exec(ctx, "SELECT set_config('app.tenant_id', $1, true)", deref(tenant)); err != nil {
+		return 0, err
+	}
+	doc := "[" + string(bytes.Join(rows, []byte(","))) + "]"
+	onConflict := ""
+	if tenant == nil {
+		onConflict = " ON CONFLICT (id) DO NOTHING"
+	}
+	sql := fmt.Sprintf(`INSERT INTO %[1]s
+  SELECT r.* FROM jsonb_array_elements($1::jsonb) AS e,
+    jsonb_populate_record(NULL::%[1]s, e || jsonb_build_object('tenant_id', $2::text)) AS r%[2]s`, table, onConflict)
+	tag, err := r.tx.Exec(ctx, sql, doc, tenant)
+	if err != nil {
+		return 0, restoreErr(table, err)
+	}
+	if tenant == nil {
+		var same int
+		err := r.tx.QueryRow(ctx, `SELECT count(*) FROM jsonb_array_elements($1::jsonb) AS e
+  JOIN plans p ON p.id = (e->>'id')::bigint
+  WHERE p.tenant_id IS NULL AND p.name = e->>'name' AND p.interval_hours = (e->>'interval_hours')::int`, doc).Scan(&same)
+		if err != nil {
+			return 0, err
+		}
+		if same != len(rows) {
+			return 0, fmt.Errorf("restore plans: %d shared template plan(s) clash with different plans already in the database", len(rows)-same)
+		}
+	}
+	return tag.RowsAffected(), nil
+}
+
+func restoreErr(table string, err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return fmt.Errorf("restore %s: %s (%s); the target already holds these ids, restore into an empty database or tenant",
+			table, pgErr.Message, pgErr.Detail)
+	}
+	return fmt.Errorf("restore %s: %w", table, err)
+}
+
+// ===== đọc dòng =====
+
+func eachRow(zr *zip.Reader, table string, fn func(k rowKey, line []byte) error) error {
+	f, err := zr.Open(fileOf(table))
+	if err != nil {
+		return fmt.Errorf("archive: %w", err)
+	}
+	defer f.Close()
+	return scanRows(f, table, fn)
+}
+
+func scanRows(r io.Reader, table string, fn func(k rowKey, line []byte) error) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64<<10), maxLine)
+	line := 0
+	for sc.Scan() {
+		line++
+		b := bytes.TrimSpace(sc.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+		var k rowKey
+		if err := json.Unmarshal(b, &k); err != nil {
+			return fmt.Errorf("archive %s line %d: %w", fileOf(table), line, err)
+		}
+		if err := fn(k, b); err != nil {
+			return err
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("archive %s: %w", fileOf(table), err)
+	}
+	return nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
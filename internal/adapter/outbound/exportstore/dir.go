@@ -0,0 +1,270 @@
+// Package exportstore: lưu export job trên filesystem (EXPORT_DIR).
+//
+// Bố cục: <root>/<tenant>/<job id>.json (metadata) và <job id>.data (file kết quả).
+// Nhiều replica dùng chung thư mục (volume) thì job tạo ở replica này tải được ở replica khác.
+// File kết quả ghi vào *.tmp rồi rename khi Close: link tải không bao giờ thấy file dở dang.
+package exportstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+)
+
+type Dir struct {
+	root string
+}
+
+// compile-time check
+var _ port.ExportStore = (*Dir)(nil)
+
+// New: tạo thư mục gốc nếu chưa có
+func New(root string) (*Dir, error) {
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("export dir: %w", err)
+	}
+	return &Dir{root: root}, nil
+}
+
+// jobRecord: định dạng metadata trên đĩa (tách khỏi domain để đổi domain không làm hỏng file cũ)
+type jobRecord struct {
+	ID         string     `json:"id"`
+	TenantID   string     `json:"tenant_id"`
+	Kind       string     `json:"kind"`
+	Format     string     `json:"format"`
+	From       time.Time  `json:"from"`
+	To         time.Time  `json:"to"`
+	DeviceID   *int64     `json:"device_id,omitempty"`
+	Status     string     `json:"status"`
+	Rows       int64      `json:"rows"`
+	Error      string     `json:"error,omitempty"`
+	CreatedBy  string     `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+}
+
+func (d *Dir) SaveJob(_ context.Context, job *domain.ExportJob) error {
+	dir, err := d.tenantDir(job.TenantID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+	rec := jobRecord{
+		ID:         job.ID,
+		TenantID:   job.TenantID,
+		Kind:       string(job.Kind),
+		Format:     job.Format,
+		From:       job.Filter.From,
+		To:         job.Filter.To,
+		Status:     string(job.Status),
+		Rows:       job.Rows,
+		Error:      job.Error,
+		CreatedBy:  job.CreatedBy,
+		CreatedAt:  job.CreatedAt,
+		FinishedAt: job.FinishedAt,
+		ExpiresAt:  job.ExpiresAt,
+	}
+	if job.Filter.DeviceID != nil {
+		id := int64(*job.Filter.DeviceID)
+		rec.DeviceID = &id
+	}
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	path, err := d.path(job.TenantID, job.ID, ".json")
+	if err != nil {
+		return err
+	}
+	return writeAtomic(path, b)
+}
+
+func (d *Dir) GetJob(_ context.Context, tenant, id string) (*domain.ExportJob, error) {
+	path, err := d.path(tenant, id, ".json")
+	if err != nil {
+		return nil, err
+	}
+	rec, err := readRecord(path)
+	if err != nil {
+		return nil, err
+	}
+	return rec.toDomain(), nil
+}
+
+func (d *Dir) Create(_ context.Context, tenant, id string) (io.WriteCloser, error) {
+	path, err := d.path(tenant, id, ".data")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), id+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	return &pendingFile{File: f, final: path}, nil
+}
+
+func (d *Dir) Open(_ context.Context, tenant, id string) (port.ExportFile, error) {
+	path, err := d.path(tenant, id, ".data")
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, notFound()
+	}
+	if err != nil {
+		return nil, err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &openFile{File: f, st: st}, nil
+}
+
+// DeleteExpired: quét <root>/*/*.json; metadata hỏng cũng bị xóa (không tải được nữa)
+func (d *Dir) DeleteExpired(_ context.Context, now time.Time) (int, error) {
+	metas, err := filepath.Glob(filepath.Join(d.root, "*", "*.json"))
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, meta := range metas {
+		rec, err := readRecord(meta)
+		if err == nil && now.Before(rec.ExpiresAt) {
+			continue
+		}
+		base := strings.TrimSuffix(meta, ".json")
+		_ = os.Remove(base + ".data")
+		tmps, _ := filepath.Glob(base + ".*.tmp")
+		for _, t := range tmps {
+			_ = os.Remove(t)
+		}
+		if err := os.Remove(meta); err == nil {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// ===== helpers =====
+
+// tenantDir: tenant là một phân đoạn path (escape "/", chặn "." và "..")
+func (d *Dir) tenantDir(tenant string) (string, error) {
+	seg := url.PathEscape(tenant)
+	if seg == "" || seg == "." || seg == ".." {
+		return "", domain.Invalid("tenant_id", "is required")
+	}
+	return filepath.Join(d.root, seg), nil
+}
+
+// path: id do usecase sinh (hex); id lạ (từ link bị sửa) coi như không có
+func (d *Dir) path(tenant, id, ext string) (string, error) {
+	if id == "" || strings.ContainsFunc(id, func(r rune) bool { return !strings.ContainsRune("0123456789abcdef", r) }) {
+		return "", notFound()
+	}
+	dir, err := d.tenantDir(tenant)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, id+ext), nil
+}
+
+func readRecord(path string) (*jobRecord, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, notFound()
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rec jobRecord
+	if err := json.Unmarshal(b, &rec); err != nil {
+		return nil, fmt.Errorf("export job %s: %w", filepath.Base(path), err)
+	}
+	return &rec, nil
+}
+
+func (r *jobRecord) toDomain() *domain.ExportJob {
+	job := &domain.ExportJob{
+		ID:         r.ID,
+		TenantID:   r.TenantID,
+		Kind:       domain.ExportKind(r.Kind),
+		Format:     r.Format,
+		Filter:     domain.ExportFilter{From: r.From, To: r.To},
+		Status:     domain.ExportJobStatus(r.Status),
+		Rows:       r.Rows,
+		Error:      r.Error,
+		CreatedBy:  r.CreatedBy,
+		CreatedAt:  r.CreatedAt,
+		FinishedAt: r.FinishedAt,
+		ExpiresAt:  r.ExpiresAt,
+	}
+	if r.DeviceID != nil {
+		id := domain.DeviceID(*r.DeviceID)
+		job.Filter.DeviceID = &id
+	}
+	return job
+}
+
+func notFound() error {
+	return domain.NotFound("export_job_not_found", "export job not found")
+}
+
+// writeAtomic: ghi file tạm rồi rename (reader không thấy JSON dở dang)
+func writeAtomic(path string, b []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// pendingFile: Close thành công mới rename sang tên thật
+type pendingFile struct {
+	*os.File
+	final string
+}
+
+func (p *pendingFile) Close() error {
+	if err := p.File.Close(); err != nil {
+		os.Remove(p.Name())
+		return err
+	}
+	return os.Rename(p.Name(), p.final)
+}
+
+type openFile struct {
+	*os.File
+	st fs.FileInfo
+}
+
+func (f *openFile) Size() int64        { return f.st.Size() }
+func (f *openFile) ModTime() time.Time { return f.st.ModTime() }
@@ -0,0 +1,319 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+)
+
+type DeviceRepository struct {
+	c *conn
+}
+
+// compile-time check
+var _ port.DeviceRepository = (*DeviceRepository)(nil)
+
+// ==== Create ====
+// uq_devices_tenant_serial + fk_devices_plan_id như bản PG
+func (r *DeviceRepository) Create(ctx context.Context, in port.CreateDeviceInput) (*domain.Device, error) {
+	tenant := tenantOf(ctx)
+	var out domain.Device
+	err := r.c.do(func(t *tables) error {
+		if err := requireTenant(tenant); err != nil {
+			return err
+		}
+		if serialTaken(t, tenant, in.SerialNumber, 0) {
+			return domain.Conflict("serial_number_taken", "serial_number already exists")
+		}
+		if in.PlanID != nil {
+			if _, ok := t.plans[int64(*in.PlanID)]; !ok {
+				return domain.Invalid("plan_id", "references an unknown plan")
+			}
+		}
+		now := time.Now()
+		out = domain.Device{
+			ID:           domain.DeviceID(r.c.s.nextID("devices")),
+			TenantID:     tenant,
+			SerialNumber: in.SerialNumber,
+			Name:         in.Name,
+			Profile: domain.DeviceProfile{
+				Model:          in.Model,
+				Manufacturer:   in.Manufacturer,
+				Year:           in.Year,
+				CommissionDate: dateOnly(in.CommissionDate),
+			},
+			State: domain.OperationalState{
+				Location:      in.Location,
+				TotalHours:    in.TotalWorkingHour,
+				AfterOverhaul: in.AfterOverhaulWorkingHour,
+				LastReadingAt: in.LastServiceAt,
+			},
+			Status:    in.Status,
+			PlanID:    in.PlanID,
+			CreatedAt: now,
+			UpdatedAt: now,
+			Version:   1,
+			Audit:     domain.AuditMeta{CreatedBy: in.CreatedBy},
+		}
+		t.devices[int64(out.ID)] = out
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ==== Get (kể cả đã xóa mềm, như GetDevice) ====
+func (r *DeviceRepository) GetByID(ctx context.Context, id domain.DeviceID) (*domain.Device, error) {
+	tenant := tenantOf(ctx)
+	var out domain.Device
+	err := r.c.do(func(t *tables) error {
+		d, ok := t.devices[int64(id)]
+		if !ok || !visible(d.TenantID, tenant) {
+			return notFound("device")
+		}
+		out = d
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetForUpdate: store đã tuần tự hóa trong transaction, không cần khóa dòng riêng
+func (r *DeviceRepository) GetForUpdate(ctx context.Context, id domain.DeviceID) (*domain.Device, error) {
+	return r.GetByID(ctx, id)
+}
+
+// ==== List: bỏ device đã xóa mềm, ORDER BY id ====
+func (r *DeviceRepository) List(ctx context.Context, scope domain.DeviceScope, limit, offset int32) ([]*domain.Device, error) {
+	tenant := tenantOf(ctx)
+	var rows []domain.Device
+	_ = r.c.do(func(t *tables) error {
+		for _, d := range t.devices {
+			if visible(d.TenantID, tenant) && d.DeletedAt == nil && scope.Allows(&d) {
+				rows = append(rows, d)
+			}
+		}
+		return nil
+	})
+	out := sortedPtrs(rows, func(a, b domain.Device) bool { return a.ID < b.ID })
+	return paginate(out, limit, offset), nil
+}
+
+// ListAfter -> id > after AND deleted_at IS NULL ORDER BY id LIMIT
+func (r *DeviceRepository) ListAfter(ctx context.Context, after domain.DeviceID, limit int32) ([]*domain.Device, error) {
+	tenant := tenantOf(ctx)
+	var rows []domain.Device
+	_ = r.c.do(func(t *tables) error {
+		for _, d := range t.devices {
+			if visible(d.TenantID, tenant) && d.DeletedAt == nil && d.ID > after {
+				rows = append(rows, d)
+			}
+		}
+		return nil
+	})
+	out := sortedPtrs(rows, func(a, b domain.Device) bool { return a.ID < b.ID })
+	return paginate(out, limit, 0), nil
+}
+
+func (r *DeviceRepository) ListBySerials(ctx context.Context, serials []string) ([]*domain.Device, error) {
+	tenant := tenantOf(ctx)
+	want := make(map[string]bool, len(serials))
+	for _, s := range serials {
+		want[s] = true
+	}
+	var rows []domain.Device
+	_ = r.c.do(func(t *tables) error {
+		for _, d := range t.devices {
+			if visible(d.TenantID, tenant) && d.DeletedAt == nil && want[d.SerialNumber] {
+				rows = append(rows, d)
+			}
+		}
+		return nil
+	})
+	return sortedPtrs(rows, func(a, b domain.Device) bool { return a.ID < b.ID }), nil
+}
+
+func (r *DeviceRepository) ListTakenSerials(ctx context.Context, serials []string) ([]string, error) {
+	tenant := tenantOf(ctx)
+	var taken []string
+	_ = r.c.do(func(t *tables) error {
+		for _, s := range serials {
+			if serialTaken(t, tenant, s, 0) {
+				taken = append(taken, s)
+			}
+		}
+		return nil
+	})
+	return taken, nil
+}
+
+func (r *DeviceRepository) UpdateBasic(ctx context.Context, id domain.DeviceID, name string, status domain.DeviceStatus, location *string, updatedBy string, expectedVersion int) (*domain.Device, error) {
+	return r.update(ctx, id, expectedVersion, func(t *tables, d *domain.Device) error {
+		d.Name = name
+		d.Status = status
+		d.State.Location = valOrEmpty(location)
+		d.Audit.UpdatedBy = updatedBy
+		return nil
+	})
+}
+
+func (r *DeviceRepository) UpdatePlan(ctx context.Context, id domain.DeviceID, planID *domain.PlanID, updatedBy string, expectedVersion int) (*domain.Device, error) {
+	return r.update(ctx, id, expectedVersion, func(t *tables, d *domain.Device) error {
+		if planID != nil {
+			if _, ok := t.plans[int64(*planID)]; !ok {
+				return domain.Invalid("plan_id", "references an unknown plan")
+			}
+		}
+		d.PlanID = planID
+		d.Audit.UpdatedBy = updatedBy
+		return nil
+	})
+}
+
+func (r *DeviceRepository) UpdateProfile(ctx context.Context, in port.UpdateDeviceProfileInput) (*domain.Device, error) {
+	tenant := tenantOf(ctx)
+	return r.update(ctx, in.ID, in.ExpectedVersion, func(t *tables, d *domain.Device) error {
+		if serialTaken(t, tenant, in.SerialNumber, in.ID) {
+			return domain.Conflict("serial_number_taken", "serial_number already exists")
+		}
+		d.SerialNumber = in.SerialNumber
+		d.Name = in.Name
+		d.Profile = domain.DeviceProfile{
+			Model:          in.Model,
+			Manufacturer:   in.Manufacturer,
+			Year:           in.Year,
+			CommissionDate: dateOnly(in.CommissionDate),
+		}
+		d.Status = in.Status
+		d.State.Location = in.Location
+		d.Audit.UpdatedBy = in.UpdatedBy
+		return nil
+	})
+}
+
+// ==== SoftDelete: không đổi updated_at/version (giống SoftDeleteDevice) ====
+func (r *DeviceRepository) SoftDelete(ctx context.Context, id domain.DeviceID, deletedBy string) error {
+	tenant := tenantOf(ctx)
+	return r.c.do(func(t *tables) error {
+		d, ok := t.devices[int64(id)]
+		if !ok || !visible(d.TenantID, tenant) {
+			return nil // UPDATE không khớp dòng nào: :exec không báo lỗi
+		}
+		d.DeletedAt = ptr(time.Now())
+		if deletedBy != "" {
+			d.Audit.DeletedBy = ptr(deletedBy)
+		}
+		t.devices[int64(id)] = d
+		return nil
+	})
+}
+
+// ==== AddUsage: cộng dồn giờ, last_service_at = GREATEST(cũ, at) ====
+func (r *DeviceRepository) AddUsage(ctx context.Context, in port.AddDeviceUsageInput) (*domain.Device, error) {
+	return r.update(ctx, in.ID, 0, func(t *tables, d *domain.Device) error {
+		d.State.TotalHours += in.HoursDelta
+		d.State.AfterOverhaul += in.HoursDelta
+		if last := d.State.LastReadingAt; last == nil || in.At.After(*last) {
+			d.State.LastReadingAt = ptr(in.At)
+		}
+		d.State.AvgDailyHours = in.AvgDailyHours
+		d.State.ExpectedNextMaint = in.ExpectedNextMaint
+		return nil
+	})
+}
+
+// ==== UpdateForecast: như bản PG, không tăng version/updated_at; đã xóa/không thấy -> bỏ qua ====
+func (r *DeviceRepository) UpdateForecast(ctx context.Context, id domain.DeviceID, avgDailyHours float64, expectedNextMaint *time.Time) error {
+	tenant := tenantOf(ctx)
+	return r.c.do(func(t *tables) error {
+		d, ok := t.devices[int64(id)]
+		if !ok || !visible(d.TenantID, tenant) || d.DeletedAt != nil {
+			return nil
+		}
+		d.State.AvgDailyHours = avgDailyHours
+		d.State.ExpectedNextMaint = expectedNextMaint
+		t.devices[int64(id)] = d
+		return nil
+	})
+}
+
+// ==== ListTenants: như app_tenant_ids(), không lọc theo tenant của principal ====
+func (r *DeviceRepository) ListTenants(ctx context.Context) ([]string, error) {
+	seen := map[string]bool{}
+	_ = r.c.do(func(t *tables) error {
+		for _, d := range t.devices {
+			if d.DeletedAt == nil {
+				seen[d.TenantID] = true
+			}
+		}
+		return nil
+	})
+	out := make([]string, 0, len(seen))
+	for tenant := range seen {
+		out = append(out, tenant)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// update: đọc-sửa-ghi một device; version != 0 phải khớp (như mapVersionErr: 404 hoặc 412)
+func (r *DeviceRepository) update(ctx context.Context, id domain.DeviceID, expectedVersion int, apply func(t *tables, d *domain.Device) error) (*domain.Device, error) {
+	tenant := tenantOf(ctx)
+	var out domain.Device
+	err := r.c.do(func(t *tables) error {
+		d, ok := t.devices[int64(id)]
+		if !ok || !visible(d.TenantID, tenant) {
+			return notFound("device")
+		}
+		if expectedVersion != 0 && d.Version != expectedVersion {
+			return versionMismatch("device")
+		}
+		if err := apply(t, &d); err != nil {
+			return err
+		}
+		d.Version++
+		d.UpdatedAt = time.Now()
+		t.devices[int64(id)] = d
+		out = d
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// --- helpers ---
+
+// serialTaken: unique (tenant_id, serial_number), kể cả device đã xóa mềm
+func serialTaken(t *tables, tenant, serial string, except domain.DeviceID) bool {
+	for _, d := range t.devices {
+		if d.TenantID == tenant && d.SerialNumber == serial && d.ID != except {
+			return true
+		}
+	}
+	return false
+}
+
+// dateOnly: cột DATE chỉ giữ ngày
+func dateOnly(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func valOrEmpty(p *string) string {
+	if p != nil {
+		return *p
+	}
+	return ""
+}
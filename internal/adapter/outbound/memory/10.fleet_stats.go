@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+)
+
+// FleetStatsRepository: cùng phép đếm với 10.fleet_stats.sql
+type FleetStatsRepository struct {
+	c *conn
+}
+
+// compile-time check
+var _ port.FleetStatsRepository = (*FleetStatsRepository)(nil)
+
+func (r *FleetStatsRepository) Snapshot(ctx context.Context, now time.Time) (*domain.FleetStats, error) {
+	tenant := tenantOf(ctx)
+	type statusSite struct {
+		status domain.DeviceStatus
+		site   string
+	}
+	devices := map[statusSite]int64{}
+	alerts := map[string]int64{}
+	overdue := map[string]int64{}
+	readings := map[string]*domain.ReadingTotal{}
+	var maint domain.MaintenanceTotal
+
+	_ = r.c.do(func(t *tables) error {
+		live := map[domain.DeviceID]bool{}
+		for _, d := range t.devices {
+			if !visible(d.TenantID, tenant) || d.DeletedAt != nil {
+				continue
+			}
+			live[d.ID] = true
+			devices[statusSite{d.Status, d.State.Location}]++
+			if d.Status != domain.StatusActive {
+				continue
+			}
+			var plan *domain.Plan
+			if d.PlanID != nil {
+				if p, ok := t.plans[int64(*d.PlanID)]; ok {
+					plan = &p
+				}
+			}
+			if d.Overdue(plan, now) {
+				overdue[d.State.Location]++
+			}
+		}
+		for _, a := range t.alerts {
+			if visible(a.tenant, tenant) && !a.row.Resolved && live[a.row.DeviceID] {
+				alerts[a.row.Type]++
+			}
+		}
+		for _, x := range t.readings {
+			if !visible(x.tenant, tenant) {
+				continue
+			}
+			rt := readings[x.row.Source]
+			if rt == nil {
+				rt = &domain.ReadingTotal{Source: x.row.Source}
+				readings[x.row.Source] = rt
+			}
+			rt.Readings++
+			rt.Hours += int64(x.row.HoursDelta)
+		}
+		for _, x := range t.maint {
+			if visible(x.tenant, tenant) {
+				maint.Events++
+				maint.Cost += x.row.Cost
+			}
+		}
+		return nil
+	})
+
+	out := &domain.FleetStats{Maintenance: maint}
+	for k, n := range devices {
+		out.Devices = append(out.Devices, domain.DeviceCount{Status: k.status, Site: k.site, Count: n})
+	}
+	for typ, n := range alerts {
+		out.OpenAlerts = append(out.OpenAlerts, domain.AlertCount{Type: typ, Count: n})
+	}
+	for site, n := range overdue {
+		out.Overdue = append(out.Overdue, domain.SiteCount{Site: site, Count: n})
+	}
+	for _, rt := range readings {
+		out.Readings = append(out.Readings, *rt)
+	}
+	// thứ tự ổn định như ORDER BY (PG GROUP BY không đảm bảo thứ tự, caller không phụ thuộc)
+	sort.Slice(out.Devices, func(i, j int) bool {
+		a, b := out.Devices[i], out.Devices[j]
+		return a.Status < b.Status || (a.Status == b.Status && a.Site < b.Site)
+	})
+	sort.Slice(out.OpenAlerts, func(i, j int) bool { return out.OpenAlerts[i].Type < out.OpenAlerts[j].Type })
+	sort.Slice(out.Overdue, func(i, j int) bool { return out.Overdue[i].Site < out.Overdue[j].Site })
+	sort.Slice(out.Readings, func(i, j int) bool { return out.Readings[i].Source < out.Readings[j].Source })
+	return out, nil
+}
@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+)
+
+type PlanRepository struct {
+	c *conn
+}
+
+// compile-time check
+var _ port.PlanRepository = (*PlanRepository)(nil)
+
+// policy plans_read: template dùng chung (tenant NULL) + plan của tenant mình
+func planReadable(p domain.Plan, tenant string) bool {
+	return p.TenantID == nil || (tenant != "" && *p.TenantID == tenant)
+}
+
+// policy plans_write: tenant_id IS NOT DISTINCT FROM app_tenant()
+func planWritable(p domain.Plan, tenant string) bool {
+	if p.TenantID == nil {
+		return tenant == ""
+	}
+	return *p.TenantID == tenant
+}
+
+func (r *PlanRepository) Create(ctx context.Context, in port.CreatePlanInput) (*domain.Plan, error) {
+	var tenantID *string
+	if tenant := tenantOf(ctx); tenant != "" {
+		tenantID = &tenant
+	}
+	if in.IntervalHours <= 0 { // CHECK (interval_hours > 0)
+		return nil, domain.Invalid("interval_hours", "violates database constraint")
+	}
+	var out domain.Plan
+	_ = r.c.do(func(t *tables) error {
+		now := time.Now()
+		out = domain.Plan{
+			ID:            domain.PlanID(r.c.s.nextID("plans")),
+			TenantID:      tenantID,
+			Name:          in.Name,
+			IntervalHours: in.IntervalHours,
+			Description:   in.Description,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+			Version:       1,
+		}
+		t.plans[int64(out.ID)] = out
+		return nil
+	})
+	return &out, nil
+}
+
+func (r *PlanRepository) GetByID(ctx context.Context, id domain.PlanID) (*domain.Plan, error) {
+	tenant := tenantOf(ctx)
+	var out domain.Plan
+	err := r.c.do(func(t *tables) error {
+		p, ok := t.plans[int64(id)]
+		if !ok || !planReadable(p, tenant) {
+			return notFound("plan")
+		}
+		out = p
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// List: ORDER BY id
+func (r *PlanRepository) List(ctx context.Context, limit, offset int32) ([]*domain.Plan, error) {
+	tenant := tenantOf(ctx)
+	var rows []domain.Plan
+	_ = r.c.do(func(t *tables) error {
+		for _, p := range t.plans {
+			if planReadable(p, tenant) {
+				rows = append(rows, p)
+			}
+		}
+		return nil
+	})
+	out := sortedPtrs(rows, func(a, b domain.Plan) bool { return a.ID < b.ID })
+	return paginate(out, limit, offset), nil
+}
+
+func (r *PlanRepository) Update(ctx context.Context, in port.UpdatePlanInput) (*domain.Plan, error) {
+	tenant := tenantOf(ctx)
+	if in.IntervalHours <= 0 {
+		return nil, domain.Invalid("interval_hours", "violates database constraint")
+	}
+	var out domain.Plan
+	err := r.c.do(func(t *tables) error {
+		p, ok := t.plans[int64(in.ID)]
+		if !ok || !planWritable(p, tenant) {
+			return notFound("plan")
+		}
+		if in.Version != 0 && p.Version != in.Version {
+			return versionMismatch("plan")
+		}
+		p.Name = in.Name
+		p.IntervalHours = in.IntervalHours
+		p.Description = in.Description
+		p.Version++
+		p.UpdatedAt = time.Now()
+		t.plans[int64(in.ID)] = p
+		out = p
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Delete: device đang gắn plan về NULL (FK ON DELETE SET NULL, không phân biệt tenant)
+func (r *PlanRepository) Delete(ctx context.Context, id domain.PlanID) error {
+	tenant := tenantOf(ctx)
+	return r.c.do(func(t *tables) error {
+		p, ok := t.plans[int64(id)]
+		if !ok || !planWritable(p, tenant) {
+			return nil // DELETE không khớp dòng nào
+		}
+		delete(t.plans, int64(id))
+		for devID, d := range t.devices {
+			if d.PlanID != nil && *d.PlanID == id {
+				d.PlanID = nil
+				t.devices[devID] = d
+			}
+		}
+		return nil
+	})
+}
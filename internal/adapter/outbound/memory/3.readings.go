@@ -0,0 +1,156 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+)
+
+type ReadingRepository struct {
+	c *conn
+}
+
+// compile-time check
+var _ port.ReadingRepository = (*ReadingRepository)(nil)
+
+func (r *ReadingRepository) Create(ctx context.Context, in port.CreateReadingInput) (*domain.Reading, error) {
+	tenant := tenantOf(ctx)
+	if in.HoursDelta < 0 { // CHECK (hours_delta >= 0)
+		return nil, domain.Invalid("hours_delta", "violates database constraint")
+	}
+	var out domain.Reading
+	err := r.c.do(func(t *tables) error {
+		if err := deviceRef(t, tenant, in.DeviceID); err != nil {
+			return err
+		}
+		out = domain.Reading{
+			ID:         r.c.s.nextID("readings"),
+			DeviceID:   in.DeviceID,
+			At:         in.At,
+			HoursDelta: in.HoursDelta,
+			Location:   valOrEmpty(in.Location),
+			OperatorID: valOrEmpty(in.OperatorID),
+			Source:     domain.ReadingSourceAPI,
+		}
+		t.readings[out.ID] = tenantRow[domain.Reading]{tenant: tenant, row: out}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateMany: như Create cho từng dòng, lỗi một dòng thì không ghi dòng nào
+func (r *ReadingRepository) CreateMany(ctx context.Context, in []port.CreateReadingInput) (int64, error) {
+	tenant := tenantOf(ctx)
+	err := r.c.do(func(t *tables) error {
+		for _, x := range in {
+			if x.HoursDelta < 0 {
+				return domain.Invalid("hours_delta", "violates database constraint")
+			}
+			if err := deviceRef(t, tenant, x.DeviceID); err != nil {
+				return err
+			}
+		}
+		for _, x := range in {
+			id := r.c.s.nextID("readings")
+			t.readings[id] = tenantRow[domain.Reading]{tenant: tenant, row: domain.Reading{
+				ID:         id,
+				DeviceID:   x.DeviceID,
+				At:         x.At,
+				HoursDelta: x.HoursDelta,
+				Location:   valOrEmpty(x.Location),
+				OperatorID: valOrEmpty(x.OperatorID),
+				Source:     domain.ReadingSourceImport,
+			}}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(in)), nil
+}
+
+// GetFirstByDevice -> ORDER BY at LIMIT 1
+func (r *ReadingRepository) GetFirstByDevice(ctx context.Context, deviceID domain.DeviceID) (*domain.Reading, error) {
+	rows := r.byDevice(ctx, deviceID)
+	if len(rows) == 0 {
+		return nil, notFound("reading")
+	}
+	return rows[len(rows)-1], nil
+}
+
+// GetLastByDevice -> ORDER BY at DESC LIMIT 1
+func (r *ReadingRepository) GetLastByDevice(ctx context.Context, deviceID domain.DeviceID) (*domain.Reading, error) {
+	rows := r.byDevice(ctx, deviceID)
+	if len(rows) == 0 {
+		return nil, notFound("reading")
+	}
+	return rows[0], nil
+}
+
+// SumHoursSince -> tổng hours_delta của reading có at > since
+func (r *ReadingRepository) SumHoursSince(ctx context.Context, deviceID domain.DeviceID, since time.Time) (int, error) {
+	sum := 0
+	for _, rd := range r.byDevice(ctx, deviceID) {
+		if rd.At.After(since) {
+			sum += rd.HoursDelta
+		}
+	}
+	return sum, nil
+}
+
+// ListByDevice -> phân trang theo at DESC
+func (r *ReadingRepository) ListByDevice(ctx context.Context, deviceID domain.DeviceID, limit, offset int32) ([]*domain.Reading, error) {
+	return paginate(r.byDevice(ctx, deviceID), limit, offset), nil
+}
+
+// ListRange -> at trong [from, to), (at, id) > keyset, ORDER BY at, id LIMIT
+func (r *ReadingRepository) ListRange(ctx context.Context, q port.RangeQuery) ([]*domain.Reading, error) {
+	tenant := tenantOf(ctx)
+	var rows []domain.Reading
+	_ = r.c.do(func(t *tables) error {
+		for _, x := range t.readings {
+			if visible(x.tenant, tenant) && inRange(q, x.row.DeviceID, x.row.At, x.row.ID) {
+				rows = append(rows, x.row)
+			}
+		}
+		return nil
+	})
+	out := sortedPtrs(rows, func(a, b domain.Reading) bool { return keysetLess(a.At, a.ID, b.At, b.ID) })
+	return paginate(out, q.Limit, 0), nil
+}
+
+func (r *ReadingRepository) Delete(ctx context.Context, id int64) error {
+	tenant := tenantOf(ctx)
+	return r.c.do(func(t *tables) error {
+		if x, ok := t.readings[id]; ok && visible(x.tenant, tenant) {
+			delete(t.readings, id)
+		}
+		return nil
+	})
+}
+
+// byDevice: at DESC, cùng at thì id DESC cho ổn định
+func (r *ReadingRepository) byDevice(ctx context.Context, deviceID domain.DeviceID) []*domain.Reading {
+	tenant := tenantOf(ctx)
+	var rows []domain.Reading
+	_ = r.c.do(func(t *tables) error {
+		for _, x := range t.readings {
+			if visible(x.tenant, tenant) && x.row.DeviceID == deviceID {
+				rows = append(rows, x.row)
+			}
+		}
+		return nil
+	})
+	return sortedPtrs(rows, func(a, b domain.Reading) bool {
+		if !a.At.Equal(b.At) {
+			return a.At.After(b.At)
+		}
+		return a.ID > b.ID
+	})
+}
@@ -0,0 +1,116 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+)
+
+type AlertRepository struct {
+	c *conn
+}
+
+// compile-time check
+var _ port.AlertRepository = (*AlertRepository)(nil)
+
+func (r *AlertRepository) Create(ctx context.Context, in port.CreateAlertInput) (*domain.Alert, error) {
+	tenant := tenantOf(ctx)
+	var out domain.Alert
+	err := r.c.do(func(t *tables) error {
+		if err := deviceRef(t, tenant, in.DeviceID); err != nil {
+			return err
+		}
+		out = domain.Alert{
+			ID:        r.c.s.nextID("alerts"),
+			DeviceID:  in.DeviceID,
+			Type:      in.Type,
+			Message:   in.Message,
+			CreatedAt: time.Now(),
+		}
+		t.alerts[out.ID] = tenantRow[domain.Alert]{tenant: tenant, row: out}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetByID -> theo id (kể cả alert đã resolve)
+func (r *AlertRepository) GetByID(ctx context.Context, id int64) (*domain.Alert, error) {
+	tenant := tenantOf(ctx)
+	var out domain.Alert
+	err := r.c.do(func(t *tables) error {
+		x, ok := t.alerts[id]
+		if !ok || !visible(x.tenant, tenant) {
+			return notFound("alert")
+		}
+		out = x.row
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListOpenByDevice -> resolved = false, ORDER BY created_at DESC
+func (r *AlertRepository) ListOpenByDevice(ctx context.Context, deviceID domain.DeviceID, limit, offset int32) ([]*domain.Alert, error) {
+	tenant := tenantOf(ctx)
+	var rows []domain.Alert
+	_ = r.c.do(func(t *tables) error {
+		for _, x := range t.alerts {
+			if visible(x.tenant, tenant) && x.row.DeviceID == deviceID && !x.row.Resolved {
+				rows = append(rows, x.row)
+			}
+		}
+		return nil
+	})
+	out := sortedPtrs(rows, func(a, b domain.Alert) bool {
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.ID > b.ID
+	})
+	return paginate(out, limit, offset), nil
+}
+
+// ListRange -> created_at trong [from, to), (created_at, id) > keyset, ORDER BY created_at, id LIMIT
+func (r *AlertRepository) ListRange(ctx context.Context, q port.RangeQuery) ([]*domain.Alert, error) {
+	tenant := tenantOf(ctx)
+	var rows []domain.Alert
+	_ = r.c.do(func(t *tables) error {
+		for _, x := range t.alerts {
+			if visible(x.tenant, tenant) && inRange(q, x.row.DeviceID, x.row.CreatedAt, x.row.ID) {
+				rows = append(rows, x.row)
+			}
+		}
+		return nil
+	})
+	out := sortedPtrs(rows, func(a, b domain.Alert) bool { return keysetLess(a.CreatedAt, a.ID, b.CreatedAt, b.ID) })
+	return paginate(out, q.Limit, 0), nil
+}
+
+// Resolve -> resolved=true, resolved_at=NOW(), resolved_by (kể cả alert đã resolve, như bản PG)
+func (r *AlertRepository) Resolve(ctx context.Context, in port.ResolveAlertInput) (*domain.Alert, error) {
+	tenant := tenantOf(ctx)
+	var out domain.Alert
+	err := r.c.do(func(t *tables) error {
+		x, ok := t.alerts[in.ID]
+		if !ok || !visible(x.tenant, tenant) {
+			return notFound("alert")
+		}
+		x.row.Resolved = true
+		x.row.ResolvedAt = ptr(time.Now())
+		x.row.ResolvedBy = valOrEmpty(in.ResolvedBy)
+		t.alerts[in.ID] = x
+		out = x.row
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"math"
+	"strconv"
+
+	"wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+)
+
+type MaintenanceRepository struct {
+	c *conn
+}
+
+// compile-time check
+var _ port.MaintenanceRepository = (*MaintenanceRepository)(nil)
+
+func (r *MaintenanceRepository) Create(ctx context.Context, in port.CreateMaintenanceInput) (*domain.MaintenanceEvent, error) {
+	tenant := tenantOf(ctx)
+
+	// cost NUMERIC(12,2) DEFAULT 0 CHECK (cost >= 0)
+	var cost float64
+	if in.Cost != nil {
+		f, err := strconv.ParseFloat(*in.Cost, 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, domain.Invalid("cost", "must be a decimal")
+		}
+		if f < 0 {
+			return nil, domain.Invalid("cost", "violates database constraint")
+		}
+		cost = math.Round(f*100) / 100
+	}
+	var interval int
+	if in.Interval != nil {
+		interval = int(*in.Interval)
+	}
+
+	var out domain.MaintenanceEvent
+	err := r.c.do(func(t *tables) error {
+		if err := deviceRef(t, tenant, in.DeviceID); err != nil {
+			return err
+		}
+		out = domain.MaintenanceEvent{
+			ID:          r.c.s.nextID("maintenance_events"),
+			DeviceID:    in.DeviceID,
+			At:          in.At,
+			Interval:    interval,
+			Notes:       valOrEmpty(in.Notes),
+			PerformedBy: valOrEmpty(in.PerformedBy),
+			Cost:        cost,
+		}
+		t.maint[out.ID] = tenantRow[domain.MaintenanceEvent]{tenant: tenant, row: out}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (r *MaintenanceRepository) Delete(ctx context.Context, id int64) error {
+	tenant := tenantOf(ctx)
+	return r.c.do(func(t *tables) error {
+		if x, ok := t.maint[id]; ok && visible(x.tenant, tenant) {
+			delete(t.maint, id)
+		}
+		return nil
+	})
+}
+
+// ListByDevice -> ORDER BY at DESC
+func (r *MaintenanceRepository) ListByDevice(ctx context.Context, deviceID domain.DeviceID, limit, offset int32) ([]*domain.MaintenanceEvent, error) {
+	tenant := tenantOf(ctx)
+	var rows []domain.MaintenanceEvent
+	_ = r.c.do(func(t *tables) error {
+		for _, x := range t.maint {
+			if visible(x.tenant, tenant) && x.row.DeviceID == deviceID {
+				rows = append(rows, x.row)
+			}
+		}
+		return nil
+	})
+	out := sortedPtrs(rows, func(a, b domain.MaintenanceEvent) bool {
+		if !a.At.Equal(b.At) {
+			return a.At.After(b.At)
+		}
+		return a.ID > b.ID
+	})
+	return paginate(out, limit, offset), nil
+}
+
+// ListRange -> at trong [from, to), (at, id) > keyset, ORDER BY at, id LIMIT
+func (r *MaintenanceRepository) ListRange(ctx context.Context, q port.RangeQuery) ([]*domain.MaintenanceEvent, error) {
+	tenant := tenantOf(ctx)
+	var rows []domain.MaintenanceEvent
+	_ = r.c.do(func(t *tables) error {
+		for _, x := range t.maint {
+			if visible(x.tenant, tenant) && inRange(q, x.row.DeviceID, x.row.At, x.row.ID) {
+				rows = append(rows, x.row)
+			}
+		}
+		return nil
+	})
+	out := sortedPtrs(rows, func(a, b domain.MaintenanceEvent) bool { return keysetLess(a.At, a.ID, b.At, b.ID) })
+	return paginate(out, q.Limit, 0), nil
+}
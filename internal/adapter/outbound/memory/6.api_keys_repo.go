@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+)
+
+// APIKeyRepository: bảng api_keys không bật RLS (GetByPrefix chạy trước khi biết tenant);
+// List/Revoke tự lọc tenant_id IS NOT DISTINCT FROM app_tenant() như query PG.
+type APIKeyRepository struct {
+	c *conn
+}
+
+// compile-time check
+var _ port.APIKeyRepository = (*APIKeyRepository)(nil)
+
+// sameTenant: IS NOT DISTINCT FROM, tenant rỗng = NULL (key cấp hệ thống)
+func sameTenant(keyTenant *string, tenant string) bool {
+	if keyTenant == nil {
+		return tenant == ""
+	}
+	return *keyTenant == tenant
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, in port.CreateAPIKeyInput) (*domain.APIKey, error) {
+	var tenantID *string
+	if tenant := tenantOf(ctx); tenant != "" {
+		tenantID = &tenant
+	}
+	var out domain.APIKey
+	err := r.c.do(func(t *tables) error {
+		for _, k := range t.apiKeys {
+			if k.Prefix == in.Prefix {
+				return domain.Conflict("api_key_prefix_taken", "api key prefix collision, retry")
+			}
+		}
+		out = domain.APIKey{
+			ID:         r.c.s.nextID("api_keys"),
+			TenantID:   tenantID,
+			Name:       in.Name,
+			Prefix:     in.Prefix,
+			SecretHash: in.SecretHash,
+			Scopes:     slices.Clone(in.Scopes),
+			DeviceIDs:  slices.Clone(in.DeviceIDs),
+			Sites:      slices.Clone(in.Sites),
+			ExpiresAt:  in.ExpiresAt,
+			CreatedAt:  time.Now(),
+			CreatedBy:  in.CreatedBy,
+		}
+		t.apiKeys[out.ID] = out
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (r *APIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	var out domain.APIKey
+	err := r.c.do(func(t *tables) error {
+		for _, k := range t.apiKeys {
+			if k.Prefix == prefix {
+				out = k
+				return nil
+			}
+		}
+		return notFound("api_key")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// List: ORDER BY id
+func (r *APIKeyRepository) List(ctx context.Context, limit, offset int32) ([]*domain.APIKey, error) {
+	tenant := tenantOf(ctx)
+	var rows []domain.APIKey
+	_ = r.c.do(func(t *tables) error {
+		for _, k := range t.apiKeys {
+			if sameTenant(k.TenantID, tenant) {
+				rows = append(rows, k)
+			}
+		}
+		return nil
+	})
+	out := sortedPtrs(rows, func(a, b domain.APIKey) bool { return a.ID < b.ID })
+	return paginate(out, limit, offset), nil
+}
+
+// Revoke: key đã revoke hoặc của tenant khác -> not found
+func (r *APIKeyRepository) Revoke(ctx context.Context, id int64) (*domain.APIKey, error) {
+	tenant := tenantOf(ctx)
+	var out domain.APIKey
+	err := r.c.do(func(t *tables) error {
+		k, ok := t.apiKeys[id]
+		if !ok || k.RevokedAt != nil || !sameTenant(k.TenantID, tenant) {
+			return notFound("api_key")
+		}
+		k.RevokedAt = ptr(time.Now())
+		t.apiKeys[id] = k
+		out = k
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// TouchLastUsed: ghi tối đa 1 lần/phút mỗi key
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id int64) error {
+	return r.c.do(func(t *tables) error {
+		k, ok := t.apiKeys[id]
+		now := time.Now()
+		if !ok || (k.LastUsedAt != nil && !k.LastUsedAt.Before(now.Add(-time.Minute))) {
+			return nil
+		}
+		k.LastUsedAt = &now
+		t.apiKeys[id] = k
+		return nil
+	})
+}
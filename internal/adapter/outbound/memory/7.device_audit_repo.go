@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"time"
+
+	"wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+)
+
+type DeviceAuditRepository struct {
+	c *conn
+}
+
+// compile-time check
+var _ port.DeviceAuditRepository = (*DeviceAuditRepository)(nil)
+
+func (r *DeviceAuditRepository) Record(ctx context.Context, in port.RecordDeviceAuditInput) (*domain.DeviceAuditEntry, error) {
+	tenant := tenantOf(ctx)
+	var out domain.DeviceAuditEntry
+	err := r.c.do(func(t *tables) error {
+		if err := deviceRef(t, tenant, in.DeviceID); err != nil {
+			return err
+		}
+		out = domain.DeviceAuditEntry{
+			ID:        r.c.s.nextID("device_audit_log"),
+			DeviceID:  in.DeviceID,
+			Action:    in.Action,
+			Actor:     in.Actor,
+			Changes:   maps.Clone(in.Changes),
+			CreatedAt: time.Now(),
+		}
+		t.audit[out.ID] = tenantRow[domain.DeviceAuditEntry]{tenant: tenant, row: out}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListByDevice -> ORDER BY created_at DESC, id DESC
+func (r *DeviceAuditRepository) ListByDevice(ctx context.Context, deviceID domain.DeviceID, limit, offset int32) ([]*domain.DeviceAuditEntry, error) {
+	tenant := tenantOf(ctx)
+	var rows []domain.DeviceAuditEntry
+	_ = r.c.do(func(t *tables) error {
+		for _, x := range t.audit {
+			if visible(x.tenant, tenant) && x.row.DeviceID == deviceID {
+				rows = append(rows, x.row)
+			}
+		}
+		return nil
+	})
+	out := sortedPtrs(rows, func(a, b domain.DeviceAuditEntry) bool {
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.ID > b.ID
+	})
+	return paginate(out, limit, offset), nil
+}
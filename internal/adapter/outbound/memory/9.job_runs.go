@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+)
+
+// JobRunRepository: như bảng job_runs (không RLS, không theo tenant)
+type JobRunRepository struct {
+	c *conn
+}
+
+// compile-time check
+var _ port.JobRunRepository = (*JobRunRepository)(nil)
+
+func (r *JobRunRepository) Create(ctx context.Context, run *domain.JobRun) (*domain.JobRun, error) {
+	out := *run
+	out.ID = r.c.s.nextID("job_runs")
+	_ = r.c.do(func(t *tables) error {
+		t.jobRuns[out.ID] = out
+		return nil
+	})
+	return &out, nil
+}
+
+// List -> WHERE job ORDER BY started_at DESC, id DESC
+func (r *JobRunRepository) List(ctx context.Context, job string, limit, offset int32) ([]*domain.JobRun, error) {
+	var rows []domain.JobRun
+	_ = r.c.do(func(t *tables) error {
+		for _, x := range t.jobRuns {
+			if x.Job == job {
+				rows = append(rows, x)
+			}
+		}
+		return nil
+	})
+	return paginate(sortedPtrs(rows, newerRun), limit, offset), nil
+}
+
+// Latest -> DISTINCT ON (job) ... ORDER BY job, started_at DESC, id DESC
+func (r *JobRunRepository) Latest(ctx context.Context) ([]*domain.JobRun, error) {
+	latest := map[string]domain.JobRun{}
+	_ = r.c.do(func(t *tables) error {
+		for _, x := range t.jobRuns {
+			if cur, ok := latest[x.Job]; !ok || newerRun(x, cur) {
+				latest[x.Job] = x
+			}
+		}
+		return nil
+	})
+	rows := make([]domain.JobRun, 0, len(latest))
+	for _, x := range latest {
+		rows = append(rows, x)
+	}
+	return sortedPtrs(rows, func(a, b domain.JobRun) bool { return a.Job < b.Job }), nil
+}
+
+func (r *JobRunRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	var n int64
+	_ = r.c.do(func(t *tables) error {
+		for id, x := range t.jobRuns {
+			if x.StartedAt.Before(before) {
+				delete(t.jobRuns, id)
+				n++
+			}
+		}
+		return nil
+	})
+	return n, nil
+}
+
+func newerRun(a, b domain.JobRun) bool {
+	if !a.StartedAt.Equal(b.StartedAt) {
+		return a.StartedAt.After(b.StartedAt)
+	}
+	return a.ID > b.ID
+}
+
+// ==== LeaderLock: một process duy nhất dùng store -> luôn là leader ====
+type LeaderLock struct{}
+
+// compile-time check
+var _ port.LeaderLock = LeaderLock{}
+
+func (LeaderLock) TryAcquire(ctx context.Context) (bool, error) { return true, nil }
+func (LeaderLock) Held(ctx context.Context) bool                { return true }
+func (LeaderLock) Release(ctx context.Context) error            { return nil }
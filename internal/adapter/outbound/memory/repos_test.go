@@ -0,0 +1,236 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+)
+
+// seedDevice: một device của tenant t1
+func seedDevice(t *testing.T, r port.Repos, serial string) *domain.Device {
+	t.Helper()
+	dev, err := r.Devices.Create(tenantCtx("t1"), port.CreateDeviceInput{SerialNumber: serial, Name: serial, Status: domain.StatusActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dev
+}
+
+// Ghi có điều kiện version: lệch -> 412, không tồn tại/khác tenant -> 404 (như mapVersionErr bản PG).
+func TestDeviceVersionedWrites(t *testing.T) {
+	writes := map[string]func(ctx context.Context, r port.Repos, id domain.DeviceID, version int) error{
+		"UpdateBasic": func(ctx context.Context, r port.Repos, id domain.DeviceID, version int) error {
+			_, err := r.Devices.UpdateBasic(ctx, id, "x", domain.StatusActive, nil, "u", version)
+			return err
+		},
+		"UpdatePlan": func(ctx context.Context, r port.Repos, id domain.DeviceID, version int) error {
+			_, err := r.Devices.UpdatePlan(ctx, id, nil, "u", version)
+			return err
+		},
+		"UpdateProfile": func(ctx context.Context, r port.Repos, id domain.DeviceID, version int) error {
+			_, err := r.Devices.UpdateProfile(ctx, port.UpdateDeviceProfileInput{ID: id, SerialNumber: "SN-1", Name: "x", Status: domain.StatusActive, ExpectedVersion: version})
+			return err
+		},
+	}
+	cases := []struct {
+		name    string
+		tenant  string
+		id      func(dev *domain.Device) domain.DeviceID
+		version func(dev *domain.Device) int
+		want    error
+	}{
+		{"current version", "t1", devID, curVersion, nil},
+		{"version 0 skips check", "t1", devID, func(*domain.Device) int { return 0 }, nil},
+		{"stale version", "t1", devID, func(d *domain.Device) int { return d.Version + 1 }, domain.ErrPreconditionFailed},
+		{"unknown id", "t1", func(*domain.Device) domain.DeviceID { return 999 }, curVersion, domain.ErrNotFound},
+		{"unknown id with stale version", "t1", func(*domain.Device) domain.DeviceID { return 999 }, func(d *domain.Device) int { return d.Version + 1 }, domain.ErrNotFound},
+		{"other tenant", "t2", devID, curVersion, domain.ErrNotFound},
+		{"other tenant with stale version", "t2", devID, func(d *domain.Device) int { return d.Version + 1 }, domain.ErrNotFound},
+	}
+	for name, write := range writes {
+		for _, tc := range cases {
+			t.Run(name+"/"+tc.name, func(t *testing.T) {
+				r := NewStore().Repos()
+				dev := seedDevice(t, r, "SN-1")
+
+				err := write(tenantCtx(tc.tenant), r, tc.id(dev), tc.version(dev))
+				if tc.want == nil {
+					if err != nil {
+						t.Fatalf("err = %v", err)
+					}
+					got, _ := r.Devices.GetByID(tenantCtx("t1"), dev.ID)
+					if got.Version != dev.Version+1 {
+						t.Errorf("version = %d, want %d", got.Version, dev.Version+1)
+					}
+					return
+				}
+				if !errors.Is(err, tc.want) {
+					t.Fatalf("err = %v, want %v", err, tc.want)
+				}
+				got, _ := r.Devices.GetByID(tenantCtx("t1"), dev.ID)
+				if got.Version != dev.Version || got.Name != dev.Name {
+					t.Errorf("device changed on failed write: %+v", got)
+				}
+			})
+		}
+	}
+}
+
+func devID(d *domain.Device) domain.DeviceID { return d.ID }
+func curVersion(d *domain.Device) int        { return d.Version }
+
+func TestPlanVersionedUpdate(t *testing.T) {
+	cases := []struct {
+		name    string
+		tenant  string
+		id      domain.PlanID // 0 = plan vừa tạo
+		version int           // -1 = version hiện tại
+		want    error
+	}{
+		{"current version", "t1", 0, -1, nil},
+		{"stale version", "t1", 0, 5, domain.ErrPreconditionFailed},
+		{"unknown id", "t1", 999, -1, domain.ErrNotFound},
+		{"other tenant", "t2", 0, -1, domain.ErrNotFound},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewStore().Repos()
+			plan, err := r.Plans.Create(tenantCtx("t1"), port.CreatePlanInput{Name: "p", IntervalHours: 250})
+			if err != nil {
+				t.Fatal(err)
+			}
+			in := port.UpdatePlanInput{ID: tc.id, Name: "q", IntervalHours: 500, Version: tc.version}
+			if in.ID == 0 {
+				in.ID = plan.ID
+			}
+			if in.Version == -1 {
+				in.Version = plan.Version
+			}
+			_, err = r.Plans.Update(tenantCtx(tc.tenant), in)
+			if tc.want == nil && err != nil || tc.want != nil && !errors.Is(err, tc.want) {
+				t.Fatalf("err = %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+// Xóa mềm: List/ListAfter/ListBySerials bỏ qua; GetByID vẫn thấy; serial vẫn bị giữ.
+func TestDeviceSoftDeleteVisibility(t *testing.T) {
+	r := NewStore().Repos()
+	ctx := tenantCtx("t1")
+	kept := seedDevice(t, r, "SN-1")
+	gone := seedDevice(t, r, "SN-2")
+	if err := r.Devices.SoftDelete(ctx, gone.ID, "admin"); err != nil {
+		t.Fatal(err)
+	}
+
+	lists := map[string]func() ([]*domain.Device, error){
+		"List":          func() ([]*domain.Device, error) { return r.Devices.List(ctx, domain.DeviceScope{}, 10, 0) },
+		"ListAfter":     func() ([]*domain.Device, error) { return r.Devices.ListAfter(ctx, 0, 10) },
+		"ListBySerials": func() ([]*domain.Device, error) { return r.Devices.ListBySerials(ctx, []string{"SN-1", "SN-2"}) },
+	}
+	for name, list := range lists {
+		t.Run(name, func(t *testing.T) {
+			devs, err := list()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(devs) != 1 || devs[0].ID != kept.ID {
+				t.Fatalf("got %d devices, want only %d", len(devs), kept.ID)
+			}
+		})
+	}
+
+	t.Run("GetByID", func(t *testing.T) {
+		got, err := r.Devices.GetByID(ctx, gone.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.DeletedAt == nil || got.Audit.DeletedBy == nil || *got.Audit.DeletedBy != "admin" {
+			t.Fatalf("deleted_at/deleted_by not set: %+v", got)
+		}
+	})
+	t.Run("serial stays taken", func(t *testing.T) {
+		taken, _ := r.Devices.ListTakenSerials(ctx, []string{"SN-2"})
+		if len(taken) != 1 {
+			t.Fatalf("taken = %v, want [SN-2]", taken)
+		}
+		_, err := r.Devices.Create(ctx, port.CreateDeviceInput{SerialNumber: "SN-2", Name: "again", Status: domain.StatusActive})
+		if !errors.Is(err, domain.ErrConflict) {
+			t.Fatalf("err = %v, want ErrConflict", err)
+		}
+	})
+	t.Run("UpdateForecast skips deleted", func(t *testing.T) {
+		if err := r.Devices.UpdateForecast(ctx, gone.ID, 8, nil); err != nil {
+			t.Fatal(err)
+		}
+		got, _ := r.Devices.GetByID(ctx, gone.ID)
+		if got.State.AvgDailyHours != 0 {
+			t.Fatalf("avg_daily_hours = %v, want 0", got.State.AvgDailyHours)
+		}
+	})
+}
+
+// Tenant khác (hoặc không có tenant) không thấy và không sửa được dữ liệu của t1.
+func TestTenantIsolation(t *testing.T) {
+	r := NewStore().Repos()
+	own := tenantCtx("t1")
+	dev := seedDevice(t, r, "SN-1")
+	alert, err := r.Alerts.Create(own, port.CreateAlertInput{DeviceID: dev.ID, Type: "maintenance_due", Message: "m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.DeviceAudit.Record(own, port.RecordDeviceAuditInput{DeviceID: dev.ID, Action: domain.AuditProfilePatched, Actor: "a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, ctx := range map[string]context.Context{"other tenant": tenantCtx("t2"), "no tenant": context.Background()} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := r.Devices.GetByID(ctx, dev.ID); !errors.Is(err, domain.ErrNotFound) {
+				t.Errorf("Devices.GetByID err = %v, want ErrNotFound", err)
+			}
+			if devs, _ := r.Devices.List(ctx, domain.DeviceScope{}, 10, 0); len(devs) != 0 {
+				t.Errorf("Devices.List = %d rows, want 0", len(devs))
+			}
+			if taken, _ := r.Devices.ListTakenSerials(ctx, []string{"SN-1"}); len(taken) != 0 {
+				t.Errorf("ListTakenSerials = %v, want none", taken)
+			}
+			if _, err := r.Alerts.GetByID(ctx, alert.ID); !errors.Is(err, domain.ErrNotFound) {
+				t.Errorf("Alerts.GetByID err = %v, want ErrNotFound", err)
+			}
+			if _, err := r.Alerts.Resolve(ctx, port.ResolveAlertInput{ID: alert.ID}); !errors.Is(err, domain.ErrNotFound) {
+				t.Errorf("Alerts.Resolve err = %v, want ErrNotFound", err)
+			}
+			if open, _ := r.Alerts.ListOpenByDevice(ctx, dev.ID, 10, 0); len(open) != 0 {
+				t.Errorf("Alerts.ListOpenByDevice = %d rows, want 0", len(open))
+			}
+			if entries, _ := r.DeviceAudit.ListByDevice(ctx, dev.ID, 10, 0); len(entries) != 0 {
+				t.Errorf("DeviceAudit.ListByDevice = %d rows, want 0", len(entries))
+			}
+			// FK kép (device_id, tenant_id): không gắn dữ liệu vào device của tenant khác
+			if _, err := r.Alerts.Create(ctx, port.CreateAlertInput{DeviceID: dev.ID, Type: "x", Message: "m"}); err == nil {
+				t.Error("Alerts.Create on foreign device succeeded")
+			}
+			if err := r.Devices.SoftDelete(ctx, dev.ID, "x"); err != nil {
+				t.Errorf("SoftDelete err = %v, want nil (no rows)", err)
+			}
+		})
+	}
+
+	got, err := r.Devices.GetByID(own, dev.ID)
+	if err != nil || got.DeletedAt != nil {
+		t.Fatalf("own device after foreign writes = %+v, %v", got, err)
+	}
+	if a, _ := r.Alerts.GetByID(own, alert.ID); a.Resolved {
+		t.Fatal("alert resolved by another tenant")
+	}
+
+	t.Run("same serial in another tenant", func(t *testing.T) {
+		if _, err := r.Devices.Create(tenantCtx("t2"), port.CreateDeviceInput{SerialNumber: "SN-1", Name: "x", Status: domain.StatusActive}); err != nil {
+			t.Fatalf("err = %v, want nil (unique per tenant)", err)
+		}
+	})
+}
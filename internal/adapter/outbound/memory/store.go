@@ -0,0 +1,220 @@
+// Package memory: triển khai in-memory cho mọi outbound port (demo, CI, test usecase không cần Postgres).
+//
+// Cùng ngữ nghĩa với bản PG:
+//   - cô lập tenant như RLS (tenant lấy từ principal trong ctx, giống bindTenant)
+//   - xóa mềm, thứ tự sắp xếp, phân trang LIMIT/OFFSET
+//   - ràng buộc unique/FK -> cùng mã lỗi domain như repository.mapErr
+//   - version + If-Match, TxManager commit/rollback
+//
+// Mọi thao tác tuần tự hóa qua một mutex; transaction giữ mutex tới khi commit/rollback
+// nên luôn thấy dữ liệu nhất quán (chặt hơn READ COMMITTED của PG, không lỏng hơn).
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/authz"
+)
+
+// tables: toàn bộ dữ liệu; value struct (không con trỏ) để snapshot bằng copy map
+type tables struct {
+	devices  map[int64]domain.Device
+	plans    map[int64]domain.Plan
+	readings map[int64]tenantRow[domain.Reading]
+	alerts   map[int64]tenantRow[domain.Alert]
+	maint    map[int64]tenantRow[domain.MaintenanceEvent]
+	audit    map[int64]tenantRow[domain.DeviceAuditEntry]
+	apiKeys  map[int64]domain.APIKey
+	jobRuns  map[int64]domain.JobRun
+}
+
+// tenantRow: bảng có tenant_id mà domain không mang field tenant
+type tenantRow[T any] struct {
+	tenant string
+	row    T
+}
+
+func newTables() *tables {
+	return &tables{
+		devices:  map[int64]domain.Device{},
+		plans:    map[int64]domain.Plan{},
+		readings: map[int64]tenantRow[domain.Reading]{},
+		alerts:   map[int64]tenantRow[domain.Alert]{},
+		maint:    map[int64]tenantRow[domain.MaintenanceEvent]{},
+		audit:    map[int64]tenantRow[domain.DeviceAuditEntry]{},
+		apiKeys:  map[int64]domain.APIKey{},
+		jobRuns:  map[int64]domain.JobRun{},
+	}
+}
+
+// clone: snapshot để rollback (row là value, field con trỏ không bị sửa tại chỗ)
+func (t *tables) clone() *tables {
+	return &tables{
+		devices:  cloneMap(t.devices),
+		plans:    cloneMap(t.plans),
+		readings: cloneMap(t.readings),
+		alerts:   cloneMap(t.alerts),
+		maint:    cloneMap(t.maint),
+		audit:    cloneMap(t.audit),
+		apiKeys:  cloneMap(t.apiKeys),
+		jobRuns:  cloneMap(t.jobRuns),
+	}
+}
+
+func cloneMap[K comparable, V any](m map[K]V) map[K]V {
+	out := make(map[K]V, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// ==== Store ====
+type Store struct {
+	mu   sync.Mutex
+	data *tables
+
+	seqMu sync.Mutex
+	seq   map[string]int64 // như BIGSERIAL: không lùi khi rollback
+}
+
+func NewStore() *Store {
+	return &Store{data: newTables(), seq: map[string]int64{}}
+}
+
+func (s *Store) nextID(table string) int64 {
+	s.seqMu.Lock()
+	defer s.seqMu.Unlock()
+	s.seq[table]++
+	return s.seq[table]
+}
+
+// conn: "kết nối" mà repo dùng; store tự khóa từng thao tác, tx đã giữ khóa sẵn
+type conn struct {
+	s    *Store
+	inTx bool
+}
+
+func (c *conn) do(fn func(t *tables) error) error {
+	if !c.inTx {
+		c.s.mu.Lock()
+		defer c.s.mu.Unlock()
+	}
+	return fn(c.s.data)
+}
+
+// Repos: bộ repo không transaction trên store
+func (s *Store) Repos() port.Repos {
+	return reposFor(&conn{s: s})
+}
+
+func reposFor(c *conn) port.Repos {
+	return port.Repos{
+		Devices:     &DeviceRepository{c: c},
+		Plans:       &PlanRepository{c: c},
+		Readings:    &ReadingRepository{c: c},
+		Alerts:      &AlertRepository{c: c},
+		Maintenance: &MaintenanceRepository{c: c},
+		DeviceAudit: &DeviceAuditRepository{c: c},
+		APIKeys:     &APIKeyRepository{c: c},
+		JobRuns:     &JobRunRepository{c: c},
+		FleetStats:  &FleetStatsRepository{c: c},
+	}
+}
+
+// ==== helpers dùng chung ====
+
+// tenantOf: như app_tenant(); rỗng = NULL (không thấy/ghi được bảng có RLS)
+func tenantOf(ctx context.Context) string {
+	if p, ok := authz.PrincipalFrom(ctx); ok {
+		return p.TenantID
+	}
+	return ""
+}
+
+// visible: policy tenant_isolation (tenant_id = app_tenant(), NULL không bằng gì)
+func visible(rowTenant, tenant string) bool {
+	return tenant != "" && rowTenant == tenant
+}
+
+// requireTenant: cột tenant_id NOT NULL DEFAULT app_tenant() -> thiếu tenant thì insert lỗi
+func requireTenant(tenant string) error {
+	if tenant == "" {
+		return domain.Invalid("tenant_id", "violates database constraint")
+	}
+	return nil
+}
+
+func notFound(entity string) error {
+	return domain.NotFound(entity+"_not_found", entity+" not found")
+}
+
+func versionMismatch(entity string) error {
+	return domain.PreconditionFailed("version_mismatch", entity+" was modified by someone else; reload and retry")
+}
+
+// paginate: LIMIT/OFFSET trên slice đã sắp xếp
+func paginate[T any](items []T, limit, offset int32) []T {
+	if offset < 0 {
+		offset = 0
+	}
+	if int(offset) >= len(items) {
+		return []T{}
+	}
+	items = items[offset:]
+	if limit >= 0 && int(limit) < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+// sortedPtrs: copy từng row ra con trỏ mới (caller sửa kết quả không ảnh hưởng store)
+func sortedPtrs[T any](rows []T, less func(a, b T) bool) []*T {
+	sort.SliceStable(rows, func(i, j int) bool { return less(rows[i], rows[j]) })
+	out := make([]*T, len(rows))
+	for i := range rows {
+		out[i] = &rows[i]
+	}
+	return out
+}
+
+func ptr[T any](v T) *T { return &v }
+
+// deviceRef: FK kép (device_id, tenant_id) -> devices; không bỏ qua device đã xóa mềm (như FK)
+func deviceRef(t *tables, tenant string, id domain.DeviceID) error {
+	if err := requireTenant(tenant); err != nil {
+		return err
+	}
+	if d, ok := t.devices[int64(id)]; !ok || d.TenantID != tenant {
+		return notFound("device")
+	}
+	return nil
+}
+
+// inRange: điều kiện WHERE của các query List*Range (khoảng [from, to), device, keyset)
+func inRange(q port.RangeQuery, device domain.DeviceID, at time.Time, id int64) bool {
+	if at.Before(q.From) || !at.Before(q.To) {
+		return false
+	}
+	if q.DeviceID != nil && device != *q.DeviceID {
+		return false
+	}
+	afterAt, afterID := q.AfterAt, q.AfterID
+	if afterAt.IsZero() {
+		afterAt, afterID = q.From, 0
+	}
+	return keysetLess(afterAt, afterID, at, id)
+}
+
+// keysetLess: (atA, idA) < (atB, idB) như so sánh tuple của PG
+func keysetLess(atA time.Time, idA int64, atB time.Time, idB int64) bool {
+	if !atA.Equal(atB) {
+		return atA.Before(atB)
+	}
+	return idA < idB
+}
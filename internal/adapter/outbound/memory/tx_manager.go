@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"context"
+
+	"wh-ma/internal/adapter/outbound/port"
+)
+
+// ==== TxManager in-memory ====
+// Giữ khóa store suốt fn; fn lỗi hoặc panic -> khôi phục snapshot (rollback).
+// WithinTx lồng (ctx của fn) không khóa lại: chỉ lấy snapshot riêng như SAVEPOINT.
+type TxManager struct {
+	s *Store
+}
+
+// txKey: ctx bên trong WithinTx mang store đang giữ khóa
+type txKey struct{}
+
+func NewTxManager(s *Store) *TxManager {
+	return &TxManager{s: s}
+}
+
+// compile-time check
+var _ port.TxManager = (*TxManager)(nil)
+
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context, r port.Repos) error) (err error) {
+	if s, _ := ctx.Value(txKey{}).(*Store); s != m.s {
+		m.s.mu.Lock()
+		defer m.s.mu.Unlock()
+		ctx = context.WithValue(ctx, txKey{}, m.s)
+	}
+
+	snapshot := m.s.data.clone()
+	committed := false
+	defer func() {
+		if !committed {
+			m.s.data = snapshot // rollback, kể cả khi panic (panic vẫn được ném tiếp)
+		}
+	}()
+
+	if err = fn(ctx, reposFor(&conn{s: m.s, inTx: true})); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
@@ -0,0 +1,181 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/authz"
+)
+
+var errBoom = errors.New("boom")
+
+func tenantCtx(tenant string) context.Context {
+	return authz.WithPrincipal(context.Background(), domain.Principal{Subject: "test", TenantID: tenant, Role: domain.RoleAdmin})
+}
+
+// createDevice + một alert trong cùng fn: thao tác nhiều bảng
+func createDeviceWithAlert(ctx context.Context, r port.Repos, serial string) error {
+	dev, err := r.Devices.Create(ctx, port.CreateDeviceInput{SerialNumber: serial, Name: serial, Status: domain.StatusActive})
+	if err != nil {
+		return err
+	}
+	_, err = r.Alerts.Create(ctx, port.CreateAlertInput{DeviceID: dev.ID, Type: "maintenance_due", Message: "m"})
+	return err
+}
+
+func countDevices(t *testing.T, s *Store) int {
+	t.Helper()
+	devs, err := s.Repos().Devices.List(tenantCtx("t1"), domain.DeviceScope{}, 100, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(devs)
+}
+
+func TestWithinTxCommit(t *testing.T) {
+	s := NewStore()
+	tx := NewTxManager(s)
+	ctx := tenantCtx("t1")
+
+	err := tx.WithinTx(ctx, func(ctx context.Context, r port.Repos) error {
+		return createDeviceWithAlert(ctx, r, "SN-1")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := countDevices(t, s); n != 1 {
+		t.Fatalf("devices = %d, want 1", n)
+	}
+	if n := len(s.data.alerts); n != 1 {
+		t.Fatalf("alerts = %d, want 1", n)
+	}
+}
+
+func TestWithinTxRollbackOnError(t *testing.T) {
+	s := NewStore()
+	tx := NewTxManager(s)
+	ctx := tenantCtx("t1")
+
+	err := tx.WithinTx(ctx, func(ctx context.Context, r port.Repos) error {
+		if err := createDeviceWithAlert(ctx, r, "SN-1"); err != nil {
+			return err
+		}
+		// trong transaction thấy được dữ liệu vừa ghi
+		if devs, _ := r.Devices.List(ctx, domain.DeviceScope{}, 10, 0); len(devs) != 1 {
+			t.Errorf("inside tx: devices = %d, want 1", len(devs))
+		}
+		return errBoom
+	})
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("err = %v, want errBoom returned unchanged", err)
+	}
+	if n := countDevices(t, s); n != 0 {
+		t.Fatalf("devices after rollback = %d, want 0", n)
+	}
+	if n := len(s.data.alerts); n != 0 {
+		t.Fatalf("alerts after rollback = %d, want 0", n)
+	}
+}
+
+func TestWithinTxRollbackOnPanic(t *testing.T) {
+	s := NewStore()
+	tx := NewTxManager(s)
+	ctx := tenantCtx("t1")
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("panic was swallowed")
+			}
+		}()
+		_ = tx.WithinTx(ctx, func(ctx context.Context, r port.Repos) error {
+			if err := createDeviceWithAlert(ctx, r, "SN-1"); err != nil {
+				return err
+			}
+			panic("boom")
+		})
+	}()
+	if n := countDevices(t, s); n != 0 {
+		t.Fatalf("devices after panic = %d, want 0", n)
+	}
+	// khóa đã được trả: store vẫn dùng được
+	if err := tx.WithinTx(ctx, func(ctx context.Context, r port.Repos) error {
+		return createDeviceWithAlert(ctx, r, "SN-2")
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWithinTxNested(t *testing.T) {
+	t.Run("inner error rolls back only inner", func(t *testing.T) {
+		s := NewStore()
+		tx := NewTxManager(s)
+		ctx := tenantCtx("t1")
+
+		err := tx.WithinTx(ctx, func(ctx context.Context, r port.Repos) error {
+			if err := createDeviceWithAlert(ctx, r, "SN-1"); err != nil {
+				return err
+			}
+			inner := tx.WithinTx(ctx, func(ctx context.Context, r port.Repos) error {
+				if err := createDeviceWithAlert(ctx, r, "SN-2"); err != nil {
+					return err
+				}
+				return errBoom
+			})
+			if !errors.Is(inner, errBoom) {
+				t.Errorf("inner err = %v, want errBoom", inner)
+			}
+			return nil // bỏ qua lỗi bên trong, transaction ngoài vẫn commit
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		devs, _ := s.Repos().Devices.List(ctx, domain.DeviceScope{}, 10, 0)
+		if len(devs) != 1 || devs[0].SerialNumber != "SN-1" {
+			t.Fatalf("devices = %+v, want only SN-1", devs)
+		}
+	})
+
+	t.Run("outer error rolls back committed inner", func(t *testing.T) {
+		s := NewStore()
+		tx := NewTxManager(s)
+		ctx := tenantCtx("t1")
+
+		err := tx.WithinTx(ctx, func(ctx context.Context, r port.Repos) error {
+			if err := tx.WithinTx(ctx, func(ctx context.Context, r port.Repos) error {
+				return createDeviceWithAlert(ctx, r, "SN-1")
+			}); err != nil {
+				return err
+			}
+			return errBoom
+		})
+		if !errors.Is(err, errBoom) {
+			t.Fatalf("err = %v, want errBoom", err)
+		}
+		if n := countDevices(t, s); n != 0 {
+			t.Fatalf("devices = %d, want 0", n)
+		}
+	})
+}
+
+// id như BIGSERIAL: không dùng lại sau rollback
+func TestWithinTxSequenceNotRolledBack(t *testing.T) {
+	s := NewStore()
+	tx := NewTxManager(s)
+	ctx := tenantCtx("t1")
+
+	_ = tx.WithinTx(ctx, func(ctx context.Context, r port.Repos) error {
+		_ = createDeviceWithAlert(ctx, r, "SN-1")
+		return errBoom
+	})
+	dev, err := s.Repos().Devices.Create(ctx, port.CreateDeviceInput{SerialNumber: "SN-1", Name: "x", Status: domain.StatusActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dev.ID != 2 {
+		t.Fatalf("id = %d, want 2", dev.ID)
+	}
+}
@@ -0,0 +1,148 @@
+// Package migration: chạy migration nhúng trong binary (db.Migrations) bằng golang-migrate.
+//
+// Bảng theo dõi là schema_migrations như CLI migrate nên dùng lẫn được với image
+// migrate/migrate trong docker-compose. Mỗi thao tác ghi giữ pg_advisory_lock
+// (khóa của driver, cùng khóa với CLI migrate) -> nhiều replica auto-migrate cùng lúc
+// thì chạy tuần tự, replica sau thấy ErrNoChange.
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/golang-migrate/migrate/v4"
+	pgxmigrate "github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+
+	"wh-ma/db"
+)
+
+type Migrator struct {
+	m *migrate.Migrate
+}
+
+// Status: trạng thái schema so với bản migration nhúng trong binary
+type Status struct {
+	Version  uint   `json:"version"` // 0 = chưa chạy migration nào
+	Dirty    bool   `json:"dirty"`   // migration trước lỗi giữa chừng -> cần sửa tay rồi "force"
+	Expected uint   `json:"expected"`
+	Pending  []uint `json:"pending"`
+}
+
+func New(databaseURL string) (*Migrator, error) {
+	cfg, err := pgx.ParseConfig(databaseURL)
+	if err != nil {
+		return nil, err
+	}
+	drv, err := pgxmigrate.WithInstance(stdlib.OpenDB(*cfg), &pgxmigrate.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+	src, err := openSource()
+	if err != nil {
+		return nil, err
+	}
+	m, err := migrate.NewWithInstance("iofs", src, "pgx5", drv)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+	return &Migrator{m: m}, nil
+}
+
+func (x *Migrator) Close() error {
+	srcErr, dbErr := x.m.Close()
+	return errors.Join(srcErr, dbErr)
+}
+
+// Up: áp toàn bộ migration còn thiếu
+func (x *Migrator) Up() error {
+	return noChange(x.m.Up())
+}
+
+// Down: lùi n bước (n <= 0: lùi hết)
+func (x *Migrator) Down(n int) error {
+	if n <= 0 {
+		return noChange(x.m.Down())
+	}
+	return noChange(x.m.Steps(-n))
+}
+
+// To: lên/xuống tới đúng version
+func (x *Migrator) To(version uint) error {
+	return noChange(x.m.Migrate(version))
+}
+
+// Force: đánh dấu version (bỏ cờ dirty) sau khi đã sửa tay, không chạy SQL
+func (x *Migrator) Force(version int) error {
+	return x.m.Force(version)
+}
+
+func (x *Migrator) Status() (Status, error) {
+	versions, err := Versions()
+	if err != nil {
+		return Status{}, err
+	}
+	st := Status{Pending: []uint{}}
+	if len(versions) > 0 {
+		st.Expected = versions[len(versions)-1]
+	}
+	st.Version, st.Dirty, err = x.m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return Status{}, err
+	}
+	for _, v := range versions {
+		if v > st.Version {
+			st.Pending = append(st.Pending, v)
+		}
+	}
+	return st, nil
+}
+
+// Versions: các version nhúng trong binary, tăng dần
+func Versions() ([]uint, error) {
+	src, err := openSource()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	var out []uint
+	v, err := src.First()
+	for err == nil {
+		out = append(out, v)
+		v, err = src.Next(v)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("migrate: read embedded migrations: %w", err)
+	}
+	return out, nil
+}
+
+// Expected: version schema mà binary này cần (migration mới nhất được nhúng)
+func Expected() (uint, error) {
+	versions, err := Versions()
+	if err != nil || len(versions) == 0 {
+		return 0, err
+	}
+	return versions[len(versions)-1], nil
+}
+
+func openSource() (source.Driver, error) {
+	src, err := iofs.New(db.Migrations, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+	return src, nil
+}
+
+// noChange: đã ở đúng version không phải lỗi
+func noChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+	return err
+}
@@ -14,17 +14,42 @@ type DeviceRepository interface {
 	// Lấy chi tiết device theo ID
 	GetByID(ctx context.Context, id domain.DeviceID) (*domain.Device, error)
 
-	// Danh sách device (có phân trang)
-	List(ctx context.Context, limit, offset int32) ([]*domain.Device, error)
+	// Như GetByID nhưng khóa dòng tới hết transaction (check-then-write không bị chen ngang)
+	GetForUpdate(ctx context.Context, id domain.DeviceID) (*domain.Device, error)
 
-	// Update thông tin cơ bản (tên, trạng thái, vị trí)
-	UpdateBasic(ctx context.Context, id domain.DeviceID, name string, status domain.DeviceStatus, location *string) (*domain.Device, error)
+	// Danh sách device trong scope (site/device của principal), có phân trang
+	List(ctx context.Context, scope domain.DeviceScope, limit, offset int32) ([]*domain.Device, error)
 
-	// Gán/bỏ Plan cho device
-	UpdatePlan(ctx context.Context, id domain.DeviceID, planID *domain.PlanID) (*domain.Device, error)
+	// Device chưa xóa có id > after, ORDER BY id (keyset cho export)
+	ListAfter(ctx context.Context, after domain.DeviceID, limit int32) ([]*domain.Device, error)
+
+	// Device (chưa xóa) theo danh sách serial, ORDER BY id
+	ListBySerials(ctx context.Context, serials []string) ([]*domain.Device, error)
+
+	// Serial nào trong danh sách đã có device dùng (kể cả đã xóa mềm: unique vẫn giữ)
+	ListTakenSerials(ctx context.Context, serials []string) ([]string, error)
+
+	// Update thông tin cơ bản (tên, trạng thái, vị trí); updatedBy = principal thực hiện.
+	// expectedVersion != 0: chỉ ghi khi version trong DB khớp, lệch -> domain.ErrPreconditionFailed
+	UpdateBasic(ctx context.Context, id domain.DeviceID, name string, status domain.DeviceStatus, location *string, updatedBy string, expectedVersion int) (*domain.Device, error)
+
+	// Gán/bỏ Plan cho device (expectedVersion như UpdateBasic)
+	UpdatePlan(ctx context.Context, id domain.DeviceID, planID *domain.PlanID, updatedBy string, expectedVersion int) (*domain.Device, error)
+
+	// Ghi toàn bộ field hồ sơ (usecase đã merge patch lên bản hiện tại); expectedVersion như UpdateBasic
+	UpdateProfile(ctx context.Context, in UpdateDeviceProfileInput) (*domain.Device, error)
 
 	// Xóa mềm
-	SoftDelete(ctx context.Context, id domain.DeviceID) error
+	SoftDelete(ctx context.Context, id domain.DeviceID, deletedBy string) error
+
+	// Cộng giờ vận hành từ reading + cập nhật dự báo
+	AddUsage(ctx context.Context, in AddDeviceUsageInput) (*domain.Device, error)
+
+	// Ghi lại dự báo (trạng thái suy ra, không tăng version); device đã xóa thì bỏ qua
+	UpdateForecast(ctx context.Context, id domain.DeviceID, avgDailyHours float64, expectedNextMaint *time.Time) error
+
+	// Tenant đang có device, bỏ qua tenant của principal (job nền lặp qua từng tenant)
+	ListTenants(ctx context.Context) ([]string, error)
 }
 
 // ==== Input struct cho Create ====
@@ -42,4 +67,31 @@ type CreateDeviceInput struct {
 	LastServiceAt            *time.Time
 	Location                 string
 	PlanID                   *domain.PlanID
+	CreatedBy                string // principal tạo (user hoặc API key)
+}
+
+// ==== Input cho UpdateProfile ====
+// Chuỗi rỗng / Year 0 / CommissionDate nil => NULL trong DB
+type UpdateDeviceProfileInput struct {
+	ID              domain.DeviceID
+	SerialNumber    string
+	Name            string
+	Model           string
+	Manufacturer    string
+	Year            int
+	CommissionDate  *time.Time
+	Status          domain.DeviceStatus
+	Location        string
+	UpdatedBy       string
+	ExpectedVersion int
+}
+
+// ==== Input cho AddUsage ====
+// HoursDelta được cộng dồn trong DB (tránh ghi đè khi nhiều reading cùng lúc)
+type AddDeviceUsageInput struct {
+	ID                domain.DeviceID
+	HoursDelta        int
+	At                time.Time
+	AvgDailyHours     float64
+	ExpectedNextMaint *time.Time
 }
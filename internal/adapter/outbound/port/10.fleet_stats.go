@@ -0,0 +1,14 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"wh-ma/internal/domain"
+)
+
+// FleetStatsRepository: số liệu tổng hợp của tenant trong ctx (RLS như mọi repo).
+// Overdue tính tại now (device active, xem domain.Device.Overdue).
+type FleetStatsRepository interface {
+	Snapshot(ctx context.Context, now time.Time) (*domain.FleetStats, error)
+}
@@ -16,6 +16,7 @@ type UpdatePlanInput struct {
 	Name          string
 	IntervalHours int
 	Description   *string
+	Version       int // 0 = không kiểm; khác 0 = phải khớp version hiện tại
 }
 
 type PlanRepository interface {
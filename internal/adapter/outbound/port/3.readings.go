@@ -10,8 +10,15 @@ import (
 // Hợp đồng để Usecase gọi
 type ReadingRepository interface {
 	Create(ctx context.Context, in CreateReadingInput) (*domain.Reading, error)
+	// CreateMany: ghi cả lô một lần (import, source = import), trả số dòng đã ghi
+	CreateMany(ctx context.Context, in []CreateReadingInput) (int64, error)
+	GetFirstByDevice(ctx context.Context, deviceID domain.DeviceID) (*domain.Reading, error)
 	GetLastByDevice(ctx context.Context, deviceID domain.DeviceID) (*domain.Reading, error)
 	ListByDevice(ctx context.Context, deviceID domain.DeviceID, limit, offset int32) ([]*domain.Reading, error)
+	// SumHoursSince: tổng hours_delta của reading có at > since (dự báo theo cửa sổ gần đây)
+	SumHoursSince(ctx context.Context, deviceID domain.DeviceID, since time.Time) (int, error)
+	// ListRange: reading trong khoảng thời gian, ORDER BY at, id (export)
+	ListRange(ctx context.Context, q RangeQuery) ([]*domain.Reading, error)
 	Delete(ctx context.Context, id int64) error
 }
 
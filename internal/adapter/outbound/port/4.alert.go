@@ -19,6 +19,9 @@ type ResolveAlertInput struct {
 
 type AlertRepository interface {
 	Create(ctx context.Context, in CreateAlertInput) (*domain.Alert, error)
+	GetByID(ctx context.Context, id int64) (*domain.Alert, error)
 	ListOpenByDevice(ctx context.Context, deviceID domain.DeviceID, limit, offset int32) ([]*domain.Alert, error)
+	// ListRange: alert (cả đã resolve) tạo trong khoảng thời gian, ORDER BY created_at, id (export)
+	ListRange(ctx context.Context, q RangeQuery) ([]*domain.Alert, error)
 	Resolve(ctx context.Context, in ResolveAlertInput) (*domain.Alert, error)
 }
@@ -19,4 +19,6 @@ type MaintenanceRepository interface {
 	Create(ctx context.Context, in CreateMaintenanceInput) (*domain.MaintenanceEvent, error)
 	Delete(ctx context.Context, id int64) error
 	ListByDevice(ctx context.Context, deviceID domain.DeviceID, limit, offset int32) ([]*domain.MaintenanceEvent, error)
+	// ListRange: sự kiện trong khoảng thời gian, ORDER BY at, id (export)
+	ListRange(ctx context.Context, q RangeQuery) ([]*domain.MaintenanceEvent, error)
 }
@@ -0,0 +1,28 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"wh-ma/internal/domain"
+)
+
+type CreateAPIKeyInput struct {
+	Name       string
+	Prefix     string
+	SecretHash string
+	Scopes     []string
+	DeviceIDs  []domain.DeviceID
+	Sites      []string
+	ExpiresAt  *time.Time
+	CreatedBy  string
+}
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, in CreateAPIKeyInput) (*domain.APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error)
+	List(ctx context.Context, limit, offset int32) ([]*domain.APIKey, error)
+	Revoke(ctx context.Context, id int64) (*domain.APIKey, error)
+	// Ghi nhận lần dùng gần nhất (DB tự bỏ qua nếu vừa ghi < 1 phút)
+	TouchLastUsed(ctx context.Context, id int64) error
+}
@@ -0,0 +1,20 @@
+package port
+
+import (
+	"context"
+
+	"wh-ma/internal/domain"
+)
+
+// DeviceAuditRepository: ghi/đọc nhật ký thay đổi thiết bị (chỉ thêm, không sửa/xóa)
+type DeviceAuditRepository interface {
+	Record(ctx context.Context, in RecordDeviceAuditInput) (*domain.DeviceAuditEntry, error)
+	ListByDevice(ctx context.Context, deviceID domain.DeviceID, limit, offset int32) ([]*domain.DeviceAuditEntry, error)
+}
+
+type RecordDeviceAuditInput struct {
+	DeviceID domain.DeviceID
+	Action   string
+	Actor    string
+	Changes  map[string]domain.FieldChange
+}
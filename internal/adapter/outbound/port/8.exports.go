@@ -0,0 +1,46 @@
+package port
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"wh-ma/internal/domain"
+)
+
+// ExportStore: nơi giữ export job (metadata + file kết quả).
+// Tenant truyền tường minh: link tải không có principal, tenant lấy từ chữ ký.
+type ExportStore interface {
+	// Ghi (tạo mới hoặc cập nhật) metadata của job
+	SaveJob(ctx context.Context, job *domain.ExportJob) error
+	// Metadata job; không có -> domain.ErrNotFound
+	GetJob(ctx context.Context, tenant, id string) (*domain.ExportJob, error)
+	// File kết quả của job (ghi đè nếu đã có)
+	Create(ctx context.Context, tenant, id string) (io.WriteCloser, error)
+	// Mở file kết quả để tải; không có -> domain.ErrNotFound
+	Open(ctx context.Context, tenant, id string) (ExportFile, error)
+	// Xóa job + file đã hết hạn trước now, trả số job đã xóa
+	DeleteExpired(ctx context.Context, now time.Time) (int, error)
+}
+
+// ExportFile: file kết quả mở để đọc (Seek cho Range request)
+type ExportFile interface {
+	io.ReadSeekCloser
+	Size() int64
+	ModTime() time.Time
+}
+
+// TableEncoder: ghi bảng ra định dạng file (CSV, XLSX, JSON Lines)
+type TableEncoder interface {
+	Supports(format string) bool
+	// format lạ -> domain.Invalid("format", ...)
+	NewWriter(w io.Writer, format string) (TableWriter, error)
+}
+
+// TableWriter: header một lần, sau đó từng dòng; Close ghi phần còn lại (XLSX ghi file lúc Close).
+// Giá trị ô: string, số nguyên, float64, bool, time.Time, *time.Time hoặc nil (ô trống).
+type TableWriter interface {
+	WriteHeader(cols []string) error
+	WriteRow(vals []any) error
+	Close() error
+}
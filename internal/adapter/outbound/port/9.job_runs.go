@@ -0,0 +1,35 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"wh-ma/internal/domain"
+)
+
+// JobRunRepository: lịch sử chạy job nền (cấp hệ thống, không theo tenant)
+type JobRunRepository interface {
+	Create(ctx context.Context, run *domain.JobRun) (*domain.JobRun, error)
+	// Lần chạy của một job, mới nhất trước
+	List(ctx context.Context, job string, limit, offset int32) ([]*domain.JobRun, error)
+	// Lần chạy mới nhất của mỗi job
+	Latest(ctx context.Context) ([]*domain.JobRun, error)
+	// Xóa lịch sử bắt đầu trước mốc, trả số dòng đã xóa
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// LeaderLock: quyền leader giữa các replica (PG: advisory lock trên một connection riêng).
+// Chỉ một replica giữ được tại một thời điểm; connection đứt thì quyền tự mất.
+type LeaderLock interface {
+	// Thử nhận quyền, không chờ; đang giữ rồi thì trả true
+	TryAcquire(ctx context.Context) (bool, error)
+	// Còn giữ quyền không (kiểm connection còn sống)
+	Held(ctx context.Context) bool
+	// Trả quyền; không giữ thì không làm gì
+	Release(ctx context.Context) error
+}
+
+// JobCatalog: job đã đăng ký và trạng thái scheduler trên replica này
+type JobCatalog interface {
+	Status() domain.SchedulerStatus
+}
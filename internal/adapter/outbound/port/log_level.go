@@ -0,0 +1,8 @@
+package port
+
+// LogLevelControl: level của logger gốc trong process (đổi lúc chạy, không lưu lại)
+type LogLevelControl interface {
+	Level() string
+	// SetLevel: debug | info | warn | error; giá trị khác trả lỗi
+	SetLevel(level string) error
+}
@@ -0,0 +1,18 @@
+package port
+
+import (
+	"time"
+
+	"wh-ma/internal/domain"
+)
+
+// RangeQuery: đọc lịch sử trong [From, To) theo từng trang keyset (thời điểm, id) cho export.
+// Trang đầu: AfterAt zero, AfterID 0; trang sau: (At, ID) của dòng cuối trang trước.
+type RangeQuery struct {
+	DeviceID *domain.DeviceID // nil = mọi device
+	From     time.Time
+	To       time.Time
+	AfterAt  time.Time
+	AfterID  int64
+	Limit    int32
+}
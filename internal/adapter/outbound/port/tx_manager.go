@@ -0,0 +1,28 @@
+package port
+
+import "context"
+
+// Repos: bộ repository dùng chung một kết nối (pool hoặc transaction)
+type Repos struct {
+	Devices     DeviceRepository
+	Plans       PlanRepository
+	Readings    ReadingRepository
+	Alerts      AlertRepository
+	Maintenance MaintenanceRepository
+	DeviceAudit DeviceAuditRepository
+	APIKeys     APIKeyRepository
+	JobRuns     JobRunRepository
+	FleetStats  FleetStatsRepository
+}
+
+// TxManager: unit of work cho usecase ghi nhiều repository.
+// WithinTx chạy fn với các repo gắn vào cùng một transaction:
+//   - fn trả nil  -> commit
+//   - fn trả lỗi hoặc panic -> rollback, lỗi được trả nguyên cho caller
+//
+// Trong fn chỉ dùng repo từ tham số r (repo ngoài transaction không thấy thay đổi chưa commit).
+// WithinTx gọi lồng với ctx của fn chạy như SAVEPOINT trong transaction ngoài: lỗi bên trong chỉ
+// rollback phần của nó, commit thật khi transaction ngoài commit.
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context, r Repos) error) error
+}
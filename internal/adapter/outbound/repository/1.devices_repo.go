@@ -2,7 +2,6 @@ package repository
 
 import (
 	"context"
-	"errors"
 	"time"
 	"wh-ma/internal/adapter/outbound/port"
 	dbsqlc "wh-ma/internal/adapter/outbound/repository/sqlc"
@@ -27,15 +26,11 @@ import (
 // ==== Input cho Create (phù hợp Twelve-Factor, tách khỏi domain nếu cần bind JSON) ====
 
 type DeviceRepositoryPG struct {
-	pool *pgxpool.Pool
-	q    *dbsqlc.Queries
+	q *dbsqlc.Queries
 }
 
 func NewDeviceRepository(pool *pgxpool.Pool) *DeviceRepositoryPG {
-	return &DeviceRepositoryPG{
-		pool: pool,
-		q:    dbsqlc.New(pool),
-	}
+	return &DeviceRepositoryPG{q: dbsqlc.New(pool)}
 }
 
 // ==== Create ====
@@ -58,9 +53,10 @@ func (r *DeviceRepositoryPG) Create(ctx context.Context, in port.CreateDeviceInp
 		LastServiceAt:            timestamptzFromPtr(in.LastServiceAt),
 		Location:                 strPtr(in.Location),
 		PlanID:                   planID,
+		CreatedBy:                strPtr(in.CreatedBy),
 	})
 	if err != nil {
-		return nil, err
+		return nil, mapErr(err, "device")
 	}
 	d := mapSqlcDeviceToDomain(row)
 	return &d, nil
@@ -70,17 +66,71 @@ func (r *DeviceRepositoryPG) Create(ctx context.Context, in port.CreateDeviceInp
 func (r *DeviceRepositoryPG) GetByID(ctx context.Context, id domain.DeviceID) (*domain.Device, error) {
 	row, err := r.q.GetDevice(ctx, int64(id))
 	if err != nil {
-		return nil, err
+		return nil, mapErr(err, "device")
+	}
+	d := mapSqlcDeviceToDomain(row)
+	return &d, nil
+}
+
+// ==== GetForUpdate: SELECT ... FOR UPDATE, chỉ có nghĩa trong TxManager.WithinTx ====
+func (r *DeviceRepositoryPG) GetForUpdate(ctx context.Context, id domain.DeviceID) (*domain.Device, error) {
+	row, err := r.q.GetDeviceForUpdate(ctx, int64(id))
+	if err != nil {
+		return nil, mapErr(err, "device")
 	}
 	d := mapSqlcDeviceToDomain(row)
 	return &d, nil
 }
 
+func (r *DeviceRepositoryPG) ListBySerials(ctx context.Context, serials []string) ([]*domain.Device, error) {
+	rows, err := r.q.ListDevicesBySerials(ctx, serials)
+	if err != nil {
+		return nil, mapErr(err, "device")
+	}
+	out := make([]*domain.Device, 0, len(rows))
+	for _, row := range rows {
+		d := mapSqlcDeviceToDomain(row)
+		out = append(out, &d)
+	}
+	return out, nil
+}
+
+func (r *DeviceRepositoryPG) ListTakenSerials(ctx context.Context, serials []string) ([]string, error) {
+	taken, err := r.q.ListTakenSerials(ctx, serials)
+	if err != nil {
+		return nil, mapErr(err, "device")
+	}
+	return taken, nil
+}
+
 // ==== List (phân trang đơn giản) ====
-func (r *DeviceRepositoryPG) List(ctx context.Context, limit, offset int32) ([]*domain.Device, error) {
-	rows, err := r.q.ListDevices(ctx, dbsqlc.ListDevicesParams{Limit: limit, Offset: offset})
+func (r *DeviceRepositoryPG) List(ctx context.Context, scope domain.DeviceScope, limit, offset int32) ([]*domain.Device, error) {
+	ids := make([]int64, 0, len(scope.DeviceIDs))
+	for _, id := range scope.DeviceIDs {
+		ids = append(ids, int64(id))
+	}
+	rows, err := r.q.ListDevices(ctx, dbsqlc.ListDevicesParams{
+		Sites: nonNil(scope.Sites), // NULL thay vì mảng rỗng thì cardinality() không = 0
+		Ids:   ids,
+		Lim:   limit,
+		Off:   offset,
+	})
 	if err != nil {
-		return nil, err
+		return nil, mapErr(err, "device")
+	}
+	out := make([]*domain.Device, 0, len(rows))
+	for _, row := range rows {
+		d := mapSqlcDeviceToDomain(row)
+		out = append(out, &d)
+	}
+	return out, nil
+}
+
+// ==== ListAfter (keyset theo id cho export) ====
+func (r *DeviceRepositoryPG) ListAfter(ctx context.Context, after domain.DeviceID, limit int32) ([]*domain.Device, error) {
+	rows, err := r.q.ListDevicesAfter(ctx, dbsqlc.ListDevicesAfterParams{AfterID: int64(after), Lim: limit})
+	if err != nil {
+		return nil, mapErr(err, "device")
 	}
 	out := make([]*domain.Device, 0, len(rows))
 	for _, row := range rows {
@@ -91,44 +141,117 @@ func (r *DeviceRepositoryPG) List(ctx context.Context, limit, offset int32) ([]*
 }
 
 // ==== UpdateBasic (đổi tên, trạng thái, vị trí) ====
-func (r *DeviceRepositoryPG) UpdateBasic(ctx context.Context, id domain.DeviceID, name string, status domain.DeviceStatus, location *string) (*domain.Device, error) {
+func (r *DeviceRepositoryPG) UpdateBasic(ctx context.Context, id domain.DeviceID, name string, status domain.DeviceStatus, location *string, updatedBy string, expectedVersion int) (*domain.Device, error) {
 	row, err := r.q.UpdateDeviceBasic(ctx, dbsqlc.UpdateDeviceBasicParams{
-		ID:       int64(id),
-		Name:     name,
-		Status:   string(status),
-		Location: location, // nullable
+		ID:              int64(id),
+		Name:            name,
+		Status:          string(status),
+		Location:        location, // nullable
+		UpdatedBy:       strPtr(updatedBy),
+		ExpectedVersion: int32(expectedVersion),
 	})
 	if err != nil {
-		return nil, err
+		return nil, mapVersionErr(err, "device", expectedVersion, r.exists(ctx, id))
 	}
 	d := mapSqlcDeviceToDomain(row)
 	return &d, nil
 }
 
 // ==== UpdatePlan (gán/bỏ plan) ====
-func (r *DeviceRepositoryPG) UpdatePlan(ctx context.Context, id domain.DeviceID, planID *domain.PlanID) (*domain.Device, error) {
+func (r *DeviceRepositoryPG) UpdatePlan(ctx context.Context, id domain.DeviceID, planID *domain.PlanID, updatedBy string, expectedVersion int) (*domain.Device, error) {
 	var pid *int64
 	if planID != nil {
 		v := int64(*planID)
 		pid = &v
 	}
 	row, err := r.q.UpdateDevicePlan(ctx, dbsqlc.UpdateDevicePlanParams{
-		ID:     int64(id),
-		PlanID: pid,
+		ID:              int64(id),
+		PlanID:          pid,
+		UpdatedBy:       strPtr(updatedBy),
+		ExpectedVersion: int32(expectedVersion),
+	})
+	if err != nil {
+		return nil, mapVersionErr(err, "device", expectedVersion, r.exists(ctx, id))
+	}
+	d := mapSqlcDeviceToDomain(row)
+	return &d, nil
+}
+
+// ==== UpdateProfile (merge patch toàn bộ hồ sơ) ====
+func (r *DeviceRepositoryPG) UpdateProfile(ctx context.Context, in port.UpdateDeviceProfileInput) (*domain.Device, error) {
+	row, err := r.q.UpdateDeviceProfile(ctx, dbsqlc.UpdateDeviceProfileParams{
+		ID:                int64(in.ID),
+		SerialNumber:      in.SerialNumber,
+		Name:              in.Name,
+		Model:             strPtr(in.Model),
+		Manufacturer:      strPtr(in.Manufacturer),
+		YearOfManufacture: int32Ptr(in.Year),
+		CommissionDate:    dateFromPtr(in.CommissionDate),
+		Status:            string(in.Status),
+		Location:          strPtr(in.Location),
+		UpdatedBy:         strPtr(in.UpdatedBy),
+		ExpectedVersion:   int32(in.ExpectedVersion),
 	})
 	if err != nil {
-		return nil, err
+		return nil, mapVersionErr(err, "device", in.ExpectedVersion, r.exists(ctx, in.ID))
 	}
 	d := mapSqlcDeviceToDomain(row)
 	return &d, nil
 }
 
+// exists: dùng sau UPDATE có điều kiện version để phân biệt 404/412
+func (r *DeviceRepositoryPG) exists(ctx context.Context, id domain.DeviceID) func() bool {
+	return func() bool {
+		_, err := r.q.GetDevice(ctx, int64(id))
+		return err == nil
+	}
+}
+
 // ==== SoftDelete ====
-func (r *DeviceRepositoryPG) SoftDelete(ctx context.Context, id domain.DeviceID) error {
+func (r *DeviceRepositoryPG) SoftDelete(ctx context.Context, id domain.DeviceID, deletedBy string) error {
 	if id == 0 {
-		return errors.New("invalid id")
+		return domain.Invalid("id", "is invalid")
+	}
+	return mapErr(r.q.SoftDeleteDevice(ctx, dbsqlc.SoftDeleteDeviceParams{
+		ID:        int64(id),
+		DeletedBy: strPtr(deletedBy),
+	}), "device")
+}
+
+// ==== AddUsage (cộng giờ từ reading + cập nhật dự báo) ====
+func (r *DeviceRepositoryPG) AddUsage(ctx context.Context, in port.AddDeviceUsageInput) (*domain.Device, error) {
+	avg := in.AvgDailyHours
+	row, err := r.q.AddDeviceUsage(ctx, dbsqlc.AddDeviceUsageParams{
+		HoursDelta:        int32(in.HoursDelta),
+		At:                pgtype.Timestamptz{Time: in.At, Valid: true},
+		AvgDailyHours:     &avg,
+		ExpectedNextMaint: timestamptzFromPtr(in.ExpectedNextMaint),
+		ID:                int64(in.ID),
+	})
+	if err != nil {
+		return nil, mapErr(err, "device")
+	}
+	d := mapSqlcDeviceToDomain(row)
+	return &d, nil
+}
+
+// ==== UpdateForecast: không đụng version/updated_at (không làm hỏng ETag của client) ====
+func (r *DeviceRepositoryPG) UpdateForecast(ctx context.Context, id domain.DeviceID, avgDailyHours float64, expectedNextMaint *time.Time) error {
+	err := r.q.UpdateDeviceForecast(ctx, dbsqlc.UpdateDeviceForecastParams{
+		AvgDailyHours:     &avgDailyHours,
+		ExpectedNextMaint: timestamptzFromPtr(expectedNextMaint),
+		ID:                int64(id),
+	})
+	return mapErr(err, "device")
+}
+
+// ==== ListTenants: qua app_tenant_ids() (migration 000012, chạy bằng whma_snapshot từ 000014), không phụ thuộc app.tenant_id ====
+func (r *DeviceRepositoryPG) ListTenants(ctx context.Context) ([]string, error) {
+	tenants, err := r.q.ListDeviceTenants(ctx)
+	if err != nil {
+		return nil, mapErr(err, "device")
 	}
-	return r.q.SoftDeleteDevice(ctx, int64(id))
+	return tenants, nil
 }
 
 // ==== Mapper: sqlc.Device -> domain.Device ====
@@ -165,6 +288,7 @@ func mapSqlcDeviceToDomain(x dbsqlc.Device) domain.Device {
 
 	return domain.Device{
 		ID:           domain.DeviceID(x.ID),
+		TenantID:     x.TenantID,
 		SerialNumber: x.SerialNumber,
 		Name:         x.Name,
 
@@ -187,6 +311,7 @@ func mapSqlcDeviceToDomain(x dbsqlc.Device) domain.Device {
 		CreatedAt: x.CreatedAt.Time,
 		UpdatedAt: x.UpdatedAt.Time,
 		DeletedAt: deletedAt,
+		Version:   int(x.Version),
 
 		Audit: domain.AuditMeta{
 			CreatedBy: strOrEmpty(x.CreatedBy), // <-- FIX: *string -> string
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"wh-ma/internal/adapter/outbound/port"
+	dbsqlc "wh-ma/internal/adapter/outbound/repository/sqlc"
+	"wh-ma/internal/domain"
+)
+
+// FleetStatsRepositoryPG: các query GROUP BY trong 10.fleet_stats.sql (mỗi query quét bảng của tenant,
+// caller tự cache kết quả)
+type FleetStatsRepositoryPG struct {
+	q *dbsqlc.Queries
+}
+
+// compile-time check
+var _ port.FleetStatsRepository = (*FleetStatsRepositoryPG)(nil)
+
+func (r *FleetStatsRepositoryPG) Snapshot(ctx context.Context, now time.Time) (*domain.FleetStats, error) {
+	out := &domain.FleetStats{}
+
+	devices, err := r.q.CountDevicesByStatusSite(ctx)
+	if err != nil {
+		return nil, mapErr(err, "fleet_stats")
+	}
+	for _, x := range devices {
+		out.Devices = append(out.Devices, domain.DeviceCount{Status: domain.DeviceStatus(x.Status), Site: x.Site, Count: x.Devices})
+	}
+
+	alerts, err := r.q.CountOpenAlertsByType(ctx)
+	if err != nil {
+		return nil, mapErr(err, "fleet_stats")
+	}
+	for _, x := range alerts {
+		out.OpenAlerts = append(out.OpenAlerts, domain.AlertCount{Type: x.Type, Count: x.Alerts})
+	}
+
+	overdue, err := r.q.CountOverdueDevicesBySite(ctx, pgtype.Timestamptz{Time: now, Valid: true})
+	if err != nil {
+		return nil, mapErr(err, "fleet_stats")
+	}
+	for _, x := range overdue {
+		out.Overdue = append(out.Overdue, domain.SiteCount{Site: x.Site, Count: x.Devices})
+	}
+
+	readings, err := r.q.SumReadingsBySource(ctx)
+	if err != nil {
+		return nil, mapErr(err, "fleet_stats")
+	}
+	for _, x := range readings {
+		out.Readings = append(out.Readings, domain.ReadingTotal{Source: x.Source, Readings: x.Readings, Hours: x.Hours})
+	}
+
+	maint, err := r.q.SumMaintenanceCost(ctx)
+	if err != nil {
+		return nil, mapErr(err, "fleet_stats")
+	}
+	out.Maintenance = domain.MaintenanceTotal{Events: maint.Events, Cost: maint.Cost}
+	return out, nil
+}
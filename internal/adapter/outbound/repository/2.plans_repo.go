@@ -28,7 +28,7 @@ func (r *PlanRepositoryPG) Create(ctx context.Context, in port.CreatePlanInput)
 		Description:   in.Description, // *string
 	})
 	if err != nil {
-		return nil, err
+		return nil, mapErr(err, "plan")
 	}
 	p := mapSqlcPlanToDomain(row)
 	return &p, nil
@@ -37,7 +37,7 @@ func (r *PlanRepositoryPG) Create(ctx context.Context, in port.CreatePlanInput)
 func (r *PlanRepositoryPG) GetByID(ctx context.Context, id domain.PlanID) (*domain.Plan, error) {
 	row, err := r.q.GetPlan(ctx, int64(id))
 	if err != nil {
-		return nil, err
+		return nil, mapErr(err, "plan")
 	}
 	p := mapSqlcPlanToDomain(row)
 	return &p, nil
@@ -46,7 +46,7 @@ func (r *PlanRepositoryPG) GetByID(ctx context.Context, id domain.PlanID) (*doma
 func (r *PlanRepositoryPG) List(ctx context.Context, limit, offset int32) ([]*domain.Plan, error) {
 	rows, err := r.q.ListPlans(ctx, dbsqlc.ListPlansParams{Limit: limit, Offset: offset})
 	if err != nil {
-		return nil, err
+		return nil, mapErr(err, "plan")
 	}
 	out := make([]*domain.Plan, 0, len(rows))
 	for _, row := range rows {
@@ -58,30 +58,36 @@ func (r *PlanRepositoryPG) List(ctx context.Context, limit, offset int32) ([]*do
 
 func (r *PlanRepositoryPG) Update(ctx context.Context, in port.UpdatePlanInput) (*domain.Plan, error) {
 	row, err := r.q.UpdatePlan(ctx, dbsqlc.UpdatePlanParams{
-		ID:            int64(in.ID),
-		Name:          in.Name,
-		IntervalHours: int32(in.IntervalHours),
-		Description:   in.Description,
+		ID:              int64(in.ID),
+		Name:            in.Name,
+		IntervalHours:   int32(in.IntervalHours),
+		Description:     in.Description,
+		ExpectedVersion: int32(in.Version),
 	})
 	if err != nil {
-		return nil, err
+		return nil, mapVersionErr(err, "plan", in.Version, func() bool {
+			_, err := r.q.GetPlan(ctx, int64(in.ID))
+			return err == nil
+		})
 	}
 	p := mapSqlcPlanToDomain(row)
 	return &p, nil
 }
 
 func (r *PlanRepositoryPG) Delete(ctx context.Context, id domain.PlanID) error {
-	return r.q.DeletePlan(ctx, int64(id))
+	return mapErr(r.q.DeletePlan(ctx, int64(id)), "plan")
 }
 
 // ===== mapping =====
 func mapSqlcPlanToDomain(x dbsqlc.Plan) domain.Plan {
 	return domain.Plan{
 		ID:            domain.PlanID(x.ID),
+		TenantID:      x.TenantID,
 		Name:          x.Name,
 		IntervalHours: int(x.IntervalHours),
 		Description:   x.Description,
 		CreatedAt:     x.CreatedAt.Time,
 		UpdatedAt:     x.UpdatedAt.Time,
+		Version:       int(x.Version),
 	}
 }
@@ -14,15 +14,11 @@ import (
 
 // Bản PG/sqlc implement ReadingRepository
 type ReadingRepositoryPG struct {
-	pool *pgxpool.Pool
-	q    *dbsqlc.Queries
+	q *dbsqlc.Queries
 }
 
 func NewReadingRepository(pool *pgxpool.Pool) *ReadingRepositoryPG {
-	return &ReadingRepositoryPG{
-		pool: pool,
-		q:    dbsqlc.New(pool),
-	}
+	return &ReadingRepositoryPG{q: dbsqlc.New(pool)}
 }
 
 // compile-time check
@@ -38,7 +34,44 @@ func (r *ReadingRepositoryPG) Create(ctx context.Context, in port.CreateReadingI
 		OperatorID: in.OperatorID, // *string
 	})
 	if err != nil {
-		return nil, err
+		return nil, mapErr(err, "reading")
+	}
+	rd := mapSqlcReadingToDomain(row)
+	return &rd, nil
+}
+
+// CreateMany -> COPY vào readings_import rồi INSERT ... SELECT sang readings.
+// COPY không chạy trực tiếp trên bảng có RLS; bước INSERT vẫn qua policy + FK kép.
+// Cần chạy trong transaction (TxManager) để bảng đệm không lộ dòng ra ngoài.
+func (r *ReadingRepositoryPG) CreateMany(ctx context.Context, in []port.CreateReadingInput) (int64, error) {
+	if len(in) == 0 {
+		return 0, nil
+	}
+	rows := make([]dbsqlc.CopyReadingsImportParams, 0, len(in))
+	for _, x := range in {
+		rows = append(rows, dbsqlc.CopyReadingsImportParams{
+			DeviceID:   int64(x.DeviceID),
+			At:         pgtype.Timestamptz{Time: x.At, Valid: true},
+			HoursDelta: int32(x.HoursDelta),
+			Location:   x.Location,
+			OperatorID: x.OperatorID,
+		})
+	}
+	if _, err := r.q.CopyReadingsImport(ctx, rows); err != nil {
+		return 0, mapErr(err, "reading")
+	}
+	n, err := r.q.InsertReadingsFromImport(ctx)
+	if err != nil {
+		return 0, mapErr(err, "reading")
+	}
+	return n, nil
+}
+
+// GetFirstByDevice -> ORDER BY at LIMIT 1
+func (r *ReadingRepositoryPG) GetFirstByDevice(ctx context.Context, deviceID domain.DeviceID) (*domain.Reading, error) {
+	row, err := r.q.GetFirstReading(ctx, int64(deviceID))
+	if err != nil {
+		return nil, mapErr(err, "reading")
 	}
 	rd := mapSqlcReadingToDomain(row)
 	return &rd, nil
@@ -48,12 +81,24 @@ func (r *ReadingRepositoryPG) Create(ctx context.Context, in port.CreateReadingI
 func (r *ReadingRepositoryPG) GetLastByDevice(ctx context.Context, deviceID domain.DeviceID) (*domain.Reading, error) {
 	row, err := r.q.GetLastReading(ctx, int64(deviceID))
 	if err != nil {
-		return nil, err
+		return nil, mapErr(err, "reading")
 	}
 	rd := mapSqlcReadingToDomain(row)
 	return &rd, nil
 }
 
+// SumHoursSince -> SUM(hours_delta) WHERE at > since (idx_readings_device_at)
+func (r *ReadingRepositoryPG) SumHoursSince(ctx context.Context, deviceID domain.DeviceID, since time.Time) (int, error) {
+	hours, err := r.q.SumReadingHoursSince(ctx, dbsqlc.SumReadingHoursSinceParams{
+		DeviceID: int64(deviceID),
+		Since:    pgtype.Timestamptz{Time: since, Valid: true},
+	})
+	if err != nil {
+		return 0, mapErr(err, "reading")
+	}
+	return int(hours), nil
+}
+
 // ListByDevice -> phân trang theo at DESC
 func (r *ReadingRepositoryPG) ListByDevice(ctx context.Context, deviceID domain.DeviceID, limit, offset int32) ([]*domain.Reading, error) {
 	rows, err := r.q.ListReadingsByDevice(ctx, dbsqlc.ListReadingsByDeviceParams{
@@ -62,7 +107,29 @@ func (r *ReadingRepositoryPG) ListByDevice(ctx context.Context, deviceID domain.
 		Offset:   offset,
 	})
 	if err != nil {
-		return nil, err
+		return nil, mapErr(err, "reading")
+	}
+	out := make([]*domain.Reading, 0, len(rows))
+	for _, row := range rows {
+		rd := mapSqlcReadingToDomain(row)
+		out = append(out, &rd)
+	}
+	return out, nil
+}
+
+// ListRange -> WHERE at trong [from, to) AND (at, id) > keyset ORDER BY at, id
+func (r *ReadingRepositoryPG) ListRange(ctx context.Context, q port.RangeQuery) ([]*domain.Reading, error) {
+	a := newRangeArgs(q)
+	rows, err := r.q.ListReadingsRange(ctx, dbsqlc.ListReadingsRangeParams{
+		FromAt:   a.from,
+		ToAt:     a.to,
+		DeviceID: a.deviceID,
+		AfterAt:  a.afterAt,
+		AfterID:  a.afterID,
+		Lim:      q.Limit,
+	})
+	if err != nil {
+		return nil, mapErr(err, "reading")
 	}
 	out := make([]*domain.Reading, 0, len(rows))
 	for _, row := range rows {
@@ -74,7 +141,7 @@ func (r *ReadingRepositoryPG) ListByDevice(ctx context.Context, deviceID domain.
 
 // Delete -> DELETE FROM readings WHERE id = $1
 func (r *ReadingRepositoryPG) Delete(ctx context.Context, id int64) error {
-	return r.q.DeleteReading(ctx, id)
+	return mapErr(r.q.DeleteReading(ctx, id), "reading")
 }
 
 // ===== mapper =====
@@ -90,6 +157,7 @@ func mapSqlcReadingToDomain(x dbsqlc.Reading) domain.Reading {
 		HoursDelta: int(x.HoursDelta),
 		Location:   strOrEmptyPtr(x.Location),
 		OperatorID: strOrEmptyPtr(x.OperatorID),
+		Source:     x.Source,
 	}
 }
 
@@ -29,7 +29,17 @@ func (r *AlertRepositoryPG) Create(ctx context.Context, in port.CreateAlertInput
 		Message:  in.Message,
 	})
 	if err != nil {
-		return nil, err
+		return nil, mapErr(err, "alert")
+	}
+	al := mapSqlcAlertToDomain(row)
+	return &al, nil
+}
+
+// GetByID -> SELECT theo id (kể cả alert đã resolve)
+func (r *AlertRepositoryPG) GetByID(ctx context.Context, id int64) (*domain.Alert, error) {
+	row, err := r.q.GetAlert(ctx, id)
+	if err != nil {
+		return nil, mapErr(err, "alert")
 	}
 	al := mapSqlcAlertToDomain(row)
 	return &al, nil
@@ -43,7 +53,29 @@ func (r *AlertRepositoryPG) ListOpenByDevice(ctx context.Context, deviceID domai
 		Offset:   offset,
 	})
 	if err != nil {
-		return nil, err
+		return nil, mapErr(err, "alert")
+	}
+	out := make([]*domain.Alert, 0, len(rows))
+	for _, row := range rows {
+		al := mapSqlcAlertToDomain(row)
+		out = append(out, &al)
+	}
+	return out, nil
+}
+
+// ListRange -> WHERE created_at trong [from, to) AND (created_at, id) > keyset ORDER BY created_at, id
+func (r *AlertRepositoryPG) ListRange(ctx context.Context, q port.RangeQuery) ([]*domain.Alert, error) {
+	a := newRangeArgs(q)
+	rows, err := r.q.ListAlertsRange(ctx, dbsqlc.ListAlertsRangeParams{
+		FromAt:   a.from,
+		ToAt:     a.to,
+		DeviceID: a.deviceID,
+		AfterAt:  a.afterAt,
+		AfterID:  a.afterID,
+		Lim:      q.Limit,
+	})
+	if err != nil {
+		return nil, mapErr(err, "alert")
 	}
 	out := make([]*domain.Alert, 0, len(rows))
 	for _, row := range rows {
@@ -60,7 +92,7 @@ func (r *AlertRepositoryPG) Resolve(ctx context.Context, in port.ResolveAlertInp
 		ResolvedBy: in.ResolvedBy,
 	})
 	if err != nil {
-		return nil, err
+		return nil, mapErr(err, "alert")
 	}
 	al := mapSqlcAlertToDomain(row)
 	return &al, nil
@@ -68,7 +100,6 @@ func (r *AlertRepositoryPG) Resolve(ctx context.Context, in port.ResolveAlertInp
 
 // ===== mapping: sqlc.Alert -> domain.Alert =====
 func mapSqlcAlertToDomain(x dbsqlc.Alert) domain.Alert {
-	// domain.Alert hiện tại không có ResolvedAt/ResolvedBy — nếu cần, thêm vào domain sau.
 	return domain.Alert{
 		ID:        x.ID,
 		DeviceID:  domain.DeviceID(x.DeviceID),
@@ -76,5 +107,8 @@ func mapSqlcAlertToDomain(x dbsqlc.Alert) domain.Alert {
 		Message:   x.Message,
 		CreatedAt: x.CreatedAt.Time, // created_at NOT NULL -> .Time ok
 		Resolved:  x.Resolved,
+
+		ResolvedAt: timePtrFromTimestamptz(x.ResolvedAt),
+		ResolvedBy: strOrEmpty(x.ResolvedBy),
 	}
 }
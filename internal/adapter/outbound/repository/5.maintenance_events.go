@@ -29,7 +29,7 @@ func (r *MaintenanceRepositoryPG) Create(ctx context.Context, in port.CreateMain
 	if in.Cost != nil {
 		// Set from text để giữ chính xác số thập phân
 		if err := cost.Scan(*in.Cost); err != nil {
-			return nil, err
+			return nil, domain.Invalid("cost", "must be a decimal")
 		}
 		cost.Valid = true
 	}
@@ -45,7 +45,7 @@ func (r *MaintenanceRepositoryPG) Create(ctx context.Context, in port.CreateMain
 
 	row, err := r.q.CreateMaintenanceEvent(ctx, params)
 	if err != nil {
-		return nil, err
+		return nil, mapErr(err, "maintenance_event")
 	}
 
 	out := mapSqlcMaintenanceToDomain(row)
@@ -53,7 +53,7 @@ func (r *MaintenanceRepositoryPG) Create(ctx context.Context, in port.CreateMain
 }
 
 func (r *MaintenanceRepositoryPG) Delete(ctx context.Context, id int64) error {
-	return r.q.DeleteMaintenanceEvent(ctx, id)
+	return mapErr(r.q.DeleteMaintenanceEvent(ctx, id), "maintenance_event")
 }
 
 func (r *MaintenanceRepositoryPG) ListByDevice(ctx context.Context, deviceID domain.DeviceID, limit, offset int32) ([]*domain.MaintenanceEvent, error) {
@@ -63,7 +63,28 @@ func (r *MaintenanceRepositoryPG) ListByDevice(ctx context.Context, deviceID dom
 		Offset:   offset,
 	})
 	if err != nil {
-		return nil, err
+		return nil, mapErr(err, "maintenance_event")
+	}
+	out := make([]*domain.MaintenanceEvent, 0, len(rows))
+	for _, row := range rows {
+		ev := mapSqlcMaintenanceToDomain(row)
+		out = append(out, &ev)
+	}
+	return out, nil
+}
+
+func (r *MaintenanceRepositoryPG) ListRange(ctx context.Context, q port.RangeQuery) ([]*domain.MaintenanceEvent, error) {
+	a := newRangeArgs(q)
+	rows, err := r.q.ListMaintenanceRange(ctx, dbsqlc.ListMaintenanceRangeParams{
+		FromAt:   a.from,
+		ToAt:     a.to,
+		DeviceID: a.deviceID,
+		AfterAt:  a.afterAt,
+		AfterID:  a.afterID,
+		Lim:      q.Limit,
+	})
+	if err != nil {
+		return nil, mapErr(err, "maintenance_event")
 	}
 	out := make([]*domain.MaintenanceEvent, 0, len(rows))
 	for _, row := range rows {
@@ -80,11 +101,11 @@ func mapSqlcMaintenanceToDomain(x dbsqlc.MaintenanceEvent) domain.MaintenanceEve
 		interval = int(*x.Interval)
 	}
 
-	// Cost: nếu ACE muốn đưa về float64, có thể dùng .Float(…)
-	// Nhưng lưu ý sai số; tốt nhất giữ string trong layer ngoài.
-	// Ví dụ (không khuyến nghị cho tiền tệ):
-	// f, _ := x.Cost.Float64Value()
-	// costFloat := f.Float64
+	// Cost: NUMERIC(12,2) -> float64 (đủ chính xác cho 2 chữ số thập phân; response format lại "%.2f")
+	var cost float64
+	if f, err := x.Cost.Float64Value(); err == nil && f.Valid {
+		cost = f.Float64
+	}
 
 	return domain.MaintenanceEvent{
 		ID:          x.ID,
@@ -93,7 +114,7 @@ func mapSqlcMaintenanceToDomain(x dbsqlc.MaintenanceEvent) domain.MaintenanceEve
 		Interval:    interval,
 		Notes:       derefOrEmpty(x.Notes),
 		PerformedBy: derefOrEmpty(x.PerformedBy),
-		// Cost: bỏ qua trong domain nếu đang là float64; hoặc thêm trường CostText string
+		Cost:        cost,
 	}
 }
 
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"wh-ma/internal/adapter/outbound/port"
+	dbsqlc "wh-ma/internal/adapter/outbound/repository/sqlc"
+	"wh-ma/internal/domain"
+)
+
+type APIKeyRepositoryPG struct {
+	q *dbsqlc.Queries
+}
+
+func NewAPIKeyRepository(pool *pgxpool.Pool) *APIKeyRepositoryPG {
+	return &APIKeyRepositoryPG{q: dbsqlc.New(pool)}
+}
+
+// compile-time check
+var _ port.APIKeyRepository = (*APIKeyRepositoryPG)(nil)
+
+func (r *APIKeyRepositoryPG) Create(ctx context.Context, in port.CreateAPIKeyInput) (*domain.APIKey, error) {
+	deviceIDs := make([]int64, 0, len(in.DeviceIDs))
+	for _, id := range in.DeviceIDs {
+		deviceIDs = append(deviceIDs, int64(id))
+	}
+	row, err := r.q.CreateAPIKey(ctx, dbsqlc.CreateAPIKeyParams{
+		Name:       in.Name,
+		Prefix:     in.Prefix,
+		SecretHash: in.SecretHash,
+		Scopes:     nonNil(in.Scopes),
+		DeviceIds:  deviceIDs,
+		Sites:      nonNil(in.Sites),
+		ExpiresAt:  timestamptzFromPtr(in.ExpiresAt),
+		CreatedBy:  strPtr(in.CreatedBy),
+	})
+	if err != nil {
+		return nil, mapErr(err, "api_key")
+	}
+	k := mapSqlcAPIKeyToDomain(row)
+	return &k, nil
+}
+
+func (r *APIKeyRepositoryPG) GetByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	row, err := r.q.GetAPIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		return nil, mapErr(err, "api_key")
+	}
+	k := mapSqlcAPIKeyToDomain(row)
+	return &k, nil
+}
+
+func (r *APIKeyRepositoryPG) List(ctx context.Context, limit, offset int32) ([]*domain.APIKey, error) {
+	rows, err := r.q.ListAPIKeys(ctx, dbsqlc.ListAPIKeysParams{Limit: limit, Offset: offset})
+	if err != nil {
+		return nil, mapErr(err, "api_key")
+	}
+	out := make([]*domain.APIKey, 0, len(rows))
+	for _, row := range rows {
+		k := mapSqlcAPIKeyToDomain(row)
+		out = append(out, &k)
+	}
+	return out, nil
+}
+
+// Revoke -> chỉ key chưa bị thu hồi; đã thu hồi thì trả domain.ErrNotFound
+func (r *APIKeyRepositoryPG) Revoke(ctx context.Context, id int64) (*domain.APIKey, error) {
+	row, err := r.q.RevokeAPIKey(ctx, id)
+	if err != nil {
+		return nil, mapErr(err, "api_key")
+	}
+	k := mapSqlcAPIKeyToDomain(row)
+	return &k, nil
+}
+
+func (r *APIKeyRepositoryPG) TouchLastUsed(ctx context.Context, id int64) error {
+	return mapErr(r.q.TouchAPIKeyLastUsed(ctx, id), "api_key")
+}
+
+// ===== mapping: sqlc.ApiKey -> domain.APIKey =====
+func mapSqlcAPIKeyToDomain(x dbsqlc.ApiKey) domain.APIKey {
+	deviceIDs := make([]domain.DeviceID, 0, len(x.DeviceIds))
+	for _, id := range x.DeviceIds {
+		deviceIDs = append(deviceIDs, domain.DeviceID(id))
+	}
+	return domain.APIKey{
+		ID:         x.ID,
+		TenantID:   x.TenantID,
+		Name:       x.Name,
+		Prefix:     x.Prefix,
+		SecretHash: x.SecretHash,
+		Scopes:     x.Scopes,
+		DeviceIDs:  deviceIDs,
+		Sites:      x.Sites,
+		ExpiresAt:  timePtrFromTimestamptz(x.ExpiresAt),
+		LastUsedAt: timePtrFromTimestamptz(x.LastUsedAt),
+		RevokedAt:  timePtrFromTimestamptz(x.RevokedAt),
+		CreatedAt:  x.CreatedAt.Time,
+		CreatedBy:  strOrEmpty(x.CreatedBy),
+	}
+}
+
+// TEXT[] NOT NULL: gửi {} thay vì NULL
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+func timePtrFromTimestamptz(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	v := t.Time
+	return &v
+}
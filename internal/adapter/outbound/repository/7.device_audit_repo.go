@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"wh-ma/internal/adapter/outbound/port"
+	dbsqlc "wh-ma/internal/adapter/outbound/repository/sqlc"
+	"wh-ma/internal/domain"
+)
+
+type DeviceAuditRepositoryPG struct {
+	q *dbsqlc.Queries
+}
+
+func NewDeviceAuditRepository(pool *pgxpool.Pool) *DeviceAuditRepositoryPG {
+	return &DeviceAuditRepositoryPG{q: dbsqlc.New(pool)}
+}
+
+// compile-time check
+var _ port.DeviceAuditRepository = (*DeviceAuditRepositoryPG)(nil)
+
+func (r *DeviceAuditRepositoryPG) Record(ctx context.Context, in port.RecordDeviceAuditInput) (*domain.DeviceAuditEntry, error) {
+	changes, err := json.Marshal(in.Changes)
+	if err != nil {
+		return nil, err
+	}
+	row, err := r.q.CreateDeviceAudit(ctx, dbsqlc.CreateDeviceAuditParams{
+		DeviceID: int64(in.DeviceID),
+		Action:   in.Action,
+		Actor:    strPtr(in.Actor),
+		Changes:  changes,
+	})
+	if err != nil {
+		return nil, mapErr(err, "device_audit")
+	}
+	return mapSqlcDeviceAuditToDomain(row)
+}
+
+func (r *DeviceAuditRepositoryPG) ListByDevice(ctx context.Context, deviceID domain.DeviceID, limit, offset int32) ([]*domain.DeviceAuditEntry, error) {
+	rows, err := r.q.ListDeviceAudit(ctx, dbsqlc.ListDeviceAuditParams{
+		DeviceID: int64(deviceID),
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		return nil, mapErr(err, "device_audit")
+	}
+	out := make([]*domain.DeviceAuditEntry, 0, len(rows))
+	for _, row := range rows {
+		e, err := mapSqlcDeviceAuditToDomain(row)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+// ===== mapping: sqlc.DeviceAuditLog -> domain.DeviceAuditEntry =====
+func mapSqlcDeviceAuditToDomain(x dbsqlc.DeviceAuditLog) (*domain.DeviceAuditEntry, error) {
+	var changes map[string]domain.FieldChange
+	if err := json.Unmarshal(x.Changes, &changes); err != nil {
+		return nil, err
+	}
+	return &domain.DeviceAuditEntry{
+		ID:        x.ID,
+		DeviceID:  domain.DeviceID(x.DeviceID),
+		Action:    x.Action,
+		Actor:     strOrEmpty(x.Actor),
+		Changes:   changes,
+		CreatedAt: x.CreatedAt.Time,
+	}, nil
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"wh-ma/internal/adapter/outbound/port"
+	dbsqlc "wh-ma/internal/adapter/outbound/repository/sqlc"
+	"wh-ma/internal/domain"
+)
+
+// JobRunRepositoryPG: bảng job_runs không có RLS nên không phụ thuộc app.tenant_id
+type JobRunRepositoryPG struct {
+	q *dbsqlc.Queries
+}
+
+func NewJobRunRepository(pool *pgxpool.Pool) *JobRunRepositoryPG {
+	return &JobRunRepositoryPG{q: dbsqlc.New(pool)}
+}
+
+// compile-time check
+var _ port.JobRunRepository = (*JobRunRepositoryPG)(nil)
+
+func (r *JobRunRepositoryPG) Create(ctx context.Context, run *domain.JobRun) (*domain.JobRun, error) {
+	row, err := r.q.CreateJobRun(ctx, dbsqlc.CreateJobRunParams{
+		Job:         run.Job,
+		Instance:    run.Instance,
+		ScheduledAt: pgtype.Timestamptz{Time: run.ScheduledAt, Valid: true},
+		StartedAt:   pgtype.Timestamptz{Time: run.StartedAt, Valid: true},
+		FinishedAt:  pgtype.Timestamptz{Time: run.FinishedAt, Valid: true},
+		Status:      string(run.Status),
+		Error:       strPtr(run.Error),
+		Summary:     strPtr(run.Summary),
+	})
+	if err != nil {
+		return nil, mapErr(err, "job_run")
+	}
+	return mapSqlcJobRunToDomain(row), nil
+}
+
+func (r *JobRunRepositoryPG) List(ctx context.Context, job string, limit, offset int32) ([]*domain.JobRun, error) {
+	rows, err := r.q.ListJobRuns(ctx, dbsqlc.ListJobRunsParams{Job: job, Limit: limit, Offset: offset})
+	if err != nil {
+		return nil, mapErr(err, "job_run")
+	}
+	return mapSqlcJobRuns(rows), nil
+}
+
+func (r *JobRunRepositoryPG) Latest(ctx context.Context) ([]*domain.JobRun, error) {
+	rows, err := r.q.LatestJobRuns(ctx)
+	if err != nil {
+		return nil, mapErr(err, "job_run")
+	}
+	return mapSqlcJobRuns(rows), nil
+}
+
+func (r *JobRunRepositoryPG) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	n, err := r.q.DeleteJobRunsBefore(ctx, pgtype.Timestamptz{Time: before, Valid: true})
+	if err != nil {
+		return 0, mapErr(err, "job_run")
+	}
+	return n, nil
+}
+
+// ===== mapping: sqlc.JobRun -> domain.JobRun =====
+func mapSqlcJobRuns(rows []dbsqlc.JobRun) []*domain.JobRun {
+	out := make([]*domain.JobRun, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, mapSqlcJobRunToDomain(row))
+	}
+	return out
+}
+
+func mapSqlcJobRunToDomain(x dbsqlc.JobRun) *domain.JobRun {
+	return &domain.JobRun{
+		ID:          x.ID,
+		Job:         x.Job,
+		Instance:    x.Instance,
+		ScheduledAt: x.ScheduledAt.Time,
+		StartedAt:   x.StartedAt.Time,
+		FinishedAt:  x.FinishedAt.Time,
+		Status:      domain.JobRunStatus(x.Status),
+		Error:       strOrEmpty(x.Error),
+		Summary:     strOrEmpty(x.Summary),
+	}
+}
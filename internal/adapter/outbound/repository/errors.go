@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"wh-ma/internal/domain"
+)
+
+// ===== pg error -> domain error (mã ổn định, không lộ message thô của pgx) =====
+
+// SQLSTATE hay gặp
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgCheckViolation      = "23514"
+	pgNotNullViolation    = "23502"
+)
+
+// Constraint có ý nghĩa nghiệp vụ riêng
+var constraintErrors = map[string]func() error{
+	"uq_devices_tenant_serial": func() error {
+		return domain.Conflict("serial_number_taken", "serial_number already exists")
+	},
+	"api_keys_prefix_key": func() error {
+		return domain.Conflict("api_key_prefix_taken", "api key prefix collision, retry")
+	},
+	"fk_devices_plan_id": func() error { return domain.Invalid("plan_id", "references an unknown plan") },
+	"fk_readings_device": func() error { return domain.NotFound("device_not_found", "device not found") },
+	"fk_alerts_device":   func() error { return domain.NotFound("device_not_found", "device not found") },
+	"fk_maint_device":    func() error { return domain.NotFound("device_not_found", "device not found") },
+}
+
+// mapErr: entity là tên số ít dùng làm tiền tố mã lỗi, vd "device" -> "device_not_found"
+func mapErr(err error, entity string) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.NotFound(entity+"_not_found", entity+" not found")
+	}
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	if f, ok := constraintErrors[pgErr.ConstraintName]; ok {
+		return f()
+	}
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		return domain.Conflict(entity+"_conflict", entity+" already exists")
+	case pgForeignKeyViolation:
+		return domain.Conflict(entity+"_reference_conflict", entity+" is referenced by or references a missing record")
+	case pgCheckViolation, pgNotNullViolation:
+		field := pgErr.ColumnName
+		if field == "" {
+			field = pgErr.ConstraintName
+		}
+		return domain.Invalid(field, "violates database constraint")
+	}
+	return fmt.Errorf("%s: %w", entity, err)
+}
+
+// mapVersionErr: UPDATE có điều kiện version không trả row thì chưa rõ do đâu
+// -> exists() phân biệt "không tồn tại" (404) với "đã bị người khác sửa" (412)
+func mapVersionErr(err error, entity string, expectedVersion int, exists func() bool) error {
+	if expectedVersion != 0 && errors.Is(err, pgx.ErrNoRows) && exists() {
+		return domain.PreconditionFailed("version_mismatch", entity+" was modified by someone else; reload and retry")
+	}
+	return mapErr(err, entity)
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"wh-ma/internal/domain"
+)
+
+func TestMapVersionErr(t *testing.T) {
+	exists := func() bool { return true }
+	missing := func() bool { return false }
+	cases := []struct {
+		name     string
+		err      error
+		version  int
+		exists   func() bool
+		want     error
+		wantCode string
+	}{
+		{"no rows, row exists", pgx.ErrNoRows, 3, exists, domain.ErrPreconditionFailed, "version_mismatch"},
+		{"no rows, row missing", pgx.ErrNoRows, 3, missing, domain.ErrNotFound, "device_not_found"},
+		{"no rows, version not checked", pgx.ErrNoRows, 0, exists, domain.ErrNotFound, "device_not_found"},
+		{"unique violation", &pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "uq_devices_tenant_serial"}, 3, exists, domain.ErrConflict, "serial_number_taken"},
+		{"nil", nil, 3, exists, nil, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := mapVersionErr(tc.err, "device", tc.version, tc.exists)
+			if tc.want == nil {
+				if err != nil {
+					t.Fatalf("err = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("err = %v, want %v", err, tc.want)
+			}
+			var de *domain.Error
+			if !errors.As(err, &de) || de.Code != tc.wantCode {
+				t.Fatalf("code = %+v, want %s", de, tc.wantCode)
+			}
+		})
+	}
+}
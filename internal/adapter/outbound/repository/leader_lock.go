@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"wh-ma/internal/adapter/outbound/port"
+)
+
+// ==== Leader election bằng advisory lock (session-level) ====
+// Replica giữ lock trên một connection lấy riêng khỏi pool tới khi Release; connection đứt
+// (replica chết, mạng mất) thì Postgres tự nhả lock và replica khác nhận quyền ở lần thử sau.
+type LeaderLockPG struct {
+	pool *pgxpool.Pool
+	key  string
+
+	mu   sync.Mutex
+	conn *pgxpool.Conn // != nil khi đang giữ lock
+}
+
+// NewLeaderLock: key phân biệt nhóm replica tranh quyền (hashtext -> id của advisory lock)
+func NewLeaderLock(pool *pgxpool.Pool, key string) *LeaderLockPG {
+	return &LeaderLockPG{pool: pool, key: key}
+}
+
+// compile-time check
+var _ port.LeaderLock = (*LeaderLockPG)(nil)
+
+func (l *LeaderLockPG) TryAcquire(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn != nil {
+		return true, nil
+	}
+	conn, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+	var ok bool
+	if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock(hashtext($1))", l.key).Scan(&ok); err != nil {
+		conn.Release()
+		return false, err
+	}
+	if !ok {
+		conn.Release()
+		return false, nil
+	}
+	l.conn = conn
+	return true, nil
+}
+
+// heldTimeout: ping kiểm lock không được treo vòng lặp scheduler
+const heldTimeout = 3 * time.Second
+
+// Held: ping bằng ctx tách khỏi hủy của caller — ctx server bị hủy lúc dừng không được làm đóng
+// connection (= nhả lock) khi job còn đang drain; chỉ Release mới trả lock.
+func (l *LeaderLockPG) Held(ctx context.Context) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn == nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), heldTimeout)
+	defer cancel()
+	if err := l.conn.Ping(ctx); err != nil {
+		// connection hỏng = lock đã mất; đóng hẳn để pool không cấp lại connection này
+		closeConn(l.conn)
+		l.conn = nil
+		return false
+	}
+	return true
+}
+
+func (l *LeaderLockPG) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn == nil {
+		return nil
+	}
+	conn := l.conn
+	l.conn = nil
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_unlock(hashtext($1))", l.key); err != nil {
+		// không chắc đã nhả (kể cả ctx hết hạn): đóng connection, Postgres nhả lock theo session
+		closeConn(conn)
+		return err
+	}
+	conn.Release()
+	return nil
+}
+
+// closeConn: gỡ connection khỏi pool (Hijack) rồi đóng; pool.Close không phải chờ connection
+// đang mượn này, kể cả khi ctx của caller đã hết hạn
+func closeConn(conn *pgxpool.Conn) {
+	c := conn.Hijack()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_ = c.Close(ctx)
+}
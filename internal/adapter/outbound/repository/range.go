@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"wh-ma/internal/adapter/outbound/port"
+)
+
+// rangeArgs: tham số chung của các query List*Range (from, to, keyset, device)
+type rangeArgs struct {
+	from, to, afterAt pgtype.Timestamptz
+	afterID           int64
+	deviceID          *int64
+}
+
+func newRangeArgs(q port.RangeQuery) rangeArgs {
+	ts := func(t time.Time) pgtype.Timestamptz { return pgtype.Timestamptz{Time: t, Valid: true} }
+	a := rangeArgs{from: ts(q.From), to: ts(q.To), afterAt: ts(q.AfterAt), afterID: q.AfterID}
+	if q.AfterAt.IsZero() { // trang đầu: (at, id) > (from, 0) lấy cả dòng đúng bằng from
+		a.afterAt, a.afterID = a.from, 0
+	}
+	if q.DeviceID != nil {
+		id := int64(*q.DeviceID)
+		a.deviceID = &id
+	}
+	return a
+}
@@ -11,14 +11,72 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const addDeviceUsage = `-- name: AddDeviceUsage :one
+UPDATE devices SET
+  total_working_hour = COALESCE(total_working_hour, 0) + $1::int,
+  after_overhaul_working_hour = COALESCE(after_overhaul_working_hour, 0) + $1::int,
+  last_service_at = GREATEST(last_service_at, $2::timestamptz),
+  avg_daily_hours = $3,
+  expected_next_maint = $4,
+  version = version + 1,
+  updated_at = NOW()
+WHERE id = $5
+RETURNING id, serial_number, name, model, manufacturer, year_of_manufacture, commission_date, total_working_hour, after_overhaul_working_hour, last_service_at, location, avg_daily_hours, expected_next_maint, status, created_at, updated_at, deleted_at, created_by, updated_by, deleted_by, plan_id, tenant_id, version
+`
+
+type AddDeviceUsageParams struct {
+	HoursDelta        int32              `json:"hours_delta"`
+	At                pgtype.Timestamptz `json:"at"`
+	AvgDailyHours     *float64           `json:"avg_daily_hours"`
+	ExpectedNextMaint pgtype.Timestamptz `json:"expected_next_maint"`
+	ID                int64              `json:"id"`
+}
+
+func (q *Queries) AddDeviceUsage(ctx context.Context, arg AddDeviceUsageParams) (Device, error) {
+	row := q.db.QueryRow(ctx, addDeviceUsage,
+		arg.HoursDelta,
+		arg.At,
+		arg.AvgDailyHours,
+		arg.ExpectedNextMaint,
+		arg.ID,
+	)
+	var i Device
+	err := row.Scan(
+		&i.ID,
+		&i.SerialNumber,
+		&i.Name,
+		&i.Model,
+		&i.Manufacturer,
+		&i.YearOfManufacture,
+		&i.CommissionDate,
+		&i.TotalWorkingHour,
+		&i.AfterOverhaulWorkingHour,
+		&i.LastServiceAt,
+		&i.Location,
+		&i.AvgDailyHours,
+		&i.ExpectedNextMaint,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+		&i.DeletedBy,
+		&i.PlanID,
+		&i.TenantID,
+		&i.Version,
+	)
+	return i, err
+}
+
 const createDevice = `-- name: CreateDevice :one
 INSERT INTO devices (
   serial_number, name, model, manufacturer, year_of_manufacture,
   commission_date, total_working_hour, after_overhaul_working_hour,
-  status, last_service_at, location, plan_id, created_at, updated_at
+  status, last_service_at, location, plan_id, created_by, created_at, updated_at
 ) VALUES (
-  $1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,NOW(),NOW()
-) RETURNING id, serial_number, name, model, manufacturer, year_of_manufacture, commission_date, total_working_hour, after_overhaul_working_hour, last_service_at, location, avg_daily_hours, expected_next_maint, status, created_at, updated_at, deleted_at, created_by, updated_by, deleted_by, plan_id
+  $1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,NOW(),NOW()
+) RETURNING id, serial_number, name, model, manufacturer, year_of_manufacture, commission_date, total_working_hour, after_overhaul_working_hour, last_service_at, location, avg_daily_hours, expected_next_maint, status, created_at, updated_at, deleted_at, created_by, updated_by, deleted_by, plan_id, tenant_id, version
 `
 
 type CreateDeviceParams struct {
@@ -34,6 +92,7 @@ type CreateDeviceParams struct {
 	LastServiceAt            pgtype.Timestamptz `json:"last_service_at"`
 	Location                 *string            `json:"location"`
 	PlanID                   *int64             `json:"plan_id"`
+	CreatedBy                *string            `json:"created_by"`
 }
 
 func (q *Queries) CreateDevice(ctx context.Context, arg CreateDeviceParams) (Device, error) {
@@ -50,6 +109,7 @@ func (q *Queries) CreateDevice(ctx context.Context, arg CreateDeviceParams) (Dev
 		arg.LastServiceAt,
 		arg.Location,
 		arg.PlanID,
+		arg.CreatedBy,
 	)
 	var i Device
 	err := row.Scan(
@@ -74,12 +134,14 @@ func (q *Queries) CreateDevice(ctx context.Context, arg CreateDeviceParams) (Dev
 		&i.UpdatedBy,
 		&i.DeletedBy,
 		&i.PlanID,
+		&i.TenantID,
+		&i.Version,
 	)
 	return i, err
 }
 
 const getDevice = `-- name: GetDevice :one
-SELECT id, serial_number, name, model, manufacturer, year_of_manufacture, commission_date, total_working_hour, after_overhaul_working_hour, last_service_at, location, avg_daily_hours, expected_next_maint, status, created_at, updated_at, deleted_at, created_by, updated_by, deleted_by, plan_id FROM devices WHERE id = $1 LIMIT 1
+SELECT id, serial_number, name, model, manufacturer, year_of_manufacture, commission_date, total_working_hour, after_overhaul_working_hour, last_service_at, location, avg_daily_hours, expected_next_maint, status, created_at, updated_at, deleted_at, created_by, updated_by, deleted_by, plan_id, tenant_id, version FROM devices WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetDevice(ctx context.Context, id int64) (Device, error) {
@@ -107,24 +169,95 @@ func (q *Queries) GetDevice(ctx context.Context, id int64) (Device, error) {
 		&i.UpdatedBy,
 		&i.DeletedBy,
 		&i.PlanID,
+		&i.TenantID,
+		&i.Version,
 	)
 	return i, err
 }
 
+const getDeviceForUpdate = `-- name: GetDeviceForUpdate :one
+SELECT id, serial_number, name, model, manufacturer, year_of_manufacture, commission_date, total_working_hour, after_overhaul_working_hour, last_service_at, location, avg_daily_hours, expected_next_maint, status, created_at, updated_at, deleted_at, created_by, updated_by, deleted_by, plan_id, tenant_id, version FROM devices WHERE id = $1 LIMIT 1 FOR UPDATE
+`
+
+func (q *Queries) GetDeviceForUpdate(ctx context.Context, id int64) (Device, error) {
+	row := q.db.QueryRow(ctx, getDeviceForUpdate, id)
+	var i Device
+	err := row.Scan(
+		&i.ID,
+		&i.SerialNumber,
+		&i.Name,
+		&i.Model,
+		&i.Manufacturer,
+		&i.YearOfManufacture,
+		&i.CommissionDate,
+		&i.TotalWorkingHour,
+		&i.AfterOverhaulWorkingHour,
+		&i.LastServiceAt,
+		&i.Location,
+		&i.AvgDailyHours,
+		&i.ExpectedNextMaint,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+		&i.DeletedBy,
+		&i.PlanID,
+		&i.TenantID,
+		&i.Version,
+	)
+	return i, err
+}
+
+const listDeviceTenants = `-- name: ListDeviceTenants :many
+SELECT t::text AS tenant_id FROM app_tenant_ids() AS t
+`
+
+func (q *Queries) ListDeviceTenants(ctx context.Context) ([]string, error) {
+	rows, err := q.db.Query(ctx, listDeviceTenants)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var tenant_id string
+		if err := rows.Scan(&tenant_id); err != nil {
+			return nil, err
+		}
+		items = append(items, tenant_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listDevices = `-- name: ListDevices :many
-SELECT id, serial_number, name, model, manufacturer, year_of_manufacture, commission_date, total_working_hour, after_overhaul_working_hour, last_service_at, location, avg_daily_hours, expected_next_maint, status, created_at, updated_at, deleted_at, created_by, updated_by, deleted_by, plan_id FROM devices
+SELECT id, serial_number, name, model, manufacturer, year_of_manufacture, commission_date, total_working_hour, after_overhaul_working_hour, last_service_at, location, avg_daily_hours, expected_next_maint, status, created_at, updated_at, deleted_at, created_by, updated_by, deleted_by, plan_id, tenant_id, version FROM devices
 WHERE deleted_at IS NULL
+  AND (cardinality($1::text[]) = 0 OR location = ANY($1::text[]))
+  AND (cardinality($2::bigint[]) = 0 OR id = ANY($2::bigint[]))
 ORDER BY id
-LIMIT $1 OFFSET $2
+LIMIT $3 OFFSET $4
 `
 
 type ListDevicesParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+	Sites []string `json:"sites"`
+	Ids   []int64  `json:"ids"`
+	Lim   int32    `json:"lim"`
+	Off   int32    `json:"off"`
 }
 
+// sites/ids rỗng = không giới hạn (phạm vi của principal, xem domain.DeviceScope)
 func (q *Queries) ListDevices(ctx context.Context, arg ListDevicesParams) ([]Device, error) {
-	rows, err := q.db.Query(ctx, listDevices, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, listDevices,
+		arg.Sites,
+		arg.Ids,
+		arg.Lim,
+		arg.Off,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -154,6 +287,8 @@ func (q *Queries) ListDevices(ctx context.Context, arg ListDevicesParams) ([]Dev
 			&i.UpdatedBy,
 			&i.DeletedBy,
 			&i.PlanID,
+			&i.TenantID,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -165,38 +300,181 @@ func (q *Queries) ListDevices(ctx context.Context, arg ListDevicesParams) ([]Dev
 	return items, nil
 }
 
+const listDevicesAfter = `-- name: ListDevicesAfter :many
+SELECT id, serial_number, name, model, manufacturer, year_of_manufacture, commission_date, total_working_hour, after_overhaul_working_hour, last_service_at, location, avg_daily_hours, expected_next_maint, status, created_at, updated_at, deleted_at, created_by, updated_by, deleted_by, plan_id, tenant_id, version FROM devices
+WHERE id > $1 AND deleted_at IS NULL
+ORDER BY id
+LIMIT $2
+`
+
+type ListDevicesAfterParams struct {
+	AfterID int64 `json:"after_id"`
+	Lim     int32 `json:"lim"`
+}
+
+func (q *Queries) ListDevicesAfter(ctx context.Context, arg ListDevicesAfterParams) ([]Device, error) {
+	rows, err := q.db.Query(ctx, listDevicesAfter, arg.AfterID, arg.Lim)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Device
+	for rows.Next() {
+		var i Device
+		if err := rows.Scan(
+			&i.ID,
+			&i.SerialNumber,
+			&i.Name,
+			&i.Model,
+			&i.Manufacturer,
+			&i.YearOfManufacture,
+			&i.CommissionDate,
+			&i.TotalWorkingHour,
+			&i.AfterOverhaulWorkingHour,
+			&i.LastServiceAt,
+			&i.Location,
+			&i.AvgDailyHours,
+			&i.ExpectedNextMaint,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+			&i.DeletedBy,
+			&i.PlanID,
+			&i.TenantID,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDevicesBySerials = `-- name: ListDevicesBySerials :many
+SELECT id, serial_number, name, model, manufacturer, year_of_manufacture, commission_date, total_working_hour, after_overhaul_working_hour, last_service_at, location, avg_daily_hours, expected_next_maint, status, created_at, updated_at, deleted_at, created_by, updated_by, deleted_by, plan_id, tenant_id, version FROM devices
+WHERE serial_number = ANY($1::text[]) AND deleted_at IS NULL
+ORDER BY id
+`
+
+func (q *Queries) ListDevicesBySerials(ctx context.Context, serials []string) ([]Device, error) {
+	rows, err := q.db.Query(ctx, listDevicesBySerials, serials)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Device
+	for rows.Next() {
+		var i Device
+		if err := rows.Scan(
+			&i.ID,
+			&i.SerialNumber,
+			&i.Name,
+			&i.Model,
+			&i.Manufacturer,
+			&i.YearOfManufacture,
+			&i.CommissionDate,
+			&i.TotalWorkingHour,
+			&i.AfterOverhaulWorkingHour,
+			&i.LastServiceAt,
+			&i.Location,
+			&i.AvgDailyHours,
+			&i.ExpectedNextMaint,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+			&i.DeletedBy,
+			&i.PlanID,
+			&i.TenantID,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTakenSerials = `-- name: ListTakenSerials :many
+SELECT serial_number FROM devices
+WHERE serial_number = ANY($1::text[])
+`
+
+func (q *Queries) ListTakenSerials(ctx context.Context, serials []string) ([]string, error) {
+	rows, err := q.db.Query(ctx, listTakenSerials, serials)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var serial_number string
+		if err := rows.Scan(&serial_number); err != nil {
+			return nil, err
+		}
+		items = append(items, serial_number)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const softDeleteDevice = `-- name: SoftDeleteDevice :exec
-UPDATE devices SET deleted_at = NOW() WHERE id = $1
+UPDATE devices SET deleted_at = NOW(), deleted_by = $2 WHERE id = $1
 `
 
-func (q *Queries) SoftDeleteDevice(ctx context.Context, id int64) error {
-	_, err := q.db.Exec(ctx, softDeleteDevice, id)
+type SoftDeleteDeviceParams struct {
+	ID        int64   `json:"id"`
+	DeletedBy *string `json:"deleted_by"`
+}
+
+func (q *Queries) SoftDeleteDevice(ctx context.Context, arg SoftDeleteDeviceParams) error {
+	_, err := q.db.Exec(ctx, softDeleteDevice, arg.ID, arg.DeletedBy)
 	return err
 }
 
 const updateDeviceBasic = `-- name: UpdateDeviceBasic :one
 UPDATE devices SET
-  name = $2,
-  status = $3,
-  location = $4,
+  name = $1,
+  status = $2,
+  location = $3,
+  updated_by = $4,
+  version = version + 1,
   updated_at = NOW()
-WHERE id = $1
-RETURNING id, serial_number, name, model, manufacturer, year_of_manufacture, commission_date, total_working_hour, after_overhaul_working_hour, last_service_at, location, avg_daily_hours, expected_next_maint, status, created_at, updated_at, deleted_at, created_by, updated_by, deleted_by, plan_id
+WHERE id = $5
+  AND ($6::int = 0 OR version = $6::int)
+RETURNING id, serial_number, name, model, manufacturer, year_of_manufacture, commission_date, total_working_hour, after_overhaul_working_hour, last_service_at, location, avg_daily_hours, expected_next_maint, status, created_at, updated_at, deleted_at, created_by, updated_by, deleted_by, plan_id, tenant_id, version
 `
 
 type UpdateDeviceBasicParams struct {
-	ID       int64   `json:"id"`
-	Name     string  `json:"name"`
-	Status   string  `json:"status"`
-	Location *string `json:"location"`
+	Name            string  `json:"name"`
+	Status          string  `json:"status"`
+	Location        *string `json:"location"`
+	UpdatedBy       *string `json:"updated_by"`
+	ID              int64   `json:"id"`
+	ExpectedVersion int32   `json:"expected_version"`
 }
 
 func (q *Queries) UpdateDeviceBasic(ctx context.Context, arg UpdateDeviceBasicParams) (Device, error) {
 	row := q.db.QueryRow(ctx, updateDeviceBasic,
-		arg.ID,
 		arg.Name,
 		arg.Status,
 		arg.Location,
+		arg.UpdatedBy,
+		arg.ID,
+		arg.ExpectedVersion,
 	)
 	var i Device
 	err := row.Scan(
@@ -221,25 +499,130 @@ func (q *Queries) UpdateDeviceBasic(ctx context.Context, arg UpdateDeviceBasicPa
 		&i.UpdatedBy,
 		&i.DeletedBy,
 		&i.PlanID,
+		&i.TenantID,
+		&i.Version,
 	)
 	return i, err
 }
 
+const updateDeviceForecast = `-- name: UpdateDeviceForecast :exec
+UPDATE devices SET
+  avg_daily_hours = $1,
+  expected_next_maint = $2
+WHERE id = $3 AND deleted_at IS NULL
+`
+
+type UpdateDeviceForecastParams struct {
+	AvgDailyHours     *float64           `json:"avg_daily_hours"`
+	ExpectedNextMaint pgtype.Timestamptz `json:"expected_next_maint"`
+	ID                int64              `json:"id"`
+}
+
+func (q *Queries) UpdateDeviceForecast(ctx context.Context, arg UpdateDeviceForecastParams) error {
+	_, err := q.db.Exec(ctx, updateDeviceForecast, arg.AvgDailyHours, arg.ExpectedNextMaint, arg.ID)
+	return err
+}
+
 const updateDevicePlan = `-- name: UpdateDevicePlan :one
 UPDATE devices SET
-  plan_id = $2,
+  plan_id = $1,
+  updated_by = $2,
+  version = version + 1,
   updated_at = NOW()
-WHERE id = $1
-RETURNING id, serial_number, name, model, manufacturer, year_of_manufacture, commission_date, total_working_hour, after_overhaul_working_hour, last_service_at, location, avg_daily_hours, expected_next_maint, status, created_at, updated_at, deleted_at, created_by, updated_by, deleted_by, plan_id
+WHERE id = $3
+  AND ($4::int = 0 OR version = $4::int)
+RETURNING id, serial_number, name, model, manufacturer, year_of_manufacture, commission_date, total_working_hour, after_overhaul_working_hour, last_service_at, location, avg_daily_hours, expected_next_maint, status, created_at, updated_at, deleted_at, created_by, updated_by, deleted_by, plan_id, tenant_id, version
 `
 
 type UpdateDevicePlanParams struct {
-	ID     int64  `json:"id"`
-	PlanID *int64 `json:"plan_id"`
+	PlanID          *int64  `json:"plan_id"`
+	UpdatedBy       *string `json:"updated_by"`
+	ID              int64   `json:"id"`
+	ExpectedVersion int32   `json:"expected_version"`
 }
 
 func (q *Queries) UpdateDevicePlan(ctx context.Context, arg UpdateDevicePlanParams) (Device, error) {
-	row := q.db.QueryRow(ctx, updateDevicePlan, arg.ID, arg.PlanID)
+	row := q.db.QueryRow(ctx, updateDevicePlan,
+		arg.PlanID,
+		arg.UpdatedBy,
+		arg.ID,
+		arg.ExpectedVersion,
+	)
+	var i Device
+	err := row.Scan(
+		&i.ID,
+		&i.SerialNumber,
+		&i.Name,
+		&i.Model,
+		&i.Manufacturer,
+		&i.YearOfManufacture,
+		&i.CommissionDate,
+		&i.TotalWorkingHour,
+		&i.AfterOverhaulWorkingHour,
+		&i.LastServiceAt,
+		&i.Location,
+		&i.AvgDailyHours,
+		&i.ExpectedNextMaint,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+		&i.DeletedBy,
+		&i.PlanID,
+		&i.TenantID,
+		&i.Version,
+	)
+	return i, err
+}
+
+const updateDeviceProfile = `-- name: UpdateDeviceProfile :one
+UPDATE devices SET
+  serial_number = $1,
+  name = $2,
+  model = $3,
+  manufacturer = $4,
+  year_of_manufacture = $5,
+  commission_date = $6,
+  status = $7,
+  location = $8,
+  updated_by = $9,
+  version = version + 1,
+  updated_at = NOW()
+WHERE id = $10
+  AND ($11::int = 0 OR version = $11::int)
+RETURNING id, serial_number, name, model, manufacturer, year_of_manufacture, commission_date, total_working_hour, after_overhaul_working_hour, last_service_at, location, avg_daily_hours, expected_next_maint, status, created_at, updated_at, deleted_at, created_by, updated_by, deleted_by, plan_id, tenant_id, version
+`
+
+type UpdateDeviceProfileParams struct {
+	SerialNumber      string      `json:"serial_number"`
+	Name              string      `json:"name"`
+	Model             *string     `json:"model"`
+	Manufacturer      *string     `json:"manufacturer"`
+	YearOfManufacture *int32      `json:"year_of_manufacture"`
+	CommissionDate    pgtype.Date `json:"commission_date"`
+	Status            string      `json:"status"`
+	Location          *string     `json:"location"`
+	UpdatedBy         *string     `json:"updated_by"`
+	ID                int64       `json:"id"`
+	ExpectedVersion   int32       `json:"expected_version"`
+}
+
+func (q *Queries) UpdateDeviceProfile(ctx context.Context, arg UpdateDeviceProfileParams) (Device, error) {
+	row := q.db.QueryRow(ctx, updateDeviceProfile,
+		arg.SerialNumber,
+		arg.Name,
+		arg.Model,
+		arg.Manufacturer,
+		arg.YearOfManufacture,
+		arg.CommissionDate,
+		arg.Status,
+		arg.Location,
+		arg.UpdatedBy,
+		arg.ID,
+		arg.ExpectedVersion,
+	)
 	var i Device
 	err := row.Scan(
 		&i.ID,
@@ -263,6 +646,8 @@ func (q *Queries) UpdateDevicePlan(ctx context.Context, arg UpdateDevicePlanPara
 		&i.UpdatedBy,
 		&i.DeletedBy,
 		&i.PlanID,
+		&i.TenantID,
+		&i.Version,
 	)
 	return i, err
 }
@@ -11,7 +11,7 @@ import (
 
 const createPlan = `-- name: CreatePlan :one
 INSERT INTO plans (name, interval_hours, description, created_at, updated_at)
-VALUES ($1,$2,$3,NOW(),NOW()) RETURNING id, name, interval_hours, description, created_at, updated_at
+VALUES ($1,$2,$3,NOW(),NOW()) RETURNING id, name, interval_hours, description, created_at, updated_at, tenant_id, version
 `
 
 type CreatePlanParams struct {
@@ -30,6 +30,8 @@ func (q *Queries) CreatePlan(ctx context.Context, arg CreatePlanParams) (Plan, e
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TenantID,
+		&i.Version,
 	)
 	return i, err
 }
@@ -44,7 +46,7 @@ func (q *Queries) DeletePlan(ctx context.Context, id int64) error {
 }
 
 const getPlan = `-- name: GetPlan :one
-SELECT id, name, interval_hours, description, created_at, updated_at FROM plans WHERE id = $1 LIMIT 1
+SELECT id, name, interval_hours, description, created_at, updated_at, tenant_id, version FROM plans WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetPlan(ctx context.Context, id int64) (Plan, error) {
@@ -57,12 +59,14 @@ func (q *Queries) GetPlan(ctx context.Context, id int64) (Plan, error) {
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TenantID,
+		&i.Version,
 	)
 	return i, err
 }
 
 const listPlans = `-- name: ListPlans :many
-SELECT id, name, interval_hours, description, created_at, updated_at FROM plans ORDER BY id LIMIT $1 OFFSET $2
+SELECT id, name, interval_hours, description, created_at, updated_at, tenant_id, version FROM plans ORDER BY id LIMIT $1 OFFSET $2
 `
 
 type ListPlansParams struct {
@@ -86,6 +90,8 @@ func (q *Queries) ListPlans(ctx context.Context, arg ListPlansParams) ([]Plan, e
 			&i.Description,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.TenantID,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -99,27 +105,31 @@ func (q *Queries) ListPlans(ctx context.Context, arg ListPlansParams) ([]Plan, e
 
 const updatePlan = `-- name: UpdatePlan :one
 UPDATE plans SET
-  name = $2,
-  interval_hours = $3,
-  description = $4,
+  name = $1,
+  interval_hours = $2,
+  description = $3,
+  version = version + 1,
   updated_at = NOW()
-WHERE id = $1
-RETURNING id, name, interval_hours, description, created_at, updated_at
+WHERE id = $4
+  AND ($5::int = 0 OR version = $5::int)
+RETURNING id, name, interval_hours, description, created_at, updated_at, tenant_id, version
 `
 
 type UpdatePlanParams struct {
-	ID            int64   `json:"id"`
-	Name          string  `json:"name"`
-	IntervalHours int32   `json:"interval_hours"`
-	Description   *string `json:"description"`
+	Name            string  `json:"name"`
+	IntervalHours   int32   `json:"interval_hours"`
+	Description     *string `json:"description"`
+	ID              int64   `json:"id"`
+	ExpectedVersion int32   `json:"expected_version"`
 }
 
 func (q *Queries) UpdatePlan(ctx context.Context, arg UpdatePlanParams) (Plan, error) {
 	row := q.db.QueryRow(ctx, updatePlan,
-		arg.ID,
 		arg.Name,
 		arg.IntervalHours,
 		arg.Description,
+		arg.ID,
+		arg.ExpectedVersion,
 	)
 	var i Plan
 	err := row.Scan(
@@ -129,6 +139,8 @@ func (q *Queries) UpdatePlan(ctx context.Context, arg UpdatePlanParams) (Plan, e
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TenantID,
+		&i.Version,
 	)
 	return i, err
 }
@@ -11,10 +11,18 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type CopyReadingsImportParams struct {
+	DeviceID   int64              `json:"device_id"`
+	At         pgtype.Timestamptz `json:"at"`
+	HoursDelta int32              `json:"hours_delta"`
+	Location   *string            `json:"location"`
+	OperatorID *string            `json:"operator_id"`
+}
+
 const createReading = `-- name: CreateReading :one
 INSERT INTO readings (device_id, at, hours_delta, location, operator_id)
 VALUES ($1,$2,$3,$4,$5)
-RETURNING id, device_id, at, hours_delta, location, operator_id, created_at
+RETURNING id, device_id, at, hours_delta, location, operator_id, created_at, tenant_id, source
 `
 
 type CreateReadingParams struct {
@@ -42,6 +50,8 @@ func (q *Queries) CreateReading(ctx context.Context, arg CreateReadingParams) (R
 		&i.Location,
 		&i.OperatorID,
 		&i.CreatedAt,
+		&i.TenantID,
+		&i.Source,
 	)
 	return i, err
 }
@@ -55,8 +65,32 @@ func (q *Queries) DeleteReading(ctx context.Context, id int64) error {
 	return err
 }
 
+const getFirstReading = `-- name: GetFirstReading :one
+SELECT id, device_id, at, hours_delta, location, operator_id, created_at, tenant_id, source FROM readings
+WHERE device_id = $1
+ORDER BY at
+LIMIT 1
+`
+
+func (q *Queries) GetFirstReading(ctx context.Context, deviceID int64) (Reading, error) {
+	row := q.db.QueryRow(ctx, getFirstReading, deviceID)
+	var i Reading
+	err := row.Scan(
+		&i.ID,
+		&i.DeviceID,
+		&i.At,
+		&i.HoursDelta,
+		&i.Location,
+		&i.OperatorID,
+		&i.CreatedAt,
+		&i.TenantID,
+		&i.Source,
+	)
+	return i, err
+}
+
 const getLastReading = `-- name: GetLastReading :one
-SELECT id, device_id, at, hours_delta, location, operator_id, created_at FROM readings
+SELECT id, device_id, at, hours_delta, location, operator_id, created_at, tenant_id, source FROM readings
 WHERE device_id = $1
 ORDER BY at DESC
 LIMIT 1
@@ -73,12 +107,33 @@ func (q *Queries) GetLastReading(ctx context.Context, deviceID int64) (Reading,
 		&i.Location,
 		&i.OperatorID,
 		&i.CreatedAt,
+		&i.TenantID,
+		&i.Source,
 	)
 	return i, err
 }
 
+const insertReadingsFromImport = `-- name: InsertReadingsFromImport :execrows
+WITH staged AS (
+  DELETE FROM readings_import
+  RETURNING device_id, at, hours_delta, location, operator_id
+)
+INSERT INTO readings (device_id, at, hours_delta, location, operator_id, source)
+SELECT device_id, at, hours_delta, location, operator_id, 'import'
+FROM staged
+ORDER BY device_id, at
+`
+
+func (q *Queries) InsertReadingsFromImport(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, insertReadingsFromImport)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const listReadingsByDevice = `-- name: ListReadingsByDevice :many
-SELECT id, device_id, at, hours_delta, location, operator_id, created_at FROM readings
+SELECT id, device_id, at, hours_delta, location, operator_id, created_at, tenant_id, source FROM readings
 WHERE device_id = $1
 ORDER BY at DESC
 LIMIT $2 OFFSET $3
@@ -107,6 +162,8 @@ func (q *Queries) ListReadingsByDevice(ctx context.Context, arg ListReadingsByDe
 			&i.Location,
 			&i.OperatorID,
 			&i.CreatedAt,
+			&i.TenantID,
+			&i.Source,
 		); err != nil {
 			return nil, err
 		}
@@ -117,3 +174,76 @@ func (q *Queries) ListReadingsByDevice(ctx context.Context, arg ListReadingsByDe
 	}
 	return items, nil
 }
+
+const listReadingsRange = `-- name: ListReadingsRange :many
+SELECT id, device_id, at, hours_delta, location, operator_id, created_at, tenant_id, source FROM readings
+WHERE at >= $1 AND at < $2
+  AND ($3::bigint IS NULL OR device_id = $3::bigint)
+  AND (at, id) > ($4::timestamptz, $5::bigint)
+ORDER BY at, id
+LIMIT $6
+`
+
+type ListReadingsRangeParams struct {
+	FromAt   pgtype.Timestamptz `json:"from_at"`
+	ToAt     pgtype.Timestamptz `json:"to_at"`
+	DeviceID *int64             `json:"device_id"`
+	AfterAt  pgtype.Timestamptz `json:"after_at"`
+	AfterID  int64              `json:"after_id"`
+	Lim      int32              `json:"lim"`
+}
+
+func (q *Queries) ListReadingsRange(ctx context.Context, arg ListReadingsRangeParams) ([]Reading, error) {
+	rows, err := q.db.Query(ctx, listReadingsRange,
+		arg.FromAt,
+		arg.ToAt,
+		arg.DeviceID,
+		arg.AfterAt,
+		arg.AfterID,
+		arg.Lim,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Reading
+	for rows.Next() {
+		var i Reading
+		if err := rows.Scan(
+			&i.ID,
+			&i.DeviceID,
+			&i.At,
+			&i.HoursDelta,
+			&i.Location,
+			&i.OperatorID,
+			&i.CreatedAt,
+			&i.TenantID,
+			&i.Source,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const sumReadingHoursSince = `-- name: SumReadingHoursSince :one
+SELECT COALESCE(SUM(hours_delta), 0)::bigint AS hours
+FROM readings
+WHERE device_id = $1 AND at > $2
+`
+
+type SumReadingHoursSinceParams struct {
+	DeviceID int64              `json:"device_id"`
+	Since    pgtype.Timestamptz `json:"since"`
+}
+
+func (q *Queries) SumReadingHoursSince(ctx context.Context, arg SumReadingHoursSinceParams) (int64, error) {
+	row := q.db.QueryRow(ctx, sumReadingHoursSince, arg.DeviceID, arg.Since)
+	var hours int64
+	err := row.Scan(&hours)
+	return hours, err
+}
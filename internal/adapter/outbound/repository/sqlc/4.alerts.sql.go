@@ -7,12 +7,14 @@ package sqlc
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createAlert = `-- name: CreateAlert :one
 INSERT INTO alerts (device_id, type, message)
 VALUES ($1,$2,$3)
-RETURNING id, device_id, type, message, created_at, resolved, resolved_at, resolved_by
+RETURNING id, device_id, type, message, created_at, resolved, resolved_at, resolved_by, tenant_id
 `
 
 type CreateAlertParams struct {
@@ -33,12 +35,90 @@ func (q *Queries) CreateAlert(ctx context.Context, arg CreateAlertParams) (Alert
 		&i.Resolved,
 		&i.ResolvedAt,
 		&i.ResolvedBy,
+		&i.TenantID,
 	)
 	return i, err
 }
 
+const getAlert = `-- name: GetAlert :one
+SELECT id, device_id, type, message, created_at, resolved, resolved_at, resolved_by, tenant_id FROM alerts
+WHERE id = $1
+`
+
+func (q *Queries) GetAlert(ctx context.Context, id int64) (Alert, error) {
+	row := q.db.QueryRow(ctx, getAlert, id)
+	var i Alert
+	err := row.Scan(
+		&i.ID,
+		&i.DeviceID,
+		&i.Type,
+		&i.Message,
+		&i.CreatedAt,
+		&i.Resolved,
+		&i.ResolvedAt,
+		&i.ResolvedBy,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const listAlertsRange = `-- name: ListAlertsRange :many
+SELECT id, device_id, type, message, created_at, resolved, resolved_at, resolved_by, tenant_id FROM alerts
+WHERE created_at >= $1 AND created_at < $2
+  AND ($3::bigint IS NULL OR device_id = $3::bigint)
+  AND (created_at, id) > ($4::timestamptz, $5::bigint)
+ORDER BY created_at, id
+LIMIT $6
+`
+
+type ListAlertsRangeParams struct {
+	FromAt   pgtype.Timestamptz `json:"from_at"`
+	ToAt     pgtype.Timestamptz `json:"to_at"`
+	DeviceID *int64             `json:"device_id"`
+	AfterAt  pgtype.Timestamptz `json:"after_at"`
+	AfterID  int64              `json:"after_id"`
+	Lim      int32              `json:"lim"`
+}
+
+func (q *Queries) ListAlertsRange(ctx context.Context, arg ListAlertsRangeParams) ([]Alert, error) {
+	rows, err := q.db.Query(ctx, listAlertsRange,
+		arg.FromAt,
+		arg.ToAt,
+		arg.DeviceID,
+		arg.AfterAt,
+		arg.AfterID,
+		arg.Lim,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Alert
+	for rows.Next() {
+		var i Alert
+		if err := rows.Scan(
+			&i.ID,
+			&i.DeviceID,
+			&i.Type,
+			&i.Message,
+			&i.CreatedAt,
+			&i.Resolved,
+			&i.ResolvedAt,
+			&i.ResolvedBy,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listOpenAlertsByDevice = `-- name: ListOpenAlertsByDevice :many
-SELECT id, device_id, type, message, created_at, resolved, resolved_at, resolved_by FROM alerts
+SELECT id, device_id, type, message, created_at, resolved, resolved_at, resolved_by, tenant_id FROM alerts
 WHERE device_id = $1 AND resolved = FALSE
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3
@@ -68,6 +148,7 @@ func (q *Queries) ListOpenAlertsByDevice(ctx context.Context, arg ListOpenAlerts
 			&i.Resolved,
 			&i.ResolvedAt,
 			&i.ResolvedBy,
+			&i.TenantID,
 		); err != nil {
 			return nil, err
 		}
@@ -85,7 +166,7 @@ UPDATE alerts SET
   resolved_at = NOW(),
   resolved_by = $2
 WHERE id = $1
-RETURNING id, device_id, type, message, created_at, resolved, resolved_at, resolved_by
+RETURNING id, device_id, type, message, created_at, resolved, resolved_at, resolved_by, tenant_id
 `
 
 type ResolveAlertParams struct {
@@ -105,6 +186,7 @@ func (q *Queries) ResolveAlert(ctx context.Context, arg ResolveAlertParams) (Ale
 		&i.Resolved,
 		&i.ResolvedAt,
 		&i.ResolvedBy,
+		&i.TenantID,
 	)
 	return i, err
 }
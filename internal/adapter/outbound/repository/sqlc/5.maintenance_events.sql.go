@@ -14,7 +14,7 @@ import (
 const createMaintenanceEvent = `-- name: CreateMaintenanceEvent :one
 INSERT INTO maintenance_events (device_id, at, interval, notes, performed_by, cost)
 VALUES ($1,$2,$3,$4,$5,$6)
-RETURNING id, device_id, at, interval, notes, performed_by, cost, created_at
+RETURNING id, device_id, at, interval, notes, performed_by, cost, created_at, tenant_id
 `
 
 type CreateMaintenanceEventParams struct {
@@ -45,6 +45,7 @@ func (q *Queries) CreateMaintenanceEvent(ctx context.Context, arg CreateMaintena
 		&i.PerformedBy,
 		&i.Cost,
 		&i.CreatedAt,
+		&i.TenantID,
 	)
 	return i, err
 }
@@ -59,7 +60,7 @@ func (q *Queries) DeleteMaintenanceEvent(ctx context.Context, id int64) error {
 }
 
 const listMaintenanceByDevice = `-- name: ListMaintenanceByDevice :many
-SELECT id, device_id, at, interval, notes, performed_by, cost, created_at FROM maintenance_events
+SELECT id, device_id, at, interval, notes, performed_by, cost, created_at, tenant_id FROM maintenance_events
 WHERE device_id = $1
 ORDER BY at DESC
 LIMIT $2 OFFSET $3
@@ -89,6 +90,62 @@ func (q *Queries) ListMaintenanceByDevice(ctx context.Context, arg ListMaintenan
 			&i.PerformedBy,
 			&i.Cost,
 			&i.CreatedAt,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMaintenanceRange = `-- name: ListMaintenanceRange :many
+SELECT id, device_id, at, interval, notes, performed_by, cost, created_at, tenant_id FROM maintenance_events
+WHERE at >= $1 AND at < $2
+  AND ($3::bigint IS NULL OR device_id = $3::bigint)
+  AND (at, id) > ($4::timestamptz, $5::bigint)
+ORDER BY at, id
+LIMIT $6
+`
+
+type ListMaintenanceRangeParams struct {
+	FromAt   pgtype.Timestamptz `json:"from_at"`
+	ToAt     pgtype.Timestamptz `json:"to_at"`
+	DeviceID *int64             `json:"device_id"`
+	AfterAt  pgtype.Timestamptz `json:"after_at"`
+	AfterID  int64              `json:"after_id"`
+	Lim      int32              `json:"lim"`
+}
+
+func (q *Queries) ListMaintenanceRange(ctx context.Context, arg ListMaintenanceRangeParams) ([]MaintenanceEvent, error) {
+	rows, err := q.db.Query(ctx, listMaintenanceRange,
+		arg.FromAt,
+		arg.ToAt,
+		arg.DeviceID,
+		arg.AfterAt,
+		arg.AfterID,
+		arg.Lim,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MaintenanceEvent
+	for rows.Next() {
+		var i MaintenanceEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.DeviceID,
+			&i.At,
+			&i.Interval,
+			&i.Notes,
+			&i.PerformedBy,
+			&i.Cost,
+			&i.CreatedAt,
+			&i.TenantID,
 		); err != nil {
 			return nil, err
 		}
@@ -17,6 +17,23 @@ type Alert struct {
 	Resolved   bool               `json:"resolved"`
 	ResolvedAt pgtype.Timestamptz `json:"resolved_at"`
 	ResolvedBy *string            `json:"resolved_by"`
+	TenantID   string             `json:"tenant_id"`
+}
+
+type ApiKey struct {
+	ID         int64              `json:"id"`
+	Name       string             `json:"name"`
+	Prefix     string             `json:"prefix"`
+	SecretHash string             `json:"secret_hash"`
+	Scopes     []string           `json:"scopes"`
+	DeviceIds  []int64            `json:"device_ids"`
+	Sites      []string           `json:"sites"`
+	ExpiresAt  pgtype.Timestamptz `json:"expires_at"`
+	LastUsedAt pgtype.Timestamptz `json:"last_used_at"`
+	RevokedAt  pgtype.Timestamptz `json:"revoked_at"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	CreatedBy  *string            `json:"created_by"`
+	TenantID   *string            `json:"tenant_id"`
 }
 
 type Device struct {
@@ -41,6 +58,30 @@ type Device struct {
 	UpdatedBy                *string            `json:"updated_by"`
 	DeletedBy                *string            `json:"deleted_by"`
 	PlanID                   *int64             `json:"plan_id"`
+	TenantID                 string             `json:"tenant_id"`
+	Version                  int32              `json:"version"`
+}
+
+type DeviceAuditLog struct {
+	ID        int64              `json:"id"`
+	TenantID  string             `json:"tenant_id"`
+	DeviceID  int64              `json:"device_id"`
+	Action    string             `json:"action"`
+	Actor     *string            `json:"actor"`
+	Changes   []byte             `json:"changes"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type JobRun struct {
+	ID          int64              `json:"id"`
+	Job         string             `json:"job"`
+	Instance    string             `json:"instance"`
+	ScheduledAt pgtype.Timestamptz `json:"scheduled_at"`
+	StartedAt   pgtype.Timestamptz `json:"started_at"`
+	FinishedAt  pgtype.Timestamptz `json:"finished_at"`
+	Status      string             `json:"status"`
+	Error       *string            `json:"error"`
+	Summary     *string            `json:"summary"`
 }
 
 type MaintenanceEvent struct {
@@ -52,6 +93,7 @@ type MaintenanceEvent struct {
 	PerformedBy *string            `json:"performed_by"`
 	Cost        pgtype.Numeric     `json:"cost"`
 	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	TenantID    string             `json:"tenant_id"`
 }
 
 type Plan struct {
@@ -61,6 +103,8 @@ type Plan struct {
 	Description   *string            `json:"description"`
 	CreatedAt     pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+	TenantID      *string            `json:"tenant_id"`
+	Version       int32              `json:"version"`
 }
 
 type Reading struct {
@@ -71,4 +115,14 @@ type Reading struct {
 	Location   *string            `json:"location"`
 	OperatorID *string            `json:"operator_id"`
 	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	TenantID   string             `json:"tenant_id"`
+	Source     string             `json:"source"`
+}
+
+type ReadingsImport struct {
+	DeviceID   int64              `json:"device_id"`
+	At         pgtype.Timestamptz `json:"at"`
+	HoursDelta int32              `json:"hours_delta"`
+	Location   *string            `json:"location"`
+	OperatorID *string            `json:"operator_id"`
 }
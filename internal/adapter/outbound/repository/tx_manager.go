@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"wh-ma/internal/adapter/outbound/port"
+	dbsqlc "wh-ma/internal/adapter/outbound/repository/sqlc"
+)
+
+// NewRepos: mọi repository dùng chung một *dbsqlc.Queries trên pool
+func NewRepos(pool *pgxpool.Pool) port.Repos {
+	return reposFor(dbsqlc.New(pool))
+}
+
+// reposFor: bộ repo trên q (pool hoặc q.WithTx(tx))
+func reposFor(q *dbsqlc.Queries) port.Repos {
+	return port.Repos{
+		Devices:     &DeviceRepositoryPG{q: q},
+		Plans:       &PlanRepositoryPG{q: q},
+		Readings:    &ReadingRepositoryPG{q: q},
+		Alerts:      &AlertRepositoryPG{q: q},
+		Maintenance: &MaintenanceRepositoryPG{q: q},
+		DeviceAudit: &DeviceAuditRepositoryPG{q: q},
+		APIKeys:     &APIKeyRepositoryPG{q: q},
+		JobRuns:     &JobRunRepositoryPG{q: q},
+		FleetStats:  &FleetStatsRepositoryPG{q: q},
+	}
+}
+
+// ==== TxManager trên pgx ====
+// Connection của transaction cũng đi qua BeforeAcquire (bindTenant) nên RLS vẫn áp dụng.
+type TxManagerPG struct {
+	pool *pgxpool.Pool
+	q    *dbsqlc.Queries
+}
+
+func NewTxManager(pool *pgxpool.Pool) *TxManagerPG {
+	return &TxManagerPG{pool: pool, q: dbsqlc.New(pool)}
+}
+
+// compile-time check
+var _ port.TxManager = (*TxManagerPG)(nil)
+
+// txKey: ctx bên trong WithinTx mang transaction đang mở
+type txKey struct{}
+
+// WithinTx: pgx.BeginFunc commit khi fn trả nil, rollback khi lỗi/panic.
+// Gọi lồng (ctx của fn): Begin trên tx đang mở = SAVEPOINT, cùng connection.
+func (m *TxManagerPG) WithinTx(ctx context.Context, fn func(ctx context.Context, r port.Repos) error) error {
+	var db interface {
+		Begin(ctx context.Context) (pgx.Tx, error)
+	} = m.pool
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		db = tx
+	}
+	return pgx.BeginFunc(ctx, db, func(tx pgx.Tx) error {
+		return fn(context.WithValue(ctx, txKey{}, tx), reposFor(m.q.WithTx(tx)))
+	})
+}
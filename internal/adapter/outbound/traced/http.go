@@ -0,0 +1,31 @@
+package traced
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// ==== HTTP client gửi ra ngoài ====
+
+// InjectHTTP: ghi traceparent/tracestate/baggage của ctx vào header request gửi ra ngoài
+func InjectHTTP(ctx context.Context, h http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(h))
+}
+
+// Transport: http.RoundTripper tự inject trace context cho mọi request (client webhook/notifier, CLI)
+type Transport struct {
+	Base http.RoundTripper // nil = http.DefaultTransport
+}
+
+func (t Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	req = req.Clone(req.Context()) // RoundTripper không được sửa request của caller
+	InjectHTTP(req.Context(), req.Header)
+	return base.RoundTrip(req)
+}
@@ -0,0 +1,355 @@
+package traced
+
+import (
+	"context"
+	"time"
+
+	"wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/tracing"
+)
+
+// Decorator cho repository: span con của span usecase, cùng thuộc tính domain;
+// span SQL của otelpgx (postgres) nằm dưới span này.
+
+// ==== DeviceRepository ====
+
+type devicesRepo struct {
+	next port.DeviceRepository
+}
+
+func (d devicesRepo) Create(ctx context.Context, in port.CreateDeviceInput) (_ *domain.Device, err error) {
+	ctx, span := tracing.Start(ctx, "DeviceRepository.Create", in)
+	defer func() { tracing.End(span, err) }()
+	return d.next.Create(ctx, in)
+}
+
+func (d devicesRepo) GetByID(ctx context.Context, id domain.DeviceID) (_ *domain.Device, err error) {
+	ctx, span := tracing.Start(ctx, "DeviceRepository.GetByID", id)
+	defer func() { tracing.End(span, err) }()
+	return d.next.GetByID(ctx, id)
+}
+
+func (d devicesRepo) GetForUpdate(ctx context.Context, id domain.DeviceID) (_ *domain.Device, err error) {
+	ctx, span := tracing.Start(ctx, "DeviceRepository.GetForUpdate", id)
+	defer func() { tracing.End(span, err) }()
+	return d.next.GetForUpdate(ctx, id)
+}
+
+func (d devicesRepo) List(ctx context.Context, scope domain.DeviceScope, limit int32, offset int32) (_ []*domain.Device, err error) {
+	ctx, span := tracing.Start(ctx, "DeviceRepository.List")
+	defer func() { tracing.End(span, err) }()
+	return d.next.List(ctx, scope, limit, offset)
+}
+
+func (d devicesRepo) ListAfter(ctx context.Context, after domain.DeviceID, limit int32) (_ []*domain.Device, err error) {
+	ctx, span := tracing.Start(ctx, "DeviceRepository.ListAfter", after)
+	defer func() { tracing.End(span, err) }()
+	return d.next.ListAfter(ctx, after, limit)
+}
+
+func (d devicesRepo) ListBySerials(ctx context.Context, serials []string) (_ []*domain.Device, err error) {
+	ctx, span := tracing.Start(ctx, "DeviceRepository.ListBySerials")
+	defer func() { tracing.End(span, err) }()
+	return d.next.ListBySerials(ctx, serials)
+}
+
+func (d devicesRepo) ListTakenSerials(ctx context.Context, serials []string) (_ []string, err error) {
+	ctx, span := tracing.Start(ctx, "DeviceRepository.ListTakenSerials")
+	defer func() { tracing.End(span, err) }()
+	return d.next.ListTakenSerials(ctx, serials)
+}
+
+func (d devicesRepo) UpdateBasic(ctx context.Context, id domain.DeviceID, name string, status domain.DeviceStatus, location *string, updatedBy string, expectedVersion int) (_ *domain.Device, err error) {
+	ctx, span := tracing.Start(ctx, "DeviceRepository.UpdateBasic", id, status)
+	defer func() { tracing.End(span, err) }()
+	return d.next.UpdateBasic(ctx, id, name, status, location, updatedBy, expectedVersion)
+}
+
+func (d devicesRepo) UpdatePlan(ctx context.Context, id domain.DeviceID, planID *domain.PlanID, updatedBy string, expectedVersion int) (_ *domain.Device, err error) {
+	ctx, span := tracing.Start(ctx, "DeviceRepository.UpdatePlan", id, planID)
+	defer func() { tracing.End(span, err) }()
+	return d.next.UpdatePlan(ctx, id, planID, updatedBy, expectedVersion)
+}
+
+func (d devicesRepo) UpdateProfile(ctx context.Context, in port.UpdateDeviceProfileInput) (_ *domain.Device, err error) {
+	ctx, span := tracing.Start(ctx, "DeviceRepository.UpdateProfile", in)
+	defer func() { tracing.End(span, err) }()
+	return d.next.UpdateProfile(ctx, in)
+}
+
+func (d devicesRepo) SoftDelete(ctx context.Context, id domain.DeviceID, deletedBy string) (err error) {
+	ctx, span := tracing.Start(ctx, "DeviceRepository.SoftDelete", id)
+	defer func() { tracing.End(span, err) }()
+	return d.next.SoftDelete(ctx, id, deletedBy)
+}
+
+func (d devicesRepo) AddUsage(ctx context.Context, in port.AddDeviceUsageInput) (_ *domain.Device, err error) {
+	ctx, span := tracing.Start(ctx, "DeviceRepository.AddUsage", in)
+	defer func() { tracing.End(span, err) }()
+	return d.next.AddUsage(ctx, in)
+}
+
+func (d devicesRepo) UpdateForecast(ctx context.Context, id domain.DeviceID, avgDailyHours float64, expectedNextMaint *time.Time) (err error) {
+	ctx, span := tracing.Start(ctx, "DeviceRepository.UpdateForecast", id)
+	defer func() { tracing.End(span, err) }()
+	return d.next.UpdateForecast(ctx, id, avgDailyHours, expectedNextMaint)
+}
+
+func (d devicesRepo) ListTenants(ctx context.Context) (_ []string, err error) {
+	ctx, span := tracing.Start(ctx, "DeviceRepository.ListTenants")
+	defer func() { tracing.End(span, err) }()
+	return d.next.ListTenants(ctx)
+}
+
+// ==== PlanRepository ====
+
+type plansRepo struct {
+	next port.PlanRepository
+}
+
+func (p plansRepo) Create(ctx context.Context, in port.CreatePlanInput) (_ *domain.Plan, err error) {
+	ctx, span := tracing.Start(ctx, "PlanRepository.Create", in)
+	defer func() { tracing.End(span, err) }()
+	return p.next.Create(ctx, in)
+}
+
+func (p plansRepo) GetByID(ctx context.Context, id domain.PlanID) (_ *domain.Plan, err error) {
+	ctx, span := tracing.Start(ctx, "PlanRepository.GetByID", id)
+	defer func() { tracing.End(span, err) }()
+	return p.next.GetByID(ctx, id)
+}
+
+func (p plansRepo) List(ctx context.Context, limit int32, offset int32) (_ []*domain.Plan, err error) {
+	ctx, span := tracing.Start(ctx, "PlanRepository.List")
+	defer func() { tracing.End(span, err) }()
+	return p.next.List(ctx, limit, offset)
+}
+
+func (p plansRepo) Update(ctx context.Context, in port.UpdatePlanInput) (_ *domain.Plan, err error) {
+	ctx, span := tracing.Start(ctx, "PlanRepository.Update", in)
+	defer func() { tracing.End(span, err) }()
+	return p.next.Update(ctx, in)
+}
+
+func (p plansRepo) Delete(ctx context.Context, id domain.PlanID) (err error) {
+	ctx, span := tracing.Start(ctx, "PlanRepository.Delete", id)
+	defer func() { tracing.End(span, err) }()
+	return p.next.Delete(ctx, id)
+}
+
+// ==== ReadingRepository ====
+
+type readingsRepo struct {
+	next port.ReadingRepository
+}
+
+func (r readingsRepo) Create(ctx context.Context, in port.CreateReadingInput) (_ *domain.Reading, err error) {
+	ctx, span := tracing.Start(ctx, "ReadingRepository.Create", in)
+	defer func() { tracing.End(span, err) }()
+	return r.next.Create(ctx, in)
+}
+
+func (r readingsRepo) CreateMany(ctx context.Context, in []port.CreateReadingInput) (_ int64, err error) {
+	ctx, span := tracing.Start(ctx, "ReadingRepository.CreateMany", in)
+	defer func() { tracing.End(span, err) }()
+	return r.next.CreateMany(ctx, in)
+}
+
+func (r readingsRepo) GetFirstByDevice(ctx context.Context, deviceID domain.DeviceID) (_ *domain.Reading, err error) {
+	ctx, span := tracing.Start(ctx, "ReadingRepository.GetFirstByDevice", deviceID)
+	defer func() { tracing.End(span, err) }()
+	return r.next.GetFirstByDevice(ctx, deviceID)
+}
+
+func (r readingsRepo) GetLastByDevice(ctx context.Context, deviceID domain.DeviceID) (_ *domain.Reading, err error) {
+	ctx, span := tracing.Start(ctx, "ReadingRepository.GetLastByDevice", deviceID)
+	defer func() { tracing.End(span, err) }()
+	return r.next.GetLastByDevice(ctx, deviceID)
+}
+
+func (r readingsRepo) SumHoursSince(ctx context.Context, deviceID domain.DeviceID, since time.Time) (_ int, err error) {
+	ctx, span := tracing.Start(ctx, "ReadingRepository.SumHoursSince", deviceID)
+	defer func() { tracing.End(span, err) }()
+	return r.next.SumHoursSince(ctx, deviceID, since)
+}
+
+func (r readingsRepo) ListByDevice(ctx context.Context, deviceID domain.DeviceID, limit int32, offset int32) (_ []*domain.Reading, err error) {
+	ctx, span := tracing.Start(ctx, "ReadingRepository.ListByDevice", deviceID)
+	defer func() { tracing.End(span, err) }()
+	return r.next.ListByDevice(ctx, deviceID, limit, offset)
+}
+
+func (r readingsRepo) ListRange(ctx context.Context, q port.RangeQuery) (_ []*domain.Reading, err error) {
+	ctx, span := tracing.Start(ctx, "ReadingRepository.ListRange", q)
+	defer func() { tracing.End(span, err) }()
+	return r.next.ListRange(ctx, q)
+}
+
+func (r readingsRepo) Delete(ctx context.Context, id int64) (err error) {
+	ctx, span := tracing.Start(ctx, "ReadingRepository.Delete")
+	defer func() { tracing.End(span, err) }()
+	return r.next.Delete(ctx, id)
+}
+
+// ==== AlertRepository ====
+
+type alertsRepo struct {
+	next port.AlertRepository
+}
+
+func (a alertsRepo) Create(ctx context.Context, in port.CreateAlertInput) (_ *domain.Alert, err error) {
+	ctx, span := tracing.Start(ctx, "AlertRepository.Create", in)
+	defer func() { tracing.End(span, err) }()
+	return a.next.Create(ctx, in)
+}
+
+func (a alertsRepo) GetByID(ctx context.Context, id int64) (_ *domain.Alert, err error) {
+	ctx, span := tracing.Start(ctx, "AlertRepository.GetByID", id)
+	defer func() { tracing.End(span, err) }()
+	return a.next.GetByID(ctx, id)
+}
+
+func (a alertsRepo) ListOpenByDevice(ctx context.Context, deviceID domain.DeviceID, limit int32, offset int32) (_ []*domain.Alert, err error) {
+	ctx, span := tracing.Start(ctx, "AlertRepository.ListOpenByDevice", deviceID)
+	defer func() { tracing.End(span, err) }()
+	return a.next.ListOpenByDevice(ctx, deviceID, limit, offset)
+}
+
+func (a alertsRepo) ListRange(ctx context.Context, q port.RangeQuery) (_ []*domain.Alert, err error) {
+	ctx, span := tracing.Start(ctx, "AlertRepository.ListRange", q)
+	defer func() { tracing.End(span, err) }()
+	return a.next.ListRange(ctx, q)
+}
+
+func (a alertsRepo) Resolve(ctx context.Context, in port.ResolveAlertInput) (_ *domain.Alert, err error) {
+	ctx, span := tracing.Start(ctx, "AlertRepository.Resolve", in)
+	defer func() { tracing.End(span, err) }()
+	return a.next.Resolve(ctx, in)
+}
+
+// ==== MaintenanceRepository ====
+
+type maintenanceRepo struct {
+	next port.MaintenanceRepository
+}
+
+func (m maintenanceRepo) Create(ctx context.Context, in port.CreateMaintenanceInput) (_ *domain.MaintenanceEvent, err error) {
+	ctx, span := tracing.Start(ctx, "MaintenanceRepository.Create", in)
+	defer func() { tracing.End(span, err) }()
+	return m.next.Create(ctx, in)
+}
+
+func (m maintenanceRepo) Delete(ctx context.Context, id int64) (err error) {
+	ctx, span := tracing.Start(ctx, "MaintenanceRepository.Delete")
+	defer func() { tracing.End(span, err) }()
+	return m.next.Delete(ctx, id)
+}
+
+func (m maintenanceRepo) ListByDevice(ctx context.Context, deviceID domain.DeviceID, limit int32, offset int32) (_ []*domain.MaintenanceEvent, err error) {
+	ctx, span := tracing.Start(ctx, "MaintenanceRepository.ListByDevice", deviceID)
+	defer func() { tracing.End(span, err) }()
+	return m.next.ListByDevice(ctx, deviceID, limit, offset)
+}
+
+func (m maintenanceRepo) ListRange(ctx context.Context, q port.RangeQuery) (_ []*domain.MaintenanceEvent, err error) {
+	ctx, span := tracing.Start(ctx, "MaintenanceRepository.ListRange", q)
+	defer func() { tracing.End(span, err) }()
+	return m.next.ListRange(ctx, q)
+}
+
+// ==== DeviceAuditRepository ====
+
+type deviceAuditRepo struct {
+	next port.DeviceAuditRepository
+}
+
+func (d deviceAuditRepo) Record(ctx context.Context, in port.RecordDeviceAuditInput) (_ *domain.DeviceAuditEntry, err error) {
+	ctx, span := tracing.Start(ctx, "DeviceAuditRepository.Record", in)
+	defer func() { tracing.End(span, err) }()
+	return d.next.Record(ctx, in)
+}
+
+func (d deviceAuditRepo) ListByDevice(ctx context.Context, deviceID domain.DeviceID, limit int32, offset int32) (_ []*domain.DeviceAuditEntry, err error) {
+	ctx, span := tracing.Start(ctx, "DeviceAuditRepository.ListByDevice", deviceID)
+	defer func() { tracing.End(span, err) }()
+	return d.next.ListByDevice(ctx, deviceID, limit, offset)
+}
+
+// ==== APIKeyRepository ====
+
+type apiKeysRepo struct {
+	next port.APIKeyRepository
+}
+
+func (a apiKeysRepo) Create(ctx context.Context, in port.CreateAPIKeyInput) (_ *domain.APIKey, err error) {
+	ctx, span := tracing.Start(ctx, "APIKeyRepository.Create", in)
+	defer func() { tracing.End(span, err) }()
+	return a.next.Create(ctx, in)
+}
+
+func (a apiKeysRepo) GetByPrefix(ctx context.Context, prefix string) (_ *domain.APIKey, err error) {
+	ctx, span := tracing.Start(ctx, "APIKeyRepository.GetByPrefix")
+	defer func() { tracing.End(span, err) }()
+	return a.next.GetByPrefix(ctx, prefix)
+}
+
+func (a apiKeysRepo) List(ctx context.Context, limit int32, offset int32) (_ []*domain.APIKey, err error) {
+	ctx, span := tracing.Start(ctx, "APIKeyRepository.List")
+	defer func() { tracing.End(span, err) }()
+	return a.next.List(ctx, limit, offset)
+}
+
+func (a apiKeysRepo) Revoke(ctx context.Context, id int64) (_ *domain.APIKey, err error) {
+	ctx, span := tracing.Start(ctx, "APIKeyRepository.Revoke")
+	defer func() { tracing.End(span, err) }()
+	return a.next.Revoke(ctx, id)
+}
+
+func (a apiKeysRepo) TouchLastUsed(ctx context.Context, id int64) (err error) {
+	ctx, span := tracing.Start(ctx, "APIKeyRepository.TouchLastUsed")
+	defer func() { tracing.End(span, err) }()
+	return a.next.TouchLastUsed(ctx, id)
+}
+
+// ==== JobRunRepository ====
+
+type jobRunsRepo struct {
+	next port.JobRunRepository
+}
+
+func (j jobRunsRepo) Create(ctx context.Context, run *domain.JobRun) (_ *domain.JobRun, err error) {
+	ctx, span := tracing.Start(ctx, "JobRunRepository.Create", run)
+	defer func() { tracing.End(span, err) }()
+	return j.next.Create(ctx, run)
+}
+
+func (j jobRunsRepo) List(ctx context.Context, job string, limit int32, offset int32) (_ []*domain.JobRun, err error) {
+	ctx, span := tracing.Start(ctx, "JobRunRepository.List")
+	defer func() { tracing.End(span, err) }()
+	return j.next.List(ctx, job, limit, offset)
+}
+
+func (j jobRunsRepo) Latest(ctx context.Context) (_ []*domain.JobRun, err error) {
+	ctx, span := tracing.Start(ctx, "JobRunRepository.Latest")
+	defer func() { tracing.End(span, err) }()
+	return j.next.Latest(ctx)
+}
+
+func (j jobRunsRepo) DeleteBefore(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := tracing.Start(ctx, "JobRunRepository.DeleteBefore")
+	defer func() { tracing.End(span, err) }()
+	return j.next.DeleteBefore(ctx, before)
+}
+
+// ==== FleetStatsRepository ====
+
+type fleetStatsRepo struct {
+	next port.FleetStatsRepository
+}
+
+func (f fleetStatsRepo) Snapshot(ctx context.Context, now time.Time) (_ *domain.FleetStats, err error) {
+	ctx, span := tracing.Start(ctx, "FleetStatsRepository.Snapshot")
+	defer func() { tracing.End(span, err) }()
+	return f.next.Snapshot(ctx, now)
+}
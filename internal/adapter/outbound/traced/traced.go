@@ -0,0 +1,40 @@
+package traced
+
+import (
+	"context"
+
+	"wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/usecase/tracing"
+)
+
+// Repos: bọc từng repository bằng decorator tạo span (postgres và memory như nhau)
+func Repos(r port.Repos) port.Repos {
+	return port.Repos{
+		Devices:     devicesRepo{next: r.Devices},
+		Plans:       plansRepo{next: r.Plans},
+		Readings:    readingsRepo{next: r.Readings},
+		Alerts:      alertsRepo{next: r.Alerts},
+		Maintenance: maintenanceRepo{next: r.Maintenance},
+		DeviceAudit: deviceAuditRepo{next: r.DeviceAudit},
+		APIKeys:     apiKeysRepo{next: r.APIKeys},
+		JobRuns:     jobRunsRepo{next: r.JobRuns},
+		FleetStats:  fleetStatsRepo{next: r.FleetStats},
+	}
+}
+
+// TxManager: span "TxManager.WithinTx" bao cả transaction, repo trong fn cũng được bọc
+func TxManager(tx port.TxManager) port.TxManager {
+	return txManager{next: tx}
+}
+
+type txManager struct {
+	next port.TxManager
+}
+
+func (t txManager) WithinTx(ctx context.Context, fn func(ctx context.Context, r port.Repos) error) (err error) {
+	ctx, span := tracing.Start(ctx, "TxManager.WithinTx")
+	defer func() { tracing.End(span, err) }()
+	return t.next.WithinTx(ctx, func(ctx context.Context, r port.Repos) error {
+		return fn(ctx, Repos(r))
+	})
+}
@@ -0,0 +1,394 @@
+package bootstrap
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"gopkg.in/yaml.v3"
+
+	"wh-ma/internal/adapter/inbound/http/metrics"
+	"wh-ma/internal/adapter/inbound/scheduler"
+	"wh-ma/internal/usecase"
+)
+
+// ===== Config =====
+
+// Thứ tự ưu tiên (sau thắng trước): mặc định trong code < file YAML < biến môi trường
+// (.env chỉ bổ sung biến chưa có trong môi trường thật, xem LoadEnvFirst).
+// Key YAML là tên biến môi trường viết thường (JOB_IDLE_AFTER <-> job_idle_after, LOG_LEVEL <-> log.level).
+// Secret đọc được từ file: đặt <TÊN>_FILE=/run/secrets/... thay cho <TÊN> (docker/k8s secret).
+const (
+	// DefaultConfigFile: dùng khi có file và không đặt CONFIG_FILE (đường dẫn tương đối như configs/.env)
+	DefaultConfigFile = "configs/config.yaml"
+)
+
+type AppConfig struct {
+	ConfigFile string `yaml:"-"` // file đã đọc; rỗng = chỉ mặc định + env
+
+	AppEnv string `yaml:"app_env"`
+	Port   string `yaml:"port"`
+	// Dừng process: /readiness báo down, chờ ShutdownDelay để load balancer bỏ replica,
+	// rồi dừng HTTP (chờ request đang xử lý tối đa HTTPDrain), scheduler, export job, storage, telemetry
+	ShutdownDelay  time.Duration `yaml:"shutdown_delay"`
+	HTTPDrain      time.Duration `yaml:"http_drain_timeout"`
+	DatabaseURL    string        `yaml:"database_url"`
+	Storage        string        `yaml:"storage"`      // postgres | memory
+	AutoMigrate    bool          `yaml:"auto_migrate"` // chạy migration nhúng lúc khởi động (khóa advisory, an toàn với nhiều replica)
+	AllowOrigin    []string      `yaml:"cors_origins"` // reload được
+	Log            LogConfig     `yaml:"log"`
+	JWTSecret      string        `yaml:"jwt_secret"`
+	RBACPolicyFile string        `yaml:"rbac_policy_file"` // rỗng = authz.DefaultPolicy()
+
+	// Export job: file kết quả + metadata (dùng chung volume nếu nhiều replica)
+	ExportDir        string        `yaml:"export_dir"`
+	ExportLinkTTL    time.Duration `yaml:"export_link_ttl"`      // link tải + file sống bao lâu sau khi job xong
+	ExportSigningKey string        `yaml:"export_signing_key"`   // HMAC ký link tải; rỗng = dùng JWT_SECRET, cả hai rỗng = khóa ngẫu nhiên
+	ExportDrain      time.Duration `yaml:"export_drain_timeout"` // lúc dừng, chờ job export đang chạy tối đa bao lâu
+
+	// Job nền: mọi replica chạy scheduler, chỉ leader (advisory lock) chạy job
+	SchedulerEnabled bool              `yaml:"scheduler_enabled"`       // false = replica này không bao giờ nhận quyền leader
+	SchedulerDrain   time.Duration     `yaml:"scheduler_drain_timeout"` // lúc dừng, chờ job đang chạy tối đa bao lâu
+	JobSchedules     map[string]string `yaml:"job_schedules"`           // job -> cron (giờ local); env JOB_<TÊN>_SCHEDULE
+	JobIdleAfter     time.Duration     `yaml:"job_idle_after"`          // device active không có reading lâu hơn -> alert idle_too_long (reload được)
+	JobRunRetention  time.Duration     `yaml:"job_run_retention"`       // purge xóa lịch sử job_runs cũ hơn (reload được)
+
+	// /metrics: số liệu đội máy được cache bao lâu giữa các lần scrape
+	FleetMetricsTTL time.Duration `yaml:"fleet_metrics_ttl"`
+	MetricsExporter string        `yaml:"metrics_exporter"` // none | otlp (đẩy thêm qua OTLP, xem InitMetrics); env METRICS_EXPORTER
+
+	Tracing TracingConfig `yaml:"tracing"`
+}
+
+func defaultConfig() AppConfig {
+	return AppConfig{
+		AppEnv:        "development",
+		Port:          "8080",
+		ShutdownDelay: 5 * time.Second,
+		HTTPDrain:     10 * time.Second,
+		Storage:       StoragePostgres,
+		AllowOrigin:   []string{"*"},
+		Log: LogConfig{
+			Level:            "info",
+			Format:           LogFormatJSON,
+			SampleInitial:    100,
+			SampleThereafter: 100,
+		},
+
+		ExportDir:     filepath.Join(os.TempDir(), "whma-exports"),
+		ExportLinkTTL: usecase.DefaultExportLinkTTL,
+		ExportDrain:   usecase.DefaultExportDrain,
+
+		SchedulerEnabled: true,
+		SchedulerDrain:   scheduler.DefaultDrain,
+		JobSchedules: map[string]string{
+			"forecast_recompute": "15 2 * * *",
+			"idle_detection":     "5 * * * *",
+			"purge":              "45 3 * * *",
+		},
+		JobIdleAfter:    usecase.DefaultIdleAfter,
+		JobRunRetention: usecase.DefaultJobRunRetention,
+
+		FleetMetricsTTL: metrics.DefaultFleetTTL,
+		MetricsExporter: MetricsExportNone,
+
+		Tracing: TracingConfig{
+			Exporter:    defaultTracingExporter(),
+			SampleRatio: 1,
+		},
+	}
+}
+
+// LoadConfig: cấu hình sai thì dừng ngay lúc khởi động, liệt kê mọi lỗi một lần
+func LoadConfig() AppConfig {
+	LoadEnvFirst()
+	cfg, err := ReadConfig()
+	if err != nil {
+		log.Fatalf("config: %v", err)
+	}
+	return cfg
+}
+
+// ReadConfig: đọc lại mặc định + file + env và validate (reload dùng lại, lỗi thì giữ cấu hình cũ)
+func ReadConfig() (AppConfig, error) {
+	cfg := defaultConfig()
+	path, err := configFile()
+	if err != nil {
+		return cfg, err
+	}
+	if path != "" {
+		if err := decodeConfigFile(path, &cfg); err != nil {
+			return cfg, err
+		}
+		cfg.ConfigFile = path
+	}
+
+	env := &envLoader{}
+	env.str("APP_ENV", &cfg.AppEnv)
+	env.str("PORT", &cfg.Port)
+	env.duration("SHUTDOWN_DELAY", &cfg.ShutdownDelay)
+	env.duration("HTTP_DRAIN_TIMEOUT", &cfg.HTTPDrain)
+	env.str("DATABASE_URL", &cfg.DatabaseURL)
+	env.str("STORAGE", &cfg.Storage)
+	env.bool("AUTO_MIGRATE", &cfg.AutoMigrate)
+	env.list("CORS_ORIGINS", &cfg.AllowOrigin)
+	env.str("LOG_LEVEL", &cfg.Log.Level)
+	env.str("LOG_FORMAT", &cfg.Log.Format)
+	env.int("LOG_SAMPLE_INITIAL", &cfg.Log.SampleInitial)
+	env.int("LOG_SAMPLE_THEREAFTER", &cfg.Log.SampleThereafter)
+	env.str("JWT_SECRET", &cfg.JWTSecret)
+	env.str("RBAC_POLICY_FILE", &cfg.RBACPolicyFile)
+	env.str("EXPORT_DIR", &cfg.ExportDir)
+	env.duration("EXPORT_LINK_TTL", &cfg.ExportLinkTTL)
+	env.str("EXPORT_SIGNING_KEY", &cfg.ExportSigningKey)
+	env.duration("EXPORT_DRAIN_TIMEOUT", &cfg.ExportDrain)
+	env.bool("SCHEDULER_ENABLED", &cfg.SchedulerEnabled)
+	env.duration("SCHEDULER_DRAIN_TIMEOUT", &cfg.SchedulerDrain)
+	for job, spec := range cfg.JobSchedules {
+		env.str("JOB_"+strings.ToUpper(job)+"_SCHEDULE", &spec)
+		cfg.JobSchedules[job] = spec
+	}
+	env.duration("JOB_IDLE_AFTER", &cfg.JobIdleAfter)
+	env.duration("JOB_RUN_RETENTION", &cfg.JobRunRetention)
+	env.duration("FLEET_METRICS_TTL", &cfg.FleetMetricsTTL)
+	env.str("METRICS_EXPORTER", &cfg.MetricsExporter) // tên riêng: OTEL_METRICS_EXPORTER chuẩn nhận giá trị khác (prometheus, console)
+	env.str("TRACING_EXPORTER", &cfg.Tracing.Exporter)
+	env.ratio("TRACING_SAMPLE_RATIO", &cfg.Tracing.SampleRatio)
+
+	if err := errors.Join(append(env.errs, cfg.Validate())...); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// configFile: CONFIG_FILE phải tồn tại; file mặc định thì có mới đọc
+func configFile() (string, error) {
+	if p := os.Getenv("CONFIG_FILE"); p != "" {
+		if _, err := os.Stat(p); err != nil {
+			return "", fmt.Errorf("CONFIG_FILE: %w", err)
+		}
+		return p, nil
+	}
+	if _, err := os.Stat(DefaultConfigFile); err == nil {
+		return DefaultConfigFile, nil
+	}
+	return "", nil
+}
+
+// decodeConfigFile: key lạ (gõ sai) là lỗi, không bị bỏ qua im lặng
+func decodeConfigFile(path string, cfg *AppConfig) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// Validate: mọi lỗi gom lại, mỗi lỗi nêu tên key
+func (c AppConfig) Validate() error {
+	var errs []error
+	bad := func(key, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: "+format, append([]any{key}, args...)...))
+	}
+
+	if n, err := strconv.Atoi(c.Port); err != nil || n < 1 || n > 65535 {
+		bad("port", "want a TCP port, got %q", c.Port)
+	}
+	if c.ShutdownDelay < 0 {
+		bad("shutdown_delay", "must not be negative, got %s", c.ShutdownDelay)
+	}
+	if c.Storage != StoragePostgres && c.Storage != StorageMemory {
+		bad("storage", "want %s or %s, got %q", StoragePostgres, StorageMemory, c.Storage)
+	}
+	if err := validateOrigins(c.AllowOrigin); err != nil {
+		bad("cors_origins", "%v", err)
+	}
+
+	var lv slog.Level
+	if err := lv.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		bad("log.level", "want debug, info, warn or error, got %q", c.Log.Level)
+	}
+	if c.Log.Format != LogFormatJSON && c.Log.Format != LogFormatText {
+		bad("log.format", "want %s or %s, got %q", LogFormatJSON, LogFormatText, c.Log.Format)
+	}
+	if c.Log.SampleInitial < 0 || c.Log.SampleThereafter < 0 {
+		bad("log.sample_initial/sample_thereafter", "must not be negative")
+	}
+
+	for _, d := range []struct {
+		key string
+		val time.Duration
+	}{
+		{"http_drain_timeout", c.HTTPDrain},
+		{"export_link_ttl", c.ExportLinkTTL},
+		{"export_drain_timeout", c.ExportDrain},
+		{"scheduler_drain_timeout", c.SchedulerDrain},
+		{"job_idle_after", c.JobIdleAfter},
+		{"job_run_retention", c.JobRunRetention},
+		{"fleet_metrics_ttl", c.FleetMetricsTTL},
+	} {
+		if d.val <= 0 {
+			bad(d.key, "want a positive duration, got %s", d.val)
+		}
+	}
+
+	known := defaultConfig().JobSchedules
+	for _, job := range sortedKeys(c.JobSchedules) {
+		spec := c.JobSchedules[job]
+		if _, ok := known[job]; !ok {
+			bad("job_schedules."+job, "unknown job (want one of %s)", strings.Join(sortedKeys(known), ", "))
+			continue
+		}
+		if _, err := cron.ParseStandard(spec); err != nil {
+			bad("job_schedules."+job, "%v", err)
+		}
+	}
+
+	if c.MetricsExporter != MetricsExportNone && c.MetricsExporter != MetricsExportOTLP {
+		bad("metrics_exporter", "want %s or %s, got %q", MetricsExportNone, MetricsExportOTLP, c.MetricsExporter)
+	}
+	// exporter lạ không chặn khởi động (InitTracing cảnh báo và tắt tracing), tỉ lệ sai thì có
+	if r := c.Tracing.SampleRatio; r < 0 || r > 1 {
+		bad("tracing.sample_ratio", "want a ratio between 0 and 1, got %g", r)
+	}
+	return errors.Join(errs...)
+}
+
+// validateOrigins: "*" đứng một mình, hoặc danh sách scheme://host[:port] (gin-contrib/cors panic nếu sai)
+func validateOrigins(origins []string) error {
+	if len(origins) == 0 {
+		return errors.New(`want "*" or a list of origins`)
+	}
+	for _, o := range origins {
+		if o == "*" {
+			if len(origins) > 1 {
+				return errors.New(`"*" must be the only origin`)
+			}
+			continue
+		}
+		u, err := url.Parse(o)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			return fmt.Errorf("origin %q: want scheme://host[:port]", o)
+		}
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// defaultTracingExporter: giữ hành vi cũ khi đã cấu hình endpoint OTLP, còn lại tắt
+func defaultTracingExporter() string {
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != "" {
+		return TracingOTLPHTTP
+	}
+	return TracingNone
+}
+
+// ===== Env override =====
+
+// envLoader: chỉ ghi đè khi biến có giá trị; lỗi parse gom lại cho ReadConfig
+type envLoader struct {
+	errs []error
+}
+
+// lookup: K có giá trị thì dùng K, không thì đọc file ở K_FILE (bỏ newline cuối)
+func (l *envLoader) lookup(k string) (string, bool) {
+	if v := os.Getenv(k); v != "" {
+		return v, true
+	}
+	p := os.Getenv(k + "_FILE")
+	if p == "" {
+		return "", false
+	}
+	b, err := os.ReadFile(p)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("%s_FILE: %w", k, err))
+		return "", false
+	}
+	return strings.TrimRight(string(b), "\r\n"), true
+}
+
+func (l *envLoader) str(k string, dst *string) {
+	if v, ok := l.lookup(k); ok {
+		*dst = v
+	}
+}
+
+func (l *envLoader) list(k string, dst *[]string) {
+	if v, ok := l.lookup(k); ok {
+		var out []string
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				out = append(out, s)
+			}
+		}
+		*dst = out
+	}
+}
+
+func (l *envLoader) bool(k string, dst *bool) {
+	if v, ok := l.lookup(k); ok {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			l.errs = append(l.errs, fmt.Errorf("%s=%q: want true or false", k, v))
+			return
+		}
+		*dst = b
+	}
+}
+
+func (l *envLoader) int(k string, dst *int) {
+	if v, ok := l.lookup(k); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			l.errs = append(l.errs, fmt.Errorf("%s=%q: want an integer like %d", k, v, *dst))
+			return
+		}
+		*dst = n
+	}
+}
+
+func (l *envLoader) duration(k string, dst *time.Duration) {
+	if v, ok := l.lookup(k); ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			l.errs = append(l.errs, fmt.Errorf("%s=%q: want a duration like %s", k, v, *dst))
+			return
+		}
+		*dst = d
+	}
+}
+
+func (l *envLoader) ratio(k string, dst *float64) {
+	if v, ok := l.lookup(k); ok {
+		r, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			l.errs = append(l.errs, fmt.Errorf("%s=%q: want a ratio between 0 and 1", k, v))
+			return
+		}
+		*dst = r
+	}
+}
@@ -0,0 +1,288 @@
+package bootstrap
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// validConfig: mặc định + storage memory (không cần DATABASE_URL)
+func validConfig() AppConfig {
+	cfg := defaultConfig()
+	cfg.Storage = StorageMemory
+	return cfg
+}
+
+func TestValidate(t *testing.T) {
+	if err := validConfig().Validate(); err != nil {
+		t.Fatalf("default config: %v", err)
+	}
+	cases := []struct {
+		key    string
+		mutate func(c *AppConfig)
+	}{
+		{"port", func(c *AppConfig) { c.Port = "http" }},
+		{"port", func(c *AppConfig) { c.Port = "70000" }},
+		{"shutdown_delay", func(c *AppConfig) { c.ShutdownDelay = -time.Second }},
+		{"storage", func(c *AppConfig) { c.Storage = "mysql" }},
+		{"cors_origins", func(c *AppConfig) { c.AllowOrigin = []string{"*", "https://a.example"} }},
+		{"cors_origins", func(c *AppConfig) { c.AllowOrigin = []string{"a.example"} }},
+		{"log.level", func(c *AppConfig) { c.Log.Level = "verbose" }},
+		{"log.format", func(c *AppConfig) { c.Log.Format = "xml" }},
+		{"http_drain_timeout", func(c *AppConfig) { c.HTTPDrain = 0 }},
+		{"job_idle_after", func(c *AppConfig) { c.JobIdleAfter = -time.Hour }},
+		{"job_schedules.nightly", func(c *AppConfig) { c.JobSchedules = map[string]string{"nightly": "@daily"} }},
+		{"job_schedules.purge", func(c *AppConfig) { c.JobSchedules = map[string]string{"purge": "every day"} }},
+		{"metrics_exporter", func(c *AppConfig) { c.MetricsExporter = "prometheus" }},
+		{"tracing.sample_ratio", func(c *AppConfig) { c.Tracing.SampleRatio = 1.5 }},
+	}
+	for _, tc := range cases {
+		t.Run(tc.key, func(t *testing.T) {
+			cfg := validConfig()
+			tc.mutate(&cfg)
+			err := cfg.Validate()
+			if err == nil || !strings.Contains(err.Error(), tc.key+":") {
+				t.Fatalf("err = %v, want error for %s", err, tc.key)
+			}
+		})
+	}
+
+	t.Run("all errors at once", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.Port, cfg.Log.Format = "x", "xml"
+		err := cfg.Validate()
+		if err == nil || !strings.Contains(err.Error(), "port:") || !strings.Contains(err.Error(), "log.format:") {
+			t.Fatalf("err = %v, want both port and log.format", err)
+		}
+	})
+}
+
+func TestEnvLoader(t *testing.T) {
+	t.Setenv("T_STR", "abc")
+	t.Setenv("T_LIST", " a, ,b ")
+	t.Setenv("T_BOOL", "true")
+	t.Setenv("T_INT", "42")
+	t.Setenv("T_DUR", "90s")
+	t.Setenv("T_RATIO", "0.25")
+	t.Setenv("T_EMPTY", "")
+
+	var (
+		env   envLoader
+		str   = "keep"
+		list  []string
+		b     bool
+		n     int
+		d     time.Duration
+		r     float64
+		empty = "keep"
+		unset = "keep"
+	)
+	env.str("T_STR", &str)
+	env.list("T_LIST", &list)
+	env.bool("T_BOOL", &b)
+	env.int("T_INT", &n)
+	env.duration("T_DUR", &d)
+	env.ratio("T_RATIO", &r)
+	env.str("T_EMPTY", &empty) // rỗng = không ghi đè
+	env.str("T_UNSET", &unset)
+	if len(env.errs) != 0 {
+		t.Fatalf("errs = %v", env.errs)
+	}
+	if str != "abc" || !slices.Equal(list, []string{"a", "b"}) || !b || n != 42 || d != 90*time.Second || r != 0.25 {
+		t.Errorf("got str=%q list=%q bool=%v int=%d dur=%s ratio=%g", str, list, b, n, d, r)
+	}
+	if empty != "keep" || unset != "keep" {
+		t.Errorf("empty/unset overwrote: %q %q", empty, unset)
+	}
+
+	t.Run("parse errors are collected and keep the old value", func(t *testing.T) {
+		t.Setenv("T_BOOL", "yes please")
+		t.Setenv("T_INT", "4x")
+		t.Setenv("T_DUR", "5")
+		var env envLoader
+		n, d := 7, time.Minute
+		env.bool("T_BOOL", new(bool))
+		env.int("T_INT", &n)
+		env.duration("T_DUR", &d)
+		if len(env.errs) != 3 {
+			t.Fatalf("errs = %v, want 3", env.errs)
+		}
+		if n != 7 || d != time.Minute {
+			t.Errorf("values changed on error: %d %s", n, d)
+		}
+		if !strings.Contains(env.errs[1].Error(), "T_INT") {
+			t.Errorf("error does not name the variable: %v", env.errs[1])
+		}
+	})
+}
+
+func TestEnvLoaderFile(t *testing.T) {
+	secret := filepath.Join(t.TempDir(), "jwt")
+	if err := os.WriteFile(secret, []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("reads and trims K_FILE", func(t *testing.T) {
+		t.Setenv("T_SECRET_FILE", secret)
+		var env envLoader
+		var v string
+		env.str("T_SECRET", &v)
+		if v != "s3cret" || len(env.errs) != 0 {
+			t.Fatalf("v = %q, errs = %v", v, env.errs)
+		}
+	})
+	t.Run("K wins over K_FILE", func(t *testing.T) {
+		t.Setenv("T_SECRET", "direct")
+		t.Setenv("T_SECRET_FILE", secret)
+		var env envLoader
+		var v string
+		env.str("T_SECRET", &v)
+		if v != "direct" {
+			t.Fatalf("v = %q, want direct", v)
+		}
+	})
+	t.Run("missing file is an error", func(t *testing.T) {
+		t.Setenv("T_SECRET_FILE", filepath.Join(t.TempDir(), "nope"))
+		var env envLoader
+		v := "keep"
+		env.str("T_SECRET", &v)
+		if len(env.errs) != 1 || !strings.Contains(env.errs[0].Error(), "T_SECRET_FILE") || v != "keep" {
+			t.Fatalf("v = %q, errs = %v", v, env.errs)
+		}
+	})
+}
+
+// writeConfig: CONFIG_FILE trỏ tới file YAML tạm
+func writeConfig(t *testing.T, yaml string) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(p, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_FILE", p)
+	return p
+}
+
+// Mặc định < YAML < env; key YAML lạ là lỗi.
+func TestReadConfigPrecedence(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "")
+	t.Setenv("STORAGE", "")
+	p := writeConfig(t, "storage: memory\nlog:\n  level: warn\n")
+
+	cfg, err := ReadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Log.Level != "warn" || cfg.Log.Format != LogFormatJSON || cfg.ConfigFile != p {
+		t.Fatalf("yaml: level=%q format=%q file=%q", cfg.Log.Level, cfg.Log.Format, cfg.ConfigFile)
+	}
+
+	t.Setenv("LOG_LEVEL", "error")
+	if cfg, _ = ReadConfig(); cfg.Log.Level != "error" {
+		t.Fatalf("env: level = %q, want error", cfg.Log.Level)
+	}
+
+	writeConfig(t, "storage: memory\nlog_level: debug\n")
+	if _, err := ReadConfig(); err == nil || !strings.Contains(err.Error(), "log_level") {
+		t.Fatalf("unknown key: err = %v", err)
+	}
+}
+
+// .env không ghi đè biến đã có trong môi trường thật.
+func TestLoadEnvFirstDoesNotOverride(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "configs"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	env := "T_DOTENV_SET=from-file\nT_DOTENV_NEW=from-file\n"
+	if err := os.WriteFile(filepath.Join(dir, "configs", ".env"), []byte(env), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+	t.Setenv("T_DOTENV_SET", "from-env")
+	t.Setenv("T_DOTENV_NEW", "") // đăng ký khôi phục sau test
+	os.Unsetenv("T_DOTENV_NEW")
+
+	LoadEnvFirst()
+	if got := os.Getenv("T_DOTENV_SET"); got != "from-env" {
+		t.Errorf("T_DOTENV_SET = %q, want from-env (environment wins)", got)
+	}
+	if got := os.Getenv("T_DOTENV_NEW"); got != "from-file" {
+		t.Errorf("T_DOTENV_NEW = %q, want from-file", got)
+	}
+}
+
+func TestConfigDiff(t *testing.T) {
+	a := validConfig()
+	b := validConfig()
+	if d := configDiff(a, b); len(d) != 0 {
+		t.Fatalf("equal configs: diff = %v", d)
+	}
+	b.Port = "9090"
+	b.Log.Level = "debug"
+	b.AllowOrigin = []string{"https://a.example"}
+	b.JobSchedules = map[string]string{"purge": "@daily"}
+	b.ConfigFile = "other.yaml" // yaml:"-": không tính
+	want := []string{"port", "cors_origins", "log.level", "job_schedules"}
+	if d := configDiff(a, b); !slices.Equal(d, want) {
+		t.Fatalf("diff = %v, want %v", d, want)
+	}
+}
+
+// testApp: App thật trên storage memory, level log điều khiển được
+func testApp(t *testing.T, cfg AppConfig) *App {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	cfg.ExportDir = t.TempDir()
+	st, err := OpenStorage(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(st.Close)
+	lv := new(slog.LevelVar)
+	app, err := Build(cfg, st, &Logging{Logger: slog.New(slog.NewTextHandler(io.Discard, nil)), level: lv})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return app
+}
+
+func TestReload(t *testing.T) {
+	app := testApp(t, validConfig())
+
+	next := app.cfg
+	next.Log.Level = "debug"
+	next.JobIdleAfter = 2 * time.Hour
+	next.Port = "9090"
+	applied, restart := app.Reload(next)
+	if !slices.Equal(applied, []string{"log.level", "job_idle_after"}) || !slices.Equal(restart, []string{"port"}) {
+		t.Fatalf("applied = %v, restart = %v", applied, restart)
+	}
+	if app.logging.Level() != "debug" {
+		t.Errorf("log level = %q, want debug", app.logging.Level())
+	}
+	if app.cfg.JobIdleAfter != 2*time.Hour || app.cfg.Port != "8080" {
+		t.Errorf("cfg after reload: idle=%s port=%s", app.cfg.JobIdleAfter, app.cfg.Port)
+	}
+
+	// key cần restart không được ghi nhận: vẫn cảnh báo; key đã áp không lặp lại
+	applied, restart = app.Reload(next)
+	if len(applied) != 0 || !slices.Equal(restart, []string{"port"}) {
+		t.Fatalf("second reload: applied = %v, restart = %v", applied, restart)
+	}
+
+	// level đặt qua /admin/log-level giữ nguyên khi cấu hình không đổi log.level
+	_ = app.logging.SetLevel("warn")
+	next.Port = "8080"
+	if applied, _ = app.Reload(next); len(applied) != 0 || app.logging.Level() != "warn" {
+		t.Fatalf("applied = %v, level = %q", applied, app.logging.Level())
+	}
+}
@@ -0,0 +1,110 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase"
+	"wh-ma/internal/usecase/authz"
+	"wh-ma/internal/usecase/dto"
+)
+
+// DemoTenant: tenant của dữ liệu seed ở chế độ memory
+const DemoTenant = "demo"
+
+// SeedDemo: nạp dữ liệu mẫu qua usecase (đi qua đủ validate/authz như request thật).
+// Key API demo (mọi scope, tenant demo) chỉ in ra log một lần lúc khởi động.
+func SeedDemo(ctx context.Context, st *Storage, policy *authz.Policy) error {
+	az := authz.NewAuthorizer(policy)
+	planUC := usecase.NewPlansUsecase(st.Repos.Plans, az)
+	devUC := usecase.NewDevicesUsecase(st.Repos.Devices, st.Repos.Plans, st.Repos.DeviceAudit, st.Tx, az)
+	readUC := usecase.NewReadingsUsecase(st.Repos.Readings, st.Repos.Devices, st.Tx, az)
+	maintUC := usecase.NewMaintenanceUsecase(st.Repos.Maintenance, st.Repos.Devices, az)
+	keyUC := usecase.NewAPIKeysUsecase(st.Repos.APIKeys, az)
+
+	system := authz.WithPrincipal(ctx, domain.Principal{Subject: "seed", Role: domain.RoleAdmin})
+	demo := authz.WithPrincipal(ctx, domain.Principal{Subject: "seed", TenantID: DemoTenant, Role: domain.RoleAdmin})
+
+	// 1) Plan template dùng chung + một plan riêng của tenant
+	var plans []domain.PlanID
+	for _, p := range []struct {
+		ctx   context.Context
+		name  string
+		hours int
+	}{
+		{system, "Bảo dưỡng 250h", 250},
+		{system, "Bảo dưỡng 500h", 500},
+		{demo, "Đại tu 2000h", 2000},
+	} {
+		pl, err := planUC.Create(p.ctx, dto.CreatePlanCmd{Name: p.name, IntervalHours: p.hours})
+		if err != nil {
+			return err
+		}
+		plans = append(plans, pl.ID)
+	}
+
+	// 2) Device + giờ máy (reading sinh alert maintenance_due khi tới hạn)
+	now := time.Now().UTC()
+	devices := []struct {
+		serial, name, model, maker, site string
+		year                             int
+		plan                             domain.PlanID
+		status                           domain.DeviceStatus
+		hours                            []int
+	}{
+		{"EX-0001", "Máy xúc 01", "PC200-8", "Komatsu", "HN", 2018, plans[0], domain.StatusActive, []int{8, 10, 9, 12, 7}},
+		{"EX-0002", "Máy xúc 02", "320D", "Caterpillar", "HN", 2020, plans[1], domain.StatusActive, []int{6, 8, 8}},
+		{"CR-0001", "Cần cẩu 01", "LTM 1050", "Liebherr", "HCM", 2015, plans[2], domain.StatusMaintenance, []int{4}},
+		{"RL-0001", "Xe lu 01", "BW 211", "Bomag", "HCM", 2012, 0, domain.StatusDecommissioned, nil},
+	}
+	for _, d := range devices {
+		commissioned := time.Date(d.year, time.March, 1, 0, 0, 0, 0, time.UTC)
+		var planID *domain.PlanID
+		if d.plan != 0 {
+			planID = &d.plan
+		}
+		dev, err := devUC.Create(demo, dto.CreateDeviceCmd{
+			SerialNumber:   d.serial,
+			Name:           d.name,
+			Model:          d.model,
+			Manufacturer:   d.maker,
+			Year:           d.year,
+			CommissionDate: &commissioned,
+			Status:         d.status,
+			Location:       &d.site,
+			PlanID:         planID,
+		})
+		if err != nil {
+			return err
+		}
+		for i, h := range d.hours {
+			at := now.AddDate(0, 0, i-len(d.hours))
+			if _, err := readUC.Submit(demo, dto.SubmitReadingCmd{DeviceID: dev.ID, At: at, HoursDelta: h, Location: &d.site}); err != nil {
+				return err
+			}
+		}
+		if d.status == domain.StatusMaintenance {
+			notes, cost := "Thay dầu thủy lực", "1500000.00"
+			if _, err := maintUC.Log(demo, dto.LogMaintenanceCmd{DeviceID: dev.ID, At: now.AddDate(0, 0, -30), Notes: &notes, Cost: &cost}); err != nil {
+				return err
+			}
+		}
+	}
+
+	// 3) API key demo cho frontend: mọi scope trong tenant demo
+	scopes := make([]string, 0, len(authz.AllActions))
+	for _, a := range authz.AllActions {
+		scopes = append(scopes, string(a))
+	}
+	key, err := keyUC.Create(demo, dto.CreateAPIKeyCmd{Name: "demo", Scopes: scopes})
+	if err != nil {
+		return err
+	}
+	// in thẳng ra stderr: logger che mọi API key, riêng key demo cần hiện cho người chạy thử
+	fmt.Fprintf(os.Stderr, "storage=memory: seeded tenant %q with %d plans, %d devices; demo API key: %s\n",
+		DemoTenant, len(plans), len(devices), key.Secret)
+	return nil
+}
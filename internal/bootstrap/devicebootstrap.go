@@ -2,59 +2,36 @@ package bootstrap
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"log/slog"
-	"net/http"
-	"os"
-	"os/signal"
-	"strings"
-	"syscall"
 	"time"
 
 	"github.com/exaring/otelpgx"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"wh-ma/internal/adapter/inbound/http/handler"
+	"wh-ma/internal/adapter/inbound/http/health"
+	"wh-ma/internal/adapter/inbound/http/metrics"
+	"wh-ma/internal/adapter/inbound/http/middleware"
+	"wh-ma/internal/adapter/inbound/http/openapi"
+	"wh-ma/internal/adapter/inbound/http/response"
 	"wh-ma/internal/adapter/inbound/http/router"
-	outrepo "wh-ma/internal/adapter/outbound/repository"
+	inport "wh-ma/internal/adapter/inbound/port"
+	"wh-ma/internal/adapter/inbound/scheduler"
+	"wh-ma/internal/adapter/inbound/tabular"
+	"wh-ma/internal/adapter/outbound/exportstore"
+	"wh-ma/internal/adapter/outbound/migration"
+	outport "wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/adapter/outbound/traced"
 	"wh-ma/internal/usecase"
+	"wh-ma/internal/usecase/authz"
+	"wh-ma/internal/usecase/tracing"
 )
 
-// ===== Config =====
-
-type AppConfig struct {
-	AppEnv      string
-	Port        string
-	DatabaseURL string
-	AllowOrigin []string
-	LogLevel    string
-}
-
-func LoadConfig() AppConfig {
-	LoadEnvFirst()
-	cfg := AppConfig{
-		AppEnv:      getenv("APP_ENV", "development"),
-		Port:        getenv("PORT", "8080"),
-		DatabaseURL: getenv("DATABASE_URL", ""),
-		LogLevel:    getenv("LOG_LEVEL", "info"),
-	}
-	origins := getenv("CORS_ORIGINS", "*")
-	if origins == "" {
-		cfg.AllowOrigin = []string{"*"}
-	} else {
-		cfg.AllowOrigin = strings.Split(origins, ",")
-	}
-	return cfg
-}
-
-func getenv(k, def string) string {
-	if v := os.Getenv(k); v != "" {
-		return v
-	}
-	return def
-}
-
 // ===== DB Pool =====
 
 func NewPGXPool(ctx context.Context, dbURL string) (*pgxpool.Pool, error) {
@@ -63,6 +40,7 @@ func NewPGXPool(ctx context.Context, dbURL string) (*pgxpool.Pool, error) {
 		return nil, err
 	}
 	cfg.ConnConfig.Tracer = otelpgx.NewTracer()
+	cfg.BeforeAcquire = bindTenant
 
 	pool, err := pgxpool.NewWithConfig(ctx, cfg)
 	if err != nil {
@@ -79,61 +57,187 @@ func NewPGXPool(ctx context.Context, dbURL string) (*pgxpool.Pool, error) {
 
 // ===== HTTP wiring (router layer định nghĩa endpoints) =====
 
-func BuildRouter(cfg AppConfig, pool *pgxpool.Pool, baseLogger *slog.Logger) *gin.Engine {
-	// 1) Repos
-	devRepo := outrepo.NewDeviceRepository(pool)
-	planRepo := outrepo.NewPlanRepository(pool)
-	alertRepo := outrepo.NewAlertRepository(pool)
+// App: router + scheduler job nền (server chạy cả hai, CLI chỉ dùng router)
+type App struct {
+	Router    *gin.Engine
+	Scheduler *scheduler.Scheduler
+	Health    *health.Registry
+	jobs      inport.JobsInbound
+	exports   *usecase.ExportsUsecase
+	cfg       AppConfig
+
+	// phần reload được (xem Reload)
+	logging *Logging
+	cors    *middleware.CORS
+	fleet   *usecase.FleetJobs
+}
+
+// StartScheduler: chạy scheduler tới khi ctx bị hủy; channel đóng khi job đã drain xong
+func (a *App) StartScheduler(ctx context.Context) <-chan struct{} {
+	done := make(chan struct{})
+	if !a.cfg.SchedulerEnabled {
+		log.Printf("scheduler disabled (SCHEDULER_ENABLED=false)")
+		close(done)
+		return done
+	}
+	// dispatcher treo (kiểm leader không trả lời...) -> replica not-ready
+	a.Health.Register(health.Heartbeat("scheduler", true, a.Scheduler.Heartbeat, schedulerMaxSilence))
+	go func() {
+		defer close(done)
+		a.Scheduler.Run(ctx, a.jobs)
+	}()
+	return done
+}
+
+// BuildRouter: chỉ phần HTTP (whma chạy in-process không cần scheduler)
+func BuildRouter(cfg AppConfig, st *Storage, baseLogger *slog.Logger) (*gin.Engine, error) {
+	app, err := Build(cfg, st, &Logging{Logger: baseLogger, level: new(slog.LevelVar)})
+	if err != nil {
+		return nil, err
+	}
+	return app.Router, nil
+}
+
+// Build: lg nil = logger dựng từ cfg.Log. Lỗi cấu hình (policy, spec lệch route...) trả về cho caller.
+func Build(cfg AppConfig, st *Storage, lg *Logging) (*App, error) {
+	if lg == nil {
+		lg = NewLogging(cfg.Log, nil)
+	}
+	baseLogger := lg.Logger
+	// 0) AuthZ policy (configs/rbac.yaml) + OpenAPI spec (embed)
+	policy, err := LoadPolicy(cfg)
+	if err != nil {
+		return nil, err
+	}
+	az := authz.NewAuthorizer(policy)
+	spec, err := openapi.Load()
+	if err != nil {
+		return nil, err
+	}
+	var schema uint // memory: không có schema để kiểm
+	if st.Pool != nil {
+		if schema, err = migration.Expected(); err != nil {
+			return nil, err
+		}
+	}
+
+	// 1) Repos + unit of work cho thao tác nhiều bảng (postgres hoặc memory, xem OpenStorage),
+	//    mỗi lời gọi repository là một span (traced)
+	repos, tx := traced.Repos(st.Repos), traced.TxManager(st.Tx)
 
 	// 2) Usecases
-	devUC := usecase.NewDevicesUsecase(devRepo, planRepo, alertRepo)
+	devUC := usecase.NewDevicesUsecase(repos.Devices, repos.Plans, repos.DeviceAudit, tx, az)
+	planUC := usecase.NewPlansUsecase(repos.Plans, az)
+	readUC := usecase.NewReadingsUsecase(repos.Readings, repos.Devices, tx, az)
+	maintUC := usecase.NewMaintenanceUsecase(repos.Maintenance, repos.Devices, az)
+	alertUC := usecase.NewAlertsUsecase(repos.Alerts, repos.Devices, az)
+	keyUC := usecase.NewAPIKeysUsecase(repos.APIKeys, az)
+	exportStore, err := exportstore.New(cfg.ExportDir)
+	if err != nil {
+		return nil, err
+	}
+	signKey := cfg.ExportSigningKey
+	if signKey == "" {
+		signKey = cfg.JWTSecret
+	}
+	exportUC := usecase.NewExportsUsecase(repos, exportStore, tabular.Encoder{}, az, usecase.ExportsConfig{
+		LinkTTL:    cfg.ExportLinkTTL,
+		SigningKey: []byte(signKey),
+	})
+	fleet := usecase.NewFleetJobs(repos, tx, exportStore, fleetJobsConfig(cfg))
+	sch, err := newScheduler(cfg, fleet, st.Leader, baseLogger)
+	if err != nil {
+		return nil, err
+	}
+	jobsUC := tracing.Jobs(usecase.NewJobsUsecase(repos.JobRuns, sch, az))
+	logUC := usecase.NewLoggingUsecase(lg, az, baseLogger)
+	fleetUC := tracing.FleetStats(usecase.NewFleetStatsUsecase(repos.Devices, repos.FleetStats))
+	registerCollector(metrics.NewFleetCollector(fleetUC, cfg.FleetMetricsTTL, baseLogger))
+	if st.Pool != nil {
+		registerCollector(metrics.NewPoolCollector(st.Pool))
+	}
 
-	// 3) Handlers
-	devH := handler.NewDevicesHandler(devUC)
+	// 3) Handlers (usecase bọc span: con của span HTTP)
+	devH := handler.NewDevicesHandler(tracing.Devices(devUC))
+	planH := handler.NewPlansHandler(tracing.Plans(planUC))
+	readH := handler.NewReadingsHandler(tracing.Readings(readUC))
+	maintH := handler.NewMaintenanceHandler(tracing.Maintenance(maintUC))
+	alertH := handler.NewAlertsHandler(tracing.Alerts(alertUC))
+	keyH := handler.NewAPIKeysHandler(tracing.APIKeys(keyUC))
+	exportH := handler.NewExportsHandler(tracing.Exports(exportUC))
+	jobsH := handler.NewJobsHandler(jobsUC)
+	logH := handler.NewLoggingHandler(tracing.Logging(logUC))
 
 	// 4) Router gốc (đã gắn Recovery, RequestID, Logger, CORS, Prometheus, healthz/readiness, /metrics)
-	r := router.New(pool, baseLogger, router.Options{
-		AppEnv:      cfg.AppEnv,
-		AllowOrigin: cfg.AllowOrigin,
+	hr := newHealthRegistry(cfg, st, schema)
+	cors := middleware.NewCORS(cfg.AllowOrigin)
+	r := router.New(baseLogger, router.Options{
+		AppEnv:    cfg.AppEnv,
+		CORS:      cors,
+		JWTSecret: []byte(cfg.JWTSecret),
+		APIKeys:   keyUC,
+		OpenAPI:   spec,
+		Health:    hr,
 	})
 
-	// 5) Mount modules vào /api
-	api := r.Group("/api")
-	router.MountDevices(api, devH)
+	// 5) Mount modules: /api/v1 là hợp đồng chính thức (response.Version);
+	//    /api giữ làm alias cho client cũ, trả kèm header Deprecation.
+	//    Request được validate theo openapi.yaml trước khi vào handler.
+	mount := func(api *gin.RouterGroup) {
+		router.MountDevices(api, devH)
+		router.MountPlans(api, planH)
+		router.MountReadings(api, readH)
+		router.MountMaintenance(api, maintH)
+		router.MountAlerts(api, alertH)
+		router.MountAPIKeys(api, keyH)
+		router.MountExports(api, exportH)
+		router.MountJobs(api, jobsH)
+		router.MountLogging(api, logH)
+	}
+	mount(r.Group(openapi.V1Prefix, middleware.APIVersion(response.Version, ""), spec.Validator()))
+	mount(r.Group(openapi.LegacyPrefix, middleware.APIVersion(response.Version, openapi.V1Prefix), spec.Validator()))
 
-	return r
+	// 6) Spec không được drift khỏi route thực tế
+	if err := spec.VerifyRoutes(r.Routes()); err != nil {
+		return nil, err
+	}
+
+	return &App{Router: r, Scheduler: sch, Health: hr, jobs: jobsUC, exports: exportUC, cfg: cfg,
+		logging: lg, cors: cors, fleet: fleet}, nil
 }
 
-// RunHTTP: chạy server với graceful shutdown
-func RunHTTP(r *gin.Engine, port string) error {
-	srv := &http.Server{
-		Addr:              ":" + port,
-		Handler:           r,
-		ReadHeaderTimeout: 10 * time.Second,
+// registerCollector: registry mặc định (/metrics); Build gọi lại (CLI, test) thì giữ collector đã đăng ký
+func registerCollector(c prometheus.Collector) {
+	if err := prometheus.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if !errors.As(err, &are) {
+			log.Fatalf("register collector: %v", err)
+		}
 	}
+}
 
-	// Start server
-	errCh := make(chan error, 1)
-	go func() {
-		log.Printf("HTTP listening on :%s", port)
-		errCh <- srv.ListenAndServe()
-	}()
+// newScheduler: danh sách job nền; lịch theo giờ local của process,
+// đổi từng job bằng job_schedules trong file cấu hình hoặc JOB_<TÊN>_SCHEDULE (vd JOB_IDLE_DETECTION_SCHEDULE="@every 30m")
+func newScheduler(cfg AppConfig, fleet *usecase.FleetJobs, leader outport.LeaderLock, logger *slog.Logger) (*scheduler.Scheduler, error) {
+	return scheduler.New([]scheduler.Job{
+		{Name: "forecast_recompute", Schedule: cfg.JobSchedules["forecast_recompute"], Timeout: 30 * time.Minute, Run: fleet.RecomputeForecasts},
+		{Name: "idle_detection", Schedule: cfg.JobSchedules["idle_detection"], Timeout: 15 * time.Minute, Run: fleet.DetectIdle},
+		{Name: "purge", Schedule: cfg.JobSchedules["purge"], Timeout: 10 * time.Minute, Run: fleet.Purge},
+	}, leader, scheduler.Options{Drain: cfg.SchedulerDrain, Logger: logger})
+}
 
-	// Wait signal
-	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+func fleetJobsConfig(cfg AppConfig) usecase.FleetJobsConfig {
+	return usecase.FleetJobsConfig{IdleAfter: cfg.JobIdleAfter, RunRetention: cfg.JobRunRetention}
+}
 
-	select {
-	case sig := <-stop:
-		log.Printf("shutdown signal: %s", sig)
-	case err := <-errCh:
-		if err != nil && err != http.ErrServerClosed {
-			return err
-		}
+// LoadPolicy: đọc RBAC policy từ file cấu hình (trống = policy mặc định)
+func LoadPolicy(cfg AppConfig) (*authz.Policy, error) {
+	if cfg.RBACPolicyFile == "" {
+		return authz.DefaultPolicy(), nil
 	}
-
-	// Graceful shutdown
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	return srv.Shutdown(ctx)
+	p, err := authz.LoadPolicy(cfg.RBACPolicyFile)
+	if err != nil {
+		return nil, fmt.Errorf("rbac: %w", err)
+	}
+	return p, nil
 }
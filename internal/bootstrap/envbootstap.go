@@ -6,16 +6,16 @@ import (
 	"github.com/joho/godotenv"
 )
 
-// ưu tiên configs/.env; nếu không có thì thử .env ở root.
-// Overload để giá trị trong file .env ghi đè môi trường local khi dev.
-// (Trong production, bạn nên để biến env từ hệ thống/compose và bỏ Overload)
+// LoadEnvFirst: nạp configs/.env (không có thì .env ở root) vào môi trường của process.
+// Load không ghi đè: biến đã có trong môi trường thật (compose, k8s, shell) luôn thắng file .env.
+// Thứ tự ưu tiên cuối cùng (sau thắng trước): mặc định < file YAML < .env < môi trường thật —
+// vì vậy .env chỉ nên chứa secret/kết nối, không chứa key đã cấu hình trong YAML (LOG_LEVEL...).
 func LoadEnvFirst() {
-	// nếu đã có biến APP_ENV từ môi trường, vẫn cho phép .env ghi đè khi dev
 	if _, err := os.Stat("configs/.env"); err == nil {
-		_ = godotenv.Overload("configs/.env")
+		_ = godotenv.Load("configs/.env")
 		return
 	}
 	if _, err := os.Stat(".env"); err == nil {
-		_ = godotenv.Overload(".env")
+		_ = godotenv.Load(".env")
 	}
 }
@@ -0,0 +1,74 @@
+package bootstrap
+
+import (
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"wh-ma/internal/adapter/inbound/http/health"
+)
+
+// ===== Health registry =====
+
+const (
+	// poolSaturation: tỉ lệ kết nối bị giữ để báo db_pool degraded
+	poolSaturation = 0.9
+	// schedulerMaxSilence: vòng lặp scheduler chạy mỗi giây; im lâu hơn = dispatcher bị treo
+	schedulerMaxSilence = 30 * time.Second
+)
+
+// newHealthRegistry: check của storage và telemetry; scheduler tự đăng ký khi StartScheduler chạy,
+// lifecycle đăng ký check báo down khi process bắt đầu dừng
+func newHealthRegistry(cfg AppConfig, st *Storage, schema uint) *health.Registry {
+	reg := health.NewRegistry()
+	if st.Pool == nil {
+		reg.Register(health.Static("storage", "memory"))
+	} else {
+		reg.Register(health.DBPing(st.Pool))
+		reg.Register(health.PoolSaturation(st.Pool, poolSaturation))
+		reg.Register(health.SchemaVersion(st.Pool, schema))
+	}
+	if addr := otlpAddr(cfg.Tracing.Exporter, "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"); addr != "" {
+		reg.Register(health.TCPDial("trace_exporter", addr))
+	}
+	if cfg.MetricsExporter == MetricsExportOTLP {
+		if addr := otlpAddr(TracingOTLPHTTP, "OTEL_EXPORTER_OTLP_METRICS_ENDPOINT"); addr != "" {
+			reg.Register(health.TCPDial("metrics_exporter", addr))
+		}
+	}
+	return reg
+}
+
+// otlpAddr: host:port collector mà exporter OTLP sẽ gửi tới (cùng thứ tự ưu tiên env như SDK);
+// "" khi exporter không dùng mạng
+func otlpAddr(exporter, signalEnv string) string {
+	port := ""
+	switch exporter {
+	case TracingOTLPHTTP:
+		port = "4318"
+	case TracingOTLPGRPC:
+		port = "4317"
+	default:
+		return ""
+	}
+	endpoint := os.Getenv(signalEnv)
+	if endpoint == "" {
+		endpoint = os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	}
+	if endpoint == "" {
+		return net.JoinHostPort("localhost", port)
+	}
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "http://" + endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Hostname() == "" {
+		return ""
+	}
+	if u.Port() != "" {
+		port = u.Port()
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
@@ -0,0 +1,266 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"wh-ma/internal/adapter/inbound/http/health"
+)
+
+// ===== Lifecycle =====
+
+// DefaultComponentDrain: hạn Stop của component không khai báo Drain
+const DefaultComponentDrain = 10 * time.Second
+
+// Component: một phần chạy lâu của process (HTTP, scheduler, watcher, storage...).
+type Component struct {
+	Name string
+	// Start: khởi động rồi trả về, không chặn. Lỗi lúc chạy (vd. listener chết) báo qua fail:
+	// cả process dừng có thứ tự như khi nhận signal. nil = không có gì để khởi động.
+	Start func(ctx context.Context, fail func(error)) error
+	// Stop: dừng và drain; ctx hết hạn sau Drain. nil = không có gì để dừng.
+	Stop  func(ctx context.Context) error
+	Drain time.Duration // 0 = DefaultComponentDrain
+}
+
+type LifecycleOptions struct {
+	// ShutdownDelay: giữa lúc /readiness báo down và lúc dừng component đầu tiên, để k8s/Caddy
+	// kịp bỏ replica khỏi vòng trong khi HTTP vẫn phục vụ request đang tới
+	ShutdownDelay time.Duration
+	Logger        *slog.Logger
+}
+
+// Lifecycle: khởi động component theo thứ tự Add, dừng theo thứ tự ngược lại khi ctx của Run
+// bị hủy (signal) hoặc một component báo lỗi. Component khởi động lỗi thì các component đã
+// chạy được dừng lại rồi Run trả lỗi.
+type Lifecycle struct {
+	opt      LifecycleOptions
+	comps    []Component
+	draining atomic.Bool
+
+	failOnce sync.Once
+	failed   chan error
+}
+
+func NewLifecycle(opt LifecycleOptions) *Lifecycle {
+	if opt.Logger == nil {
+		opt.Logger = slog.Default()
+	}
+	return &Lifecycle{opt: opt, failed: make(chan error, 1)}
+}
+
+func (l *Lifecycle) Add(cs ...Component) {
+	l.comps = append(l.comps, cs...)
+}
+
+// HealthCheck: critical, down ngay khi bắt đầu dừng (gần như không cache)
+func (l *Lifecycle) HealthCheck() health.Check {
+	return health.Check{
+		Name:     "lifecycle",
+		Critical: true,
+		TTL:      100 * time.Millisecond,
+		Run: func(context.Context) (any, error) {
+			if l.draining.Load() {
+				return nil, errors.New("shutting down")
+			}
+			return nil, nil
+		},
+	}
+}
+
+// Run: chặn tới khi mọi component đã dừng. Trả lỗi khởi động/lúc chạy và lỗi dừng (drain quá hạn...).
+func (l *Lifecycle) Run(ctx context.Context) error {
+	logger := l.opt.Logger
+	// component sống tới lúc bị Stop, không chết theo signal
+	base := context.WithoutCancel(ctx)
+
+	var runErr error
+	started := 0
+	for _, c := range l.comps {
+		if c.Start != nil {
+			if err := c.Start(base, l.fail(c.Name)); err != nil {
+				runErr = fmt.Errorf("start %s: %w", c.Name, err)
+				break
+			}
+		}
+		started++
+		logger.Debug("component started", "component", c.Name)
+	}
+
+	if runErr == nil {
+		select {
+		case <-ctx.Done():
+			logger.Info("shutting down", "reason", context.Cause(ctx))
+		case runErr = <-l.failed:
+			logger.Error("component failed, shutting down", "error", runErr)
+		}
+		l.draining.Store(true)
+		if l.opt.ShutdownDelay > 0 {
+			time.Sleep(l.opt.ShutdownDelay)
+		}
+	}
+
+	errs := []error{runErr}
+	for i := started - 1; i >= 0; i-- {
+		errs = append(errs, l.stop(l.comps[i]))
+	}
+	return errors.Join(errs...)
+}
+
+func (l *Lifecycle) fail(name string) func(error) {
+	return func(err error) {
+		l.failOnce.Do(func() { l.failed <- fmt.Errorf("%s: %w", name, err) })
+	}
+}
+
+func (l *Lifecycle) stop(c Component) error {
+	if c.Stop == nil {
+		return nil
+	}
+	drain := c.Drain
+	if drain <= 0 {
+		drain = DefaultComponentDrain
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), drain)
+	defer cancel()
+
+	start := time.Now()
+	err := c.Stop(ctx)
+	if err != nil {
+		l.opt.Logger.Warn("component stop failed", "component", c.Name, "error", err, "took_ms", time.Since(start).Milliseconds())
+		return fmt.Errorf("stop %s: %w", c.Name, err)
+	}
+	l.opt.Logger.Info("component stopped", "component", c.Name, "took_ms", time.Since(start).Milliseconds())
+	return nil
+}
+
+// ===== Components =====
+
+// HTTPComponent: listen ngay lúc Start (cổng bận là lỗi khởi động); Stop ngừng nhận kết nối
+// mới rồi chờ request đang xử lý xong trong hạn drain. logger nil = slog.Default().
+func HTTPComponent(h http.Handler, port string, drain time.Duration, logger *slog.Logger) Component {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	srv := &http.Server{
+		Addr:              ":" + port,
+		Handler:           h,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return Component{
+		Name:  "http",
+		Drain: drain,
+		Start: func(ctx context.Context, fail func(error)) error {
+			ln, err := net.Listen("tcp", srv.Addr)
+			if err != nil {
+				return err
+			}
+			srv.BaseContext = func(net.Listener) context.Context { return ctx }
+			logger.Info("HTTP listening", "addr", ln.Addr().String())
+			go func() {
+				if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					fail(err)
+				}
+			}()
+			return nil
+		},
+		Stop: srv.Shutdown,
+	}
+}
+
+// SchedulerComponent: job đang chạy được drain theo SCHEDULER_DRAIN_TIMEOUT (scheduler tự hủy job
+// quá hạn), Stop chờ đủ StopTimeout của scheduler (chờ job dừng sau khi hủy + trả quyền leader)
+func (a *App) SchedulerComponent() Component {
+	var (
+		cancel context.CancelFunc
+		done   <-chan struct{}
+	)
+	return Component{
+		Name:  "scheduler",
+		Drain: a.Scheduler.StopTimeout() + time.Second,
+		Start: func(ctx context.Context, _ func(error)) error {
+			ctx, cancel = context.WithCancel(ctx)
+			done = a.StartScheduler(ctx)
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			cancel()
+			return waitDone(ctx, done)
+		},
+	}
+}
+
+// ExportsComponent: không có gì để khởi động; Stop chờ job export nền đang chạy (xem
+// ExportsUsecase.Shutdown). Dừng sau HTTP (không còn job mới) và trước storage.
+func (a *App) ExportsComponent() Component {
+	return Component{
+		Name:  "exports",
+		Drain: a.cfg.ExportDrain,
+		Stop:  a.exports.Shutdown,
+	}
+}
+
+// ConfigWatcherComponent: reload cấu hình (xem WatchConfig)
+func (a *App) ConfigWatcherComponent() Component {
+	var (
+		cancel context.CancelFunc
+		done   = make(chan struct{})
+	)
+	return Component{
+		Name: "config_watcher",
+		Start: func(ctx context.Context, _ func(error)) error {
+			ctx, cancel = context.WithCancel(ctx)
+			go func() {
+				defer close(done)
+				a.WatchConfig(ctx)
+			}()
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			cancel()
+			return waitDone(ctx, done)
+		},
+	}
+}
+
+// StorageComponent: đóng pool sau khi mọi component dùng DB đã dừng (thêm trước chúng).
+// pool.Close chờ kết nối đang mượn được trả; quá hạn thì bỏ chờ, process vẫn thoát.
+func StorageComponent(st *Storage) Component {
+	return Component{
+		Name: "storage",
+		Stop: func(ctx context.Context) error {
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				st.Close()
+			}()
+			return waitDone(ctx, done)
+		},
+	}
+}
+
+// TelemetryComponent: flush span/metric cuối cùng; thêm đầu tiên để dừng sau cùng
+func TelemetryComponent(tr *Tracing, mt *Metrics) Component {
+	return Component{
+		Name: "telemetry",
+		Stop: func(ctx context.Context) error {
+			return errors.Join(tr.Shutdown(ctx), mt.Shutdown(ctx))
+		},
+	}
+}
+
+func waitDone(ctx context.Context, done <-chan struct{}) error {
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("not drained: %w", ctx.Err())
+	}
+}
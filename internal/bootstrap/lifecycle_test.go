@@ -0,0 +1,205 @@
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// journal: thứ tự start/stop của các component giả
+type journal struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (j *journal) add(e string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.events = append(j.events, e)
+}
+
+func (j *journal) get() []string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return slices.Clone(j.events)
+}
+
+func (j *journal) comp(name string) Component {
+	return Component{
+		Name:  name,
+		Start: func(context.Context, func(error)) error { j.add("start " + name); return nil },
+		Stop:  func(context.Context) error { j.add("stop " + name); return nil },
+	}
+}
+
+func newTestLifecycle(delay time.Duration) *Lifecycle {
+	return NewLifecycle(LifecycleOptions{ShutdownDelay: delay, Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+}
+
+// runAsync: Run trong goroutine, trả channel nhận kết quả
+func runAsync(l *Lifecycle, ctx context.Context) <-chan error {
+	done := make(chan error, 1)
+	go func() { done <- l.Run(ctx) }()
+	return done
+}
+
+func wait(t *testing.T, done <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return")
+		return nil
+	}
+}
+
+func TestLifecycleStopsInReverseOrder(t *testing.T) {
+	j := &journal{}
+	l := newTestLifecycle(0)
+	l.Add(j.comp("storage"), Component{Name: "noop"}, j.comp("scheduler"), j.comp("http"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := runAsync(l, ctx)
+	cancel()
+	if err := wait(t, done); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"start storage", "start scheduler", "start http", "stop http", "stop scheduler", "stop storage"}
+	if got := j.get(); !slices.Equal(got, want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+}
+
+// Component khởi động lỗi: chỉ component đã chạy được dừng (ngược thứ tự), Run trả lỗi ngay.
+func TestLifecycleStartErrorStopsStarted(t *testing.T) {
+	j := &journal{}
+	boom := errors.New("port in use")
+	bad := j.comp("http")
+	bad.Start = func(context.Context, func(error)) error { j.add("start http"); return boom }
+
+	l := newTestLifecycle(time.Hour) // lỗi khởi động không chờ ShutdownDelay
+	l.Add(j.comp("storage"), j.comp("scheduler"), bad, j.comp("watcher"))
+	err := wait(t, runAsync(l, context.Background()))
+	if !errors.Is(err, boom) || !strings.Contains(err.Error(), "start http") {
+		t.Fatalf("err = %v, want start http: %v", err, boom)
+	}
+	want := []string{"start storage", "start scheduler", "start http", "stop scheduler", "stop storage"}
+	if got := j.get(); !slices.Equal(got, want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+}
+
+// Lỗi lúc chạy (fail) dừng cả process như signal; chỉ lỗi đầu tiên được giữ.
+func TestLifecycleRuntimeFailShutsDown(t *testing.T) {
+	j := &journal{}
+	boom := errors.New("listener died")
+	failing := j.comp("http")
+	failing.Start = func(_ context.Context, fail func(error)) error {
+		j.add("start http")
+		go func() {
+			fail(boom)
+			fail(errors.New("second failure"))
+		}()
+		return nil
+	}
+
+	l := newTestLifecycle(0)
+	l.Add(j.comp("storage"), failing)
+	err := wait(t, runAsync(l, context.Background())) // ctx không bao giờ bị hủy
+	if !errors.Is(err, boom) || !strings.Contains(err.Error(), "http:") || strings.Contains(err.Error(), "second") {
+		t.Fatalf("err = %v, want only http: %v", err, boom)
+	}
+	want := []string{"start storage", "start http", "stop http", "stop storage"}
+	if got := j.get(); !slices.Equal(got, want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+}
+
+// /readiness báo down trước ShutdownDelay và trước Stop đầu tiên.
+func TestLifecycleReadinessDownBeforeStop(t *testing.T) {
+	const delay = 100 * time.Millisecond
+	l := newTestLifecycle(delay)
+	check := l.HealthCheck()
+	if !check.Critical {
+		t.Fatal("lifecycle check must be critical")
+	}
+
+	var (
+		stopErr  error
+		stopAt   time.Time
+		canceled time.Time
+	)
+	l.Add(Component{Name: "http", Stop: func(ctx context.Context) error {
+		stopAt = time.Now()
+		_, stopErr = check.Run(ctx)
+		return nil
+	}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := runAsync(l, ctx)
+	if _, err := check.Run(ctx); err != nil {
+		t.Fatalf("ready while running: %v", err)
+	}
+	canceled = time.Now()
+	cancel()
+
+	// trong ShutdownDelay: đã down nhưng chưa Stop
+	time.Sleep(delay / 2)
+	if _, err := check.Run(context.Background()); err == nil {
+		t.Error("still ready during shutdown delay")
+	}
+	if err := wait(t, done); err != nil {
+		t.Fatal(err)
+	}
+	if stopErr == nil {
+		t.Error("ready at first Stop, want down")
+	}
+	if stopAt.Sub(canceled) < delay {
+		t.Errorf("first Stop after %s, want at least ShutdownDelay %s", stopAt.Sub(canceled), delay)
+	}
+}
+
+// Stop quá Drain: lỗi nêu component, các component sau vẫn được dừng.
+func TestLifecycleStopTimeout(t *testing.T) {
+	j := &journal{}
+	stuck := Component{Name: "scheduler", Drain: 20 * time.Millisecond, Stop: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+	l := newTestLifecycle(0)
+	l.Add(j.comp("storage"), stuck)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := wait(t, runAsync(l, ctx))
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "stop scheduler") {
+		t.Fatalf("err = %v, want stop scheduler deadline", err)
+	}
+	if got := j.get(); !slices.Equal(got, []string{"start storage", "stop storage"}) {
+		t.Fatalf("events = %v", got)
+	}
+}
+
+// HTTPComponent ghi log qua logger được truyền vào (format/level của process), không qua slog global.
+func TestHTTPComponentUsesLogger(t *testing.T) {
+	var buf bytes.Buffer
+	c := HTTPComponent(http.NotFoundHandler(), "0", time.Second, slog.New(slog.NewTextHandler(&buf, nil)))
+	if err := c.Start(context.Background(), func(err error) { t.Errorf("fail(%v)", err) }); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "HTTP listening") {
+		t.Fatalf("log = %q, want the listening address", buf.String())
+	}
+}
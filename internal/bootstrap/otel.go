@@ -2,38 +2,163 @@ package bootstrap
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
 
+	prombridge "go.opentelemetry.io/contrib/bridges/prometheus"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
 )
 
+// Trace export (TRACING_EXPORTER): tracing là tùy chọn, cấu hình sai hay collector chết
+// không bao giờ làm API ngừng phục vụ — chỉ mất span.
+const (
+	TracingNone     = "none"
+	TracingStdout   = "stdout"    // JSON ra stdout, để debug local
+	TracingOTLPHTTP = "otlp-http" // OTEL_EXPORTER_OTLP_ENDPOINT (mặc định localhost:4318)
+	TracingOTLPGRPC = "otlp-grpc" // OTEL_EXPORTER_OTLP_ENDPOINT (mặc định localhost:4317)
+)
+
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter"`     // TracingNone | TracingStdout | TracingOTLPHTTP | TracingOTLPGRPC
+	SampleRatio float64 `yaml:"sample_ratio"` // trace gốc được giữ theo tỉ lệ này; có parent thì theo quyết định của parent
+}
+
 type Tracing struct {
 	Shutdown func(context.Context) error
 }
 
-func InitTracing(ctx context.Context, serviceName string) *Tracing {
-	exp, err := otlptracehttp.New(ctx) // đọc endpoint từ ENV: OTEL_EXPORTER_OTLP_ENDPOINT
+// InitTracing: luôn cài propagator (trace context đi qua service kể cả khi không export);
+// exporter lỗi -> log cảnh báo, chạy với tracer no-op.
+func InitTracing(ctx context.Context, serviceName string, cfg TracingConfig, logger *slog.Logger) *Tracing {
+	// W3C trace context + baggage: middleware HTTP extract, client gửi ra ngoài inject (traced.InjectHTTP)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	// lỗi export (collector down) đi qua handler này thay vì log mỗi batch
+	otel.SetErrorHandler(newThrottledErrorHandler(logger, time.Minute))
+
+	noop := &Tracing{Shutdown: func(context.Context) error { return nil }}
+	exp, err := newSpanExporter(ctx, cfg.Exporter)
 	if err != nil {
-		log.Fatalf("otlp exporter: %v", err)
+		logger.Warn("tracing disabled", "exporter", cfg.Exporter, "error", err)
+		return noop
+	}
+	if exp == nil {
+		return noop
 	}
 
-	res, err := resource.New(ctx,
-		resource.WithFromEnv(),
-		resource.WithTelemetrySDK(),
-		resource.WithAttributes(semconv.ServiceName(serviceName)),
-	)
+	res, err := newResource(ctx, serviceName)
 	if err != nil {
-		log.Fatalf("resource: %v", err)
+		// resource.New vẫn trả phần đã dựng được (vd. OTEL_RESOURCE_ATTRIBUTES sai cú pháp)
+		logger.Warn("tracing resource incomplete", "error", err)
 	}
 
 	tp := sdktrace.NewTracerProvider(
 		sdktrace.WithBatcher(exp),
 		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
 	)
 	otel.SetTracerProvider(tp)
+	logger.Info("tracing enabled", "exporter", cfg.Exporter, "sample_ratio", cfg.SampleRatio)
 	return &Tracing{Shutdown: tp.Shutdown}
 }
+
+// newSpanExporter: nil, nil = tắt tracing. Exporter OTLP không kết nối lúc tạo nên collector
+// chưa lên vẫn khởi động bình thường; span gửi lỗi sẽ bị bỏ.
+func newSpanExporter(ctx context.Context, exporter string) (sdktrace.SpanExporter, error) {
+	switch exporter {
+	case "", TracingNone:
+		return nil, nil
+	case TracingStdout:
+		return stdouttrace.New()
+	case TracingOTLPHTTP:
+		return otlptracehttp.New(ctx)
+	case TracingOTLPGRPC:
+		return otlptracegrpc.New(ctx)
+	}
+	return nil, fmt.Errorf("TRACING_EXPORTER: unknown exporter %q (want none, stdout, otlp-http or otlp-grpc)", exporter)
+}
+
+// throttledErrorHandler: lỗi nội bộ của OTel SDK, tối đa một dòng log mỗi interval
+type throttledErrorHandler struct {
+	logger   *slog.Logger
+	interval time.Duration
+
+	mu         sync.Mutex
+	last       time.Time
+	suppressed int
+}
+
+func newThrottledErrorHandler(logger *slog.Logger, interval time.Duration) *throttledErrorHandler {
+	return &throttledErrorHandler{logger: logger, interval: interval}
+}
+
+func (h *throttledErrorHandler) Handle(err error) {
+	h.mu.Lock()
+	if time.Since(h.last) < h.interval {
+		h.suppressed++
+		h.mu.Unlock()
+		return
+	}
+	suppressed := h.suppressed
+	h.last, h.suppressed = time.Now(), 0
+	h.mu.Unlock()
+	h.logger.Warn("opentelemetry export error", "error", err, "suppressed", suppressed)
+}
+
+// Metrics export: /metrics luôn phục vụ; đặt METRICS_EXPORTER=otlp để đẩy thêm
+// toàn bộ registry Prometheus mặc định tới otel-collector (OTEL_EXPORTER_OTLP_ENDPOINT,
+// chu kỳ OTEL_METRIC_EXPORT_INTERVAL). Khi đẩy thì bỏ scrape trực tiếp để tránh trùng series.
+const (
+	MetricsExportNone = "none"
+	MetricsExportOTLP = "otlp"
+)
+
+type Metrics struct {
+	Shutdown func(context.Context) error
+}
+
+// InitMetrics: lỗi exporter chỉ ghi log, API vẫn chạy và /metrics vẫn scrape được
+func InitMetrics(ctx context.Context, serviceName, exporter string, logger *slog.Logger) *Metrics {
+	noop := &Metrics{Shutdown: func(context.Context) error { return nil }}
+	switch exporter {
+	case "", MetricsExportNone:
+		return noop
+	case MetricsExportOTLP:
+	default:
+		logger.Warn("metrics export disabled", "error", fmt.Errorf("metrics_exporter: unknown exporter %q", exporter))
+		return noop
+	}
+
+	exp, err := otlpmetrichttp.New(ctx)
+	if err != nil {
+		logger.Warn("metrics export disabled", "error", fmt.Errorf("otlp metric exporter: %w", err))
+		return noop
+	}
+	res, err := newResource(ctx, serviceName)
+	if err != nil {
+		logger.Warn("metrics export disabled", "error", fmt.Errorf("resource: %w", err))
+		return noop
+	}
+	reader := sdkmetric.NewPeriodicReader(exp, sdkmetric.WithProducer(prombridge.NewMetricProducer()))
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader), sdkmetric.WithResource(res))
+	otel.SetMeterProvider(mp)
+	return &Metrics{Shutdown: mp.Shutdown}
+}
+
+func newResource(ctx context.Context, serviceName string) (*resource.Resource, error) {
+	return resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(semconv.ServiceName(serviceName)),
+	)
+}
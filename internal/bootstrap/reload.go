@@ -0,0 +1,137 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ===== Reload cấu hình =====
+
+// configPollInterval: file cấu hình được kiểm (mtime/size) theo chu kỳ này; ConfigMap k8s
+// đổi qua symlink nên poll đáng tin hơn theo dõi inode
+const configPollInterval = 5 * time.Second
+
+// hotKeys: key áp dụng được lúc chạy; đổi key khác chỉ được cảnh báo là cần restart
+var hotKeys = map[string]bool{
+	"log.level":         true,
+	"cors_origins":      true,
+	"job_idle_after":    true,
+	"job_run_retention": true,
+}
+
+// WatchConfig: đọc lại cấu hình khi nhận SIGHUP hoặc file cấu hình đổi, tới khi ctx bị hủy.
+// Cấu hình mới không hợp lệ thì bị từ chối, giữ nguyên cấu hình đang chạy.
+func (a *App) WatchConfig(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var poll <-chan time.Time
+	if a.cfg.ConfigFile != "" {
+		t := time.NewTicker(configPollInterval)
+		defer t.Stop()
+		poll = t.C
+	}
+	stamp := fileStamp(a.cfg.ConfigFile)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			stamp = fileStamp(a.cfg.ConfigFile)
+			a.reload("sighup")
+		case <-poll:
+			if s := fileStamp(a.cfg.ConfigFile); s != stamp {
+				stamp = s
+				a.reload("file_changed")
+			}
+		}
+	}
+}
+
+func (a *App) reload(trigger string) {
+	logger := a.logging.Logger
+	next, err := ReadConfig()
+	if err != nil {
+		logger.Error("config reload rejected, keeping current config", "trigger", trigger, "error", err)
+		return
+	}
+	applied, restart := a.Reload(next)
+	if len(applied) > 0 || len(restart) == 0 {
+		logger.Info("config reloaded", "trigger", trigger, "applied", applied)
+	}
+	if len(restart) > 0 {
+		logger.Warn("config changes need a restart to take effect", "trigger", trigger, "keys", restart)
+	}
+}
+
+// Reload: áp phần đổi được lúc chạy của next; trả key đã áp và key cần restart.
+// Key cần restart không được ghi nhận nên lần reload sau vẫn tiếp tục cảnh báo.
+// log.level chỉ áp khi giá trị trong cấu hình đổi: level đặt qua /admin/log-level được giữ tới lúc đó.
+func (a *App) Reload(next AppConfig) (applied, restart []string) {
+	cur := a.cfg
+	for _, key := range configDiff(cur, next) {
+		if !hotKeys[key] {
+			restart = append(restart, key)
+			continue
+		}
+		applied = append(applied, key)
+	}
+	for _, key := range applied {
+		switch key {
+		case "log.level":
+			_ = a.logging.SetLevel(next.Log.Level) // đã validate
+			cur.Log.Level = next.Log.Level
+		case "cors_origins":
+			a.cors.SetOrigins(next.AllowOrigin)
+			cur.AllowOrigin = next.AllowOrigin
+		case "job_idle_after", "job_run_retention":
+			cur.JobIdleAfter, cur.JobRunRetention = next.JobIdleAfter, next.JobRunRetention
+			a.fleet.SetConfig(fleetJobsConfig(cur))
+		}
+	}
+	a.cfg = cur
+	return applied, restart
+}
+
+// configDiff: key YAML (log.level, cors_origins...) có giá trị khác nhau giữa hai cấu hình
+func configDiff(a, b AppConfig) []string {
+	var out []string
+	var walk func(prefix string, x, y reflect.Value)
+	walk = func(prefix string, x, y reflect.Value) {
+		for i := 0; i < x.NumField(); i++ {
+			f := x.Type().Field(i)
+			tag := strings.Split(f.Tag.Get("yaml"), ",")[0]
+			if tag == "" || tag == "-" {
+				continue
+			}
+			if f.Type.Kind() == reflect.Struct {
+				walk(prefix+tag+".", x.Field(i), y.Field(i))
+				continue
+			}
+			if !reflect.DeepEqual(x.Field(i).Interface(), y.Field(i).Interface()) {
+				out = append(out, prefix+tag)
+			}
+		}
+	}
+	walk("", reflect.ValueOf(a), reflect.ValueOf(b))
+	return out
+}
+
+// fileStamp: đổi khi nội dung file có thể đã đổi; "" khi không có file
+func fileStamp(path string) string {
+	if path == "" {
+		return ""
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d", fi.ModTime().UnixNano(), fi.Size())
+}
@@ -0,0 +1,45 @@
+package bootstrap
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"wh-ma/internal/adapter/inbound/http/openapi"
+)
+
+// Router thật (storage memory) không được lệch khỏi openapi.yaml.
+func TestRoutesMatchOpenAPI(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := defaultConfig()
+	cfg.Storage = StorageMemory
+	cfg.ExportDir = t.TempDir()
+
+	st, err := OpenStorage(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	r, err := BuildRouter(cfg, st, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("BuildRouter: %v", err)
+	}
+
+	spec, err := openapi.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := spec.VerifyRoutes(r.Routes()); err != nil {
+		t.Fatal(err)
+	}
+
+	// route ngoài spec phải bị phát hiện
+	r.GET(openapi.V1Prefix+"/not-in-spec", func(c *gin.Context) { c.Status(http.StatusOK) })
+	if err := spec.VerifyRoutes(r.Routes()); err == nil {
+		t.Fatal("VerifyRoutes: want error for undocumented route")
+	}
+}
@@ -0,0 +1,98 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"wh-ma/internal/adapter/outbound/memory"
+	"wh-ma/internal/adapter/outbound/migration"
+	"wh-ma/internal/adapter/outbound/port"
+	outrepo "wh-ma/internal/adapter/outbound/repository"
+)
+
+// ===== Storage: chọn adapter outbound theo cấu hình =====
+//
+//	postgres (mặc định): pgxpool + sqlc, cần DATABASE_URL
+//	memory:              store in-memory có seed dữ liệu demo (frontend/CI chạy không cần Postgres)
+
+const (
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+)
+
+type Storage struct {
+	Kind  string
+	Repos port.Repos
+	Tx    port.TxManager
+	Pool  *pgxpool.Pool // nil khi chạy memory
+
+	// Leader: quyền chạy job nền giữa các replica (memory: một process, luôn là leader)
+	Leader port.LeaderLock
+}
+
+// schedulerLockKey: advisory lock của scheduler (mọi replica cùng database tranh một key)
+const schedulerLockKey = "whma:scheduler"
+
+// OpenStorage: mở storage theo cfg.Storage; memory thì seed luôn dữ liệu demo
+func OpenStorage(ctx context.Context, cfg AppConfig) (*Storage, error) {
+	switch cfg.Storage {
+	case StoragePostgres, "":
+		if cfg.AutoMigrate {
+			if err := Migrate(cfg.DatabaseURL); err != nil {
+				return nil, err
+			}
+		}
+		pool, err := NewPGXPool(ctx, cfg.DatabaseURL)
+		if err != nil {
+			return nil, fmt.Errorf("db connect: %w", err)
+		}
+		return &Storage{
+			Kind:  StoragePostgres,
+			Repos: outrepo.NewRepos(pool),
+			Tx:    outrepo.NewTxManager(pool),
+			Pool:  pool,
+
+			Leader: outrepo.NewLeaderLock(pool, schedulerLockKey),
+		}, nil
+	case StorageMemory:
+		s := memory.NewStore()
+		st := &Storage{Kind: StorageMemory, Repos: s.Repos(), Tx: memory.NewTxManager(s), Leader: memory.LeaderLock{}}
+		policy, err := LoadPolicy(cfg)
+		if err != nil {
+			return nil, err
+		}
+		if err := SeedDemo(ctx, st, policy); err != nil {
+			return nil, fmt.Errorf("seed demo data: %w", err)
+		}
+		return st, nil
+	default:
+		return nil, fmt.Errorf("unknown storage %q (want %s or %s)", cfg.Storage, StoragePostgres, StorageMemory)
+	}
+}
+
+// Migrate: auto-migrate lúc khởi động; replica khác đang migrate thì chờ khóa advisory rồi thấy đã xong
+func Migrate(databaseURL string) error {
+	m, err := migration.New(databaseURL)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+	if err := m.Up(); err != nil {
+		return fmt.Errorf("auto-migrate: %w", err)
+	}
+	st, err := m.Status()
+	if err != nil {
+		return err
+	}
+	log.Printf("schema at version %d (expected %d)", st.Version, st.Expected)
+	return nil
+}
+
+func (s *Storage) Close() {
+	if s.Pool != nil {
+		s.Pool.Close()
+	}
+}
@@ -0,0 +1,93 @@
+package bootstrap
+
+import (
+	"context"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"wh-ma/internal/adapter/outbound/migration"
+	outrepo "wh-ma/internal/adapter/outbound/repository"
+)
+
+// pgTestURL: test cần Postgres chỉ chạy khi WHMA_TEST_DATABASE_URL trỏ tới database dùng riêng
+// cho test (schema bị lùi hết rồi migrate lại), user superuser (migration tạo role whma_snapshot).
+func pgTestURL(t *testing.T) string {
+	t.Helper()
+	url := os.Getenv("WHMA_TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("WHMA_TEST_DATABASE_URL not set")
+	}
+	return url
+}
+
+// Job nền lấy danh sách tenant qua app_tenant_ids(); RLS vẫn áp dụng cho user của server nên
+// hàm phải tự đọc được mọi tenant (migration 000014 bỏ bypass qua app.snapshot).
+func TestListTenantsAfterFullMigrate(t *testing.T) {
+	url := pgTestURL(t)
+	ctx := context.Background()
+
+	m, err := migration.New(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	if err := m.Down(0); err != nil {
+		t.Fatalf("migrate down: %v", err)
+	}
+	if err := m.Up(); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+
+	su, err := pgxpool.New(ctx, url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer su.Close()
+	// superuser bỏ qua RLS: dữ liệu ghi thẳng, server giả lập bằng role thường
+	for _, stmt := range []string{
+		`DO $$ BEGIN
+		   IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'whma_test_app') THEN
+		     CREATE ROLE whma_test_app NOLOGIN NOBYPASSRLS;
+		   END IF;
+		 END $$`,
+		`GRANT USAGE ON SCHEMA public TO whma_test_app`,
+		`GRANT SELECT ON devices TO whma_test_app`,
+		`INSERT INTO devices (serial_number, name, tenant_id, deleted_at) VALUES
+		   ('SN-1', 'a', 't-a', NULL),
+		   ('SN-2', 'b', 't-b', NULL),
+		   ('SN-3', 'c', 't-b', NULL),
+		   ('SN-4', 'd', 't-c', now())`,
+	} {
+		if _, err := su.Exec(ctx, stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+
+	cfg, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+		_, err := conn.Exec(ctx, "SET ROLE whma_test_app")
+		return err
+	}
+	cfg.BeforeAcquire = bindTenant
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	got, err := outrepo.NewRepos(pool).Devices.ListTenants(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// t-c chỉ còn device đã xóa mềm
+	if want := []string{"t-a", "t-b"}; !slices.Equal(got, want) {
+		t.Fatalf("ListTenants = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,26 @@
+package bootstrap
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"wh-ma/internal/usecase/authz"
+)
+
+// ===== Multi-tenant: gắn tenant của principal vào connection =====
+//
+// RLS (migration 000007) lọc mọi bảng theo app_tenant() = current_setting('app.tenant_id').
+// Mỗi lần acquire connection từ pool thì set lại theo principal trong ctx,
+// không có principal/tenant => chuỗi rỗng (chỉ thấy plan template, không ghi được dữ liệu tenant).
+func bindTenant(ctx context.Context, conn *pgx.Conn) bool {
+	var tenant string
+	if p, ok := authz.PrincipalFrom(ctx); ok {
+		tenant = p.TenantID
+	}
+	// set_config(..., false): giữ tới hết session, lần acquire sau sẽ ghi đè
+	if _, err := conn.Exec(ctx, "SELECT set_config('app.tenant_id', $1, false)", tenant); err != nil {
+		return false // bỏ connection này, pool lấy connection khác
+	}
+	return true
+}
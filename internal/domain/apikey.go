@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// ==== API key cho client máy (gateway đồng hồ giờ, script ETL) ====
+type APIKey struct {
+	ID         int64
+	TenantID   *string // nil = key cấp hệ thống
+	Name       string
+	Prefix     string // phần đầu của key: tra cứu + hiển thị, không bí mật
+	SecretHash string // sha256(key) hex — không bao giờ trả ra ngoài API
+
+	Scopes    []string   // vd "readings:write"
+	DeviceIDs []DeviceID // rỗng = mọi device
+	Sites     []string   // rỗng = mọi site
+
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+	CreatedBy  string
+}
+
+// Principal: key và JWT cùng ra một kiểu principal cho usecase
+func (k APIKey) Principal() Principal {
+	return Principal{
+		Subject:   "apikey:" + k.Prefix,
+		TenantID:  strOrEmpty(k.TenantID),
+		Scopes:    k.Scopes,
+		Sites:     k.Sites,
+		DeviceIDs: k.DeviceIDs,
+	}
+}
+
+func strOrEmpty(p *string) string {
+	if p == nil {
+		return ""
+	}
+	return *p
+}
@@ -20,7 +20,8 @@ const (
 // ==== Root Aggregate: Device ====
 type Device struct {
 	ID           DeviceID
-	SerialNumber string
+	TenantID     string
+	SerialNumber string // duy nhất trong phạm vi tenant
 	Name         string
 
 	Profile  DeviceProfile
@@ -32,6 +33,7 @@ type Device struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt *time.Time
+	Version   int // tăng mỗi lần ghi; dùng làm ETag (optimistic concurrency)
 
 	Audit AuditMeta
 }
@@ -89,8 +91,14 @@ type Reading struct {
 	HoursDelta int
 	Location   string
 	OperatorID string // bổ sung để phân tích hành vi người vận hành
+	Source     string // ReadingSourceAPI | ReadingSourceImport
 }
 
+const (
+	ReadingSourceAPI    = "api"    // nhập từng reading
+	ReadingSourceImport = "import" // import hàng loạt
+)
+
 // Bảo dưỡng/tu sửa
 type MaintenanceEvent struct {
 	ID          int64
@@ -110,4 +118,19 @@ type Alert struct {
 	Message   string
 	CreatedAt time.Time
 	Resolved  bool
+
+	ResolvedAt *time.Time
+	ResolvedBy string
+}
+
+// AlertSeverity: mức độ theo loại alert (metrics/cảnh báo vận hành); loại lạ -> "warning"
+func AlertSeverity(alertType string) string {
+	switch alertType {
+	case "over_usage":
+		return "critical"
+	case "idle_too_long":
+		return "info"
+	default: // maintenance_due
+		return "warning"
+	}
 }
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// ==== Nhật ký thay đổi thiết bị (ai sửa gì, khi nào) ====
+type DeviceAuditEntry struct {
+	ID        int64
+	DeviceID  DeviceID
+	Action    string // vd AuditProfilePatched
+	Actor     string
+	Changes   map[string]FieldChange // key = tên field JSON
+	CreatedAt time.Time
+}
+
+// FieldChange: giá trị trước/sau (nil = trống)
+type FieldChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+const AuditProfilePatched = "profile_patched"
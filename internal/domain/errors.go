@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+// ==== Loại lỗi (sentinel): adapter HTTP map sang status, so bằng errors.Is ====
+var (
+	ErrNotFound           = errors.New("not found")
+	ErrConflict           = errors.New("conflict")
+	ErrValidation         = errors.New("validation failed")
+	ErrPreconditionFailed = errors.New("precondition failed")
+	ErrForbidden          = errors.New("forbidden")
+	ErrUnauthenticated    = errors.New("authentication required")
+)
+
+// FieldError: chi tiết lỗi validate của một field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ==== Error: lỗi nghiệp vụ có mã ổn định cho client ====
+type Error struct {
+	Kind   error        // một trong các sentinel ở trên
+	Code   string       // mã ổn định, vd "device_not_found", "serial_number_taken"
+	Detail string       // mô tả cho người đọc
+	Fields []FieldError // chỉ dùng với ErrValidation
+}
+
+func (e *Error) Error() string {
+	if e.Detail != "" {
+		return e.Detail
+	}
+	if len(e.Fields) > 0 {
+		parts := make([]string, 0, len(e.Fields))
+		for _, f := range e.Fields {
+			parts = append(parts, f.Field+" "+f.Message)
+		}
+		return strings.Join(parts, "; ")
+	}
+	return e.Kind.Error()
+}
+
+func (e *Error) Unwrap() error { return e.Kind }
+
+// --- constructors ---
+func NotFound(code, detail string) *Error {
+	return &Error{Kind: ErrNotFound, Code: code, Detail: detail}
+}
+
+func Conflict(code, detail string) *Error {
+	return &Error{Kind: ErrConflict, Code: code, Detail: detail}
+}
+
+func PreconditionFailed(code, detail string) *Error {
+	return &Error{Kind: ErrPreconditionFailed, Code: code, Detail: detail}
+}
+
+func Forbidden(code, detail string) *Error {
+	return &Error{Kind: ErrForbidden, Code: code, Detail: detail}
+}
+
+// Invalid: lỗi validate một field, vd Invalid("name", "is required")
+func Invalid(field, message string) *Error {
+	return &Error{
+		Kind:   ErrValidation,
+		Code:   "validation_failed",
+		Fields: []FieldError{{Field: field, Message: message}},
+	}
+}
+
+// Violations: gom lỗi của nhiều field rồi trả về một lần
+type Violations []FieldError
+
+func (v *Violations) Add(field, message string) {
+	*v = append(*v, FieldError{Field: field, Message: message})
+}
+
+// Err: nil nếu không có vi phạm
+func (v Violations) Err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return &Error{Kind: ErrValidation, Code: "validation_failed", Fields: v}
+}
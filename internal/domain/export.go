@@ -0,0 +1,54 @@
+package domain
+
+import "time"
+
+// ==== Export: dữ liệu thô cho tài chính/quản lý (CSV, XLSX, JSON Lines) ====
+type ExportKind string
+
+const (
+	ExportDevices     ExportKind = "devices"     // device + state hiện tại
+	ExportReadings    ExportKind = "readings"    // giờ máy trong khoảng thời gian
+	ExportMaintenance ExportKind = "maintenance" // sự kiện bảo dưỡng kèm chi phí
+	ExportAlerts      ExportKind = "alerts"      // lịch sử cảnh báo (cả đã resolve)
+)
+
+func (k ExportKind) Valid() bool {
+	switch k {
+	case ExportDevices, ExportReadings, ExportMaintenance, ExportAlerts:
+		return true
+	}
+	return false
+}
+
+// ExportFilter: khoảng thời gian [From, To) và device (không áp dụng cho ExportDevices)
+type ExportFilter struct {
+	From     time.Time // zero = từ đầu
+	To       time.Time // zero = tới thời điểm chạy
+	DeviceID *DeviceID // nil = mọi device
+}
+
+// ==== Export job: export lớn chạy nền, kết quả tải qua link ký có hạn ====
+type ExportJobStatus string
+
+const (
+	ExportQueued  ExportJobStatus = "queued"
+	ExportRunning ExportJobStatus = "running"
+	ExportDone    ExportJobStatus = "done"
+	ExportFailed  ExportJobStatus = "failed"
+)
+
+type ExportJob struct {
+	ID        string // ngẫu nhiên, không đoán được
+	TenantID  string
+	Kind      ExportKind
+	Format    string // csv | xlsx | jsonl
+	Filter    ExportFilter
+	Status    ExportJobStatus
+	Rows      int64
+	Error     string // khi failed
+	CreatedBy string
+	CreatedAt time.Time
+
+	FinishedAt *time.Time
+	ExpiresAt  time.Time // job + file bị xóa, link hết hiệu lực sau thời điểm này
+}
@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+// ==== Số liệu tổng hợp đội máy của một tenant (Prometheus, dashboard) ====
+type FleetStats struct {
+	Devices     []DeviceCount  // device chưa xóa theo trạng thái + site
+	OpenAlerts  []AlertCount   // alert chưa resolve theo loại (device chưa xóa)
+	Overdue     []SiteCount    // device active đã quá hạn bảo dưỡng theo site
+	Readings    []ReadingTotal // theo nguồn (ReadingSourceAPI/Import)
+	Maintenance MaintenanceTotal
+}
+
+type DeviceCount struct {
+	Status DeviceStatus
+	Site   string // devices.location; rỗng = chưa gán site
+	Count  int64
+}
+
+type AlertCount struct {
+	Type  string
+	Count int64
+}
+
+type SiteCount struct {
+	Site  string
+	Count int64
+}
+
+type ReadingTotal struct {
+	Source   string
+	Readings int64
+	Hours    int64 // tổng hours_delta
+}
+
+type MaintenanceTotal struct {
+	Events int64
+	Cost   float64
+}
+
+// Overdue: quá ngày dự kiến, hoặc giờ sau đại tu đã chạm chu kỳ của plan
+func (d *Device) Overdue(plan *Plan, now time.Time) bool {
+	if d.State.ExpectedNextMaint != nil && !d.State.ExpectedNextMaint.After(now) {
+		return true
+	}
+	return plan != nil && plan.IntervalHours > 0 && d.State.AfterOverhaul >= plan.IntervalHours
+}
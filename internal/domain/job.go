@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// ==== Job nền: chạy theo lịch trên một replica (leader), lưu lịch sử từng lần chạy ====
+type JobRunStatus string
+
+const (
+	JobOK       JobRunStatus = "ok"
+	JobFailed   JobRunStatus = "failed"
+	JobTimeout  JobRunStatus = "timeout"  // quá timeout của job
+	JobCanceled JobRunStatus = "canceled" // server dừng (hết thời gian drain) hoặc mất quyền leader
+)
+
+type JobRun struct {
+	ID          int64
+	Job         string
+	Instance    string // replica đã chạy
+	ScheduledAt time.Time
+	StartedAt   time.Time
+	FinishedAt  time.Time
+	Status      JobRunStatus
+	Error       string
+	Summary     string // kết quả ngắn, vd "tenants=3 devices=120 updated=118"
+}
+
+func (r JobRun) Duration() time.Duration { return r.FinishedAt.Sub(r.StartedAt) }
+
+// JobInfo: cấu hình + trạng thái hiện tại của một job trên replica này
+type JobInfo struct {
+	Name      string
+	Schedule  string // biểu thức cron 5 trường hoặc @hourly/@daily/@every 15m
+	Timeout   time.Duration
+	NextRunAt *time.Time // nil khi replica này không phải leader
+	Running   bool
+}
+
+// SchedulerStatus: replica đang trả lời có giữ quyền leader không (chỉ leader chạy job)
+type SchedulerStatus struct {
+	Instance string
+	Leader   bool
+	Jobs     []JobInfo
+}
@@ -4,10 +4,15 @@ import "time"
 
 type Plan struct {
 	ID            PlanID
+	TenantID      *string // nil = template dùng chung cho mọi tenant
 	Name          string
 	IntervalHours int
 	Description   *string
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	Version   int // tăng mỗi lần ghi; dùng làm ETag
 }
+
+// Shared: template dùng chung, tenant chỉ đọc
+func (p Plan) Shared() bool { return p.TenantID == nil }
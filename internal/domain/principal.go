@@ -0,0 +1,76 @@
+package domain
+
+// ==== Roles (phân quyền theo vai trò) ====
+type Role string
+
+const (
+	RoleOperator   Role = "operator"   // vận hành: nhập giờ máy tại site của mình
+	RoleTechnician Role = "technician" // kỹ thuật: ghi nhận bảo dưỡng
+	RoleSupervisor Role = "supervisor" // giám sát: đóng cảnh báo, đổi kế hoạch
+	RoleAdmin      Role = "admin"      // quản trị: xóa thiết bị, toàn quyền
+)
+
+// ==== Principal: người/máy gọi API đã được xác thực ====
+type Principal struct {
+	Subject  string   // user id / client id
+	TenantID string   // nhà thầu sở hữu dữ liệu; rỗng = cấp hệ thống (chỉ thấy plan template)
+	Role     Role     // vai trò quyết định quyền theo policy (JWT)
+	Sites    []string // site được phép thao tác; rỗng = mọi site
+
+	// API key: quyền lấy theo scopes thay cho role; có thể giới hạn theo device
+	Scopes    []string
+	DeviceIDs []DeviceID // rỗng = mọi device
+}
+
+// AtSite: principal có được phép thao tác ở site này không
+func (p Principal) AtSite(site string) bool {
+	if len(p.Sites) == 0 {
+		return true
+	}
+	for _, s := range p.Sites {
+		if s == site {
+			return true
+		}
+	}
+	return false
+}
+
+// ForDevice: principal có được thao tác trên device này không
+func (p Principal) ForDevice(id DeviceID) bool {
+	if len(p.DeviceIDs) == 0 {
+		return true
+	}
+	for _, d := range p.DeviceIDs {
+		if d == id {
+			return true
+		}
+	}
+	return false
+}
+
+// ==== DeviceScope: device principal được thấy khi liệt kê (lọc ngay trong query) ====
+// Danh sách rỗng = không giới hạn theo tiêu chí đó; cùng tiêu chí với AtSite/ForDevice.
+type DeviceScope struct {
+	Sites     []string
+	DeviceIDs []DeviceID // API key giới hạn theo device
+}
+
+func (p Principal) DeviceScope() DeviceScope {
+	return DeviceScope{Sites: p.Sites, DeviceIDs: p.DeviceIDs}
+}
+
+// Allows: device có nằm trong phạm vi không
+func (s DeviceScope) Allows(d *Device) bool {
+	p := Principal{Sites: s.Sites, DeviceIDs: s.DeviceIDs}
+	return p.AtSite(d.State.Location) && p.ForDevice(d.ID)
+}
+
+// HasScope: dùng cho principal từ API key
+func (p Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+
+	inport "wh-ma/internal/adapter/inbound/port"
+	outport "wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/authz"
+)
+
+type AlertsUsecase struct {
+	alertRepo outport.AlertRepository
+	devRepo   outport.DeviceRepository
+	authz     *authz.Authorizer
+}
+
+func NewAlertsUsecase(alertRepo outport.AlertRepository, devRepo outport.DeviceRepository, az *authz.Authorizer) *AlertsUsecase {
+	return &AlertsUsecase{alertRepo: alertRepo, devRepo: devRepo, authz: az}
+}
+
+// ✅ compile-time check: UC triển khai inbound port
+var _ inport.AlertsInbound = (*AlertsUsecase)(nil)
+
+func (uc *AlertsUsecase) ListOpenByDevice(ctx context.Context, deviceID domain.DeviceID, limit, offset int32) ([]*domain.Alert, error) {
+	if err := uc.authz.Require(ctx, authz.AlertsRead); err != nil {
+		return nil, err
+	}
+	if err := uc.requireDevice(ctx, authz.AlertsRead, deviceID); err != nil {
+		return nil, err
+	}
+	return uc.alertRepo.ListOpenByDevice(ctx, deviceID, limit, offset)
+}
+
+// RESOLVE: resolved_by = principal đang gọi; chỉ alert của device principal được thao tác
+func (uc *AlertsUsecase) Resolve(ctx context.Context, id int64) (*domain.Alert, error) {
+	if err := uc.authz.Require(ctx, authz.AlertsResolve); err != nil {
+		return nil, err
+	}
+	al, err := uc.alertRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.requireDevice(ctx, authz.AlertsResolve, al.DeviceID); err != nil {
+		return nil, err
+	}
+	var by *string
+	if p, ok := authz.PrincipalFrom(ctx); ok {
+		by = &p.Subject
+	}
+	return uc.alertRepo.Resolve(ctx, outport.ResolveAlertInput{ID: id, ResolvedBy: by})
+}
+
+// requireDevice: alert đi theo device, site/device của principal áp lên device đó
+func (uc *AlertsUsecase) requireDevice(ctx context.Context, act authz.Action, id domain.DeviceID) error {
+	dev, err := uc.devRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	return uc.authz.RequireDevice(ctx, act, dev)
+}
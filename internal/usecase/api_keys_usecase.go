@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	inport "wh-ma/internal/adapter/inbound/port"
+	outport "wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/authz"
+	"wh-ma/internal/usecase/dto"
+)
+
+// Định dạng key: whma_<prefix 8 hex>_<secret 48 hex>
+const apiKeyTag = "whma_"
+
+var errInvalidAPIKey = fmt.Errorf("%w: invalid api key", authz.ErrUnauthenticated)
+
+type APIKeysUsecase struct {
+	keyRepo outport.APIKeyRepository
+	authz   *authz.Authorizer
+}
+
+func NewAPIKeysUsecase(keyRepo outport.APIKeyRepository, az *authz.Authorizer) *APIKeysUsecase {
+	return &APIKeysUsecase{keyRepo: keyRepo, authz: az}
+}
+
+// ✅ compile-time check: UC triển khai inbound port
+var _ inport.APIKeysInbound = (*APIKeysUsecase)(nil)
+
+// CREATE
+// - required: Name, ít nhất 1 scope (scope = action trong RBAC, không cho "*")
+// - ExpiresAt (nếu có) phải ở tương lai
+// - key không rộng hơn người tạo: mọi scope người tạo phải có, site/device nằm trong phạm vi người tạo
+// - chỉ lưu sha256(key); key gốc trả về một lần duy nhất
+func (uc *APIKeysUsecase) Create(ctx context.Context, in dto.CreateAPIKeyCmd) (*dto.IssuedAPIKey, error) {
+	if err := uc.authz.Require(ctx, authz.APIKeysManage); err != nil {
+		return nil, err
+	}
+	var v domain.Violations
+	if in.Name == "" {
+		v.Add("name", "is required")
+	}
+	if len(in.Scopes) == 0 {
+		v.Add("scopes", "must contain at least one scope")
+	}
+	for _, s := range in.Scopes {
+		if !authz.IsKnownAction(authz.Action(s)) {
+			v.Add("scopes", fmt.Sprintf("unknown scope %q", s))
+		}
+	}
+	if in.ExpiresAt != nil && !in.ExpiresAt.After(time.Now()) {
+		v.Add("expires_at", "must be in the future")
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+	if err := uc.requireDelegable(ctx, in); err != nil {
+		return nil, err
+	}
+
+	prefix, secret, err := generateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	k, err := uc.keyRepo.Create(ctx, outport.CreateAPIKeyInput{
+		Name:       in.Name,
+		Prefix:     prefix,
+		SecretHash: hashAPIKey(secret),
+		Scopes:     in.Scopes,
+		DeviceIDs:  in.DeviceIDs,
+		Sites:      in.Sites,
+		ExpiresAt:  in.ExpiresAt,
+		CreatedBy:  actor(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.IssuedAPIKey{Key: k, Secret: secret}, nil
+}
+
+func (uc *APIKeysUsecase) List(ctx context.Context, limit, offset int32) ([]*domain.APIKey, error) {
+	if err := uc.authz.Require(ctx, authz.APIKeysManage); err != nil {
+		return nil, err
+	}
+	return uc.keyRepo.List(ctx, limit, offset)
+}
+
+func (uc *APIKeysUsecase) Revoke(ctx context.Context, id int64) (*domain.APIKey, error) {
+	if err := uc.authz.Require(ctx, authz.APIKeysManage); err != nil {
+		return nil, err
+	}
+	return uc.keyRepo.Revoke(ctx, id)
+}
+
+// AUTHENTICATE
+// - tra theo prefix, so sánh hash constant-time
+// - key đã thu hồi/hết hạn -> 401
+// - cập nhật last_used_at (lỗi ghi không chặn request)
+func (uc *APIKeysUsecase) Authenticate(ctx context.Context, raw string) (domain.Principal, error) {
+	prefix, ok := apiKeyPrefix(raw)
+	if !ok {
+		return domain.Principal{}, errInvalidAPIKey
+	}
+	k, err := uc.keyRepo.GetByPrefix(ctx, prefix)
+	if err != nil {
+		return domain.Principal{}, errInvalidAPIKey
+	}
+	if subtle.ConstantTimeCompare([]byte(hashAPIKey(raw)), []byte(k.SecretHash)) != 1 {
+		return domain.Principal{}, errInvalidAPIKey
+	}
+	if k.RevokedAt != nil {
+		return domain.Principal{}, fmt.Errorf("%w: api key revoked", authz.ErrUnauthenticated)
+	}
+	if k.ExpiresAt != nil && !k.ExpiresAt.After(time.Now()) {
+		return domain.Principal{}, fmt.Errorf("%w: api key expired", authz.ErrUnauthenticated)
+	}
+	_ = uc.keyRepo.TouchLastUsed(ctx, k.ID)
+	return k.Principal(), nil
+}
+
+// --- helpers ---
+
+// requireDelegable: chặn leo thang quyền qua API key (vd. supervisor tạo key devices:delete,
+// operator một site tạo key mọi site)
+func (uc *APIKeysUsecase) requireDelegable(ctx context.Context, in dto.CreateAPIKeyCmd) error {
+	for _, s := range in.Scopes {
+		if err := uc.authz.Require(ctx, authz.Action(s)); err != nil {
+			return err
+		}
+	}
+	p, _ := authz.PrincipalFrom(ctx)
+	if len(p.Sites) > 0 {
+		if len(in.Sites) == 0 {
+			return fmt.Errorf("%w: %s is limited to sites %v; key must list sites", authz.ErrForbidden, p.Subject, p.Sites)
+		}
+		for _, site := range in.Sites {
+			if !p.AtSite(site) {
+				return fmt.Errorf("%w: %s is not allowed at site %q", authz.ErrForbidden, p.Subject, site)
+			}
+		}
+	}
+	if len(p.DeviceIDs) > 0 {
+		if len(in.DeviceIDs) == 0 {
+			return fmt.Errorf("%w: %s is limited to devices %v; key must list devices", authz.ErrForbidden, p.Subject, p.DeviceIDs)
+		}
+		for _, id := range in.DeviceIDs {
+			if !p.ForDevice(id) {
+				return fmt.Errorf("%w: %s is not allowed on device %d", authz.ErrForbidden, p.Subject, id)
+			}
+		}
+	}
+	return nil
+}
+
+func generateAPIKey() (prefix, key string, err error) {
+	b := make([]byte, 4+24)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	prefix = apiKeyTag + hex.EncodeToString(b[:4])
+	return prefix, prefix + "_" + hex.EncodeToString(b[4:]), nil
+}
+
+func apiKeyPrefix(raw string) (string, bool) {
+	if !strings.HasPrefix(raw, apiKeyTag) {
+		return "", false
+	}
+	i := strings.LastIndexByte(raw, '_')
+	if i <= len(apiKeyTag) {
+		return "", false
+	}
+	return raw[:i], true
+}
+
+func hashAPIKey(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/authz"
+	"wh-ma/internal/usecase/dto"
+)
+
+// Key không được rộng hơn principal tạo ra nó.
+func TestAPIKeyCreateRejectsEscalation(t *testing.T) {
+	manage := string(authz.APIKeysManage)
+	siteAdmin := domain.Principal{Subject: "adm-a", Role: domain.RoleAdmin, Sites: []string{"site-a"}}
+	scopedKey := domain.Principal{Subject: "key:1", Scopes: []string{manage, "readings:write"}, DeviceIDs: []domain.DeviceID{1, 2}}
+
+	tests := []struct {
+		name    string
+		caller  domain.Principal
+		in      dto.CreateAPIKeyCmd
+		wantErr error
+	}{
+		{"key scope caller lacks", scopedKey,
+			dto.CreateAPIKeyCmd{Name: "k", Scopes: []string{"devices:delete"}, DeviceIDs: []domain.DeviceID{1}}, domain.ErrForbidden},
+		{"all sites from one site", siteAdmin,
+			dto.CreateAPIKeyCmd{Name: "k", Scopes: []string{"readings:write"}}, domain.ErrForbidden},
+		{"other site", siteAdmin,
+			dto.CreateAPIKeyCmd{Name: "k", Scopes: []string{"readings:write"}, Sites: []string{"site-a", "site-b"}}, domain.ErrForbidden},
+		{"all devices from device list", scopedKey,
+			dto.CreateAPIKeyCmd{Name: "k", Scopes: []string{"readings:write"}}, domain.ErrForbidden},
+		{"other device", scopedKey,
+			dto.CreateAPIKeyCmd{Name: "k", Scopes: []string{"readings:write"}, DeviceIDs: []domain.DeviceID{3}}, domain.ErrForbidden},
+
+		{"subset of sites", siteAdmin,
+			dto.CreateAPIKeyCmd{Name: "k", Scopes: []string{"readings:write", "devices:delete"}, Sites: []string{"site-a"}}, nil},
+		{"subset of devices and scopes", scopedKey,
+			dto.CreateAPIKeyCmd{Name: "k", Scopes: []string{"readings:write"}, DeviceIDs: []domain.DeviceID{2}}, nil},
+		{"unrestricted admin", domain.Principal{Subject: "admin", Role: domain.RoleAdmin},
+			dto.CreateAPIKeyCmd{Name: "k", Scopes: []string{"devices:delete"}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			issued, err := f.apiKeys.Create(as(tt.caller), tt.in)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			// key mới dùng được, với đúng phạm vi đã xin
+			p, err := f.apiKeys.Authenticate(admin(), issued.Secret)
+			if err != nil {
+				t.Fatalf("Authenticate: %v", err)
+			}
+			if len(p.Scopes) != len(tt.in.Scopes) || len(p.Sites) != len(tt.in.Sites) || len(p.DeviceIDs) != len(tt.in.DeviceIDs) {
+				t.Fatalf("principal = %+v, want scopes/sites/devices of %+v", p, tt.in)
+			}
+		})
+	}
+}
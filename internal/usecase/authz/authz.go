@@ -0,0 +1,158 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+
+	"wh-ma/internal/domain"
+)
+
+// ==== Actions (quyền nghiệp vụ mà usecase kiểm tra) ====
+type Action string
+
+const (
+	DevicesRead   Action = "devices:read"
+	DevicesWrite  Action = "devices:write"
+	DevicesPlan   Action = "devices:plan"   // gán/bỏ kế hoạch cho thiết bị
+	DevicesSerial Action = "devices:serial" // sửa serial_number (định danh với nhà sản xuất/bảo hành)
+	DevicesDelete Action = "devices:delete"
+
+	PlansRead  Action = "plans:read"
+	PlansWrite Action = "plans:write"
+
+	ReadingsRead  Action = "readings:read"
+	ReadingsWrite Action = "readings:write"
+
+	MaintenanceRead  Action = "maintenance:read"
+	MaintenanceWrite Action = "maintenance:write"
+
+	AlertsRead    Action = "alerts:read"
+	AlertsResolve Action = "alerts:resolve"
+
+	ExportsRun Action = "exports:run" // xuất dữ liệu hàng loạt; còn cần quyền :read của loại dữ liệu
+
+	APIKeysManage Action = "apikeys:manage"
+
+	JobsRead Action = "jobs:read" // trạng thái + lịch sử job nền (cấp hệ thống)
+
+	LoggingAdmin Action = "logging:admin" // xem/đổi log level lúc chạy (cấp hệ thống)
+)
+
+// AllActions: dùng để validate policy đọc từ file cấu hình
+var AllActions = []Action{
+	DevicesRead, DevicesWrite, DevicesPlan, DevicesSerial, DevicesDelete,
+	PlansRead, PlansWrite,
+	ReadingsRead, ReadingsWrite,
+	MaintenanceRead, MaintenanceWrite,
+	AlertsRead, AlertsResolve,
+	ExportsRun,
+	APIKeysManage,
+	JobsRead,
+	LoggingAdmin,
+}
+
+func IsKnownAction(a Action) bool {
+	for _, x := range AllActions {
+		if x == a {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	ErrUnauthenticated = domain.ErrUnauthenticated
+	ErrForbidden       = domain.ErrForbidden
+)
+
+// ==== Principal trong context (middleware gắn, usecase đọc) ====
+type ctxPrincipalKey struct{}
+
+func WithPrincipal(ctx context.Context, p domain.Principal) context.Context {
+	return context.WithValue(ctx, ctxPrincipalKey{}, p)
+}
+
+func PrincipalFrom(ctx context.Context) (domain.Principal, bool) {
+	if ctx == nil {
+		return domain.Principal{}, false
+	}
+	p, ok := ctx.Value(ctxPrincipalKey{}).(domain.Principal)
+	return p, ok
+}
+
+// ==== Authorizer: usecase gọi trước mỗi thao tác ====
+type Authorizer struct {
+	policy *Policy
+}
+
+func NewAuthorizer(p *Policy) *Authorizer {
+	if p == nil {
+		p = DefaultPolicy()
+	}
+	return &Authorizer{policy: p}
+}
+
+// Require: principal trong ctx phải có quyền act
+func (a *Authorizer) Require(ctx context.Context, act Action) error {
+	_, err := a.principalFor(ctx, act)
+	return err
+}
+
+// RequireDevice: như Require, thêm ràng buộc theo thiết bị cụ thể
+//   - role site_scoped (vd operator) chỉ thao tác ở site trong principal
+//   - API key có thể bị giới hạn theo danh sách site/device
+func (a *Authorizer) RequireDevice(ctx context.Context, act Action, dev *domain.Device) error {
+	p, err := a.principalFor(ctx, act)
+	if err != nil {
+		return err
+	}
+	if err := a.requireSites(p); err != nil {
+		return err
+	}
+	site := dev.State.Location
+	if !p.AtSite(site) {
+		return fmt.Errorf("%w: %s is not allowed at site %q", ErrForbidden, p.Subject, site)
+	}
+	if !p.ForDevice(dev.ID) {
+		return fmt.Errorf("%w: %s is not allowed on device %d", ErrForbidden, p.Subject, dev.ID)
+	}
+	return nil
+}
+
+// RequireScope: như Require, trả phạm vi device của principal để lọc trong query danh sách
+// (cùng ràng buộc với RequireDevice, áp cho cả tập thay vì từng device)
+func (a *Authorizer) RequireScope(ctx context.Context, act Action) (domain.DeviceScope, error) {
+	p, err := a.principalFor(ctx, act)
+	if err != nil {
+		return domain.DeviceScope{}, err
+	}
+	if err := a.requireSites(p); err != nil {
+		return domain.DeviceScope{}, err
+	}
+	return p.DeviceScope(), nil
+}
+
+// requireSites: role site_scoped chưa được gán site thì không thấy site nào
+func (a *Authorizer) requireSites(p domain.Principal) error {
+	if a.policy.siteScoped(p.Role) && len(p.Sites) == 0 {
+		return fmt.Errorf("%w: %s has no site assigned", ErrForbidden, p.Subject)
+	}
+	return nil
+}
+
+func (a *Authorizer) principalFor(ctx context.Context, act Action) (domain.Principal, error) {
+	p, ok := PrincipalFrom(ctx)
+	if !ok {
+		return domain.Principal{}, ErrUnauthenticated
+	}
+	if len(p.Scopes) > 0 { // API key: chỉ theo scopes
+		if !p.HasScope(string(act)) {
+			return p, fmt.Errorf("%w: key scopes do not include %s", ErrForbidden, act)
+		}
+		return p, nil
+	}
+	if !a.policy.Allows(p.Role, act) {
+		return p, fmt.Errorf("%w: role %q cannot %s", ErrForbidden, p.Role, act)
+	}
+	return p, nil
+}
@@ -0,0 +1,115 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"wh-ma/internal/domain"
+)
+
+func TestRequire(t *testing.T) {
+	az := NewAuthorizer(nil)
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		act     Action
+		wantErr error
+	}{
+		{"no principal", context.Background(), DevicesRead, ErrUnauthenticated},
+		{"operator reads devices", withRole(domain.RoleOperator), DevicesRead, nil},
+		{"operator cannot delete", withRole(domain.RoleOperator), DevicesDelete, ErrForbidden},
+		{"technician logs maintenance", withRole(domain.RoleTechnician), MaintenanceWrite, nil},
+		{"supervisor cannot change serial", withRole(domain.RoleSupervisor), DevicesSerial, ErrForbidden},
+		{"admin wildcard", withRole(domain.RoleAdmin), LoggingAdmin, nil},
+		{"unknown role", withRole("guest"), DevicesRead, ErrForbidden},
+		// API key: chỉ theo scopes, role bị bỏ qua
+		{"key scope", withKey(DevicesRead), DevicesRead, nil},
+		{"key without scope", withKey(DevicesRead), DevicesWrite, ErrForbidden},
+		{"key scopes override admin role", WithPrincipal(context.Background(),
+			domain.Principal{Subject: "k", Role: domain.RoleAdmin, Scopes: []string{string(PlansRead)}}), DevicesRead, ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := az.Require(tt.ctx, tt.act)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("Require(%s) = %v, want %v", tt.act, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRequireDevice(t *testing.T) {
+	az := NewAuthorizer(nil)
+	dev := &domain.Device{ID: 7, State: domain.OperationalState{Location: "site-a"}}
+	tests := []struct {
+		name    string
+		p       domain.Principal
+		act     Action
+		wantErr error
+	}{
+		{"operator at site", domain.Principal{Role: domain.RoleOperator, Sites: []string{"site-a"}}, ReadingsWrite, nil},
+		{"operator at other site", domain.Principal{Role: domain.RoleOperator, Sites: []string{"site-b"}}, ReadingsWrite, ErrForbidden},
+		{"site-scoped role without sites", domain.Principal{Role: domain.RoleOperator}, ReadingsWrite, ErrForbidden},
+		{"role check still applies", domain.Principal{Role: domain.RoleOperator, Sites: []string{"site-a"}}, DevicesDelete, ErrForbidden},
+		{"supervisor anywhere", domain.Principal{Role: domain.RoleSupervisor}, DevicesWrite, nil},
+		{"supervisor limited to other site", domain.Principal{Role: domain.RoleSupervisor, Sites: []string{"site-b"}}, DevicesWrite, ErrForbidden},
+		{"key for device", domain.Principal{Scopes: []string{string(ReadingsWrite)}, DeviceIDs: []domain.DeviceID{7}}, ReadingsWrite, nil},
+		{"key for other device", domain.Principal{Scopes: []string{string(ReadingsWrite)}, DeviceIDs: []domain.DeviceID{8}}, ReadingsWrite, ErrForbidden},
+		{"key for other site", domain.Principal{Scopes: []string{string(ReadingsWrite)}, Sites: []string{"site-b"}}, ReadingsWrite, ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.p.Subject = "u"
+			err := az.RequireDevice(WithPrincipal(context.Background(), tt.p), tt.act, dev)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("RequireDevice(%s) = %v, want %v", tt.act, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	p, err := LoadPolicy(filepath.Join("..", "..", "..", "configs", "rbac.yaml"))
+	if err != nil {
+		t.Fatalf("configs/rbac.yaml: %v", err)
+	}
+	for _, role := range []domain.Role{domain.RoleOperator, domain.RoleTechnician, domain.RoleSupervisor, domain.RoleAdmin} {
+		for _, act := range AllActions {
+			if got, want := p.Allows(role, act), DefaultPolicy().Allows(role, act); got != want {
+				t.Errorf("rbac.yaml %s %s = %v, default policy = %v", role, act, got, want)
+			}
+		}
+	}
+
+	bad := map[string]string{
+		"unknown role":   "roles:\n  guest:\n    actions: [devices:read]\n",
+		"unknown action": "roles:\n  operator:\n    actions: [devices:fly]\n",
+		"not yaml":       "roles: [",
+	}
+	for name, src := range bad {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "rbac.yaml")
+			if err := os.WriteFile(path, []byte(src), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadPolicy(path); err == nil {
+				t.Fatal("LoadPolicy: want error")
+			}
+		})
+	}
+}
+
+func withRole(r domain.Role) context.Context {
+	return WithPrincipal(context.Background(), domain.Principal{Subject: "u", Role: r})
+}
+
+func withKey(scopes ...Action) context.Context {
+	p := domain.Principal{Subject: "key"}
+	for _, s := range scopes {
+		p.Scopes = append(p.Scopes, string(s))
+	}
+	return WithPrincipal(context.Background(), p)
+}
@@ -0,0 +1,115 @@
+package authz
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"wh-ma/internal/domain"
+)
+
+// ==== Policy: role -> danh sách action (định nghĩa trong configs/rbac.yaml) ====
+type Policy struct {
+	roles map[domain.Role]rolePolicy
+}
+
+type rolePolicy struct {
+	SiteScoped bool     `yaml:"site_scoped"` // true: chỉ thao tác ở site trong principal
+	Actions    []Action `yaml:"actions"`     // "*" = mọi action
+
+	allowed map[Action]bool
+}
+
+type policyFile struct {
+	Roles map[domain.Role]rolePolicy `yaml:"roles"`
+}
+
+// LoadPolicy đọc policy từ file YAML, báo lỗi nếu role/action không hợp lệ
+func LoadPolicy(path string) (*Policy, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read rbac policy: %w", err)
+	}
+	var f policyFile
+	if err := yaml.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("parse rbac policy %s: %w", path, err)
+	}
+	return newPolicy(f.Roles)
+}
+
+// DefaultPolicy: dùng khi không cấu hình RBAC_POLICY_FILE
+func DefaultPolicy() *Policy {
+	p, err := newPolicy(map[domain.Role]rolePolicy{
+		domain.RoleOperator: {
+			SiteScoped: true,
+			Actions:    []Action{DevicesRead, PlansRead, ReadingsRead, ReadingsWrite, AlertsRead},
+		},
+		domain.RoleTechnician: {
+			Actions: []Action{DevicesRead, PlansRead, ReadingsRead, MaintenanceRead, MaintenanceWrite, AlertsRead},
+		},
+		domain.RoleSupervisor: {
+			Actions: []Action{
+				DevicesRead, DevicesWrite, DevicesPlan,
+				PlansRead, PlansWrite,
+				ReadingsRead, ReadingsWrite,
+				MaintenanceRead, MaintenanceWrite,
+				AlertsRead, AlertsResolve,
+				ExportsRun,
+			},
+		},
+		domain.RoleAdmin: {Actions: []Action{"*"}},
+	})
+	if err != nil {
+		panic(err) // policy mặc định luôn hợp lệ
+	}
+	return p
+}
+
+func (p *Policy) Allows(role domain.Role, act Action) bool {
+	rp, ok := p.roles[role]
+	if !ok {
+		return false
+	}
+	return rp.allowed["*"] || rp.allowed[act]
+}
+
+func (p *Policy) siteScoped(role domain.Role) bool {
+	return p.roles[role].SiteScoped
+}
+
+func newPolicy(roles map[domain.Role]rolePolicy) (*Policy, error) {
+	known := make(map[Action]bool, len(AllActions)+1)
+	known["*"] = true
+	for _, a := range AllActions {
+		known[a] = true
+	}
+
+	out := &Policy{roles: make(map[domain.Role]rolePolicy, len(roles))}
+	for role, rp := range roles {
+		if !isKnownRole(role) {
+			return nil, fmt.Errorf("rbac policy: unknown role %q", role)
+		}
+		rp.allowed = make(map[Action]bool, len(rp.Actions))
+		for _, a := range rp.Actions {
+			if !known[a] {
+				return nil, fmt.Errorf("rbac policy: role %q has unknown action %q", role, a)
+			}
+			rp.allowed[a] = true
+		}
+		out.roles[role] = rp
+	}
+	return out, nil
+}
+
+func isKnownRole(r domain.Role) bool {
+	switch r {
+	case domain.RoleOperator,
+		domain.RoleTechnician,
+		domain.RoleSupervisor,
+		domain.RoleAdmin:
+		return true
+	default:
+		return false
+	}
+}
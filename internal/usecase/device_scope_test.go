@@ -0,0 +1,230 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	outport "wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/authz"
+	"wh-ma/internal/usecase/dto"
+)
+
+// Mọi thao tác theo device phải áp site/device của principal, không chỉ quyền theo role/scope.
+func TestDeviceScopedCallsDenyOutsidePrincipal(t *testing.T) {
+	allScopes := make([]string, 0, len(authz.AllActions))
+	for _, a := range authz.AllActions {
+		allScopes = append(allScopes, string(a))
+	}
+	denied := map[string]domain.Principal{
+		"supervisor at other site":  {Subject: "sup-b", Role: domain.RoleSupervisor, Sites: []string{"site-b"}},
+		"admin at other site":       {Subject: "adm-b", Role: domain.RoleAdmin, Sites: []string{"site-b"}},
+		"key bound to other device": {Subject: "key:1", Scopes: allScopes, DeviceIDs: []domain.DeviceID{9999}},
+		"key bound to other site":   {Subject: "key:2", Scopes: allScopes, Sites: []string{"site-b"}},
+	}
+
+	calls := []struct {
+		name string
+		call func(ctx context.Context, f *fixture, dev *domain.Device, alertID int64) error
+	}{
+		{"devices.Get", func(ctx context.Context, f *fixture, dev *domain.Device, _ int64) error {
+			_, err := f.devices.Get(ctx, dev.ID)
+			return err
+		}},
+		{"devices.UpdateBasic", func(ctx context.Context, f *fixture, dev *domain.Device, _ int64) error {
+			_, err := f.devices.UpdateBasic(ctx, dto.UpdateDeviceBasicCmd{ID: dev.ID, Name: "x", Status: domain.StatusActive, Location: ptrTo("site-a")})
+			return err
+		}},
+		{"devices.UpdatePlan", func(ctx context.Context, f *fixture, dev *domain.Device, _ int64) error {
+			_, err := f.devices.UpdatePlan(ctx, dto.UpdateDevicePlanCmd{ID: dev.ID})
+			return err
+		}},
+		{"devices.SoftDelete", func(ctx context.Context, f *fixture, dev *domain.Device, _ int64) error {
+			return f.devices.SoftDelete(ctx, dev.ID)
+		}},
+		{"devices.Patch", func(ctx context.Context, f *fixture, dev *domain.Device, _ int64) error {
+			_, err := f.devices.Patch(ctx, dto.PatchDeviceCmd{ID: dev.ID, Name: dto.Opt[string]{Set: true, Value: ptrTo("x")}})
+			return err
+		}},
+		{"devices.ListAudit", func(ctx context.Context, f *fixture, dev *domain.Device, _ int64) error {
+			_, err := f.devices.ListAudit(ctx, dev.ID, 10, 0)
+			return err
+		}},
+		{"readings.Submit", func(ctx context.Context, f *fixture, dev *domain.Device, _ int64) error {
+			_, err := f.readings.Submit(ctx, dto.SubmitReadingCmd{DeviceID: dev.ID, HoursDelta: 0})
+			return err
+		}},
+		{"readings.ListByDevice", func(ctx context.Context, f *fixture, dev *domain.Device, _ int64) error {
+			_, err := f.readings.ListByDevice(ctx, dev.ID, 10, 0)
+			return err
+		}},
+		{"maintenance.Log", func(ctx context.Context, f *fixture, dev *domain.Device, _ int64) error {
+			_, err := f.maint.Log(ctx, dto.LogMaintenanceCmd{DeviceID: dev.ID})
+			return err
+		}},
+		{"maintenance.ListByDevice", func(ctx context.Context, f *fixture, dev *domain.Device, _ int64) error {
+			_, err := f.maint.ListByDevice(ctx, dev.ID, 10, 0)
+			return err
+		}},
+		{"alerts.ListOpenByDevice", func(ctx context.Context, f *fixture, dev *domain.Device, _ int64) error {
+			_, err := f.alerts.ListOpenByDevice(ctx, dev.ID, 10, 0)
+			return err
+		}},
+		{"alerts.Resolve", func(ctx context.Context, f *fixture, _ *domain.Device, alertID int64) error {
+			_, err := f.alerts.Resolve(ctx, alertID)
+			return err
+		}},
+	}
+
+	allowed := domain.Principal{Subject: "adm-a", Role: domain.RoleAdmin, Sites: []string{"site-a"}}
+
+	for _, c := range calls {
+		t.Run(c.name+"/allowed in site", func(t *testing.T) {
+			f := newFixture(t)
+			dev := f.device(t, "SN-1", "site-a")
+			al, err := f.repos.Alerts.Create(admin(), outport.CreateAlertInput{DeviceID: dev.ID, Type: "idle_too_long", Message: "m"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			// lỗi nghiệp vụ khác (vd. còn alert mở) vẫn có thể xảy ra, chỉ không phải forbidden
+			if err := c.call(as(allowed), f, dev, al.ID); errors.Is(err, domain.ErrForbidden) {
+				t.Fatalf("err = %v, want not forbidden", err)
+			}
+		})
+		for who, p := range denied {
+			t.Run(c.name+"/"+who, func(t *testing.T) {
+				f := newFixture(t)
+				dev := f.device(t, "SN-1", "site-a")
+				al, err := f.repos.Alerts.Create(admin(), outport.CreateAlertInput{DeviceID: dev.ID, Type: "idle_too_long", Message: "m"})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := c.call(as(p), f, dev, al.ID); !errors.Is(err, domain.ErrForbidden) {
+					t.Fatalf("err = %v, want ErrForbidden", err)
+				}
+			})
+		}
+	}
+}
+
+// Đổi location sang site ngoài phạm vi cũng bị chặn, kể cả khi device hiện ở site được phép.
+func TestDeviceMoveOutsideSitesDenied(t *testing.T) {
+	f := newFixture(t)
+	dev := f.device(t, "SN-1", "site-a")
+	ctx := as(domain.Principal{Subject: "sup-a", Role: domain.RoleSupervisor, Sites: []string{"site-a"}})
+
+	_, err := f.devices.UpdateBasic(ctx, dto.UpdateDeviceBasicCmd{ID: dev.ID, Name: dev.Name, Status: dev.Status, Location: ptrTo("site-b")})
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("UpdateBasic: err = %v, want ErrForbidden", err)
+	}
+	_, err = f.devices.Patch(ctx, dto.PatchDeviceCmd{ID: dev.ID, Location: dto.Opt[string]{Set: true, Value: ptrTo("site-b")}})
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("Patch: err = %v, want ErrForbidden", err)
+	}
+
+	// trong site: được phép
+	if _, err := f.devices.UpdateBasic(ctx, dto.UpdateDeviceBasicCmd{ID: dev.ID, Name: "đổi tên", Status: dev.Status, Location: ptrTo("site-a")}); err != nil {
+		t.Fatalf("UpdateBasic in site: %v", err)
+	}
+}
+
+// List/Get cùng phạm vi với export: operator chỉ thấy device ở site của mình.
+func TestDeviceListAndGetScopedToSites(t *testing.T) {
+	f := newFixture(t)
+	a1 := f.device(t, "SN-A1", "site-a")
+	b1 := f.device(t, "SN-B1", "site-b")
+	a2 := f.device(t, "SN-A2", "site-a")
+
+	cases := []struct {
+		name string
+		p    domain.Principal
+		want []domain.DeviceID
+	}{
+		{"operator at site-a", domain.Principal{Subject: "op-a", Role: domain.RoleOperator, Sites: []string{"site-a"}}, []domain.DeviceID{a1.ID, a2.ID}},
+		{"operator at site-b", domain.Principal{Subject: "op-b", Role: domain.RoleOperator, Sites: []string{"site-b"}}, []domain.DeviceID{b1.ID}},
+		{"supervisor at both", domain.Principal{Subject: "sup", Role: domain.RoleSupervisor, Sites: []string{"site-a", "site-b"}}, []domain.DeviceID{a1.ID, b1.ID, a2.ID}},
+		{"technician unscoped", domain.Principal{Subject: "tech", Role: domain.RoleTechnician}, []domain.DeviceID{a1.ID, b1.ID, a2.ID}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := as(tc.p)
+			devs, err := f.devices.List(ctx, 100, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []domain.DeviceID
+			for _, d := range devs {
+				got = append(got, d.ID)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Fatalf("List = %v, want %v", got, tc.want)
+			}
+			for _, d := range []*domain.Device{a1, b1, a2} {
+				_, err := f.devices.Get(ctx, d.ID)
+				if visible := slices.Contains(tc.want, d.ID); visible != (err == nil) {
+					t.Errorf("Get(%d): err = %v, want visible = %v", d.ID, err, visible)
+				}
+			}
+		})
+	}
+
+	t.Run("paging counts only visible devices", func(t *testing.T) {
+		ctx := as(domain.Principal{Subject: "op-a", Role: domain.RoleOperator, Sites: []string{"site-a"}})
+		devs, err := f.devices.List(ctx, 1, 1)
+		if err != nil || len(devs) != 1 || devs[0].ID != a2.ID {
+			t.Fatalf("List(1, 1) = %v, %v; want [%d]", devs, err, a2.ID)
+		}
+	})
+	t.Run("site-scoped role without sites", func(t *testing.T) {
+		_, err := f.devices.List(as(domain.Principal{Subject: "op", Role: domain.RoleOperator}), 100, 0)
+		if !errors.Is(err, domain.ErrForbidden) {
+			t.Fatalf("err = %v, want ErrForbidden", err)
+		}
+	})
+}
+
+// API key giới hạn theo device/site không liệt kê được cả tenant qua GET /devices.
+func TestDeviceListScopedToAPIKey(t *testing.T) {
+	f := newFixture(t)
+	a1 := f.device(t, "SN-A1", "site-a")
+	b1 := f.device(t, "SN-B1", "site-b")
+	a2 := f.device(t, "SN-A2", "site-a")
+	read := []string{string(authz.DevicesRead)}
+
+	cases := []struct {
+		name string
+		key  domain.Principal
+		want []domain.DeviceID
+	}{
+		{"bound to one device", domain.Principal{Subject: "key:1", Scopes: read, DeviceIDs: []domain.DeviceID{b1.ID}}, []domain.DeviceID{b1.ID}},
+		{"bound to site-a", domain.Principal{Subject: "key:2", Scopes: read, Sites: []string{"site-a"}}, []domain.DeviceID{a1.ID, a2.ID}},
+		{"site and device both apply", domain.Principal{Subject: "key:3", Scopes: read, Sites: []string{"site-a"}, DeviceIDs: []domain.DeviceID{a2.ID, b1.ID}}, []domain.DeviceID{a2.ID}},
+		{"unbound", domain.Principal{Subject: "key:4", Scopes: read}, []domain.DeviceID{a1.ID, b1.ID, a2.ID}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			devs, err := f.devices.List(as(tc.key), 100, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []domain.DeviceID
+			for _, d := range devs {
+				got = append(got, d.ID)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Fatalf("List = %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	t.Run("key without devices:read", func(t *testing.T) {
+		key := domain.Principal{Subject: "key:5", Scopes: []string{string(authz.ReadingsWrite)}, DeviceIDs: []domain.DeviceID{a1.ID}}
+		if _, err := f.devices.List(as(key), 100, 0); !errors.Is(err, domain.ErrForbidden) {
+			t.Fatalf("err = %v, want ErrForbidden", err)
+		}
+	})
+}
+
+func ptrTo[T any](v T) *T { return &v }
@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	outport "wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/authz"
+	"wh-ma/internal/usecase/dto"
+)
+
+// MaxImportRows: giới hạn một lần nhập (một transaction)
+const MaxImportRows = 5000
+
+// errImportRejected: một dòng lỗi khi ghi -> rollback toàn bộ, lỗi đã nằm trong báo cáo
+var errImportRejected = errors.New("import rejected")
+
+// 7) IMPORT
+// - mỗi dòng kiểm như Create (validateCreate) + lỗi đọc ô từ adapter
+// - plan theo tên (không phân biệt hoa thường) -> id; tên trùng nhau giữa các plan thấy được => lỗi
+// - serial trùng trong file hoặc đã có trong DB (kể cả device đã xóa mềm) => lỗi dòng
+// - DryRun hoặc còn dòng lỗi: chỉ trả báo cáo
+// - ghi: tất cả trong một transaction; dòng nào lỗi khi ghi thì rollback hết
+func (uc *DevicesUsecase) Import(ctx context.Context, in dto.ImportDevicesCmd) (*dto.ImportReport, error) {
+	if err := uc.authz.Require(ctx, authz.DevicesWrite); err != nil {
+		return nil, err
+	}
+	if len(in.Rows) == 0 {
+		return nil, domain.Invalid("file", "has no data rows")
+	}
+	if len(in.Rows) > MaxImportRows {
+		return nil, domain.Invalid("file", fmt.Sprintf("has more than %d rows", MaxImportRows))
+	}
+
+	plans, err := uc.planIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rep := &dto.ImportReport{DryRun: in.DryRun, Total: len(in.Rows), Rows: make([]dto.ImportRowResult, len(in.Rows))}
+	cmds := make([]dto.CreateDeviceCmd, len(in.Rows))
+	firstLine := map[string]int{} // serial -> dòng đầu tiên dùng nó
+	serials := make([]string, 0, len(in.Rows))
+
+	for i, row := range in.Rows {
+		cmd := row.Device
+		// lỗi đọc ô đứng trước; rule của Create chỉ báo cho field đọc được
+		v := domain.Violations(append([]domain.FieldError(nil), row.Errors...))
+		for _, fe := range validateCreate(cmd) {
+			if !hasField(row.Errors, fe.Field) {
+				v = append(v, fe)
+			}
+		}
+		if cmd.Status == "" {
+			cmd.Status = domain.StatusActive
+		}
+		switch {
+		case cmd.PlanID != nil:
+			if !plans.hasID(*cmd.PlanID) {
+				v.Add("plan_id", "references an unknown plan")
+			}
+		case row.PlanName != "":
+			id, msg := plans.resolve(row.PlanName)
+			if msg != "" {
+				v.Add("plan", msg)
+			} else {
+				cmd.PlanID = &id
+			}
+		}
+		if s := cmd.SerialNumber; s != "" {
+			if line, dup := firstLine[s]; dup {
+				v.Add("serial_number", fmt.Sprintf("duplicates line %d", line))
+			} else {
+				firstLine[s] = row.Line
+				serials = append(serials, s)
+			}
+		}
+		cmds[i] = cmd
+		rep.Rows[i] = dto.ImportRowResult{Line: row.Line, SerialNumber: cmd.SerialNumber, Errors: v}
+	}
+
+	taken, err := uc.devRepo.ListTakenSerials(ctx, serials)
+	if err != nil {
+		return nil, err
+	}
+	if len(taken) > 0 {
+		isTaken := make(map[string]bool, len(taken))
+		for _, s := range taken {
+			isTaken[s] = true
+		}
+		for i := range rep.Rows {
+			if isTaken[cmds[i].SerialNumber] {
+				rep.Rows[i].Errors = append(rep.Rows[i].Errors, domain.FieldError{Field: "serial_number", Message: "already exists"})
+			}
+		}
+	}
+
+	tally(rep)
+	if in.DryRun || rep.Invalid > 0 {
+		return rep, nil
+	}
+
+	created := make([]*domain.Device, len(cmds))
+	err = uc.tx.WithinTx(ctx, func(ctx context.Context, r outport.Repos) error {
+		for i, cmd := range cmds {
+			dev, err := r.Devices.Create(ctx, createInput(ctx, cmd))
+			var de *domain.Error
+			if errors.As(err, &de) {
+				// trạng thái đổi giữa lúc kiểm và lúc ghi (vd serial vừa bị lấy): gán lỗi cho dòng
+				rep.Rows[i].Errors = append(rep.Rows[i].Errors, rowErrors(de)...)
+				return errImportRejected
+			}
+			if err != nil {
+				return err
+			}
+			created[i] = dev
+		}
+		return nil
+	})
+	switch {
+	case errors.Is(err, errImportRejected):
+		tally(rep)
+		return rep, nil
+	case err != nil:
+		return nil, err
+	}
+	for i, dev := range created {
+		rep.Rows[i].Device = dev
+	}
+	rep.Committed = true
+	rep.Created = len(created)
+	return rep, nil
+}
+
+// tally: đếm lại số dòng hợp lệ/lỗi
+func tally(rep *dto.ImportReport) {
+	rep.Valid, rep.Invalid = 0, 0
+	for _, r := range rep.Rows {
+		if r.OK() {
+			rep.Valid++
+		} else {
+			rep.Invalid++
+		}
+	}
+}
+
+func hasField(errs []domain.FieldError, field string) bool {
+	for _, e := range errs {
+		if e.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// rowErrors: lỗi domain khi ghi -> lỗi field của dòng
+func rowErrors(e *domain.Error) []domain.FieldError {
+	if len(e.Fields) > 0 {
+		return e.Fields
+	}
+	field := ""
+	if e.Code == "serial_number_taken" {
+		field = "serial_number"
+	}
+	return []domain.FieldError{{Field: field, Message: e.Error()}}
+}
+
+// planIndex: plan thấy được (template dùng chung + của tenant), tra theo id và tên
+type planIndex struct {
+	ids    map[domain.PlanID]bool
+	byName map[string][]domain.PlanID
+}
+
+func (uc *DevicesUsecase) planIndex(ctx context.Context) (*planIndex, error) {
+	idx := &planIndex{ids: map[domain.PlanID]bool{}, byName: map[string][]domain.PlanID{}}
+	const page = 500
+	for offset := int32(0); ; offset += page {
+		plans, err := uc.planRepo.List(ctx, page, offset)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range plans {
+			idx.ids[p.ID] = true
+			key := strings.ToLower(strings.TrimSpace(p.Name))
+			idx.byName[key] = append(idx.byName[key], p.ID)
+		}
+		if len(plans) < page {
+			return idx, nil
+		}
+	}
+}
+
+func (p *planIndex) hasID(id domain.PlanID) bool { return p.ids[id] }
+
+// resolve: id theo tên, hoặc thông báo lỗi cho dòng
+func (p *planIndex) resolve(name string) (domain.PlanID, string) {
+	ids := p.byName[strings.ToLower(strings.TrimSpace(name))]
+	switch len(ids) {
+	case 0:
+		return 0, fmt.Sprintf("unknown plan %q", name)
+	case 1:
+		return ids[0], ""
+	default:
+		return 0, fmt.Sprintf("plan name %q is ambiguous; use plan_id", name)
+	}
+}
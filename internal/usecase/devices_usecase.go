@@ -8,21 +8,26 @@ import (
 	inport "wh-ma/internal/adapter/inbound/port"
 	outport "wh-ma/internal/adapter/outbound/port"
 	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/authz"
 	"wh-ma/internal/usecase/dto"
 )
 
 type DevicesUsecase struct {
 	devRepo   outport.DeviceRepository
 	planRepo  outport.PlanRepository
-	alertRepo outport.AlertRepository
+	auditRepo outport.DeviceAuditRepository
+	tx        outport.TxManager // ghi nhiều repo (plan + alert, hồ sơ + nhật ký) trong một transaction
+	authz     *authz.Authorizer
 }
 
 func NewDevicesUsecase(
 	devRepo outport.DeviceRepository,
 	planRepo outport.PlanRepository,
-	alertRepo outport.AlertRepository,
+	auditRepo outport.DeviceAuditRepository,
+	tx outport.TxManager,
+	az *authz.Authorizer,
 ) *DevicesUsecase {
-	return &DevicesUsecase{devRepo: devRepo, planRepo: planRepo, alertRepo: alertRepo}
+	return &DevicesUsecase{devRepo: devRepo, planRepo: planRepo, auditRepo: auditRepo, tx: tx, authz: az}
 }
 
 // ✅ compile-time check: UC triển khai inbound port
@@ -35,29 +40,45 @@ var _ inport.DevicesInbound = (*DevicesUsecase)(nil)
 // - if PlanID != nil -> verify plan exists
 // - ExpectedNextMaint: chưa tính ở đây (để Readings/Maintenance)
 func (uc *DevicesUsecase) Create(ctx context.Context, in dto.CreateDeviceCmd) (*domain.Device, error) {
+	if err := uc.authz.Require(ctx, authz.DevicesWrite); err != nil {
+		return nil, err
+	}
+	if err := validateCreate(in).Err(); err != nil {
+		return nil, err
+	}
+	if in.Status == "" {
+		in.Status = domain.StatusActive
+	}
+	if err := uc.checkPlanRef(ctx, in.PlanID); err != nil {
+		return nil, err
+	}
+	return uc.devRepo.Create(ctx, createInput(ctx, in))
+}
+
+// validateCreate: rule của Create (dùng chung với Import)
+func validateCreate(in dto.CreateDeviceCmd) domain.Violations {
+	var v domain.Violations
 	if in.SerialNumber == "" {
-		return nil, errors.New("serial_number is required")
+		v.Add("serial_number", "is required")
 	}
 	if in.Name == "" {
-		return nil, errors.New("name is required")
+		v.Add("name", "is required")
 	}
 	if in.Year < 1970 {
-		return nil, errors.New("year is invalid (must be >= 1970)")
+		v.Add("year", "must be >= 1970")
 	}
 	if in.CommissionDate != nil && in.CommissionDate.After(time.Now()) {
-		return nil, errors.New("commission_date cannot be in the future")
-	}
-	if in.Status == "" {
-		in.Status = domain.StatusActive
+		v.Add("commission_date", "cannot be in the future")
 	}
-	if in.PlanID != nil {
-		if _, err := uc.planRepo.GetByID(ctx, *in.PlanID); err != nil {
-			return nil, err
-		}
+	if in.Status != "" && !isAllowedStatus(in.Status) {
+		v.Add("status", "is invalid")
 	}
+	return v
+}
 
-	// map DTO -> input repo (outbound)
-	repoIn := outport.CreateDeviceInput{
+// createInput: map DTO -> input repo (outbound)
+func createInput(ctx context.Context, in dto.CreateDeviceCmd) outport.CreateDeviceInput {
+	return outport.CreateDeviceInput{
 		SerialNumber:   in.SerialNumber,
 		Name:           in.Name,
 		Model:          in.Model,
@@ -67,16 +88,32 @@ func (uc *DevicesUsecase) Create(ctx context.Context, in dto.CreateDeviceCmd) (*
 		Status:         in.Status,
 		Location:       valOrEmpty(in.Location),
 		PlanID:         in.PlanID,
+		CreatedBy:      actor(ctx),
 	}
-	return uc.devRepo.Create(ctx, repoIn)
 }
 
-// 5) GET/LIST: thuần repo
+// 5) GET/LIST: trong phạm vi site/device của principal
 func (uc *DevicesUsecase) Get(ctx context.Context, id domain.DeviceID) (*domain.Device, error) {
-	return uc.devRepo.GetByID(ctx, id)
+	if err := uc.authz.Require(ctx, authz.DevicesRead); err != nil {
+		return nil, err
+	}
+	dev, err := uc.devRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.authz.RequireDevice(ctx, authz.DevicesRead, dev); err != nil {
+		return nil, err
+	}
+	return dev, nil
 }
+
+// List: chỉ device trong phạm vi principal (site, device của API key), lọc trong query để phân trang đúng
 func (uc *DevicesUsecase) List(ctx context.Context, limit, offset int32) ([]*domain.Device, error) {
-	return uc.devRepo.List(ctx, limit, offset)
+	scope, err := uc.authz.RequireScope(ctx, authz.DevicesRead)
+	if err != nil {
+		return nil, err
+	}
+	return uc.devRepo.List(ctx, scope, limit, offset)
 }
 
 // 2) UPDATE BASIC
@@ -84,47 +121,66 @@ func (uc *DevicesUsecase) List(ctx context.Context, limit, offset int32) ([]*dom
 // - CHO phép chuyển từ decommissioned -> active (theo yêu cầu ACE)
 // - location có thể nil/"" đều được
 func (uc *DevicesUsecase) UpdateBasic(ctx context.Context, in dto.UpdateDeviceBasicCmd) (*domain.Device, error) {
+	if err := uc.authz.Require(ctx, authz.DevicesWrite); err != nil {
+		return nil, err
+	}
+	var v domain.Violations
 	if in.Name == "" {
-		return nil, errors.New("name is required")
+		v.Add("name", "is required")
 	}
 	if !isAllowedStatus(in.Status) {
-		return nil, errors.New("invalid status")
+		v.Add("status", "is invalid")
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
 	}
-	return uc.devRepo.UpdateBasic(ctx, in.ID, in.Name, in.Status, in.Location)
+	dev, err := uc.devRepo.GetByID(ctx, in.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.authz.RequireDevice(ctx, authz.DevicesWrite, dev); err != nil {
+		return nil, err
+	}
+	if err := uc.requireMoveTo(ctx, authz.DevicesWrite, dev, valOrEmpty(in.Location)); err != nil {
+		return nil, err
+	}
+	return uc.devRepo.UpdateBasic(ctx, in.ID, in.Name, in.Status, in.Location, actor(ctx), in.Version)
 }
 
 // 3) UPDATE PLAN
 // - gắn plan: verify tồn tại
 // - nếu AfterOverhaul >= IntervalHours ở thời điểm gắn -> tạo alert "maintenance_due" nếu chưa có
 // - bỏ plan: chỉ ghi nhận, không tạo/đóng alert
+// - đổi plan + tạo alert trong cùng transaction
 func (uc *DevicesUsecase) UpdatePlan(ctx context.Context, in dto.UpdateDevicePlanCmd) (*domain.Device, error) {
-	if in.PlanID != nil {
-		if _, err := uc.planRepo.GetByID(ctx, *in.PlanID); err != nil {
-			return nil, err
-		}
-	}
-	dev, err := uc.devRepo.UpdatePlan(ctx, in.ID, in.PlanID)
-	if err != nil {
+	if err := uc.authz.Require(ctx, authz.DevicesPlan); err != nil {
 		return nil, err
 	}
-
-	if in.PlanID != nil { // vừa gắn plan
-		plan, perr := uc.planRepo.GetByID(ctx, *in.PlanID)
-		if perr == nil && plan.IntervalHours > 0 {
-			if dev.State.AfterOverhaul >= plan.IntervalHours {
-				open, _ := uc.alertRepo.ListOpenByDevice(ctx, dev.ID, 50, 0)
-				for _, a := range open {
-					if a.Type == "maintenance_due" {
-						return dev, nil // đã có alert mở, thôi
-					}
-				}
-				_, _ = uc.alertRepo.Create(ctx, outport.CreateAlertInput{
-					DeviceID: dev.ID,
-					Type:     "maintenance_due",
-					Message:  "Thiết bị đã vượt ngưỡng giờ bảo dưỡng theo kế hoạch mới",
-				})
-			}
+	var dev *domain.Device
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context, r outport.Repos) error {
+		cur, err := r.Devices.GetForUpdate(ctx, in.ID)
+		if err != nil {
+			return err
+		}
+		if err := uc.authz.RequireDevice(ctx, authz.DevicesPlan, cur); err != nil {
+			return err
 		}
+		plan, err := planRef(ctx, r.Plans, in.PlanID)
+		if err != nil {
+			return err
+		}
+		dev, err = r.Devices.UpdatePlan(ctx, in.ID, in.PlanID, actor(ctx), in.Version)
+		if err != nil {
+			return err
+		}
+		if plan != nil && plan.IntervalHours > 0 && dev.State.AfterOverhaul >= plan.IntervalHours { // vừa gắn plan
+			return raiseMaintenanceDue(ctx, r.Alerts, dev.ID,
+				"Thiết bị đã vượt ngưỡng giờ bảo dưỡng theo kế hoạch mới")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	return dev, nil
 }
@@ -133,22 +189,208 @@ func (uc *DevicesUsecase) UpdatePlan(ctx context.Context, in dto.UpdateDevicePla
 //   - chỉ xóa mềm khi KHÔNG còn alert mở
 //   - KHÔNG xóa mềm nếu status là maintenance hoặc repair (đang thao tác kỹ thuật)
 //     (mid_repair được phép xóa theo yêu cầu)
+//   - khóa dòng device trong transaction: reading/alert mới không chen vào giữa lúc kiểm và lúc xóa
 func (uc *DevicesUsecase) SoftDelete(ctx context.Context, id domain.DeviceID) error {
-	dev, err := uc.devRepo.GetByID(ctx, id)
+	if err := uc.authz.Require(ctx, authz.DevicesDelete); err != nil {
+		return err
+	}
+	return uc.tx.WithinTx(ctx, func(ctx context.Context, r outport.Repos) error {
+		dev, err := r.Devices.GetForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := uc.authz.RequireDevice(ctx, authz.DevicesDelete, dev); err != nil {
+			return err
+		}
+		if dev.Status == domain.StatusMaintenance || dev.Status == domain.StatusRepair {
+			return domain.Conflict("device_busy", "cannot soft delete while device is under maintenance/repair")
+		}
+		open, err := r.Alerts.ListOpenByDevice(ctx, id, 1, 0)
+		if err != nil {
+			return err
+		}
+		if len(open) > 0 {
+			return domain.Conflict("device_has_open_alerts", "cannot soft delete while there are open alerts")
+		}
+		return r.Devices.SoftDelete(ctx, id, actor(ctx))
+	})
+}
+
+// 6) PATCH (JSON Merge Patch toàn bộ hồ sơ)
+// - cùng rule với Create: serial/name bắt buộc, Year >= 1970, CommissionDate <= today, status hợp lệ
+// - chỉ kiểm field có trong patch (dữ liệu cũ thiếu year vẫn sửa được field khác)
+// - đổi serial_number cần thêm quyền devices:serial
+// - không có If-Match cụ thể ("*") vẫn ghi theo version vừa đọc để không đè thay đổi xen giữa
+// - mỗi lần đổi thực sự ghi một dòng nhật ký {field: {from, to}}
+func (uc *DevicesUsecase) Patch(ctx context.Context, in dto.PatchDeviceCmd) (*domain.Device, error) {
+	if err := uc.authz.Require(ctx, authz.DevicesWrite); err != nil {
+		return nil, err
+	}
+	dev, err := uc.devRepo.GetByID(ctx, in.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.authz.RequireDevice(ctx, authz.DevicesWrite, dev); err != nil {
+		return nil, err
+	}
+	if in.Version != 0 && in.Version != dev.Version {
+		return nil, domain.PreconditionFailed("version_mismatch", "device was modified by someone else; reload and retry")
+	}
+
+	next, changes, err := mergeDevicePatch(dev, in)
 	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return dev, nil // patch không đổi gì: không ghi, không tăng version
+	}
+	if err := uc.requireMoveTo(ctx, authz.DevicesWrite, dev, next.Location); err != nil {
+		return nil, err
+	}
+	if _, ok := changes["serial_number"]; ok {
+		if err := uc.authz.Require(ctx, authz.DevicesSerial); err != nil {
+			return nil, err
+		}
+	}
+
+	next.UpdatedBy = actor(ctx)
+
+	// hồ sơ + nhật ký: cùng commit hoặc cùng rollback
+	var updated *domain.Device
+	err = uc.tx.WithinTx(ctx, func(ctx context.Context, r outport.Repos) error {
+		var err error
+		if updated, err = r.Devices.UpdateProfile(ctx, next); err != nil {
+			return err
+		}
+		_, err = r.DeviceAudit.Record(ctx, outport.RecordDeviceAuditInput{
+			DeviceID: updated.ID,
+			Action:   domain.AuditProfilePatched,
+			Actor:    actor(ctx),
+			Changes:  changes,
+		})
 		return err
+	})
+	if err != nil {
+		return nil, err
 	}
-	if dev.Status == domain.StatusMaintenance || dev.Status == domain.StatusRepair {
-		return errors.New("cannot soft delete while device is under maintenance/repair")
+	return updated, nil
+}
+
+// requireMoveTo: đổi location cũng phải được phép ở site đích
+// (không chuyển device ra khỏi các site mình được thao tác)
+func (uc *DevicesUsecase) requireMoveTo(ctx context.Context, act authz.Action, dev *domain.Device, location string) error {
+	if location == dev.State.Location {
+		return nil
 	}
-	open, _ := uc.alertRepo.ListOpenByDevice(ctx, id, 1, 0)
-	if len(open) > 0 {
-		return errors.New("cannot soft delete while there are open alerts")
+	moved := *dev
+	moved.State.Location = location
+	return uc.authz.RequireDevice(ctx, act, &moved)
+}
+
+func (uc *DevicesUsecase) ListAudit(ctx context.Context, id domain.DeviceID, limit, offset int32) ([]*domain.DeviceAuditEntry, error) {
+	if _, err := uc.Get(ctx, id); err != nil {
+		return nil, err
 	}
-	return uc.devRepo.SoftDelete(ctx, id)
+	return uc.auditRepo.ListByDevice(ctx, id, limit, offset)
+}
+
+// mergeDevicePatch: áp patch lên hồ sơ hiện tại -> input ghi DB + danh sách field thực sự đổi
+func mergeDevicePatch(dev *domain.Device, in dto.PatchDeviceCmd) (outport.UpdateDeviceProfileInput, map[string]domain.FieldChange, error) {
+	var commission *time.Time
+	if !dev.Profile.CommissionDate.IsZero() {
+		t := dev.Profile.CommissionDate
+		commission = &t
+	}
+	next := outport.UpdateDeviceProfileInput{
+		ID:              dev.ID,
+		SerialNumber:    dev.SerialNumber,
+		Name:            dev.Name,
+		Model:           dev.Profile.Model,
+		Manufacturer:    dev.Profile.Manufacturer,
+		Year:            dev.Profile.Year,
+		CommissionDate:  commission,
+		Status:          dev.Status,
+		Location:        dev.State.Location,
+		ExpectedVersion: dev.Version,
+	}
+	changes := map[string]domain.FieldChange{}
+	var v domain.Violations
+
+	setString := func(field string, o dto.Opt[string], dst *string, required bool) {
+		if !o.Set {
+			return
+		}
+		val := valOrEmpty(o.Value)
+		if required && val == "" {
+			v.Add(field, "is required")
+			return
+		}
+		if val != *dst {
+			changes[field] = domain.FieldChange{From: nilIfZero(*dst), To: nilIfZero(val)}
+			*dst = val
+		}
+	}
+	setString("serial_number", in.SerialNumber, &next.SerialNumber, true)
+	setString("name", in.Name, &next.Name, true)
+	setString("model", in.Model, &next.Model, false)
+	setString("manufacturer", in.Manufacturer, &next.Manufacturer, false)
+	setString("location", in.Location, &next.Location, false)
+
+	if in.Year.Set {
+		switch {
+		case in.Year.Value == nil || *in.Year.Value < 1970:
+			v.Add("year", "must be >= 1970")
+		case *in.Year.Value != next.Year:
+			changes["year"] = domain.FieldChange{From: nilIfZero(next.Year), To: *in.Year.Value}
+			next.Year = *in.Year.Value
+		}
+	}
+	if in.CommissionDate.Set {
+		to := in.CommissionDate.Value
+		switch {
+		case to != nil && to.After(time.Now()):
+			v.Add("commission_date", "cannot be in the future")
+		case !sameDate(next.CommissionDate, to):
+			changes["commission_date"] = domain.FieldChange{From: dateOrNil(next.CommissionDate), To: dateOrNil(to)}
+			next.CommissionDate = to
+		}
+	}
+	if in.Status.Set {
+		switch {
+		case in.Status.Value == nil:
+			v.Add("status", "is required")
+		case !isAllowedStatus(*in.Status.Value):
+			v.Add("status", "is invalid")
+		case *in.Status.Value != next.Status:
+			changes["status"] = domain.FieldChange{From: next.Status, To: *in.Status.Value}
+			next.Status = *in.Status.Value
+		}
+	}
+	if err := v.Err(); err != nil {
+		return next, nil, err
+	}
+	return next, changes, nil
 }
 
 // --- helpers ---
+
+// checkPlanRef: plan_id trong request trỏ tới plan không tồn tại => lỗi validate, không phải 404
+func (uc *DevicesUsecase) checkPlanRef(ctx context.Context, id *domain.PlanID) error {
+	_, err := planRef(ctx, uc.planRepo, id)
+	return err
+}
+
+func planRef(ctx context.Context, plans outport.PlanRepository, id *domain.PlanID) (*domain.Plan, error) {
+	if id == nil {
+		return nil, nil
+	}
+	plan, err := plans.GetByID(ctx, *id)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, domain.Invalid("plan_id", "references an unknown plan")
+	}
+	return plan, err
+}
+
 func isAllowedStatus(s domain.DeviceStatus) bool {
 	switch s {
 	case domain.StatusActive,
@@ -167,3 +409,60 @@ func valOrEmpty(p *string) string {
 	}
 	return ""
 }
+
+func nilIfZero[T comparable](x T) any {
+	var zero T
+	if x == zero {
+		return nil
+	}
+	return x
+}
+
+// commission_date là DATE: so theo ngày, nhật ký ghi "YYYY-MM-DD"
+func sameDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Format(time.DateOnly) == b.Format(time.DateOnly)
+}
+
+func dateOrNil(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.Format(time.DateOnly)
+}
+
+// actor: subject của principal đang gọi (user hoặc API key) để ghi audit
+func actor(ctx context.Context) string {
+	if p, ok := authz.PrincipalFrom(ctx); ok {
+		return p.Subject
+	}
+	return ""
+}
+
+// raiseMaintenanceDue: tạo alert "maintenance_due" nếu device chưa có alert mở cùng loại.
+// Gọi trong transaction: lỗi phải trả về để rollback cả thao tác.
+func raiseMaintenanceDue(ctx context.Context, alertRepo outport.AlertRepository, id domain.DeviceID, msg string) error {
+	_, err := raiseOpenAlert(ctx, alertRepo, id, "maintenance_due", msg)
+	return err
+}
+
+// raiseOpenAlert: tạo alert loại typ nếu device chưa có alert mở cùng loại; trả true nếu đã tạo
+func raiseOpenAlert(ctx context.Context, alertRepo outport.AlertRepository, id domain.DeviceID, typ, msg string) (bool, error) {
+	open, err := alertRepo.ListOpenByDevice(ctx, id, 50, 0)
+	if err != nil {
+		return false, err
+	}
+	for _, a := range open {
+		if a.Type == typ {
+			return false, nil // đã có alert mở, thôi
+		}
+	}
+	_, err = alertRepo.Create(ctx, outport.CreateAlertInput{
+		DeviceID: id,
+		Type:     typ,
+		Message:  msg,
+	})
+	return err == nil, err
+}
@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	outport "wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/authz"
+	"wh-ma/internal/usecase/dto"
+)
+
+// PATCH ghi updated_by = principal và đúng một dòng nhật ký với các field đã đổi.
+func TestPatchRecordsActorAndAudit(t *testing.T) {
+	f := newFixture(t)
+	dev := f.device(t, "SN-1", "site-a")
+	ctx := as(domain.Principal{Subject: "sup-1", Role: domain.RoleSupervisor})
+
+	got, err := f.devices.Patch(ctx, dto.PatchDeviceCmd{
+		ID:       dev.ID,
+		Version:  dev.Version,
+		Name:     dto.Opt[string]{Set: true, Value: ptrTo("Máy mới")},
+		Location: dto.Opt[string]{Set: true, Value: ptrTo("site-b")},
+		Model:    dto.Opt[string]{Set: true, Value: ptrTo("")}, // đã trống: không tính là đổi
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Audit.UpdatedBy != "sup-1" {
+		t.Errorf("updated_by = %q, want sup-1", got.Audit.UpdatedBy)
+	}
+	if got.Version != dev.Version+1 {
+		t.Errorf("version = %d, want %d", got.Version, dev.Version+1)
+	}
+	stored, err := f.devices.Get(admin(), dev.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Audit.UpdatedBy != "sup-1" || stored.Name != "Máy mới" || stored.State.Location != "site-b" {
+		t.Errorf("stored = %+v", stored)
+	}
+
+	entries, err := f.devices.ListAudit(admin(), dev.ID, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("audit entries = %d, want 1", len(entries))
+	}
+	e := entries[0]
+	if e.Actor != "sup-1" || e.Action != domain.AuditProfilePatched {
+		t.Errorf("audit = %+v", e)
+	}
+	want := map[string]domain.FieldChange{
+		"name":     {From: dev.Name, To: "Máy mới"},
+		"location": {From: "site-a", To: "site-b"},
+	}
+	if len(e.Changes) != len(want) {
+		t.Fatalf("changes = %v, want %v", e.Changes, want)
+	}
+	for k, w := range want {
+		if c := e.Changes[k]; c != w {
+			t.Errorf("changes[%s] = %v, want %v", k, c, w)
+		}
+	}
+}
+
+// Patch không đổi gì: không ghi, không nhật ký, không tăng version.
+func TestPatchNoopWritesNothing(t *testing.T) {
+	f := newFixture(t)
+	dev := f.device(t, "SN-1", "site-a")
+
+	got, err := f.devices.Patch(admin(), dto.PatchDeviceCmd{ID: dev.ID, Name: dto.Opt[string]{Set: true, Value: ptrTo(dev.Name)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Version != dev.Version {
+		t.Errorf("version = %d, want %d", got.Version, dev.Version)
+	}
+	entries, _ := f.devices.ListAudit(admin(), dev.ID, 10, 0)
+	if len(entries) != 0 {
+		t.Errorf("audit entries = %d, want 0", len(entries))
+	}
+}
+
+func TestPatchStaleVersion(t *testing.T) {
+	f := newFixture(t)
+	dev := f.device(t, "SN-1", "site-a")
+
+	_, err := f.devices.Patch(admin(), dto.PatchDeviceCmd{ID: dev.ID, Version: dev.Version + 1, Name: dto.Opt[string]{Set: true, Value: ptrTo("x")}})
+	if !errors.Is(err, domain.ErrPreconditionFailed) {
+		t.Fatalf("err = %v, want ErrPreconditionFailed", err)
+	}
+}
+
+// auditFailTx: TxManager thật nhưng repo nhật ký trong transaction luôn lỗi
+type auditFailTx struct{ inner outport.TxManager }
+
+type failingAudit struct{ outport.DeviceAuditRepository }
+
+func (failingAudit) Record(context.Context, outport.RecordDeviceAuditInput) (*domain.DeviceAuditEntry, error) {
+	return nil, errAudit
+}
+
+var errAudit = errors.New("audit down")
+
+func (m auditFailTx) WithinTx(ctx context.Context, fn func(ctx context.Context, r outport.Repos) error) error {
+	return m.inner.WithinTx(ctx, func(ctx context.Context, r outport.Repos) error {
+		r.DeviceAudit = failingAudit{r.DeviceAudit}
+		return fn(ctx, r)
+	})
+}
+
+// Ghi nhật ký lỗi -> cập nhật device cũng rollback (devices + device_audit cùng transaction).
+func TestPatchRollsBackWhenAuditFails(t *testing.T) {
+	f := newFixture(t)
+	dev := f.device(t, "SN-1", "site-a")
+	uc := NewDevicesUsecase(f.repos.Devices, f.repos.Plans, f.repos.DeviceAudit, auditFailTx{f.tx}, authz.NewAuthorizer(nil))
+
+	_, err := uc.Patch(admin(), dto.PatchDeviceCmd{ID: dev.ID, Version: dev.Version, Name: dto.Opt[string]{Set: true, Value: ptrTo("x")}})
+	if !errors.Is(err, errAudit) {
+		t.Fatalf("err = %v, want errAudit", err)
+	}
+	stored, err := f.devices.Get(admin(), dev.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Name != dev.Name || stored.Version != dev.Version {
+		t.Errorf("stored = %+v, want unchanged", stored)
+	}
+	entries, _ := f.devices.ListAudit(admin(), dev.ID, 10, 0)
+	if len(entries) != 0 {
+		t.Errorf("audit entries = %d, want 0", len(entries))
+	}
+}
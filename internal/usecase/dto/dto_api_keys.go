@@ -0,0 +1,20 @@
+package dto
+
+import (
+	"time"
+	"wh-ma/internal/domain"
+)
+
+type CreateAPIKeyCmd struct {
+	Name      string
+	Scopes    []string          // vd "readings:write"
+	DeviceIDs []domain.DeviceID // rỗng = mọi device
+	Sites     []string          // rỗng = mọi site
+	ExpiresAt *time.Time        // nil = không hết hạn
+}
+
+// IssuedAPIKey: key gốc chỉ trả về đúng một lần lúc tạo
+type IssuedAPIKey struct {
+	Key    *domain.APIKey
+	Secret string
+}
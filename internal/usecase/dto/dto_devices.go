@@ -22,9 +22,67 @@ type UpdateDeviceBasicCmd struct {
 	Name     string
 	Status   domain.DeviceStatus
 	Location *string
+	Version  int // từ If-Match; 0 = "*" (ghi đè không kiểm)
 }
 
 type UpdateDevicePlanCmd struct {
-	ID     domain.DeviceID
-	PlanID *domain.PlanID // nil = bỏ kế hoạch
+	ID      domain.DeviceID
+	PlanID  *domain.PlanID // nil = bỏ kế hoạch
+	Version int            // từ If-Match; 0 = "*"
 }
+
+// PatchDeviceCmd: JSON Merge Patch (RFC 7396) lên hồ sơ device.
+// Field không Set = giữ nguyên; Set + Value nil = xóa (chỉ field cho phép trống).
+type PatchDeviceCmd struct {
+	ID      domain.DeviceID
+	Version int // từ If-Match; 0 = "*"
+
+	SerialNumber   Opt[string]
+	Name           Opt[string]
+	Model          Opt[string]
+	Manufacturer   Opt[string]
+	Year           Opt[int]
+	CommissionDate Opt[time.Time]
+	Status         Opt[domain.DeviceStatus]
+	Location       Opt[string]
+}
+
+// Opt: giá trị có phân biệt "không gửi" (Set=false) với "gửi null" (Set=true, Value=nil)
+type Opt[T any] struct {
+	Set   bool
+	Value *T
+}
+
+// ImportDevicesCmd: nhập hàng loạt (CSV/XLSX đã được adapter đọc thành từng dòng).
+// DryRun = chỉ trả báo cáo, không ghi; ghi thật thì tất cả hoặc không dòng nào.
+type ImportDevicesCmd struct {
+	Rows   []ImportDeviceRow
+	DryRun bool
+}
+
+type ImportDeviceRow struct {
+	Line     int // số dòng trong file (để báo lỗi)
+	Device   CreateDeviceCmd
+	PlanName string              // tên plan; dùng khi Device.PlanID nil
+	Errors   []domain.FieldError // lỗi đọc ô (sai kiểu số/ngày) từ adapter
+}
+
+// ImportReport: kết quả từng dòng; Committed=true khi đã ghi toàn bộ
+type ImportReport struct {
+	DryRun    bool
+	Committed bool
+	Total     int
+	Valid     int
+	Invalid   int
+	Created   int
+	Rows      []ImportRowResult
+}
+
+type ImportRowResult struct {
+	Line         int
+	SerialNumber string
+	Errors       []domain.FieldError
+	Device       *domain.Device // chỉ có khi đã ghi
+}
+
+func (r ImportRowResult) OK() bool { return len(r.Errors) == 0 }
@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"io"
+	"time"
+
+	"wh-ma/internal/domain"
+)
+
+type ExportCmd struct {
+	Kind   domain.ExportKind
+	Format string // csv | xlsx | jsonl
+	Filter domain.ExportFilter
+}
+
+// ExportJobView: job + token tải (chỉ khi done và chưa hết hạn)
+type ExportJobView struct {
+	Job           *domain.ExportJob
+	DownloadToken string
+}
+
+// ExportDownload: file kết quả mở sẵn; caller phải Close File
+type ExportDownload struct {
+	Job      *domain.ExportJob
+	FileName string
+	File     io.ReadSeekCloser
+	Size     int64
+	ModTime  time.Time
+}
@@ -0,0 +1,9 @@
+package dto
+
+import "wh-ma/internal/domain"
+
+// TenantFleetStats: số liệu của một tenant (collector gắn nhãn tenant)
+type TenantFleetStats struct {
+	Tenant string
+	Stats  *domain.FleetStats
+}
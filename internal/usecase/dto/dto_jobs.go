@@ -0,0 +1,15 @@
+package dto
+
+import "wh-ma/internal/domain"
+
+// JobsOverview: scheduler trên replica trả lời + từng job kèm lần chạy mới nhất (của bất kỳ replica nào)
+type JobsOverview struct {
+	Instance string
+	Leader   bool
+	Jobs     []JobView
+}
+
+type JobView struct {
+	Info    domain.JobInfo
+	LastRun *domain.JobRun // nil = chưa chạy lần nào
+}
@@ -0,0 +1,15 @@
+package dto
+
+import (
+	"time"
+	"wh-ma/internal/domain"
+)
+
+type LogMaintenanceCmd struct {
+	DeviceID    domain.DeviceID
+	At          time.Time // zero -> thời điểm hiện tại
+	Interval    *int      // nil nếu không theo bậc interval
+	Notes       *string
+	PerformedBy *string // nil -> lấy từ principal
+	Cost        *string // decimal string, ví dụ "12345.67"
+}
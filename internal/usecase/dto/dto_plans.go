@@ -0,0 +1,17 @@
+package dto
+
+import "wh-ma/internal/domain"
+
+type CreatePlanCmd struct {
+	Name          string
+	IntervalHours int
+	Description   *string
+}
+
+type UpdatePlanCmd struct {
+	ID            domain.PlanID
+	Name          string
+	IntervalHours int
+	Description   *string
+	Version       int // từ If-Match; 0 = "*"
+}
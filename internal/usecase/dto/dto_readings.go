@@ -0,0 +1,69 @@
+package dto
+
+import (
+	"time"
+	"wh-ma/internal/domain"
+)
+
+type SubmitReadingCmd struct {
+	DeviceID   domain.DeviceID
+	At         time.Time // zero -> thời điểm hiện tại
+	HoursDelta int
+	Location   *string
+	OperatorID *string
+}
+
+// ImportReadingsCmd: nhập lịch sử giờ máy (sổ nhật ký) cho nhiều device.
+// Mỗi dòng có HoursDelta hoặc Meter (số đồng hồ tổng giờ; delta = chênh với số trước đó).
+type ImportReadingsCmd struct {
+	Rows   []ImportReadingRow
+	DryRun bool
+}
+
+type ImportReadingRow struct {
+	Line         int
+	DeviceID     domain.DeviceID // 0 = tra theo SerialNumber
+	SerialNumber string
+	At           time.Time
+	HoursDelta   *int
+	Meter        *int
+	Location     *string
+	OperatorID   *string
+	Errors       []domain.FieldError // lỗi đọc ô từ adapter
+}
+
+// ReadingsImportReport: kết quả từng dòng + tổng kết từng device (sau import, hoặc dự kiến nếu dry-run)
+type ReadingsImportReport struct {
+	DryRun     bool
+	Committed  bool
+	Total      int
+	Valid      int
+	Invalid    int
+	Duplicates int // dòng trùng hệt dòng khác: bỏ qua, không tính lỗi
+	Created    int
+	Rows       []ReadingImportRowResult
+	Devices    []ReadingImportDeviceResult
+}
+
+type ReadingImportRowResult struct {
+	Line        int
+	DeviceID    domain.DeviceID
+	At          time.Time
+	HoursDelta  int
+	DuplicateOf int // > 0: trùng dòng này, bỏ qua
+	Errors      []domain.FieldError
+}
+
+func (r ReadingImportRowResult) OK() bool { return len(r.Errors) == 0 }
+
+type ReadingImportDeviceResult struct {
+	DeviceID          domain.DeviceID
+	Readings          int
+	HoursAdded        int
+	TotalHours        int
+	AfterOverhaul     int
+	LastReadingAt     time.Time
+	AvgDailyHours     float64
+	ExpectedNextMaint *time.Time
+	MaintenanceDue    bool // AOH đã tới ngưỡng plan -> alert maintenance_due
+}
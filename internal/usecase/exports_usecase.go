@@ -0,0 +1,604 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	inport "wh-ma/internal/adapter/inbound/port"
+	outport "wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/authz"
+	"wh-ma/internal/usecase/dto"
+	"wh-ma/internal/usecase/tracing"
+)
+
+const (
+	// exportPageSize: số dòng mỗi lần đọc DB; bộ nhớ chỉ giữ một trang
+	exportPageSize = 1000
+	// MaxExportRunTime: job chạy quá thì bị hủy (failed)
+	MaxExportRunTime = 2 * time.Hour
+	// DefaultExportLinkTTL: link tải + file sống bao lâu sau khi job xong
+	DefaultExportLinkTTL = 24 * time.Hour
+	// DefaultExportJobsRunning: số job chạy đồng thời mỗi process; job khác chờ ở trạng thái queued
+	DefaultExportJobsRunning = 2
+	// DefaultExportDrain: lúc dừng, chờ job đang chạy tối đa bao lâu (quá hạn thì job failed)
+	DefaultExportDrain = time.Minute
+)
+
+type ExportsConfig struct {
+	LinkTTL    time.Duration // 0 = DefaultExportLinkTTL
+	SigningKey []byte        // HMAC ký link tải; rỗng = khóa ngẫu nhiên (link mất hiệu lực khi restart)
+	MaxRunning int           // 0 = DefaultExportJobsRunning
+}
+
+type ExportsUsecase struct {
+	repos outport.Repos
+	store outport.ExportStore
+	enc   outport.TableEncoder
+	authz *authz.Authorizer
+	ttl   time.Duration
+	key   []byte
+	slots chan struct{}
+
+	// job nền: base bị hủy khi Shutdown quá hạn; stopping đóng lúc Shutdown (job còn chờ slot -> failed)
+	base     context.Context
+	cancel   context.CancelFunc
+	running  sync.WaitGroup
+	stopping chan struct{}
+	stopOnce sync.Once
+}
+
+// errExportShuttingDown: job còn queued lúc process dừng; tạo lại job sau khi replica khác lên
+var errExportShuttingDown = errors.New("server shutting down before the export started")
+
+func NewExportsUsecase(repos outport.Repos, store outport.ExportStore, enc outport.TableEncoder, az *authz.Authorizer, cfg ExportsConfig) *ExportsUsecase {
+	uc := &ExportsUsecase{repos: repos, store: store, enc: enc, authz: az, ttl: cfg.LinkTTL, key: cfg.SigningKey}
+	if uc.ttl <= 0 {
+		uc.ttl = DefaultExportLinkTTL
+	}
+	if len(uc.key) == 0 {
+		uc.key = make([]byte, 32)
+		_, _ = rand.Read(uc.key)
+	}
+	n := cfg.MaxRunning
+	if n <= 0 {
+		n = DefaultExportJobsRunning
+	}
+	uc.slots = make(chan struct{}, n)
+	uc.base, uc.cancel = context.WithCancel(context.Background())
+	uc.stopping = make(chan struct{})
+	return uc
+}
+
+// ✅ compile-time check: UC triển khai inbound port
+var _ inport.ExportsInbound = (*ExportsUsecase)(nil)
+
+// quyền đọc tương ứng từng loại dữ liệu (ngoài exports:run)
+var exportReadAction = map[domain.ExportKind]authz.Action{
+	domain.ExportDevices:     authz.DevicesRead,
+	domain.ExportReadings:    authz.ReadingsRead,
+	domain.ExportMaintenance: authz.MaintenanceRead,
+	domain.ExportAlerts:      authz.AlertsRead,
+}
+
+// 1) EXPORT đồng bộ
+// - kiểm quyền + tham số trước khi ghi byte nào (lỗi lúc này vẫn trả được problem+json)
+// - đọc DB theo trang keyset, ghi từng dòng ra w: không giữ cả tập dữ liệu trong bộ nhớ
+// - dòng của device mà principal không được thấy (site/device của API key) bị bỏ qua
+func (uc *ExportsUsecase) Export(ctx context.Context, in dto.ExportCmd, w io.Writer) (int64, error) {
+	in, err := uc.check(ctx, in, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return uc.write(ctx, in, w)
+}
+
+// 2) START JOB: ghi metadata (queued) rồi chạy nền với principal của người tạo
+func (uc *ExportsUsecase) StartJob(ctx context.Context, in dto.ExportCmd) (*dto.ExportJobView, error) {
+	now := time.Now()
+	in, err := uc.check(ctx, in, now)
+	if err != nil {
+		return nil, err
+	}
+	p, _ := authz.PrincipalFrom(ctx)
+	job := &domain.ExportJob{
+		ID:        newJobID(),
+		TenantID:  p.TenantID,
+		Kind:      in.Kind,
+		Format:    in.Format,
+		Filter:    in.Filter,
+		Status:    domain.ExportQueued,
+		CreatedBy: p.Subject,
+		CreatedAt: now,
+		// tạm thời: đủ cho thời gian chạy tối đa; xong thì tính lại từ FinishedAt
+		ExpiresAt: now.Add(MaxExportRunTime + uc.ttl),
+	}
+	// dọn job/file hết hạn trước khi tạo thêm
+	if _, err := uc.store.DeleteExpired(ctx, now); err != nil {
+		return nil, err
+	}
+	if err := uc.store.SaveJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	// job sống tiếp sau request: ctx mới chỉ mang principal (tenant/RLS), không giữ gì của
+	// request (gin.Context được tái sử dụng sau khi handler trả về); trace nối bằng link
+	jobCtx := authz.WithPrincipal(uc.base, p)
+	uc.running.Add(1)
+	go func() {
+		defer uc.running.Done()
+		uc.run(jobCtx, trace.SpanContextFromContext(ctx), *job)
+	}()
+	return &dto.ExportJobView{Job: job}, nil
+}
+
+// 3) GET JOB: trong tenant của principal; done thì kèm token tải
+func (uc *ExportsUsecase) GetJob(ctx context.Context, id string) (*dto.ExportJobView, error) {
+	if err := uc.authz.Require(ctx, authz.ExportsRun); err != nil {
+		return nil, err
+	}
+	p, _ := authz.PrincipalFrom(ctx)
+	job, err := uc.store.GetJob(ctx, p.TenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	view := &dto.ExportJobView{Job: job}
+	if job.Status == domain.ExportDone && time.Now().Before(job.ExpiresAt) {
+		view.DownloadToken = uc.sign(job)
+	}
+	return view, nil
+}
+
+// 4) DOWNLOAD: token = payload.chữ ký (HMAC-SHA256); hết hạn hoặc sai chữ ký -> không mở file
+func (uc *ExportsUsecase) Download(ctx context.Context, token string) (*dto.ExportDownload, error) {
+	claims, err := uc.verify(token)
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().Unix() >= claims.Expires {
+		return nil, domain.NotFound("export_link_expired", "download link has expired")
+	}
+	job, err := uc.store.GetJob(ctx, claims.Tenant, claims.Job)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != domain.ExportDone {
+		return nil, domain.NotFound("export_job_not_found", "export job not found")
+	}
+	f, err := uc.store.Open(ctx, claims.Tenant, claims.Job)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ExportDownload{
+		Job:      job,
+		FileName: exportFileName(job.Kind, job.Format, job.CreatedAt),
+		File:     f,
+		Size:     f.Size(),
+		ModTime:  f.ModTime(),
+	}, nil
+}
+
+// ===== chạy job =====
+
+// Shutdown: chờ job đang chạy xong (job còn chờ slot thì ghi failed, không bắt đầu nữa).
+// ctx hết hạn trước: hủy ctx của job còn lại (job ghi failed) rồi trả lỗi.
+func (uc *ExportsUsecase) Shutdown(ctx context.Context) error {
+	uc.stopOnce.Do(func() { close(uc.stopping) })
+	done := make(chan struct{})
+	go func() {
+		uc.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		uc.cancel()
+		return fmt.Errorf("export jobs not drained: %w", ctx.Err())
+	}
+}
+
+func (uc *ExportsUsecase) run(ctx context.Context, from trace.SpanContext, job domain.ExportJob) {
+	ctx, span := tracing.StartLinked(ctx, from, "ExportsUsecase.run")
+	var err error
+	defer func() { tracing.End(span, err) }()
+
+	select {
+	case uc.slots <- struct{}{}:
+	case <-uc.stopping:
+		err = errExportShuttingDown
+		uc.finish(ctx, &job, 0, err)
+		return
+	}
+	defer func() { <-uc.slots }()
+
+	ctx, cancel := context.WithTimeout(ctx, MaxExportRunTime)
+	defer cancel()
+
+	job.Status = domain.ExportRunning
+	if err = uc.store.SaveJob(ctx, &job); err != nil {
+		uc.finish(ctx, &job, 0, err)
+		return
+	}
+	var rows int64
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("export panicked: %v", r)
+			}
+		}()
+		rows, err = uc.writeFile(ctx, &job)
+	}()
+	uc.finish(ctx, &job, rows, err)
+}
+
+func (uc *ExportsUsecase) writeFile(ctx context.Context, job *domain.ExportJob) (int64, error) {
+	f, err := uc.store.Create(ctx, job.TenantID, job.ID)
+	if err != nil {
+		return 0, err
+	}
+	n, err := uc.write(ctx, dto.ExportCmd{Kind: job.Kind, Format: job.Format, Filter: job.Filter}, f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return n, err
+}
+
+// finish: ghi kết quả; ctx của job có thể đã hết hạn nên lưu bằng ctx không deadline
+func (uc *ExportsUsecase) finish(ctx context.Context, job *domain.ExportJob, rows int64, err error) {
+	now := time.Now()
+	job.FinishedAt = &now
+	job.ExpiresAt = now.Add(uc.ttl)
+	job.Rows = rows
+	if err != nil {
+		job.Status, job.Error = domain.ExportFailed, err.Error()
+	} else {
+		job.Status = domain.ExportDone
+	}
+	_ = uc.store.SaveJob(context.WithoutCancel(ctx), job)
+}
+
+// ===== kiểm tra + ghi =====
+
+// check: quyền (exports:run + quyền đọc loại dữ liệu), tham số; trả cmd đã chuẩn hóa
+func (uc *ExportsUsecase) check(ctx context.Context, in dto.ExportCmd, now time.Time) (dto.ExportCmd, error) {
+	if err := uc.authz.Require(ctx, authz.ExportsRun); err != nil {
+		return in, err
+	}
+	var v domain.Violations
+	if !in.Kind.Valid() {
+		v.Add("kind", "must be one of devices, readings, maintenance, alerts")
+	}
+	in.Format = strings.ToLower(in.Format)
+	if in.Format == "" {
+		in.Format = "csv"
+	}
+	if !uc.enc.Supports(in.Format) {
+		v.Add("format", "must be one of csv, xlsx, jsonl")
+	}
+	if in.Filter.To.IsZero() {
+		in.Filter.To = now
+	}
+	if !in.Filter.From.IsZero() && !in.Filter.From.Before(in.Filter.To) {
+		v.Add("from", "must be before to")
+	}
+	if err := v.Err(); err != nil {
+		return in, err
+	}
+	if err := uc.authz.Require(ctx, exportReadAction[in.Kind]); err != nil {
+		return in, err
+	}
+	if p, _ := authz.PrincipalFrom(ctx); p.TenantID == "" {
+		return in, domain.Forbidden("tenant_required", "exports are per tenant; the credential has no tenant")
+	}
+	return in, nil
+}
+
+func (uc *ExportsUsecase) write(ctx context.Context, in dto.ExportCmd, w io.Writer) (int64, error) {
+	tw, err := uc.enc.NewWriter(w, in.Format)
+	if err != nil {
+		return 0, err
+	}
+	ex := &exporter{uc: uc, ctx: ctx, act: exportReadAction[in.Kind], devices: map[domain.DeviceID]*exportDevice{}}
+	var n int64
+	emit := func(vals []any) error {
+		n++
+		return tw.WriteRow(vals)
+	}
+	switch in.Kind {
+	case domain.ExportDevices:
+		err = tw.WriteHeader(deviceExportColumns)
+		if err == nil {
+			err = ex.devicesRows(emit)
+		}
+	case domain.ExportReadings:
+		err = tw.WriteHeader(readingExportColumns)
+		if err == nil {
+			err = ex.readingsRows(in.Filter, emit)
+		}
+	case domain.ExportMaintenance:
+		err = tw.WriteHeader(maintenanceExportColumns)
+		if err == nil {
+			err = ex.maintenanceRows(in.Filter, emit)
+		}
+	case domain.ExportAlerts:
+		err = tw.WriteHeader(alertExportColumns)
+		if err == nil {
+			err = ex.alertsRows(in.Filter, emit)
+		}
+	}
+	if cerr := tw.Close(); err == nil {
+		err = cerr
+	}
+	return n, err
+}
+
+// ===== cột + dòng theo loại dữ liệu =====
+
+var (
+	deviceExportColumns = []string{
+		"id", "serial_number", "name", "model", "manufacturer", "year", "commission_date", "status",
+		"location", "plan_id", "plan_name", "total_hours", "after_overhaul_hours", "avg_daily_hours",
+		"last_reading_at", "expected_next_maint", "created_at", "updated_at",
+	}
+	readingExportColumns     = []string{"id", "device_id", "serial_number", "at", "hours_delta", "location", "operator_id"}
+	maintenanceExportColumns = []string{"id", "device_id", "serial_number", "at", "interval_hours", "cost", "performed_by", "notes"}
+	alertExportColumns       = []string{"id", "device_id", "serial_number", "type", "message", "created_at", "resolved", "resolved_at", "resolved_by"}
+)
+
+// exporter: trạng thái của một lần export (cache device/plan để kiểm quyền và lấy serial/tên plan)
+type exporter struct {
+	uc      *ExportsUsecase
+	ctx     context.Context
+	act     authz.Action
+	devices map[domain.DeviceID]*exportDevice
+	plans   map[domain.PlanID]string
+}
+
+type exportDevice struct {
+	serial  string
+	allowed bool
+}
+
+// device: serial + quyền theo device (site/device của principal); device không còn -> bỏ dòng
+func (ex *exporter) device(id domain.DeviceID) (*exportDevice, error) {
+	if d, ok := ex.devices[id]; ok {
+		return d, nil
+	}
+	d := &exportDevice{}
+	dev, err := ex.uc.repos.Devices.GetByID(ex.ctx, id)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+	case err != nil:
+		return nil, err
+	default:
+		d.serial = dev.SerialNumber
+		d.allowed = ex.uc.authz.RequireDevice(ex.ctx, ex.act, dev) == nil
+	}
+	ex.devices[id] = d
+	return d, nil
+}
+
+func (ex *exporter) planName(id *domain.PlanID) (any, error) {
+	if id == nil {
+		return nil, nil
+	}
+	if ex.plans == nil {
+		ex.plans = map[domain.PlanID]string{}
+	}
+	if name, ok := ex.plans[*id]; ok {
+		return name, nil
+	}
+	p, err := ex.uc.repos.Plans.GetByID(ex.ctx, *id)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+	if p != nil {
+		ex.plans[*id] = p.Name
+	}
+	return ex.plans[*id], nil
+}
+
+func (ex *exporter) devicesRows(emit func([]any) error) error {
+	var after domain.DeviceID
+	for {
+		page, err := ex.uc.repos.Devices.ListAfter(ex.ctx, after, exportPageSize)
+		if err != nil {
+			return err
+		}
+		for _, d := range page {
+			after = d.ID
+			if ex.uc.authz.RequireDevice(ex.ctx, ex.act, d) != nil {
+				continue
+			}
+			plan, err := ex.planName(d.PlanID)
+			if err != nil {
+				return err
+			}
+			var planID any
+			if d.PlanID != nil {
+				planID = int64(*d.PlanID)
+			}
+			err = emit([]any{
+				int64(d.ID), d.SerialNumber, d.Name, emptyNil(d.Profile.Model), emptyNil(d.Profile.Manufacturer), zeroNil(d.Profile.Year),
+				timeOrNil(d.Profile.CommissionDate), string(d.Status), emptyNil(d.State.Location), planID, plan,
+				d.State.TotalHours, d.State.AfterOverhaul, d.State.AvgDailyHours,
+				d.State.LastReadingAt, d.State.ExpectedNextMaint, d.CreatedAt, d.UpdatedAt,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if len(page) < exportPageSize {
+			return nil
+		}
+	}
+}
+
+func (ex *exporter) readingsRows(f domain.ExportFilter, emit func([]any) error) error {
+	q := rangeQuery(f)
+	for {
+		page, err := ex.uc.repos.Readings.ListRange(ex.ctx, q)
+		if err != nil {
+			return err
+		}
+		for _, r := range page {
+			q.AfterAt, q.AfterID = r.At, r.ID
+			d, err := ex.device(r.DeviceID)
+			if err != nil {
+				return err
+			}
+			if !d.allowed {
+				continue
+			}
+			if err := emit([]any{r.ID, int64(r.DeviceID), d.serial, r.At, r.HoursDelta, emptyNil(r.Location), emptyNil(r.OperatorID)}); err != nil {
+				return err
+			}
+		}
+		if len(page) < exportPageSize {
+			return nil
+		}
+	}
+}
+
+func (ex *exporter) maintenanceRows(f domain.ExportFilter, emit func([]any) error) error {
+	q := rangeQuery(f)
+	for {
+		page, err := ex.uc.repos.Maintenance.ListRange(ex.ctx, q)
+		if err != nil {
+			return err
+		}
+		for _, m := range page {
+			q.AfterAt, q.AfterID = m.At, m.ID
+			d, err := ex.device(m.DeviceID)
+			if err != nil {
+				return err
+			}
+			if !d.allowed {
+				continue
+			}
+			err = emit([]any{m.ID, int64(m.DeviceID), d.serial, m.At, zeroNil(m.Interval), m.Cost, emptyNil(m.PerformedBy), emptyNil(m.Notes)})
+			if err != nil {
+				return err
+			}
+		}
+		if len(page) < exportPageSize {
+			return nil
+		}
+	}
+}
+
+func (ex *exporter) alertsRows(f domain.ExportFilter, emit func([]any) error) error {
+	q := rangeQuery(f)
+	for {
+		page, err := ex.uc.repos.Alerts.ListRange(ex.ctx, q)
+		if err != nil {
+			return err
+		}
+		for _, a := range page {
+			q.AfterAt, q.AfterID = a.CreatedAt, a.ID
+			d, err := ex.device(a.DeviceID)
+			if err != nil {
+				return err
+			}
+			if !d.allowed {
+				continue
+			}
+			err = emit([]any{a.ID, int64(a.DeviceID), d.serial, a.Type, a.Message, a.CreatedAt, a.Resolved, a.ResolvedAt, emptyNil(a.ResolvedBy)})
+			if err != nil {
+				return err
+			}
+		}
+		if len(page) < exportPageSize {
+			return nil
+		}
+	}
+}
+
+func rangeQuery(f domain.ExportFilter) outport.RangeQuery {
+	return outport.RangeQuery{DeviceID: f.DeviceID, From: f.From, To: f.To, Limit: exportPageSize}
+}
+
+func zeroNil(n int) any {
+	if n == 0 {
+		return nil
+	}
+	return n
+}
+
+// emptyNil: chuỗi rỗng (NULL trong DB) -> ô trống / null
+func emptyNil(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func timeOrNil(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
+
+func exportFileName(kind domain.ExportKind, format string, at time.Time) string {
+	return fmt.Sprintf("%s-%s.%s", kind, at.UTC().Format("20060102-150405"), format)
+}
+
+// ===== link tải ký HMAC =====
+
+type exportClaims struct {
+	Tenant  string `json:"t"`
+	Job     string `json:"j"`
+	Expires int64  `json:"e"` // unix giây
+}
+
+func newJobID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func (uc *ExportsUsecase) sign(job *domain.ExportJob) string {
+	payload, _ := json.Marshal(exportClaims{Tenant: job.TenantID, Job: job.ID, Expires: job.ExpiresAt.Unix()})
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(payload) + "." + enc.EncodeToString(uc.mac(payload))
+}
+
+func (uc *ExportsUsecase) verify(token string) (*exportClaims, error) {
+	invalid := domain.Forbidden("export_link_invalid", "download link is invalid")
+	enc := base64.RawURLEncoding.Strict() // một token hợp lệ chỉ có một cách viết
+	p, s, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, invalid
+	}
+	payload, err1 := enc.DecodeString(p)
+	sig, err2 := enc.DecodeString(s)
+	if err1 != nil || err2 != nil || !hmac.Equal(sig, uc.mac(payload)) {
+		return nil, invalid
+	}
+	var c exportClaims
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return nil, invalid
+	}
+	return &c, nil
+}
+
+func (uc *ExportsUsecase) mac(payload []byte) []byte {
+	h := hmac.New(sha256.New, uc.key)
+	h.Write(payload)
+	return h.Sum(nil)
+}
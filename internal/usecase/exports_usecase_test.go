@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"wh-ma/internal/adapter/inbound/tabular"
+	"wh-ma/internal/adapter/outbound/exportstore"
+	outport "wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/authz"
+	"wh-ma/internal/usecase/dto"
+)
+
+// gatedStore: Create (bắt đầu ghi file) chờ gate mở hoặc ctx của job bị hủy
+type gatedStore struct {
+	outport.ExportStore
+	entered chan struct{}
+	gate    chan struct{}
+}
+
+func (s *gatedStore) Create(ctx context.Context, tenant, id string) (io.WriteCloser, error) {
+	s.entered <- struct{}{}
+	select {
+	case <-s.gate:
+		return s.ExportStore.Create(ctx, tenant, id)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func newGatedExports(t *testing.T, f *fixture, maxRunning int) (*ExportsUsecase, *gatedStore) {
+	t.Helper()
+	dir, err := exportstore.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	gs := &gatedStore{ExportStore: dir, entered: make(chan struct{}, 4), gate: make(chan struct{})}
+	uc := NewExportsUsecase(f.repos, gs, tabular.Encoder{}, authz.NewAuthorizer(nil), ExportsConfig{MaxRunning: maxRunning})
+	return uc, gs
+}
+
+func waitJob(t *testing.T, store outport.ExportStore, id string, want domain.ExportJobStatus) *domain.ExportJob {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		job, err := store.GetJob(context.Background(), testTenant, id)
+		if err == nil && job.Status == want {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %s: %+v, %v; want status %s", id, job, err, want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// Job nền không dùng ctx của request (bị hủy/tái sử dụng sau khi handler trả về);
+// Shutdown chờ job đang chạy xong.
+func TestExportJobOutlivesRequestAndDrainsOnShutdown(t *testing.T) {
+	f := newFixture(t)
+	f.device(t, "SN-1", "site-a")
+	uc, gs := newGatedExports(t, f, 0)
+
+	reqCtx, cancelReq := context.WithCancel(admin())
+	view, err := uc.StartJob(reqCtx, dto.ExportCmd{Kind: domain.ExportDevices})
+	cancelReq()
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-gs.entered
+
+	errc := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		errc <- uc.Shutdown(ctx)
+	}()
+	select {
+	case err := <-errc:
+		t.Fatalf("Shutdown returned %v while the job was still running", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(gs.gate)
+	if err := <-errc; err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	job := waitJob(t, gs, view.Job.ID, domain.ExportDone)
+	if job.Rows != 1 {
+		t.Fatalf("rows = %d, want 1", job.Rows)
+	}
+}
+
+// Quá hạn drain: job đang chạy bị hủy, job còn chờ slot không bắt đầu; cả hai ghi failed.
+func TestExportShutdownDeadlineFailsRemainingJobs(t *testing.T) {
+	f := newFixture(t)
+	f.device(t, "SN-1", "site-a")
+	uc, gs := newGatedExports(t, f, 1)
+
+	running, err := uc.StartJob(admin(), dto.ExportCmd{Kind: domain.ExportDevices})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-gs.entered
+	queued, err := uc.StartJob(admin(), dto.ExportCmd{Kind: domain.ExportDevices})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := uc.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown = %v, want DeadlineExceeded", err)
+	}
+	if job := waitJob(t, gs, running.Job.ID, domain.ExportFailed); job.Error != context.Canceled.Error() {
+		t.Errorf("running job error = %q", job.Error)
+	}
+	if job := waitJob(t, gs, queued.Job.ID, domain.ExportFailed); job.Error != errExportShuttingDown.Error() {
+		t.Errorf("queued job error = %q", job.Error)
+	}
+}
@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"wh-ma/internal/adapter/outbound/memory"
+	outport "wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/authz"
+	"wh-ma/internal/usecase/dto"
+)
+
+const testTenant = "t1"
+
+// fixture: usecase thật trên storage memory, policy mặc định
+type fixture struct {
+	store *memory.Store
+	repos outport.Repos
+	tx    outport.TxManager
+
+	devices  *DevicesUsecase
+	readings *ReadingsUsecase
+	maint    *MaintenanceUsecase
+	alerts   *AlertsUsecase
+	apiKeys  *APIKeysUsecase
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	s := memory.NewStore()
+	repos, tx := s.Repos(), memory.NewTxManager(s)
+	az := authz.NewAuthorizer(nil)
+	return &fixture{
+		store:    s,
+		repos:    repos,
+		tx:       tx,
+		devices:  NewDevicesUsecase(repos.Devices, repos.Plans, repos.DeviceAudit, tx, az),
+		readings: NewReadingsUsecase(repos.Readings, repos.Devices, tx, az),
+		maint:    NewMaintenanceUsecase(repos.Maintenance, repos.Devices, az),
+		alerts:   NewAlertsUsecase(repos.Alerts, repos.Devices, az),
+		apiKeys:  NewAPIKeysUsecase(repos.APIKeys, az),
+	}
+}
+
+// as: ctx của principal trong tenant test
+func as(p domain.Principal) context.Context {
+	if p.TenantID == "" {
+		p.TenantID = testTenant
+	}
+	return authz.WithPrincipal(context.Background(), p)
+}
+
+func admin() context.Context {
+	return as(domain.Principal{Subject: "admin", Role: domain.RoleAdmin})
+}
+
+// device: tạo device ở site location (admin)
+func (f *fixture) device(t *testing.T, serial, location string) *domain.Device {
+	t.Helper()
+	dev, err := f.devices.Create(admin(), dto.CreateDeviceCmd{
+		SerialNumber: serial,
+		Name:         "Máy " + serial,
+		Year:         2020,
+		Location:     &location,
+	})
+	if err != nil {
+		t.Fatalf("create device %s: %v", serial, err)
+	}
+	return dev
+}
@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	outport "wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/authz"
+)
+
+const (
+	// fleetJobPageSize: số device mỗi lần đọc khi job quét cả đội máy
+	fleetJobPageSize = 500
+	// DefaultIdleAfter: device active không có reading lâu hơn thì báo idle_too_long
+	DefaultIdleAfter = 7 * 24 * time.Hour
+	// DefaultJobRunRetention: lịch sử job_runs cũ hơn bị purge xóa
+	DefaultJobRunRetention = 30 * 24 * time.Hour
+)
+
+type FleetJobsConfig struct {
+	IdleAfter    time.Duration // 0 = DefaultIdleAfter
+	RunRetention time.Duration // 0 = DefaultJobRunRetention
+}
+
+// FleetJobs: việc định kỳ trên toàn đội máy (scheduler gọi, chỉ chạy trên replica leader).
+// Không có người gọi nên không qua authz: mỗi tenant chạy với principal hệ thống của tenant đó
+// (RLS/tenantOf vẫn cô lập dữ liệu như request thường).
+type FleetJobs struct {
+	repos   outport.Repos
+	tx      outport.TxManager
+	exports outport.ExportStore
+	cfg     atomic.Pointer[FleetJobsConfig] // đổi được lúc chạy (reload cấu hình), job sau đọc giá trị mới
+}
+
+func NewFleetJobs(repos outport.Repos, tx outport.TxManager, exports outport.ExportStore, cfg FleetJobsConfig) *FleetJobs {
+	j := &FleetJobs{repos: repos, tx: tx, exports: exports}
+	j.SetConfig(cfg)
+	return j
+}
+
+// SetConfig: ngưỡng mới áp dụng từ lần chạy job kế tiếp
+func (j *FleetJobs) SetConfig(cfg FleetJobsConfig) {
+	if cfg.IdleAfter <= 0 {
+		cfg.IdleAfter = DefaultIdleAfter
+	}
+	if cfg.RunRetention <= 0 {
+		cfg.RunRetention = DefaultJobRunRetention
+	}
+	j.cfg.Store(&cfg)
+}
+
+// RecomputeForecasts: tính lại avg giờ/ngày + ngày bảo dưỡng dự kiến tới thời điểm chạy.
+// Reading chỉ cập nhật dự báo khi có giờ mới; device ngừng chạy thì avg phải giảm dần theo thời gian.
+func (j *FleetJobs) RecomputeForecasts(ctx context.Context) (string, error) {
+	var devices, updated int
+	tenants, err := j.eachTenant(ctx, "forecast_recompute", func(ctx context.Context) error {
+		plans := map[domain.PlanID]*domain.Plan{}
+		now := time.Now()
+		return j.eachDevice(ctx, func(dev *domain.Device) error {
+			if dev.Status == domain.StatusDecommissioned {
+				return nil
+			}
+			devices++
+			var plan *domain.Plan
+			if dev.PlanID != nil {
+				p, ok := plans[*dev.PlanID]
+				if !ok {
+					p, _ = j.repos.Plans.GetByID(ctx, *dev.PlanID) // plan đã xóa -> như không có plan
+					plans[*dev.PlanID] = p
+				}
+				plan = p
+			}
+			w, err := recentUsage(ctx, j.repos.Readings, dev, now)
+			if err != nil {
+				return err
+			}
+			avg, next := forecastUsage(dev, plan, w, 0, now)
+			if sameForecast(dev.State, avg, next) {
+				return nil
+			}
+			if err := j.repos.Devices.UpdateForecast(ctx, dev.ID, avg, next); err != nil {
+				return err
+			}
+			updated++
+			return nil
+		})
+	})
+	return fmt.Sprintf("tenants=%d devices=%d updated=%d", tenants, devices, updated), err
+}
+
+// DetectIdle: device active không có reading quá IdleAfter -> alert idle_too_long (mỗi device một alert mở)
+func (j *FleetJobs) DetectIdle(ctx context.Context) (string, error) {
+	var idle, raised int
+	tenants, err := j.eachTenant(ctx, "idle_detection", func(ctx context.Context) error {
+		cutoff := time.Now().Add(-j.cfg.Load().IdleAfter)
+		return j.eachDevice(ctx, func(dev *domain.Device) error {
+			if !idleSince(dev, cutoff) {
+				return nil
+			}
+			idle++
+			// khóa device rồi kiểm lại: reading vừa ghi giữa lúc quét thì không báo
+			return j.tx.WithinTx(ctx, func(ctx context.Context, r outport.Repos) error {
+				dev, err := r.Devices.GetForUpdate(ctx, dev.ID)
+				if err != nil || !idleSince(dev, cutoff) {
+					return err
+				}
+				ok, err := raiseOpenAlert(ctx, r.Alerts, dev.ID, "idle_too_long",
+					fmt.Sprintf("no readings since %s", lastActivity(dev).UTC().Format(time.RFC3339)))
+				if ok {
+					raised++
+				}
+				return err
+			})
+		})
+	})
+	return fmt.Sprintf("tenants=%d idle=%d raised=%d", tenants, idle, raised), err
+}
+
+// Purge: xóa export job/file hết hạn và lịch sử job cũ hơn RunRetention
+func (j *FleetJobs) Purge(ctx context.Context) (string, error) {
+	now := time.Now()
+	exports, err := j.exports.DeleteExpired(ctx, now)
+	if err != nil {
+		return "", err
+	}
+	runs, err := j.repos.JobRuns.DeleteBefore(ctx, now.Add(-j.cfg.Load().RunRetention))
+	return fmt.Sprintf("exports=%d job_runs=%d", exports, runs), err
+}
+
+// eachTenant: chạy fn lần lượt cho từng tenant có device, ctx mang principal hệ thống của tenant
+func (j *FleetJobs) eachTenant(ctx context.Context, job string, fn func(ctx context.Context) error) (int, error) {
+	return forEachTenant(ctx, j.repos.Devices, "job:"+job, fn)
+}
+
+// forEachTenant: tenant lấy từ ListTenants; subject ghi vào principal hệ thống (audit/log)
+func forEachTenant(ctx context.Context, devices outport.DeviceRepository, subject string, fn func(ctx context.Context) error) (int, error) {
+	tenants, err := devices.ListTenants(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for i, t := range tenants {
+		if err := ctx.Err(); err != nil {
+			return i, err
+		}
+		tctx := authz.WithPrincipal(ctx, domain.Principal{Subject: subject, TenantID: t, Role: domain.RoleAdmin})
+		if err := fn(tctx); err != nil {
+			return i, err
+		}
+	}
+	return len(tenants), nil
+}
+
+// eachDevice: device chưa xóa của tenant trong ctx, theo trang (keyset theo id)
+func (j *FleetJobs) eachDevice(ctx context.Context, fn func(dev *domain.Device) error) error {
+	var after domain.DeviceID
+	for {
+		page, err := j.repos.Devices.ListAfter(ctx, after, fleetJobPageSize)
+		if err != nil {
+			return err
+		}
+		for _, dev := range page {
+			if err := fn(dev); err != nil {
+				return err
+			}
+		}
+		if len(page) < fleetJobPageSize {
+			return nil
+		}
+		after = page[len(page)-1].ID
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+// sameForecast: avg lệch dưới 0.01 giờ/ngày và ngày dự kiến lệch dưới một phút thì không ghi
+func sameForecast(st domain.OperationalState, avg float64, next *time.Time) bool {
+	if d := st.AvgDailyHours - avg; d > 0.01 || d < -0.01 {
+		return false
+	}
+	if (st.ExpectedNextMaint == nil) != (next == nil) {
+		return false
+	}
+	if next == nil {
+		return true
+	}
+	d := st.ExpectedNextMaint.Sub(*next)
+	return d < time.Minute && d > -time.Minute
+}
+
+func idleSince(dev *domain.Device, cutoff time.Time) bool {
+	return dev.Status == domain.StatusActive && lastActivity(dev).Before(cutoff)
+}
+
+// lastActivity: reading gần nhất; chưa có reading thì lúc device được tạo
+func lastActivity(dev *domain.Device) time.Time {
+	if dev.State.LastReadingAt != nil {
+		return *dev.State.LastReadingAt
+	}
+	return dev.CreatedAt
+}
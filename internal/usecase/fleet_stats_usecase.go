@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	inport "wh-ma/internal/adapter/inbound/port"
+	outport "wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/usecase/authz"
+	"wh-ma/internal/usecase/dto"
+)
+
+// FleetStatsUsecase: gom số liệu đội máy theo tenant cho /metrics.
+// Giống FleetJobs: mỗi tenant đọc với principal hệ thống để RLS vẫn áp dụng.
+type FleetStatsUsecase struct {
+	devices outport.DeviceRepository
+	stats   outport.FleetStatsRepository
+}
+
+func NewFleetStatsUsecase(devices outport.DeviceRepository, stats outport.FleetStatsRepository) *FleetStatsUsecase {
+	return &FleetStatsUsecase{devices: devices, stats: stats}
+}
+
+// ✅ compile-time check: UC triển khai inbound port
+var _ inport.FleetStatsInbound = (*FleetStatsUsecase)(nil)
+
+func (uc *FleetStatsUsecase) Collect(ctx context.Context) ([]dto.TenantFleetStats, error) {
+	now := time.Now()
+	var out []dto.TenantFleetStats
+	_, err := forEachTenant(ctx, uc.devices, "metrics:fleet", func(ctx context.Context) error {
+		st, err := uc.stats.Snapshot(ctx, now)
+		if err != nil {
+			return err
+		}
+		p, _ := authz.PrincipalFrom(ctx)
+		out = append(out, dto.TenantFleetStats{Tenant: p.TenantID, Stats: st})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
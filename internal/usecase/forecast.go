@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	outport "wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+)
+
+// maxForecastDays: xa hơn mốc này thì coi như không có ngày bảo dưỡng dự kiến
+const maxForecastDays = 100 * 365
+
+// forecastWindow: avg giờ/ngày lấy theo reading trong khoảng gần đây, không theo cả đời máy
+// (giờ khai lúc tạo device và những năm trước khi đưa vào hệ thống kéo avg về gần 0)
+const forecastWindow = 30 * 24 * time.Hour
+
+// usageWindow: khoảng (From, at] để tính avg và tổng giờ reading trong đó
+type usageWindow struct {
+	From  time.Time
+	Hours int
+}
+
+// windowStart: at - forecastWindow, nhưng không sớm hơn mốc bắt đầu sử dụng since
+func windowStart(since, at time.Time) time.Time {
+	from := at.Add(-forecastWindow)
+	if since.After(from) {
+		return since
+	}
+	return from
+}
+
+// recentUsage: cửa sổ tính avg tới at từ reading đã ghi (gọi sau khi ghi reading mới)
+func recentUsage(ctx context.Context, readings outport.ReadingRepository, dev *domain.Device, at time.Time) (usageWindow, error) {
+	since, err := trackingStart(ctx, readings, dev)
+	if err != nil {
+		return usageWindow{}, err
+	}
+	from := windowStart(since, at)
+	hours, err := readings.SumHoursSince(ctx, dev.ID, from)
+	if err != nil {
+		return usageWindow{}, err
+	}
+	return usageWindow{From: from, Hours: hours}, nil
+}
+
+// trackingStart: mốc bắt đầu ghi giờ của device. Giờ chạy trước khi vào hệ thống đã khai gộp trong
+// total_working_hour, nên lấy created_at (hoặc commission_date nếu muộn hơn); lịch sử import có thể
+// cũ hơn mốc đó thì lấy reading đầu tiên.
+func trackingStart(ctx context.Context, readings outport.ReadingRepository, dev *domain.Device) (time.Time, error) {
+	since := dev.CreatedAt
+	if dev.Profile.CommissionDate.After(since) {
+		since = dev.Profile.CommissionDate
+	}
+	first, err := readings.GetFirstByDevice(ctx, dev.ID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+	case err != nil:
+		return time.Time{}, err
+	case first.At.Before(since):
+		since = first.At
+	}
+	return since, nil
+}
+
+// forecastUsage tính lại trung bình giờ/ngày và ngày bảo dưỡng dự kiến tại thời điểm at,
+// khi AOH của device vừa tăng thêm hoursDelta (chưa có trong dev.State).
+// - avg = giờ reading trong cửa sổ w / số ngày của cửa sổ (tối thiểu 1 ngày)
+// - next = at + (interval - AOH) / avg ngày; đã quá ngưỡng -> next = at
+func forecastUsage(dev *domain.Device, plan *domain.Plan, w usageWindow, hoursDelta int, at time.Time) (float64, *time.Time) {
+	days := at.Sub(w.From).Hours() / 24
+	if days < 1 {
+		days = 1
+	}
+	avg := float64(w.Hours) / days
+
+	if plan == nil || plan.IntervalHours <= 0 {
+		return avg, nil
+	}
+	remaining := plan.IntervalHours - (dev.State.AfterOverhaul + hoursDelta)
+	if remaining <= 0 {
+		next := at
+		return avg, &next
+	}
+	if avg <= 0 {
+		return avg, nil
+	}
+	days = float64(remaining) / avg
+	if days > maxForecastDays {
+		// device gần như không chạy: không có ngày dự kiến (tránh tràn time.Duration ~292 năm)
+		return avg, nil
+	}
+	next := at.Add(time.Duration(days * 24 * float64(time.Hour)))
+	return avg, &next
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/dto"
+)
+
+func TestForecastUsage(t *testing.T) {
+	at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	plan := &domain.Plan{IntervalHours: 500}
+	// máy cũ: commission từ 2010, đã khai 2000 giờ lúc tạo; avg không được kéo về gần 0 theo tuổi máy
+	dev := &domain.Device{
+		Profile:   domain.DeviceProfile{CommissionDate: time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC)},
+		State:     domain.OperationalState{TotalHours: 2000, AfterOverhaul: 100},
+		CreatedAt: at.AddDate(0, -6, 0),
+	}
+
+	tests := []struct {
+		name     string
+		w        usageWindow
+		delta    int
+		wantAvg  float64
+		wantNext *time.Time
+	}{
+		{
+			name:     "8h/ngày trong 30 ngày",
+			w:        usageWindow{From: at.Add(-forecastWindow), Hours: 240},
+			wantAvg:  8,
+			wantNext: ptrTo(at.Add(50 * 24 * time.Hour)), // (500-100)/8
+		},
+		{
+			name:     "cửa sổ dưới một ngày tính là một ngày",
+			w:        usageWindow{From: at.Add(-2 * time.Hour), Hours: 4},
+			delta:    4,
+			wantAvg:  4,
+			wantNext: ptrTo(at.Add(99 * 24 * time.Hour)), // (500-104)/4
+		},
+		{
+			name:     "đã quá ngưỡng: đến hạn ngay",
+			w:        usageWindow{From: at.Add(-forecastWindow), Hours: 300},
+			delta:    400,
+			wantAvg:  10,
+			wantNext: &at,
+		},
+		{
+			name:    "không chạy trong cửa sổ: không có ngày dự kiến",
+			w:       usageWindow{From: at.Add(-forecastWindow)},
+			wantAvg: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			avg, next := forecastUsage(dev, plan, tt.w, tt.delta, at)
+			if avg != tt.wantAvg {
+				t.Errorf("avg = %v, want %v", avg, tt.wantAvg)
+			}
+			switch {
+			case tt.wantNext == nil && next != nil:
+				t.Errorf("next = %v, want nil", next)
+			case tt.wantNext != nil && (next == nil || !next.Equal(*tt.wantNext)):
+				t.Errorf("next = %v, want %v", next, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestWindowStart(t *testing.T) {
+	at := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	if got := windowStart(at.AddDate(-10, 0, 0), at); !got.Equal(at.Add(-forecastWindow)) {
+		t.Errorf("old device: from = %v, want %v", got, at.Add(-forecastWindow))
+	}
+	recent := at.AddDate(0, 0, -10)
+	if got := windowStart(recent, at); !got.Equal(recent) {
+		t.Errorf("new device: from = %v, want %v", got, recent)
+	}
+}
+
+// Import 60 ngày lịch sử 8h/ngày: avg theo 30 ngày gần nhất, không chia cho tuổi máy.
+func TestImportForecastUsesRecentWindow(t *testing.T) {
+	f := newFixture(t)
+	dev := f.device(t, "SN-1", "site-a")
+	last := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	var rows []dto.ImportReadingRow
+	for d := 59; d >= 0; d-- {
+		rows = append(rows, dto.ImportReadingRow{
+			Line:       len(rows) + 2,
+			DeviceID:   dev.ID,
+			At:         last.AddDate(0, 0, -d),
+			HoursDelta: ptrTo(8),
+		})
+	}
+	if _, err := f.readings.Import(admin(), dto.ImportReadingsCmd{Rows: rows}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := f.devices.Get(admin(), dev.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.State.AvgDailyHours < 7.9 || got.State.AvgDailyHours > 8.1 {
+		t.Fatalf("avg after import = %v, want ~8", got.State.AvgDailyHours)
+	}
+
+	// reading tiếp theo: vẫn ~8h/ngày
+	if _, err := f.readings.Submit(admin(), dto.SubmitReadingCmd{DeviceID: dev.ID, At: last.Add(30 * time.Minute), HoursDelta: 0}); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = f.devices.Get(admin(), dev.ID)
+	if got.State.AvgDailyHours < 7.5 || got.State.AvgDailyHours > 8.1 {
+		t.Fatalf("avg after submit = %v, want ~8", got.State.AvgDailyHours)
+	}
+}
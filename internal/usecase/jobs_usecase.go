@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+
+	inport "wh-ma/internal/adapter/inbound/port"
+	outport "wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/authz"
+	"wh-ma/internal/usecase/dto"
+)
+
+type JobsUsecase struct {
+	runs    outport.JobRunRepository
+	catalog outport.JobCatalog
+	authz   *authz.Authorizer
+}
+
+func NewJobsUsecase(runs outport.JobRunRepository, catalog outport.JobCatalog, az *authz.Authorizer) *JobsUsecase {
+	return &JobsUsecase{runs: runs, catalog: catalog, authz: az}
+}
+
+// ✅ compile-time check: UC triển khai inbound port
+var _ inport.JobsInbound = (*JobsUsecase)(nil)
+
+// LIST JOBS: cấu hình/trạng thái từ scheduler của replica này, lần chạy mới nhất từ job_runs
+func (uc *JobsUsecase) ListJobs(ctx context.Context) (*dto.JobsOverview, error) {
+	if err := uc.authz.Require(ctx, authz.JobsRead); err != nil {
+		return nil, err
+	}
+	latest, err := uc.runs.Latest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	last := make(map[string]*domain.JobRun, len(latest))
+	for _, r := range latest {
+		last[r.Job] = r
+	}
+	st := uc.catalog.Status()
+	out := &dto.JobsOverview{Instance: st.Instance, Leader: st.Leader, Jobs: make([]dto.JobView, 0, len(st.Jobs))}
+	for _, j := range st.Jobs {
+		out.Jobs = append(out.Jobs, dto.JobView{Info: j, LastRun: last[j.Name]})
+	}
+	return out, nil
+}
+
+// LIST RUNS: job không đăng ký -> 404 (tránh nhầm tên job thành danh sách rỗng)
+func (uc *JobsUsecase) ListRuns(ctx context.Context, job string, limit, offset int32) ([]*domain.JobRun, error) {
+	if err := uc.authz.Require(ctx, authz.JobsRead); err != nil {
+		return nil, err
+	}
+	known := false
+	for _, j := range uc.catalog.Status().Jobs {
+		known = known || j.Name == job
+	}
+	if !known {
+		return nil, domain.NotFound("job_not_found", "job not found")
+	}
+	return uc.runs.List(ctx, job, limit, offset)
+}
+
+func (uc *JobsUsecase) RecordRun(ctx context.Context, run *domain.JobRun) error {
+	_, err := uc.runs.Create(ctx, run)
+	return err
+}
@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	inport "wh-ma/internal/adapter/inbound/port"
+	outport "wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/authz"
+)
+
+type LoggingUsecase struct {
+	level  outport.LogLevelControl
+	authz  *authz.Authorizer
+	logger *slog.Logger
+}
+
+func NewLoggingUsecase(level outport.LogLevelControl, az *authz.Authorizer, logger *slog.Logger) *LoggingUsecase {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &LoggingUsecase{level: level, authz: az, logger: logger}
+}
+
+// ✅ compile-time check: UC triển khai inbound port
+var _ inport.LoggingInbound = (*LoggingUsecase)(nil)
+
+func (uc *LoggingUsecase) GetLogLevel(ctx context.Context) (string, error) {
+	if err := uc.authz.Require(ctx, authz.LoggingAdmin); err != nil {
+		return "", err
+	}
+	return uc.level.Level(), nil
+}
+
+// SET: ghi lại ai đổi (ở mức warn để vẫn thấy khi level đang là warn/error)
+func (uc *LoggingUsecase) SetLogLevel(ctx context.Context, level string) (string, error) {
+	if err := uc.authz.Require(ctx, authz.LoggingAdmin); err != nil {
+		return "", err
+	}
+	prev := uc.level.Level()
+	if err := uc.level.SetLevel(level); err != nil {
+		return "", domain.Invalid("level", "must be one of debug, info, warn, error")
+	}
+	p, _ := authz.PrincipalFrom(ctx)
+	uc.logger.Warn("log level changed", "from", prev, "to", uc.level.Level(), "by", p.Subject)
+	return uc.level.Level(), nil
+}
@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	inport "wh-ma/internal/adapter/inbound/port"
+	outport "wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/authz"
+	"wh-ma/internal/usecase/dto"
+)
+
+type MaintenanceUsecase struct {
+	maintRepo outport.MaintenanceRepository
+	devRepo   outport.DeviceRepository
+	authz     *authz.Authorizer
+}
+
+func NewMaintenanceUsecase(
+	maintRepo outport.MaintenanceRepository,
+	devRepo outport.DeviceRepository,
+	az *authz.Authorizer,
+) *MaintenanceUsecase {
+	return &MaintenanceUsecase{maintRepo: maintRepo, devRepo: devRepo, authz: az}
+}
+
+// ✅ compile-time check: UC triển khai inbound port
+var _ inport.MaintenanceInbound = (*MaintenanceUsecase)(nil)
+
+// LOG
+// - device phải còn tồn tại
+// - at không ở tương lai; interval > 0 nếu có; cost >= 0 nếu có
+// - performed_by mặc định = principal đang gọi
+func (uc *MaintenanceUsecase) Log(ctx context.Context, in dto.LogMaintenanceCmd) (*domain.MaintenanceEvent, error) {
+	if err := uc.authz.Require(ctx, authz.MaintenanceWrite); err != nil {
+		return nil, err
+	}
+	dev, err := uc.devRepo.GetByID(ctx, in.DeviceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.authz.RequireDevice(ctx, authz.MaintenanceWrite, dev); err != nil {
+		return nil, err
+	}
+	if dev.DeletedAt != nil {
+		return nil, domain.NotFound("device_not_found", "device is deleted")
+	}
+
+	now := time.Now()
+	if in.At.IsZero() {
+		in.At = now
+	}
+	var v domain.Violations
+	if in.At.After(now) {
+		v.Add("at", "cannot be in the future")
+	}
+	if in.Interval != nil && *in.Interval <= 0 {
+		v.Add("interval", "must be > 0")
+	}
+	if in.Cost != nil {
+		c, err := strconv.ParseFloat(*in.Cost, 64)
+		if err != nil || c < 0 {
+			v.Add("cost", "must be a non-negative decimal")
+		}
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+	if in.PerformedBy == nil {
+		if p, ok := authz.PrincipalFrom(ctx); ok {
+			in.PerformedBy = &p.Subject
+		}
+	}
+
+	var interval *int32
+	if in.Interval != nil {
+		v := int32(*in.Interval)
+		interval = &v
+	}
+	return uc.maintRepo.Create(ctx, outport.CreateMaintenanceInput{
+		DeviceID:    in.DeviceID,
+		At:          in.At,
+		Interval:    interval,
+		Notes:       in.Notes,
+		PerformedBy: in.PerformedBy,
+		Cost:        in.Cost,
+	})
+}
+
+// LIST: at DESC; chỉ device principal được xem
+func (uc *MaintenanceUsecase) ListByDevice(ctx context.Context, deviceID domain.DeviceID, limit, offset int32) ([]*domain.MaintenanceEvent, error) {
+	if err := uc.authz.Require(ctx, authz.MaintenanceRead); err != nil {
+		return nil, err
+	}
+	dev, err := uc.devRepo.GetByID(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.authz.RequireDevice(ctx, authz.MaintenanceRead, dev); err != nil {
+		return nil, err
+	}
+	return uc.maintRepo.ListByDevice(ctx, deviceID, limit, offset)
+}
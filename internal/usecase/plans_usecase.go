@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+
+	inport "wh-ma/internal/adapter/inbound/port"
+	outport "wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/authz"
+	"wh-ma/internal/usecase/dto"
+)
+
+type PlansUsecase struct {
+	planRepo outport.PlanRepository
+	authz    *authz.Authorizer
+}
+
+func NewPlansUsecase(planRepo outport.PlanRepository, az *authz.Authorizer) *PlansUsecase {
+	return &PlansUsecase{planRepo: planRepo, authz: az}
+}
+
+// ✅ compile-time check: UC triển khai inbound port
+var _ inport.PlansInbound = (*PlansUsecase)(nil)
+
+// CREATE
+// - required: Name, IntervalHours > 0
+// - tenant_id lấy từ connection: principal có tenant => plan riêng, cấp hệ thống => template dùng chung
+func (uc *PlansUsecase) Create(ctx context.Context, in dto.CreatePlanCmd) (*domain.Plan, error) {
+	if err := uc.authz.Require(ctx, authz.PlansWrite); err != nil {
+		return nil, err
+	}
+	if err := validatePlan(in.Name, in.IntervalHours); err != nil {
+		return nil, err
+	}
+	return uc.planRepo.Create(ctx, outport.CreatePlanInput{
+		Name:          in.Name,
+		IntervalHours: in.IntervalHours,
+		Description:   in.Description,
+	})
+}
+
+// GET/LIST: thuần repo
+func (uc *PlansUsecase) Get(ctx context.Context, id domain.PlanID) (*domain.Plan, error) {
+	if err := uc.authz.Require(ctx, authz.PlansRead); err != nil {
+		return nil, err
+	}
+	return uc.planRepo.GetByID(ctx, id)
+}
+func (uc *PlansUsecase) List(ctx context.Context, limit, offset int32) ([]*domain.Plan, error) {
+	if err := uc.authz.Require(ctx, authz.PlansRead); err != nil {
+		return nil, err
+	}
+	return uc.planRepo.List(ctx, limit, offset)
+}
+
+// UPDATE: cùng rule với Create; template dùng chung chỉ sửa ở cấp hệ thống
+func (uc *PlansUsecase) Update(ctx context.Context, in dto.UpdatePlanCmd) (*domain.Plan, error) {
+	if err := uc.authz.Require(ctx, authz.PlansWrite); err != nil {
+		return nil, err
+	}
+	if err := validatePlan(in.Name, in.IntervalHours); err != nil {
+		return nil, err
+	}
+	if err := uc.requireOwnPlan(ctx, in.ID); err != nil {
+		return nil, err
+	}
+	return uc.planRepo.Update(ctx, outport.UpdatePlanInput{
+		ID:            in.ID,
+		Name:          in.Name,
+		IntervalHours: in.IntervalHours,
+		Description:   in.Description,
+		Version:       in.Version,
+	})
+}
+
+// DELETE: devices đang gắn plan sẽ về NULL (FK ON DELETE SET NULL)
+func (uc *PlansUsecase) Delete(ctx context.Context, id domain.PlanID) error {
+	if err := uc.authz.Require(ctx, authz.PlansWrite); err != nil {
+		return err
+	}
+	if err := uc.requireOwnPlan(ctx, id); err != nil {
+		return err
+	}
+	return uc.planRepo.Delete(ctx, id)
+}
+
+// --- helpers ---
+
+// requireOwnPlan: tenant không được sửa/xóa template dùng chung (RLS cũng chặn, đây để báo lỗi rõ ràng)
+func (uc *PlansUsecase) requireOwnPlan(ctx context.Context, id domain.PlanID) error {
+	p, err := uc.planRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if pr, _ := authz.PrincipalFrom(ctx); p.Shared() && pr.TenantID != "" {
+		return domain.Forbidden("shared_plan_read_only", "shared template plans are read-only")
+	}
+	return nil
+}
+
+func validatePlan(name string, intervalHours int) error {
+	var v domain.Violations
+	if name == "" {
+		v.Add("name", "is required")
+	}
+	if intervalHours <= 0 {
+		v.Add("interval_hours", "must be > 0")
+	}
+	return v.Err()
+}
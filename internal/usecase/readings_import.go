@@ -0,0 +1,328 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	outport "wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/authz"
+	"wh-ma/internal/usecase/dto"
+)
+
+// MaxReadingImportRows: giới hạn một lần nhập (một transaction)
+const MaxReadingImportRows = 200000
+
+// IMPORT (lịch sử giờ máy từ sổ nhật ký)
+//   - device theo device_id hoặc serial_number; mỗi dòng có hours_delta hoặc meter (số đồng hồ)
+//   - dòng của mỗi device được sắp theo thời gian; dòng trùng hệt (cùng thời điểm, cùng giá trị) bị bỏ qua,
+//     cùng thời điểm nhưng khác giá trị => lỗi
+//   - kiểm như Submit: device còn hoạt động, quyền theo device, không ở tương lai,
+//     không trước reading gần nhất, delta không vượt thời gian trôi qua
+//   - ghi cả lô một lần (CreateMany), rồi mỗi device cộng TotalHours/AOH, tính dự báo
+//     và kiểm ngưỡng bảo dưỡng đúng một lần ở cuối
+//   - device bị khóa dòng suốt transaction; dry-run hoặc còn dòng lỗi => rollback, chỉ trả báo cáo
+func (uc *ReadingsUsecase) Import(ctx context.Context, in dto.ImportReadingsCmd) (*dto.ReadingsImportReport, error) {
+	if err := uc.authz.Require(ctx, authz.ReadingsWrite); err != nil {
+		return nil, err
+	}
+	if len(in.Rows) == 0 {
+		return nil, domain.Invalid("file", "has no data rows")
+	}
+	if len(in.Rows) > MaxReadingImportRows {
+		return nil, domain.Invalid("file", fmt.Sprintf("has more than %d rows", MaxReadingImportRows))
+	}
+
+	rep := &dto.ReadingsImportReport{DryRun: in.DryRun, Total: len(in.Rows), Rows: make([]dto.ReadingImportRowResult, len(in.Rows))}
+	for i, row := range in.Rows {
+		rep.Rows[i] = dto.ReadingImportRowResult{Line: row.Line, DeviceID: row.DeviceID, At: row.At, Errors: checkImportRow(row)}
+	}
+
+	now := time.Now()
+	var created int64
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context, r outport.Repos) error {
+		if err := resolveSerials(ctx, r.Devices, in.Rows, rep); err != nil {
+			return err
+		}
+
+		// gom dòng theo device; khóa device theo thứ tự id (tránh deadlock giữa hai import)
+		groups := map[domain.DeviceID][]int{}
+		for i := range in.Rows {
+			if id := rep.Rows[i].DeviceID; id != 0 {
+				groups[id] = append(groups[id], i)
+			}
+		}
+		ids := make([]domain.DeviceID, 0, len(groups))
+		for id := range groups {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(a, b int) bool { return ids[a] < ids[b] })
+
+		var batch []outport.CreateReadingInput
+		var usage []outport.AddDeviceUsageInput
+		for _, id := range ids {
+			idx := groups[id]
+			dev, err := uc.lockImportDevice(ctx, r.Devices, id)
+			var de *domain.Error
+			if errors.As(err, &de) {
+				for _, i := range idx {
+					rep.Rows[i].Errors = append(rep.Rows[i].Errors, deviceRowError(de))
+				}
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			rows := planDeviceReadings(dev, in.Rows, idx, rep, now)
+			if len(rows) == 0 {
+				continue
+			}
+			sum := 0
+			for _, x := range rows {
+				sum += x.HoursDelta
+			}
+			lastAt := rows[len(rows)-1].At
+			var plan *domain.Plan
+			if dev.PlanID != nil {
+				plan, _ = r.Plans.GetByID(ctx, *dev.PlanID)
+			}
+			// cửa sổ gồm reading đã có và các dòng sắp ghi; lịch sử có thể cũ hơn mốc bắt đầu ghi giờ
+			since, err := trackingStart(ctx, r.Readings, dev)
+			if err != nil {
+				return err
+			}
+			if first := rows[0].At; first.Before(since) {
+				since = first
+			}
+			w := usageWindow{From: windowStart(since, lastAt)}
+			if w.Hours, err = r.Readings.SumHoursSince(ctx, id, w.From); err != nil {
+				return err
+			}
+			for _, x := range rows {
+				if x.At.After(w.From) {
+					w.Hours += x.HoursDelta
+				}
+			}
+			avg, next := forecastUsage(dev, plan, w, sum, lastAt)
+			res := dto.ReadingImportDeviceResult{
+				DeviceID:          id,
+				Readings:          len(rows),
+				HoursAdded:        sum,
+				TotalHours:        dev.State.TotalHours + sum,
+				AfterOverhaul:     dev.State.AfterOverhaul + sum,
+				LastReadingAt:     lastAt,
+				AvgDailyHours:     avg,
+				ExpectedNextMaint: next,
+			}
+			res.MaintenanceDue = plan != nil && plan.IntervalHours > 0 && res.AfterOverhaul >= plan.IntervalHours
+			rep.Devices = append(rep.Devices, res)
+			batch = append(batch, rows...)
+			usage = append(usage, outport.AddDeviceUsageInput{
+				ID:                id,
+				HoursDelta:        sum,
+				At:                lastAt,
+				AvgDailyHours:     avg,
+				ExpectedNextMaint: next,
+			})
+		}
+
+		tallyReadings(rep)
+		if in.DryRun || rep.Invalid > 0 {
+			return errImportRejected
+		}
+
+		var err error
+		if created, err = r.Readings.CreateMany(ctx, batch); err != nil {
+			return err
+		}
+		for i, u := range usage {
+			dev, err := r.Devices.AddUsage(ctx, u)
+			if err != nil {
+				return err
+			}
+			res := &rep.Devices[i]
+			res.TotalHours, res.AfterOverhaul = dev.State.TotalHours, dev.State.AfterOverhaul
+			if res.MaintenanceDue {
+				if err := raiseMaintenanceDue(ctx, r.Alerts, dev.ID,
+					"Thiết bị đã vượt ngưỡng giờ bảo dưỡng theo kế hoạch"); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	switch {
+	case errors.Is(err, errImportRejected):
+		return rep, nil
+	case err != nil:
+		return nil, err
+	}
+	rep.Committed = true
+	rep.Created = int(created)
+	return rep, nil
+}
+
+// checkImportRow: lỗi không cần DB (thiếu device/thời điểm/giờ)
+func checkImportRow(row dto.ImportReadingRow) []domain.FieldError {
+	v := domain.Violations(append([]domain.FieldError(nil), row.Errors...))
+	if row.DeviceID == 0 && row.SerialNumber == "" && !hasField(v, "device_id") && !hasField(v, "serial_number") {
+		v.Add("device_id", "device_id or serial_number is required")
+	}
+	if row.At.IsZero() && !hasField(v, "at") {
+		v.Add("at", "is required")
+	}
+	switch {
+	case row.HoursDelta != nil && row.Meter != nil:
+		v.Add("hours_delta", "set either hours_delta or meter, not both")
+	case row.HoursDelta == nil && row.Meter == nil && !hasField(v, "hours_delta") && !hasField(v, "meter"):
+		v.Add("hours_delta", "hours_delta or meter is required")
+	case row.HoursDelta != nil && *row.HoursDelta < 0:
+		v.Add("hours_delta", "must be >= 0")
+	case row.Meter != nil && *row.Meter < 0:
+		v.Add("meter", "must be >= 0")
+	}
+	return v
+}
+
+// resolveSerials: serial_number -> device_id (device chưa xóa, trong tenant)
+func resolveSerials(ctx context.Context, devices outport.DeviceRepository, rows []dto.ImportReadingRow, rep *dto.ReadingsImportReport) error {
+	seen := map[string]bool{}
+	var serials []string
+	for _, row := range rows {
+		if row.DeviceID == 0 && row.SerialNumber != "" && !seen[row.SerialNumber] {
+			seen[row.SerialNumber] = true
+			serials = append(serials, row.SerialNumber)
+		}
+	}
+	if len(serials) == 0 {
+		return nil
+	}
+	found, err := devices.ListBySerials(ctx, serials)
+	if err != nil {
+		return err
+	}
+	bySerial := make(map[string]domain.DeviceID, len(found))
+	for _, d := range found {
+		bySerial[d.SerialNumber] = d.ID
+	}
+	for i, row := range rows {
+		if row.DeviceID != 0 || row.SerialNumber == "" {
+			continue
+		}
+		if id, ok := bySerial[row.SerialNumber]; ok {
+			rep.Rows[i].DeviceID = id
+		} else {
+			rep.Rows[i].Errors = append(rep.Rows[i].Errors,
+				domain.FieldError{Field: "serial_number", Message: "references an unknown device"})
+		}
+	}
+	return nil
+}
+
+// lockImportDevice: khóa dòng device + kiểm quyền/trạng thái như Submit
+func (uc *ReadingsUsecase) lockImportDevice(ctx context.Context, devices outport.DeviceRepository, id domain.DeviceID) (*domain.Device, error) {
+	dev, err := devices.GetForUpdate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.authz.RequireDevice(ctx, authz.ReadingsWrite, dev); err != nil {
+		return nil, err
+	}
+	if err := checkDeviceAcceptsReadings(dev); err != nil {
+		return nil, err
+	}
+	return dev, nil
+}
+
+func deviceRowError(e *domain.Error) domain.FieldError {
+	if errors.Is(e, domain.ErrNotFound) {
+		return domain.FieldError{Field: "device_id", Message: "references an unknown device"}
+	}
+	return domain.FieldError{Field: "device_id", Message: e.Error()}
+}
+
+// planDeviceReadings: sắp dòng của một device theo thời gian, bỏ trùng, đổi meter -> delta,
+// kiểm từng dòng so với dòng hợp lệ trước nó. Trả các reading sẽ ghi (theo thứ tự thời gian).
+func planDeviceReadings(dev *domain.Device, rows []dto.ImportReadingRow, idx []int, rep *dto.ReadingsImportReport, now time.Time) []outport.CreateReadingInput {
+	sort.SliceStable(idx, func(a, b int) bool { return rows[idx[a]].At.Before(rows[idx[b]].At) })
+
+	meter := dev.State.TotalHours // số đồng hồ hiện tại = tổng giờ đã ghi nhận
+	last := dev.State.LastReadingAt
+	prev := -1 // dòng hợp lệ gần nhất
+	var out []outport.CreateReadingInput
+	for _, i := range idx {
+		row, res := rows[i], &rep.Rows[i]
+		if !res.OK() {
+			continue
+		}
+		if prev >= 0 && rows[prev].At.Equal(row.At) {
+			if sameImportReading(rows[prev], row) {
+				res.DuplicateOf = rows[prev].Line
+			} else {
+				res.Errors = append(res.Errors, domain.FieldError{Field: "at",
+					Message: fmt.Sprintf("conflicts with line %d (same time, different values)", rows[prev].Line)})
+			}
+			continue
+		}
+
+		delta := 0
+		if row.Meter != nil {
+			delta = *row.Meter - meter
+			if delta < 0 {
+				res.Errors = append(res.Errors, domain.FieldError{Field: "meter",
+					Message: fmt.Sprintf("is lower than the previous reading (%d)", meter)})
+				continue
+			}
+		} else {
+			delta = *row.HoursDelta
+		}
+		res.HoursDelta = delta
+		if v := checkReading(last, row.At, delta, now); len(v) > 0 {
+			res.Errors = append(res.Errors, v...)
+			continue
+		}
+
+		meter += delta
+		at := row.At
+		last = &at
+		prev = i
+		out = append(out, outport.CreateReadingInput{
+			DeviceID:   dev.ID,
+			At:         row.At,
+			HoursDelta: delta,
+			Location:   row.Location,
+			OperatorID: row.OperatorID,
+		})
+	}
+	return out
+}
+
+func sameImportReading(a, b dto.ImportReadingRow) bool {
+	return eqPtr(a.HoursDelta, b.HoursDelta) && eqPtr(a.Meter, b.Meter) &&
+		eqPtr(a.Location, b.Location) && eqPtr(a.OperatorID, b.OperatorID)
+}
+
+func eqPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func tallyReadings(rep *dto.ReadingsImportReport) {
+	rep.Valid, rep.Invalid, rep.Duplicates = 0, 0, 0
+	for _, r := range rep.Rows {
+		switch {
+		case !r.OK():
+			rep.Invalid++
+		case r.DuplicateOf > 0:
+			rep.Duplicates++
+		default:
+			rep.Valid++
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	inport "wh-ma/internal/adapter/inbound/port"
+	outport "wh-ma/internal/adapter/outbound/port"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/authz"
+	"wh-ma/internal/usecase/dto"
+)
+
+type ReadingsUsecase struct {
+	readRepo outport.ReadingRepository
+	devRepo  outport.DeviceRepository
+	tx       outport.TxManager // Submit ghi readings + devices + alerts
+	authz    *authz.Authorizer
+}
+
+func NewReadingsUsecase(readRepo outport.ReadingRepository, devRepo outport.DeviceRepository, tx outport.TxManager, az *authz.Authorizer) *ReadingsUsecase {
+	return &ReadingsUsecase{readRepo: readRepo, devRepo: devRepo, tx: tx, authz: az}
+}
+
+// ✅ compile-time check: UC triển khai inbound port
+var _ inport.ReadingsInbound = (*ReadingsUsecase)(nil)
+
+// SUBMIT
+//   - operator chỉ nhập cho thiết bị ở site của mình (policy site_scoped)
+//   - device phải còn tồn tại, không decommissioned
+//   - hours_delta >= 0, at không ở tương lai, không trước reading gần nhất
+//   - hours_delta không vượt quá số giờ thực tế kể từ reading gần nhất
+//   - cộng TotalHours/AOH, tính lại AvgDailyHours + ExpectedNextMaint
+//   - AOH >= interval của plan -> alert "maintenance_due" (nếu chưa có)
+//   - cả chuỗi chạy trong một transaction, device bị khóa dòng: hai reading đồng thời
+//     không cùng qua được kiểm tra "không trước reading gần nhất"
+func (uc *ReadingsUsecase) Submit(ctx context.Context, in dto.SubmitReadingCmd) (*domain.Reading, error) {
+	if err := uc.authz.Require(ctx, authz.ReadingsWrite); err != nil {
+		return nil, err
+	}
+	var rd *domain.Reading
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context, r outport.Repos) error {
+		dev, err := r.Devices.GetForUpdate(ctx, in.DeviceID)
+		if err != nil {
+			return err
+		}
+		if err := uc.authz.RequireDevice(ctx, authz.ReadingsWrite, dev); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if in.At.IsZero() {
+			in.At = now
+		}
+		if err := validateReading(dev, in, now); err != nil {
+			return err
+		}
+
+		rd, err = r.Readings.Create(ctx, outport.CreateReadingInput{
+			DeviceID:   in.DeviceID,
+			At:         in.At,
+			HoursDelta: in.HoursDelta,
+			Location:   in.Location,
+			OperatorID: in.OperatorID,
+		})
+		if err != nil {
+			return err
+		}
+
+		var plan *domain.Plan
+		if dev.PlanID != nil {
+			plan, _ = r.Plans.GetByID(ctx, *dev.PlanID)
+		}
+		w, err := recentUsage(ctx, r.Readings, dev, in.At)
+		if err != nil {
+			return err
+		}
+		avg, next := forecastUsage(dev, plan, w, in.HoursDelta, in.At)
+		dev, err = r.Devices.AddUsage(ctx, outport.AddDeviceUsageInput{
+			ID:                in.DeviceID,
+			HoursDelta:        in.HoursDelta,
+			At:                in.At,
+			AvgDailyHours:     avg,
+			ExpectedNextMaint: next,
+		})
+		if err != nil {
+			return err
+		}
+
+		if plan != nil && plan.IntervalHours > 0 && dev.State.AfterOverhaul >= plan.IntervalHours {
+			return raiseMaintenanceDue(ctx, r.Alerts, dev.ID,
+				"Thiết bị đã vượt ngưỡng giờ bảo dưỡng theo kế hoạch")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rd, nil
+}
+
+// LIST: at DESC; chỉ device principal được xem
+func (uc *ReadingsUsecase) ListByDevice(ctx context.Context, deviceID domain.DeviceID, limit, offset int32) ([]*domain.Reading, error) {
+	if err := uc.authz.Require(ctx, authz.ReadingsRead); err != nil {
+		return nil, err
+	}
+	dev, err := uc.devRepo.GetByID(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.authz.RequireDevice(ctx, authz.ReadingsRead, dev); err != nil {
+		return nil, err
+	}
+	return uc.readRepo.ListByDevice(ctx, deviceID, limit, offset)
+}
+
+// --- helpers ---
+func validateReading(dev *domain.Device, in dto.SubmitReadingCmd, now time.Time) error {
+	if err := checkDeviceAcceptsReadings(dev); err != nil {
+		return err
+	}
+	return checkReading(dev.State.LastReadingAt, in.At, in.HoursDelta, now).Err()
+}
+
+func checkDeviceAcceptsReadings(dev *domain.Device) error {
+	if dev.DeletedAt != nil {
+		return domain.NotFound("device_not_found", "device is deleted")
+	}
+	if dev.Status == domain.StatusDecommissioned {
+		return domain.Conflict("device_decommissioned", "cannot submit readings for a decommissioned device")
+	}
+	return nil
+}
+
+// checkReading: rule của một reading so với reading gần nhất (last) của device
+func checkReading(last *time.Time, at time.Time, hoursDelta int, now time.Time) domain.Violations {
+	var v domain.Violations
+	if hoursDelta < 0 {
+		v.Add("hours_delta", "must be >= 0")
+	}
+	if at.After(now) {
+		v.Add("at", "cannot be in the future")
+	}
+	if last != nil {
+		if at.Before(*last) {
+			v.Add("at", "must not be before the last reading")
+		}
+		if float64(hoursDelta) > at.Sub(*last).Hours()+1 {
+			v.Add("hours_delta", "exceeds elapsed time since the last reading")
+		}
+	}
+	return v
+}
@@ -0,0 +1,323 @@
+package tracing
+
+import (
+	"context"
+	"io"
+
+	inport "wh-ma/internal/adapter/inbound/port"
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/dto"
+)
+
+// Decorator cho inbound port: mỗi lời gọi usecase là một span con của span HTTP/job,
+// kèm tenant, subject và DeviceID/PlanID lấy từ tham số (xem Start).
+
+// ==== DevicesInbound ====
+
+type devices struct {
+	next inport.DevicesInbound
+}
+
+func Devices(next inport.DevicesInbound) inport.DevicesInbound {
+	return devices{next: next}
+}
+
+func (d devices) Create(ctx context.Context, in dto.CreateDeviceCmd) (_ *domain.Device, err error) {
+	ctx, span := Start(ctx, "DevicesUsecase.Create", in)
+	defer func() { End(span, err) }()
+	return d.next.Create(ctx, in)
+}
+
+func (d devices) Get(ctx context.Context, id domain.DeviceID) (_ *domain.Device, err error) {
+	ctx, span := Start(ctx, "DevicesUsecase.Get", id)
+	defer func() { End(span, err) }()
+	return d.next.Get(ctx, id)
+}
+
+func (d devices) List(ctx context.Context, limit int32, offset int32) (_ []*domain.Device, err error) {
+	ctx, span := Start(ctx, "DevicesUsecase.List")
+	defer func() { End(span, err) }()
+	return d.next.List(ctx, limit, offset)
+}
+
+func (d devices) UpdateBasic(ctx context.Context, in dto.UpdateDeviceBasicCmd) (_ *domain.Device, err error) {
+	ctx, span := Start(ctx, "DevicesUsecase.UpdateBasic", in)
+	defer func() { End(span, err) }()
+	return d.next.UpdateBasic(ctx, in)
+}
+
+func (d devices) UpdatePlan(ctx context.Context, in dto.UpdateDevicePlanCmd) (_ *domain.Device, err error) {
+	ctx, span := Start(ctx, "DevicesUsecase.UpdatePlan", in)
+	defer func() { End(span, err) }()
+	return d.next.UpdatePlan(ctx, in)
+}
+
+func (d devices) SoftDelete(ctx context.Context, id domain.DeviceID) (err error) {
+	ctx, span := Start(ctx, "DevicesUsecase.SoftDelete", id)
+	defer func() { End(span, err) }()
+	return d.next.SoftDelete(ctx, id)
+}
+
+func (d devices) Patch(ctx context.Context, in dto.PatchDeviceCmd) (_ *domain.Device, err error) {
+	ctx, span := Start(ctx, "DevicesUsecase.Patch", in)
+	defer func() { End(span, err) }()
+	return d.next.Patch(ctx, in)
+}
+
+func (d devices) ListAudit(ctx context.Context, id domain.DeviceID, limit int32, offset int32) (_ []*domain.DeviceAuditEntry, err error) {
+	ctx, span := Start(ctx, "DevicesUsecase.ListAudit", id)
+	defer func() { End(span, err) }()
+	return d.next.ListAudit(ctx, id, limit, offset)
+}
+
+func (d devices) Import(ctx context.Context, in dto.ImportDevicesCmd) (_ *dto.ImportReport, err error) {
+	ctx, span := Start(ctx, "DevicesUsecase.Import", in)
+	defer func() { End(span, err) }()
+	return d.next.Import(ctx, in)
+}
+
+// ==== PlansInbound ====
+
+type plans struct {
+	next inport.PlansInbound
+}
+
+func Plans(next inport.PlansInbound) inport.PlansInbound {
+	return plans{next: next}
+}
+
+func (p plans) Create(ctx context.Context, in dto.CreatePlanCmd) (_ *domain.Plan, err error) {
+	ctx, span := Start(ctx, "PlansUsecase.Create", in)
+	defer func() { End(span, err) }()
+	return p.next.Create(ctx, in)
+}
+
+func (p plans) Get(ctx context.Context, id domain.PlanID) (_ *domain.Plan, err error) {
+	ctx, span := Start(ctx, "PlansUsecase.Get", id)
+	defer func() { End(span, err) }()
+	return p.next.Get(ctx, id)
+}
+
+func (p plans) List(ctx context.Context, limit int32, offset int32) (_ []*domain.Plan, err error) {
+	ctx, span := Start(ctx, "PlansUsecase.List")
+	defer func() { End(span, err) }()
+	return p.next.List(ctx, limit, offset)
+}
+
+func (p plans) Update(ctx context.Context, in dto.UpdatePlanCmd) (_ *domain.Plan, err error) {
+	ctx, span := Start(ctx, "PlansUsecase.Update", in)
+	defer func() { End(span, err) }()
+	return p.next.Update(ctx, in)
+}
+
+func (p plans) Delete(ctx context.Context, id domain.PlanID) (err error) {
+	ctx, span := Start(ctx, "PlansUsecase.Delete", id)
+	defer func() { End(span, err) }()
+	return p.next.Delete(ctx, id)
+}
+
+// ==== ReadingsInbound ====
+
+type readings struct {
+	next inport.ReadingsInbound
+}
+
+func Readings(next inport.ReadingsInbound) inport.ReadingsInbound {
+	return readings{next: next}
+}
+
+func (r readings) Submit(ctx context.Context, in dto.SubmitReadingCmd) (_ *domain.Reading, err error) {
+	ctx, span := Start(ctx, "ReadingsUsecase.Submit", in)
+	defer func() { End(span, err) }()
+	return r.next.Submit(ctx, in)
+}
+
+func (r readings) ListByDevice(ctx context.Context, deviceID domain.DeviceID, limit int32, offset int32) (_ []*domain.Reading, err error) {
+	ctx, span := Start(ctx, "ReadingsUsecase.ListByDevice", deviceID)
+	defer func() { End(span, err) }()
+	return r.next.ListByDevice(ctx, deviceID, limit, offset)
+}
+
+func (r readings) Import(ctx context.Context, in dto.ImportReadingsCmd) (_ *dto.ReadingsImportReport, err error) {
+	ctx, span := Start(ctx, "ReadingsUsecase.Import", in)
+	defer func() { End(span, err) }()
+	return r.next.Import(ctx, in)
+}
+
+// ==== MaintenanceInbound ====
+
+type maintenance struct {
+	next inport.MaintenanceInbound
+}
+
+func Maintenance(next inport.MaintenanceInbound) inport.MaintenanceInbound {
+	return maintenance{next: next}
+}
+
+func (m maintenance) Log(ctx context.Context, in dto.LogMaintenanceCmd) (_ *domain.MaintenanceEvent, err error) {
+	ctx, span := Start(ctx, "MaintenanceUsecase.Log", in)
+	defer func() { End(span, err) }()
+	return m.next.Log(ctx, in)
+}
+
+func (m maintenance) ListByDevice(ctx context.Context, deviceID domain.DeviceID, limit int32, offset int32) (_ []*domain.MaintenanceEvent, err error) {
+	ctx, span := Start(ctx, "MaintenanceUsecase.ListByDevice", deviceID)
+	defer func() { End(span, err) }()
+	return m.next.ListByDevice(ctx, deviceID, limit, offset)
+}
+
+// ==== AlertsInbound ====
+
+type alerts struct {
+	next inport.AlertsInbound
+}
+
+func Alerts(next inport.AlertsInbound) inport.AlertsInbound {
+	return alerts{next: next}
+}
+
+func (a alerts) ListOpenByDevice(ctx context.Context, deviceID domain.DeviceID, limit int32, offset int32) (_ []*domain.Alert, err error) {
+	ctx, span := Start(ctx, "AlertsUsecase.ListOpenByDevice", deviceID)
+	defer func() { End(span, err) }()
+	return a.next.ListOpenByDevice(ctx, deviceID, limit, offset)
+}
+
+func (a alerts) Resolve(ctx context.Context, id int64) (_ *domain.Alert, err error) {
+	ctx, span := Start(ctx, "AlertsUsecase.Resolve")
+	defer func() { End(span, err) }()
+	return a.next.Resolve(ctx, id)
+}
+
+// ==== APIKeysInbound ====
+
+type aPIKeys struct {
+	next inport.APIKeysInbound
+}
+
+func APIKeys(next inport.APIKeysInbound) inport.APIKeysInbound {
+	return aPIKeys{next: next}
+}
+
+func (a aPIKeys) Create(ctx context.Context, in dto.CreateAPIKeyCmd) (_ *dto.IssuedAPIKey, err error) {
+	ctx, span := Start(ctx, "APIKeysUsecase.Create", in)
+	defer func() { End(span, err) }()
+	return a.next.Create(ctx, in)
+}
+
+func (a aPIKeys) List(ctx context.Context, limit int32, offset int32) (_ []*domain.APIKey, err error) {
+	ctx, span := Start(ctx, "APIKeysUsecase.List")
+	defer func() { End(span, err) }()
+	return a.next.List(ctx, limit, offset)
+}
+
+func (a aPIKeys) Revoke(ctx context.Context, id int64) (_ *domain.APIKey, err error) {
+	ctx, span := Start(ctx, "APIKeysUsecase.Revoke")
+	defer func() { End(span, err) }()
+	return a.next.Revoke(ctx, id)
+}
+
+func (a aPIKeys) Authenticate(ctx context.Context, raw string) (_ domain.Principal, err error) {
+	ctx, span := Start(ctx, "APIKeysUsecase.Authenticate")
+	defer func() { End(span, err) }()
+	return a.next.Authenticate(ctx, raw)
+}
+
+// ==== ExportsInbound ====
+
+type exports struct {
+	next inport.ExportsInbound
+}
+
+func Exports(next inport.ExportsInbound) inport.ExportsInbound {
+	return exports{next: next}
+}
+
+func (e exports) Export(ctx context.Context, in dto.ExportCmd, w io.Writer) (_ int64, err error) {
+	ctx, span := Start(ctx, "ExportsUsecase.Export", in)
+	defer func() { End(span, err) }()
+	return e.next.Export(ctx, in, w)
+}
+
+func (e exports) StartJob(ctx context.Context, in dto.ExportCmd) (_ *dto.ExportJobView, err error) {
+	ctx, span := Start(ctx, "ExportsUsecase.StartJob", in)
+	defer func() { End(span, err) }()
+	return e.next.StartJob(ctx, in)
+}
+
+func (e exports) GetJob(ctx context.Context, id string) (_ *dto.ExportJobView, err error) {
+	ctx, span := Start(ctx, "ExportsUsecase.GetJob")
+	defer func() { End(span, err) }()
+	return e.next.GetJob(ctx, id)
+}
+
+func (e exports) Download(ctx context.Context, token string) (_ *dto.ExportDownload, err error) {
+	ctx, span := Start(ctx, "ExportsUsecase.Download")
+	defer func() { End(span, err) }()
+	return e.next.Download(ctx, token)
+}
+
+// ==== JobsInbound ====
+
+type jobs struct {
+	next inport.JobsInbound
+}
+
+func Jobs(next inport.JobsInbound) inport.JobsInbound {
+	return jobs{next: next}
+}
+
+func (j jobs) ListJobs(ctx context.Context) (_ *dto.JobsOverview, err error) {
+	ctx, span := Start(ctx, "JobsUsecase.ListJobs")
+	defer func() { End(span, err) }()
+	return j.next.ListJobs(ctx)
+}
+
+func (j jobs) ListRuns(ctx context.Context, job string, limit int32, offset int32) (_ []*domain.JobRun, err error) {
+	ctx, span := Start(ctx, "JobsUsecase.ListRuns")
+	defer func() { End(span, err) }()
+	return j.next.ListRuns(ctx, job, limit, offset)
+}
+
+func (j jobs) RecordRun(ctx context.Context, run *domain.JobRun) (err error) {
+	ctx, span := Start(ctx, "JobsUsecase.RecordRun", run)
+	defer func() { End(span, err) }()
+	return j.next.RecordRun(ctx, run)
+}
+
+// ==== FleetStatsInbound ====
+
+type fleetStats struct {
+	next inport.FleetStatsInbound
+}
+
+func FleetStats(next inport.FleetStatsInbound) inport.FleetStatsInbound {
+	return fleetStats{next: next}
+}
+
+func (f fleetStats) Collect(ctx context.Context) (_ []dto.TenantFleetStats, err error) {
+	ctx, span := Start(ctx, "FleetStatsUsecase.Collect")
+	defer func() { End(span, err) }()
+	return f.next.Collect(ctx)
+}
+
+// ==== LoggingInbound ====
+
+type logging struct {
+	next inport.LoggingInbound
+}
+
+func Logging(next inport.LoggingInbound) inport.LoggingInbound {
+	return logging{next: next}
+}
+
+func (l logging) GetLogLevel(ctx context.Context) (_ string, err error) {
+	ctx, span := Start(ctx, "LoggingUsecase.GetLogLevel")
+	defer func() { End(span, err) }()
+	return l.next.GetLogLevel(ctx)
+}
+
+func (l logging) SetLogLevel(ctx context.Context, level string) (_ string, err error) {
+	ctx, span := Start(ctx, "LoggingUsecase.SetLogLevel")
+	defer func() { End(span, err) }()
+	return l.next.SetLogLevel(ctx, level)
+}
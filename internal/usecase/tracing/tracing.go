@@ -0,0 +1,94 @@
+package tracing
+
+import (
+	"context"
+	"reflect"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"wh-ma/internal/domain"
+	"wh-ma/internal/usecase/authz"
+)
+
+// Tên instrumentation scope; provider/propagator global do bootstrap.InitTracing cài
+const Instrumentation = "wh-ma"
+
+// Thuộc tính domain gắn vào span (cùng tên ở usecase và repository để lọc trên Jaeger)
+const (
+	AttrTenant   = attribute.Key("whma.tenant")
+	AttrSubject  = attribute.Key("whma.subject")
+	AttrDeviceID = attribute.Key("whma.device.id")
+	AttrPlanID   = attribute.Key("whma.plan.id")
+)
+
+var (
+	deviceIDType = reflect.TypeOf(domain.DeviceID(0))
+	planIDType   = reflect.TypeOf(domain.PlanID(0))
+)
+
+// Start: span con của span trong ctx (request HTTP, job nền), thuộc tính lấy từ principal và
+// tham số của lời gọi: DeviceID/PlanID trực tiếp hoặc field cấp một của command/input struct.
+func Start(ctx context.Context, name string, args ...any) (context.Context, trace.Span) {
+	return start(ctx, name, nil, args)
+}
+
+// StartLinked: span gốc cho việc chạy tiếp sau request (job export nền): trace riêng,
+// link về span của request đã tạo ra nó (from không hợp lệ thì bỏ link).
+func StartLinked(ctx context.Context, from trace.SpanContext, name string, args ...any) (context.Context, trace.Span) {
+	opts := []trace.SpanStartOption{trace.WithNewRoot()}
+	if from.IsValid() {
+		opts = append(opts, trace.WithLinks(trace.Link{SpanContext: from}))
+	}
+	return start(ctx, name, opts, args)
+}
+
+func start(ctx context.Context, name string, opts []trace.SpanStartOption, args []any) (context.Context, trace.Span) {
+	opts = append(opts, trace.WithSpanKind(trace.SpanKindInternal))
+	ctx, span := otel.Tracer(Instrumentation).Start(ctx, name, opts...)
+	if !span.IsRecording() {
+		return ctx, span
+	}
+	if p, ok := authz.PrincipalFrom(ctx); ok {
+		span.SetAttributes(AttrTenant.String(p.TenantID), AttrSubject.String(p.Subject))
+	}
+	for _, a := range args {
+		span.SetAttributes(domainAttrs(reflect.ValueOf(a), true)...)
+	}
+	return ctx, span
+}
+
+// End: lỗi được ghi lên span rồi trả nguyên cho caller
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func domainAttrs(v reflect.Value, descend bool) []attribute.KeyValue {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	switch {
+	case v.Type() == deviceIDType:
+		return []attribute.KeyValue{AttrDeviceID.Int64(v.Int())}
+	case v.Type() == planIDType:
+		return []attribute.KeyValue{AttrPlanID.Int64(v.Int())}
+	case v.Kind() == reflect.Struct && descend:
+		var out []attribute.KeyValue
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				out = append(out, domainAttrs(v.Field(i), false)...)
+			}
+		}
+		return out
+	}
+	return nil
+}